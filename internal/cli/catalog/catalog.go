@@ -0,0 +1,25 @@
+package catalog
+
+import (
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// PathsGetter is a function that returns the Paths instance.
+type PathsGetter func() *config.Paths
+
+// NewCatalogCmd creates the catalog command with all subcommands.
+func NewCatalogCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "catalog",
+		Short: "Browse the databases and tables in the warehouse",
+		Long: `Browse the local warehouse: databases, tables, partitions and their sizes.
+
+Metadata is read from the metastore Thrift API (hive.metastore.uris, port
+9083 by default), so Hive's metastore must be running.`,
+	}
+
+	cmd.AddCommand(newLsCmd(pathsGetter))
+
+	return cmd
+}
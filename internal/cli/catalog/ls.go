@@ -0,0 +1,575 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/dataset"
+	ms "github.com/danieljhkim/local-data-platform/internal/metastore"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// Catalog is the metastore access ls needs; *ms.ThriftCatalog implements
+// it.
+type Catalog interface {
+	ms.Catalog
+	TablesByName(ctx context.Context, db string, names []string) ([]*ms.Table, error)
+	TableCounts(ctx context.Context) (map[string]int, error)
+	PartitionNames(ctx context.Context, db, table string) ([]string, error)
+}
+
+// sizer returns the on-disk size of each location it can measure.
+type sizer func(locations []string) map[string]int64
+
+type databaseEntry struct {
+	Name      string `json:"name"`
+	Location  string `json:"location,omitempty"`
+	Tables    int    `json:"tables"`
+	SizeBytes *int64 `json:"size-bytes,omitempty"`
+}
+
+type tableEntry struct {
+	Database      string           `json:"database"`
+	Name          string           `json:"name"`
+	Type          string           `json:"type"`
+	Format        string           `json:"format,omitempty"`
+	Location      string           `json:"location,omitempty"`
+	Columns       []ms.Column      `json:"columns,omitempty"`
+	PartitionKeys []ms.Column      `json:"partition-keys,omitempty"`
+	Partitions    *int             `json:"partitions,omitempty"` // nil if not partitioned
+	SizeBytes     *int64           `json:"size-bytes,omitempty"`
+	PartitionList []partitionEntry `json:"partition-list,omitempty"`
+}
+
+type partitionEntry struct {
+	Name      string   `json:"name"`
+	Values    []string `json:"values"`
+	Location  string   `json:"location,omitempty"`
+	SizeBytes *int64   `json:"size-bytes,omitempty"`
+}
+
+func newLsCmd(pathsGetter PathsGetter) *cobra.Command {
+	var (
+		jsonOut bool
+		noSize  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ls [db[.table]]",
+		Short: "List databases, tables or a table's partitions",
+		Long: `List databases, the tables of a database, or the details and partitions of
+a table, with formats, locations, partition counts and on-disk sizes.
+
+Sizes are measured on the local filesystem or with 'hdfs dfs -du': one per
+directory holding several listed locations (such as a database directory)
+and one for the rest; --no-size skips them.
+
+Examples:
+  local-data catalog ls
+  local-data catalog ls sales
+  local-data catalog ls sales.orders --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			host, port, err := ms.ThriftEndpoint(paths.CurrentHiveConf())
+			if err != nil {
+				return err
+			}
+			catalog, err := ms.ConnectThrift(host, port)
+			if err != nil {
+				return err
+			}
+			defer catalog.Close()
+
+			var size sizer
+			if !noSize {
+				size = func(locations []string) map[string]int64 { return locationSizes(paths, locations) }
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			out := cmd.OutOrStdout()
+
+			var target string
+			if len(args) == 1 {
+				target = strings.ToLower(args[0])
+			}
+			db, table, _ := strings.Cut(target, ".")
+			switch {
+			case db == "":
+				dbs, err := listDatabases(ctx, catalog, size)
+				if err != nil {
+					return err
+				}
+				if jsonOut {
+					return writeJSON(out, dbs)
+				}
+				return writeDatabases(out, dbs)
+			case table == "":
+				tables, err := listTables(ctx, catalog, db, size)
+				if err != nil {
+					return err
+				}
+				if jsonOut {
+					return writeJSON(out, tables)
+				}
+				return writeTables(out, tables)
+			default:
+				t, err := describeTable(ctx, catalog, db, table, size)
+				if err != nil {
+					return err
+				}
+				if jsonOut {
+					return writeJSON(out, t)
+				}
+				return writeTable(out, t)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Print JSON")
+	cmd.Flags().BoolVar(&noSize, "no-size", false, "Do not measure sizes on disk")
+
+	return cmd
+}
+
+func listDatabases(ctx context.Context, catalog Catalog, size sizer) ([]*databaseEntry, error) {
+	names, err := catalog.Databases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	counts, err := catalog.TableCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The metastore has no call returning several databases, so their
+	// locations are fetched one by one
+	var entries []*databaseEntry
+	for _, name := range names {
+		db, err := catalog.Database(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &databaseEntry{Name: name, Location: db.Location, Tables: counts[strings.ToLower(name)]})
+	}
+
+	if size != nil {
+		var locations []string
+		for _, e := range entries {
+			locations = append(locations, e.Location)
+		}
+		sizes := indexSizes(size(locations))
+		measured := map[*databaseEntry]int64{}
+		for _, e := range entries {
+			if e.SizeBytes = sizes.lookup(e.Location); e.SizeBytes != nil {
+				measured[e] = *e.SizeBytes
+			}
+		}
+		// default lives at the warehouse root, which also holds the other
+		// databases' <db>.db directories; count those only once
+		for _, e := range entries {
+			n, ok := measured[e]
+			if parent := enclosingDatabase(entries, e); ok && parent != nil && parent.SizeBytes != nil {
+				*parent.SizeBytes -= n
+			}
+		}
+	}
+	return entries, nil
+}
+
+// enclosingDatabase returns the database whose location most closely
+// contains e's location, or nil.
+func enclosingDatabase(entries []*databaseEntry, e *databaseEntry) *databaseEntry {
+	var parent *databaseEntry
+	for _, p := range entries {
+		if p == e || p.Location == "" || !strings.HasPrefix(locationKey(e.Location, false), strings.TrimRight(locationKey(p.Location, false), "/")+"/") {
+			continue
+		}
+		if parent == nil || len(p.Location) > len(parent.Location) {
+			parent = p
+		}
+	}
+	return parent
+}
+
+func listTables(ctx context.Context, catalog Catalog, db string, size sizer) ([]*tableEntry, error) {
+	if _, err := catalog.Database(ctx, db); err != nil {
+		return nil, err
+	}
+	names, err := catalog.Tables(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var tables []*ms.Table
+	if len(names) > 0 {
+		if tables, err = catalog.TablesByName(ctx, db, names); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+
+	var entries []*tableEntry
+	var locations []string
+	for _, t := range tables {
+		e := newTableEntry(t)
+		if len(t.PartitionKeys) > 0 {
+			// Partition names only; their storage descriptors are not needed
+			parts, err := catalog.PartitionNames(ctx, db, t.Name)
+			if err != nil {
+				return nil, err
+			}
+			n := len(parts)
+			e.Partitions = &n
+		}
+		entries = append(entries, e)
+		if e.Location != "" {
+			locations = append(locations, e.Location)
+		}
+	}
+
+	if size != nil {
+		sizes := indexSizes(size(locations))
+		for _, e := range entries {
+			e.SizeBytes = sizes.lookup(e.Location)
+		}
+	}
+	return entries, nil
+}
+
+func describeTable(ctx context.Context, catalog Catalog, db, name string, size sizer) (*tableEntry, error) {
+	t, err := catalog.Table(ctx, db, name)
+	if err != nil {
+		return nil, err
+	}
+	e := newTableEntry(t)
+	e.Columns = t.Columns
+	e.PartitionKeys = t.PartitionKeys
+
+	locations := []string{e.Location}
+	if len(t.PartitionKeys) > 0 {
+		parts, err := catalog.Partitions(ctx, db, name)
+		if err != nil {
+			return nil, err
+		}
+		n := len(parts)
+		e.Partitions = &n
+		for _, p := range parts {
+			pe := partitionEntry{Name: partitionName(t.PartitionKeys, p.Values), Values: p.Values}
+			if p.Storage != nil {
+				pe.Location = p.Storage.Location
+				locations = append(locations, pe.Location)
+			}
+			e.PartitionList = append(e.PartitionList, pe)
+		}
+		sort.Slice(e.PartitionList, func(i, j int) bool { return e.PartitionList[i].Name < e.PartitionList[j].Name })
+	}
+
+	if size != nil && !t.IsView() {
+		sizes := indexSizes(size(locations))
+		e.SizeBytes = sizes.lookup(e.Location)
+		for i := range e.PartitionList {
+			e.PartitionList[i].SizeBytes = sizes.lookup(e.PartitionList[i].Location)
+		}
+	}
+	return e, nil
+}
+
+func newTableEntry(t *ms.Table) *tableEntry {
+	e := &tableEntry{Database: t.Database, Name: t.Name, Type: tableType(t.Type), Format: storageFormat(t)}
+	if t.Storage != nil && !t.IsView() {
+		e.Location = t.Storage.Location
+	}
+	return e
+}
+
+// tableType returns a short name for a metastore table type.
+func tableType(t string) string {
+	switch t {
+	case ms.ManagedTable:
+		return "managed"
+	case ms.ExternalTable:
+		return "external"
+	case ms.VirtualView:
+		return "view"
+	case ms.MaterializedView:
+		return "materialized-view"
+	default:
+		return strings.ToLower(t)
+	}
+}
+
+// storageFormat derives the file format from table parameters and the
+// storage descriptor.
+func storageFormat(t *ms.Table) string {
+	if t.IsView() {
+		return ""
+	}
+	if strings.EqualFold(t.Parameters["table_type"], "ICEBERG") {
+		return "iceberg"
+	}
+	if provider := t.Parameters["spark.sql.sources.provider"]; provider != "" && !strings.EqualFold(provider, "hive") {
+		return strings.ToLower(provider)
+	}
+	if t.Storage == nil {
+		return ""
+	}
+	input := strings.ToLower(t.Storage.InputFormat)
+	serde := strings.ToLower(t.Storage.SerDe)
+	switch {
+	case strings.Contains(input, "parquet"):
+		return "parquet"
+	case strings.Contains(input, "orc"):
+		return "orc"
+	case strings.Contains(input, "avro"):
+		return "avro"
+	case strings.Contains(input, "sequencefile"):
+		return "sequencefile"
+	case strings.Contains(serde, "json"):
+		return "json"
+	case strings.Contains(serde, "opencsv"):
+		return "csv"
+	case strings.Contains(input, "textinputformat"):
+		return "text"
+	case t.Storage.InputFormat != "":
+		return t.Storage.InputFormat[strings.LastIndex(t.Storage.InputFormat, ".")+1:]
+	default:
+		return ""
+	}
+}
+
+// partitionName returns the key=value/... partition name.
+func partitionName(keys []ms.Column, values []string) string {
+	parts := make([]string, 0, len(values))
+	for i, v := range values {
+		key := fmt.Sprintf("_c%d", i)
+		if i < len(keys) {
+			key = keys[i].Name
+		}
+		parts = append(parts, key+"="+v)
+	}
+	return strings.Join(parts, "/")
+}
+
+// locationSizes measures locations grouped by file system. Locations that
+// share a parent directory, like the tables of a database, are measured
+// with one ChildSizes call on it and the rest with one Sizes call;
+// locations that cannot be measured are left out with a warning.
+func locationSizes(paths *config.Paths, locations []string) map[string]int64 {
+	groups := map[string][]string{}
+	var schemes []string
+	for _, loc := range locations {
+		if loc == "" {
+			continue
+		}
+		scheme := ""
+		if u, err := url.Parse(loc); err == nil {
+			scheme = u.Scheme
+		}
+		if _, ok := groups[scheme]; !ok {
+			schemes = append(schemes, scheme)
+		}
+		groups[scheme] = append(groups[scheme], loc)
+	}
+
+	sizes := map[string]int64{}
+	add := func(measured map[string]int64, err error) {
+		if err != nil {
+			util.Warn("Cannot measure sizes: %v", err)
+			return
+		}
+		for loc, n := range measured {
+			sizes[loc] = n
+		}
+	}
+	for _, scheme := range schemes {
+		fs, err := dataset.NewFileSystem(paths, groups[scheme][0])
+		if err != nil {
+			util.Warn("Cannot measure sizes: %v", err)
+			continue
+		}
+
+		children := map[string][]string{}
+		var parents, rest []string
+		for _, loc := range groups[scheme] {
+			parent, ok := parentLocation(loc)
+			if !ok {
+				rest = append(rest, loc)
+				continue
+			}
+			if _, seen := children[parent]; !seen {
+				parents = append(parents, parent)
+			}
+			children[parent] = append(children[parent], loc)
+		}
+		for _, parent := range parents {
+			if len(children[parent]) == 1 {
+				rest = append(rest, children[parent]...)
+				continue
+			}
+			add(fs.ChildSizes(parent))
+		}
+		if len(rest) > 0 {
+			add(fs.Sizes(rest))
+		}
+	}
+	return sizes
+}
+
+// parentLocation returns the directory holding location; ok is false for
+// a file system root.
+func parentLocation(location string) (string, bool) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", false
+	}
+	p := path.Clean("/" + u.Path)
+	if p == "/" {
+		return "", false
+	}
+	u.Path = path.Dir(p)
+	u.RawPath = ""
+	return u.String(), true
+}
+
+// sizeIndex holds measured sizes by locationKey.
+type sizeIndex map[string]int64
+
+// indexSizes indexes sizes by location with and without the authority, so
+// a location matches however the file system printed it.
+func indexSizes(sizes map[string]int64) sizeIndex {
+	idx := sizeIndex{}
+	for loc, n := range sizes {
+		idx[locationKey(loc, true)] = n
+	}
+	for loc, n := range sizes {
+		idx[locationKey(loc, false)] = n
+	}
+	return idx
+}
+
+func (idx sizeIndex) lookup(location string) *int64 {
+	if location == "" {
+		return nil
+	}
+	if n, ok := idx[locationKey(location, false)]; ok {
+		return &n
+	}
+	if n, ok := idx[locationKey(location, true)]; ok {
+		return &n
+	}
+	return nil
+}
+
+// locationKey normalizes a location for comparison: lower-case scheme and
+// host, a clean path without trailing slash, and file:/x, file:///x and
+// file://localhost/x alike. hostless also drops the authority.
+func locationKey(location string, hostless bool) string {
+	u, err := url.Parse(strings.TrimSpace(location))
+	if err != nil {
+		return strings.TrimRight(location, "/")
+	}
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	if hostless || scheme == "file" {
+		host = ""
+	}
+	key := path.Clean("/" + u.Path)
+	if scheme != "" {
+		key = scheme + "://" + host + key
+	}
+	return key
+}
+
+func formatSize(n *int64) string {
+	if n == nil {
+		return "-"
+	}
+	return util.FormatSize(*n)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeDatabases(w io.Writer, dbs []*databaseEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATABASE\tTABLES\tSIZE\tLOCATION")
+	for _, db := range dbs {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", db.Name, db.Tables, formatSize(db.SizeBytes), orDash(db.Location))
+	}
+	return tw.Flush()
+}
+
+func writeTables(w io.Writer, tables []*tableEntry) error {
+	if len(tables) == 0 {
+		_, err := fmt.Fprintln(w, "No tables found.")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TABLE\tTYPE\tFORMAT\tPARTITIONS\tSIZE\tLOCATION")
+	for _, t := range tables {
+		partitions := "-"
+		if t.Partitions != nil {
+			partitions = fmt.Sprint(*t.Partitions)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			t.Name, t.Type, orDash(t.Format), partitions, formatSize(t.SizeBytes), orDash(t.Location))
+	}
+	return tw.Flush()
+}
+
+func writeTable(w io.Writer, t *tableEntry) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Table:     %s.%s\n", t.Database, t.Name)
+	fmt.Fprintf(&sb, "Type:      %s\n", t.Type)
+	fmt.Fprintf(&sb, "Format:    %s\n", orDash(t.Format))
+	fmt.Fprintf(&sb, "Location:  %s\n", orDash(t.Location))
+	fmt.Fprintf(&sb, "Size:      %s\n", formatSize(t.SizeBytes))
+
+	tw := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "\nColumns:")
+	for _, c := range t.Columns {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.Name, c.Type)
+	}
+	if len(t.PartitionKeys) > 0 {
+		fmt.Fprintln(tw, "\nPartitioned by:")
+		for _, c := range t.PartitionKeys {
+			fmt.Fprintf(tw, "  %s\t%s\n", c.Name, c.Type)
+		}
+	}
+	tw.Flush()
+
+	if t.Partitions != nil {
+		fmt.Fprintf(&sb, "\nPartitions (%d):\n", *t.Partitions)
+		tw = tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+		for _, p := range t.PartitionList {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", p.Name, formatSize(p.SizeBytes), orDash(p.Location))
+		}
+		tw.Flush()
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
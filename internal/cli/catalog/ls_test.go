@@ -0,0 +1,281 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	ms "github.com/danieljhkim/local-data-platform/internal/metastore"
+)
+
+// fakeCatalog is a read-only in-memory Catalog that counts the table
+// lookups ls makes.
+type fakeCatalog struct {
+	ms.Catalog
+	dbs        map[string]*ms.Database
+	tables     map[string]*ms.Table
+	partitions map[string][]*ms.Partition
+	lookups    int
+}
+
+func (f *fakeCatalog) Databases(context.Context) ([]string, error) {
+	var names []string
+	for name := range f.dbs {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (f *fakeCatalog) Database(_ context.Context, name string) (*ms.Database, error) {
+	db, ok := f.dbs[name]
+	if !ok {
+		return nil, errors.New("no such database")
+	}
+	return db, nil
+}
+
+func (f *fakeCatalog) Tables(_ context.Context, db string) ([]string, error) {
+	var names []string
+	for key := range f.tables {
+		if name, ok := strings.CutPrefix(key, db+"."); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (f *fakeCatalog) Table(_ context.Context, db, name string) (*ms.Table, error) {
+	f.lookups++
+	t, ok := f.tables[db+"."+name]
+	if !ok {
+		return nil, errors.New("no such table")
+	}
+	return t, nil
+}
+
+func (f *fakeCatalog) TablesByName(_ context.Context, db string, names []string) ([]*ms.Table, error) {
+	f.lookups++
+	var tables []*ms.Table
+	for _, name := range names {
+		if t, ok := f.tables[db+"."+name]; ok {
+			tables = append(tables, t)
+		}
+	}
+	return tables, nil
+}
+
+func (f *fakeCatalog) TableCounts(context.Context) (map[string]int, error) {
+	counts := map[string]int{}
+	for key := range f.tables {
+		db, _, _ := strings.Cut(key, ".")
+		counts[db]++
+	}
+	return counts, nil
+}
+
+func (f *fakeCatalog) PartitionNames(_ context.Context, db, table string) ([]string, error) {
+	var names []string
+	for _, p := range f.partitions[db+"."+table] {
+		names = append(names, "dt="+p.Values[0])
+	}
+	return names, nil
+}
+
+func (f *fakeCatalog) Partitions(_ context.Context, db, table string) ([]*ms.Partition, error) {
+	return f.partitions[db+"."+table], nil
+}
+
+func newTestCatalog() *fakeCatalog {
+	const wh = "file:/warehouse/sales.db"
+	return &fakeCatalog{
+		dbs: map[string]*ms.Database{
+			"default": {Name: "default", Location: "file:/warehouse"},
+			"sales":   {Name: "sales", Location: wh},
+		},
+		tables: map[string]*ms.Table{
+			"sales.orders": {
+				Database:      "sales",
+				Name:          "orders",
+				Type:          ms.ExternalTable,
+				Columns:       []ms.Column{{Name: "id", Type: "bigint"}},
+				PartitionKeys: []ms.Column{{Name: "dt", Type: "string"}},
+				Storage: &ms.Storage{
+					Location:    wh + "/orders",
+					InputFormat: "org.apache.hadoop.hive.ql.io.parquet.MapredParquetInputFormat",
+				},
+			},
+			"sales.recent": {Database: "sales", Name: "recent", Type: ms.VirtualView, Storage: &ms.Storage{}},
+		},
+		partitions: map[string][]*ms.Partition{
+			"sales.orders": {
+				{Values: []string{"2024-01-02"}, Storage: &ms.Storage{Location: wh + "/orders/dt=2024-01-02"}},
+				{Values: []string{"2024-01-01"}, Storage: &ms.Storage{Location: wh + "/orders/dt=2024-01-01"}},
+			},
+		},
+	}
+}
+
+func fakeSizer(locations []string) map[string]int64 {
+	sizes := map[string]int64{}
+	for _, loc := range locations {
+		if loc != "" {
+			sizes[loc] = int64(len(loc))
+		}
+	}
+	return sizes
+}
+
+func TestListDatabases(t *testing.T) {
+	dbs, err := listDatabases(context.Background(), newTestCatalog(), fakeSizer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dbs) != 2 || dbs[0].Name != "default" || dbs[1].Name != "sales" {
+		t.Fatalf("databases = %+v", dbs)
+	}
+	if dbs[1].Tables != 2 {
+		t.Errorf("sales tables = %d, want 2", dbs[1].Tables)
+	}
+	if dbs[1].SizeBytes == nil || *dbs[1].SizeBytes != int64(len("file:/warehouse/sales.db")) {
+		t.Errorf("sales size = %v", dbs[1].SizeBytes)
+	}
+}
+
+func TestListDatabases_NestedLocations(t *testing.T) {
+	sizes := map[string]int64{
+		"file:/warehouse":          1000, // includes sales.db
+		"file:/warehouse/sales.db": 300,
+	}
+	dbs, err := listDatabases(context.Background(), newTestCatalog(), func([]string) map[string]int64 { return sizes })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dbs[0].SizeBytes == nil || *dbs[0].SizeBytes != 700 {
+		t.Errorf("default size = %v, want 700", dbs[0].SizeBytes)
+	}
+	if dbs[1].SizeBytes == nil || *dbs[1].SizeBytes != 300 {
+		t.Errorf("sales size = %v, want 300", dbs[1].SizeBytes)
+	}
+}
+
+func TestListTables(t *testing.T) {
+	catalog := newTestCatalog()
+	tables, err := listTables(context.Background(), catalog, "sales", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("tables = %+v", tables)
+	}
+	if catalog.lookups != 1 {
+		t.Errorf("tables fetched with %d calls, want 1", catalog.lookups)
+	}
+	orders, view := tables[0], tables[1]
+	if orders.Format != "parquet" || orders.Type != "external" || orders.Partitions == nil || *orders.Partitions != 2 {
+		t.Errorf("orders = %+v", orders)
+	}
+	if orders.SizeBytes != nil {
+		t.Error("sizes should not be measured without a sizer")
+	}
+	if view.Type != "view" || view.Format != "" || view.Location != "" || view.Partitions != nil {
+		t.Errorf("view = %+v", view)
+	}
+
+	if _, err := listTables(context.Background(), newTestCatalog(), "missing", nil); err == nil {
+		t.Error("expected error for a missing database")
+	}
+}
+
+func TestListTables_SizeLookupNormalizesLocations(t *testing.T) {
+	// du reports the locations with a trailing slash and file:/// form
+	sizes := map[string]int64{"file:///warehouse/sales.db/orders/": 42}
+	tables, err := listTables(context.Background(), newTestCatalog(), "sales", func([]string) map[string]int64 { return sizes })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tables[0].SizeBytes == nil || *tables[0].SizeBytes != 42 {
+		t.Errorf("orders size = %v, want 42", tables[0].SizeBytes)
+	}
+}
+
+func TestLocationKey(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		hostless bool
+	}{
+		{"file:/warehouse/t", "file:///warehouse/t/", false},
+		{"HDFS://NameNode:8020/w/t", "hdfs://namenode:8020/w/t", false},
+		{"hdfs://nn:8020/w/t", "hdfs:///w/t", true},
+	}
+	for _, tt := range tests {
+		if a, b := locationKey(tt.a, tt.hostless), locationKey(tt.b, tt.hostless); a != b {
+			t.Errorf("locationKey(%q) = %q, locationKey(%q) = %q", tt.a, a, tt.b, b)
+		}
+	}
+
+	idx := indexSizes(map[string]int64{"hdfs:/w/t": 7})
+	if n := idx.lookup("hdfs://nn:8020/w/t"); n == nil || *n != 7 {
+		t.Errorf("lookup without authority = %v, want 7", n)
+	}
+}
+
+func TestParentLocation(t *testing.T) {
+	if parent, ok := parentLocation("hdfs://nn:8020/warehouse/sales.db/orders/"); !ok || parent != "hdfs://nn:8020/warehouse/sales.db" {
+		t.Errorf("parentLocation = %q, %v", parent, ok)
+	}
+	if _, ok := parentLocation("hdfs://nn:8020/"); ok {
+		t.Error("a root has no parent")
+	}
+}
+
+func TestDescribeTable(t *testing.T) {
+	table, err := describeTable(context.Background(), newTestCatalog(), "sales", "orders", fakeSizer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table.PartitionList) != 2 || table.PartitionList[0].Name != "dt=2024-01-01" {
+		t.Fatalf("partitions = %+v", table.PartitionList)
+	}
+	if table.PartitionList[0].SizeBytes == nil {
+		t.Error("partition size not measured")
+	}
+
+	var out strings.Builder
+	if err := writeTable(&out, table); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Table:     sales.orders", "Format:    parquet", "Partitioned by:", "Partitions (2):", "dt=2024-01-01"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestStorageFormat(t *testing.T) {
+	tests := []struct {
+		table *ms.Table
+		want  string
+	}{
+		{&ms.Table{Parameters: map[string]string{"table_type": "ICEBERG"}}, "iceberg"},
+		{&ms.Table{Parameters: map[string]string{"spark.sql.sources.provider": "delta"}}, "delta"},
+		{&ms.Table{Storage: &ms.Storage{InputFormat: "org.apache.hadoop.hive.ql.io.orc.OrcInputFormat"}}, "orc"},
+		{&ms.Table{Storage: &ms.Storage{
+			InputFormat: "org.apache.hadoop.mapred.TextInputFormat",
+			SerDe:       "org.apache.hadoop.hive.serde2.OpenCSVSerde",
+		}}, "csv"},
+		{&ms.Table{Storage: &ms.Storage{
+			InputFormat: "org.apache.hadoop.mapred.TextInputFormat",
+			SerDe:       "org.apache.hadoop.hive.serde2.JsonSerDe",
+		}}, "json"},
+		{&ms.Table{Storage: &ms.Storage{InputFormat: "org.apache.hadoop.mapred.TextInputFormat"}}, "text"},
+		{&ms.Table{Type: ms.VirtualView}, ""},
+	}
+	for _, tt := range tests {
+		if got := storageFormat(tt.table); got != tt.want {
+			t.Errorf("storageFormat(%+v) = %q, want %q", tt.table, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package data
+
+import (
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	ms "github.com/danieljhkim/local-data-platform/internal/metastore"
+	"github.com/spf13/cobra"
+)
+
+// PathsGetter is a function that returns the Paths instance.
+type PathsGetter func() *config.Paths
+
+// NewDataCmd creates the data command with all subcommands.
+func NewDataCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "data",
+		Short: "Load, export and compare table data",
+		Long: `Move data between local files and Hive tables.
+
+Load creates a table from CSV, JSON or Parquet files, inferring the schema,
+and copies the files into the warehouse (local filesystem or HDFS, depending
+on the profile). Export writes a table, or a filtered subset, to a local CSV,
+JSON or Parquet file, and diff compares two tables or files with a Spark job.
+Hive must be running.`,
+	}
+
+	cmd.AddCommand(newLoadCmd(pathsGetter))
+	cmd.AddCommand(newExportCmd(pathsGetter))
+	cmd.AddCommand(newDiffCmd(pathsGetter))
+
+	return cmd
+}
+
+// connectCatalog connects to the metastore configured in the runtime overlay.
+func connectCatalog(paths *config.Paths) (*ms.ThriftCatalog, error) {
+	host, port, err := ms.ThriftEndpoint(paths.CurrentHiveConf())
+	if err != nil {
+		return nil, err
+	}
+	return ms.ConnectThrift(host, port)
+}
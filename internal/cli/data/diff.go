@@ -0,0 +1,102 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/danieljhkim/local-data-platform/internal/dataset"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newDiffCmd(pathsGetter PathsGetter) *cobra.Command {
+	var (
+		keys      []string
+		ignore    []string
+		tolerance float64
+		sample    int
+		jsonOut   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff <left> <right>",
+		Short: "Compare two tables or datasets with a Spark job",
+		Long: `Compare two datasets and report rows only on the left, only on the right,
+and (with --key) rows whose values changed, with a sample of each.
+
+Each side is a local file or directory (CSV with a header, JSON lines or
+Parquet) if the path exists, and a db.table name otherwise. The comparison
+runs as a generated Spark job, so datasets do not need to fit in memory.
+
+With --key, rows are matched on the key columns and --tolerance allows
+numeric values to differ by up to the given amount. Without keys, whole rows
+are compared as multisets. Column names are compared case-insensitively;
+--ignore leaves columns (e.g. load timestamps) out of the comparison.
+
+The command fails if the datasets differ.
+
+Examples:
+  local-data data diff sales.orders tests/golden/orders.csv --key id
+  local-data data diff staging.daily prod.daily --key ds,region --tolerance 0.001 --ignore updated_at`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			left, err := dataset.ParseDiffSource(args[0])
+			if err != nil {
+				return err
+			}
+			right, err := dataset.ParseDiffSource(args[1])
+			if err != nil {
+				return err
+			}
+			opts := dataset.DiffOptions{
+				Left:      left,
+				Right:     right,
+				Keys:      keys,
+				Ignore:    ignore,
+				Tolerance: tolerance,
+				Sample:    sample,
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if !jsonOut {
+				util.Log("Running Spark diff job...")
+			}
+			res, err := dataset.Diff(ctx, paths, opts)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(res); err != nil {
+					return err
+				}
+			} else if err := dataset.WriteDiffSummary(out, opts, res); err != nil {
+				return err
+			}
+
+			if !res.Equal() {
+				return fmt.Errorf("datasets differ")
+			}
+			if !jsonOut {
+				util.Success("Datasets match")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&keys, "key", "k", nil, "Key columns used to match rows (comma-separated or repeated)")
+	cmd.Flags().StringSliceVar(&ignore, "ignore", nil, "Columns to leave out of the comparison")
+	cmd.Flags().Float64Var(&tolerance, "tolerance", 0, "Absolute tolerance for numeric columns (requires --key)")
+	cmd.Flags().IntVar(&sample, "sample", dataset.DefaultDiffSample, "Differing rows to show per category")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Print the result as JSON")
+
+	return cmd
+}
@@ -0,0 +1,106 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/danieljhkim/local-data-platform/internal/dataset"
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+	"github.com/danieljhkim/local-data-platform/internal/sqlscript"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd(pathsGetter PathsGetter) *cobra.Command {
+	var (
+		format string
+		outDir string
+		where  string
+		limit  int
+		engine string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export <db.table>",
+		Short: "Write a Hive table to a local CSV, JSON or Parquet file",
+		Long: `Write the rows of a Hive table to a single local file, e.g. to compare
+pipeline outputs with golden files kept in git.
+
+The file is <out>/<table>.csv, <table>.jsonl (one JSON object per line) or
+<table>.parquet and is overwritten if it exists. CSV and JSON are fetched over
+HiveServer2 by default; Parquet is written by a Spark job (--engine spark).
+
+Rows are exported in the order the query returns them; use --where and
+--limit to select a subset.
+
+Examples:
+  local-data data export sales.orders --out tests/golden
+  local-data data export raw.events --format json --where "ds = '2024-01-01'" --limit 100
+  local-data data export raw.events --format parquet --out /tmp/events`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			db, tbl, err := dataset.ParseTableName(args[0])
+			if err != nil {
+				return err
+			}
+			opts := dataset.ExportOptions{Database: db, Table: tbl, Format: format, Where: where, Limit: limit}
+			switch format {
+			case dataset.FormatCSV, dataset.FormatJSON, dataset.FormatParquet:
+			default:
+				return fmt.Errorf("unsupported format %q (supported: csv, json, parquet)", format)
+			}
+			if limit < 0 {
+				return fmt.Errorf("--limit must not be negative")
+			}
+			if engine == "" {
+				engine = sqlscript.EngineHive
+				if format == dataset.FormatParquet {
+					engine = sqlscript.EngineSpark
+				}
+			}
+
+			if err := util.MkdirAll(outDir); err != nil {
+				return err
+			}
+			path := filepath.Join(outDir, opts.FileName())
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			switch engine {
+			case sqlscript.EngineHive:
+				client, err := hs2.Connect(ctx, hs2.OptionsFromConf(paths.CurrentHiveConf()))
+				if err != nil {
+					return err
+				}
+				defer client.Close()
+				rows, err := dataset.ExportHS2(ctx, client, opts, path)
+				if err != nil {
+					return err
+				}
+				util.Success("Exported %d row(s) from %s.%s to %s", rows, db, tbl, path)
+			case sqlscript.EngineSpark:
+				util.Log("Running Spark export job...")
+				if err := dataset.ExportSpark(ctx, paths, opts, path); err != nil {
+					return err
+				}
+				util.Success("Exported %s.%s to %s", db, tbl, path)
+			default:
+				return fmt.Errorf("unsupported engine %q (supported: %s, %s)", engine, sqlscript.EngineHive, sqlscript.EngineSpark)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", dataset.FormatCSV, "Output format: csv, json or parquet")
+	cmd.Flags().StringVarP(&outDir, "out", "o", ".", "Output directory")
+	cmd.Flags().StringVar(&where, "where", "", "Filter expression added as a WHERE clause")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of rows (0: all)")
+	cmd.Flags().StringVar(&engine, "engine", "", "Query engine: hive (HiveServer2) or spark (default: hive, spark for parquet)")
+
+	return cmd
+}
@@ -1,10 +1,15 @@
 package env
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"strings"
 
+	"github.com/danieljhkim/local-data-platform/internal/config"
 	envpkg "github.com/danieljhkim/local-data-platform/internal/env"
+	"github.com/danieljhkim/local-data-platform/internal/metastore"
+	"github.com/danieljhkim/local-data-platform/internal/service/hive"
 	"github.com/spf13/cobra"
 )
 
@@ -29,6 +34,9 @@ Examples:
 
 			// Run doctor checks
 			result := envpkg.RunDoctor(target)
+			if target == "" || target == "start hive" {
+				checkMetastoreSchema(pathsGetter(), result)
+			}
 
 			// Print results
 			result.Print()
@@ -41,3 +49,58 @@ Examples:
 
 	return cmd
 }
+
+// checkMetastoreSchema adds metastore connectivity and schema version notes
+// when Hive has been initialized.
+func checkMetastoreSchema(paths *config.Paths, result *envpkg.DoctorResult) {
+	if _, err := os.Stat(paths.CurrentHiveConf()); err != nil {
+		return // Not initialized yet
+	}
+
+	service, err := hive.NewHiveService(paths)
+	if err != nil {
+		return
+	}
+
+	if !envpkg.NewToolDetector().IsInstalled("schematool") {
+		// Without schematool only connectivity can be checked
+		dbType, err := service.CheckMetastoreConnection(context.Background())
+		if err != nil {
+			addMetastoreErrorNote(result, err)
+		} else if dbType != metastore.Derby {
+			result.AddNote(envpkg.DoctorNote{Message: fmt.Sprintf("%s metastore reachable", dbType), Ok: true})
+		}
+		return
+	}
+
+	status, info, err := service.MetastoreSchema()
+	switch {
+	case err != nil:
+		addMetastoreErrorNote(result, err)
+	case status == hive.SchemaVersionMismatch:
+		result.AddNote(envpkg.DoctorNote{
+			Message: fmt.Sprintf("metastore schema %s does not match hive %s", info.SchemaVersion, info.HiveVersion),
+			Fix:     "local-data metastore upgrade",
+		})
+	case status == hive.SchemaNotInitialized:
+		result.AddNote(envpkg.DoctorNote{
+			Message: "metastore schema not initialized",
+			Fix:     "local-data init",
+		})
+	default:
+		result.AddNote(envpkg.DoctorNote{
+			Message: fmt.Sprintf("metastore schema %s", info.SchemaVersion),
+			Ok:      true,
+		})
+	}
+}
+
+// addMetastoreErrorNote reports typed metastore connectivity failures.
+// Other errors (e.g. a running embedded Derby) are not dependency problems.
+func addMetastoreErrorNote(result *envpkg.DoctorResult, err error) {
+	fix := metastore.FixHint(err)
+	if fix == "" {
+		return
+	}
+	result.AddNote(envpkg.DoctorNote{Message: err.Error(), Fix: fix})
+}
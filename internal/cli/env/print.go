@@ -16,12 +16,14 @@ Output can be evaluated in your shell to set up the local-data-platform environm
   eval "$(local-data env print)"
 
 This sets HADOOP_CONF_DIR, HIVE_CONF_DIR, SPARK_CONF_DIR, PATH, and other
-variables to use the active profile configuration.`,
+variables to use the active profile configuration. When a .local-data.yaml is
+found in the working directory or a parent, its profile, overrides and jars
+are applied and the first line of the output names the project file.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			paths := pathsGetter()
 
 			// Compute environment
-			env, err := envpkg.Compute(paths)
+			env, err := envpkg.ComputeForProject(paths)
 			if err != nil {
 				return err
 			}
@@ -11,10 +11,22 @@ import (
 	"github.com/danieljhkim/local-data-platform/internal/config/generator"
 	"github.com/danieljhkim/local-data-platform/internal/metastore"
 	"github.com/danieljhkim/local-data-platform/internal/service/hive"
+	"github.com/danieljhkim/local-data-platform/internal/service/metastoredb"
 	"github.com/spf13/cobra"
 )
 
 var runMetastoreBootstrap = func(paths *config.Paths, in io.Reader, out, errOut io.Writer) error {
+	// A managed metastore DB creates its own role and database on start
+	db, err := metastoredb.NewMetastoreDBService(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create metastore-db service: %w", err)
+	}
+	if db.Enabled() {
+		if err := db.Start(); err != nil {
+			return err
+		}
+	}
+
 	svc, err := hive.NewHiveService(paths)
 	if err != nil {
 		return fmt.Errorf("failed to create Hive service: %w", err)
@@ -108,6 +120,9 @@ Defaults to Derby metastore for zero-setup local usage.`,
 			fmt.Fprintf(cmd.OutOrStdout(), "\nProfiles directory: %s\n", paths.UserProfilesDir())
 
 			if err := runMetastoreBootstrap(paths, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+				if hint := metastore.FixHint(err); hint != "" {
+					return fmt.Errorf("%w\n\nFix: %s", err, hint)
+				}
 				return err
 			}
 			fmt.Fprintln(cmd.OutOrStdout(), "Metastore bootstrap completed.")
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/metastore"
 )
 
 func TestInit_ConfirmsEachMutableSetting(t *testing.T) {
@@ -148,3 +150,28 @@ func TestInit_RejectsDBTypeDBURLMismatch(t *testing.T) {
 		t.Fatalf("expected warning in stderr:\n%s", errBuf.String())
 	}
 }
+
+func TestInit_BootstrapErrorIncludesFixHint(t *testing.T) {
+	baseDir := t.TempDir()
+	paths := config.NewPaths("", baseDir)
+
+	orig := runMetastoreBootstrap
+	runMetastoreBootstrap = func(paths *config.Paths, in io.Reader, out, errOut io.Writer) error {
+		info := &metastore.ConnInfo{DBType: metastore.Postgres, Host: "localhost", Port: "5432", Database: "metastore"}
+		return &metastore.CheckError{Kind: metastore.ErrAuthFailed, Info: info, Err: errors.New("password authentication failed")}
+	}
+	defer func() { runMetastoreBootstrap = orig }()
+
+	cmd := newInitCmd(func() *config.Paths { return paths })
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetIn(strings.NewReader("\npostgres\njdbc:postgresql://localhost:5432/metastore\n\n"))
+
+	err := cmd.Execute()
+	if !errors.Is(err, metastore.ErrAuthFailed) {
+		t.Fatalf("init error = %v, want ErrAuthFailed", err)
+	}
+	if !strings.Contains(err.Error(), "Fix: check the metastore credentials") {
+		t.Fatalf("init error should include a fix hint: %v", err)
+	}
+}
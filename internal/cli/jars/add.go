@@ -0,0 +1,43 @@
+package jars
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newAddCmd(pathsGetter PathsGetter) *cobra.Command {
+	var targets []string
+
+	cmd := &cobra.Command{
+		Use:   "add <jar>...",
+		Short: "Copy jars into the repository and register them",
+		Long: `Copy jars into $BASE_DIR/jars and register them for --target classpaths.
+Adding a jar that is already registered replaces its targets.
+
+Examples:
+  local-data jars add postgresql-42.7.4.jar --target hive,spark
+  local-data jars add my-udfs.jar
+  local-data jars add hadoop-aws-3.3.4.jar aws-java-sdk-bundle-1.12.262.jar --target hadoop,hive,spark`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo := config.NewJarRepository(pathsGetter())
+			out := cmd.OutOrStdout()
+			for _, src := range args {
+				jar, err := repo.Add(src, targets)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "Added %s (%s)\n", jar.Name, strings.Join(jar.Targets, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&targets, "target", []string{config.JarTargetHive, config.JarTargetSpark},
+		"Classpaths to add the jars to: "+strings.Join(config.JarTargets, ", "))
+
+	return cmd
+}
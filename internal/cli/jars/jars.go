@@ -0,0 +1,33 @@
+package jars
+
+import (
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// PathsGetter is a function that returns the Paths instance.
+type PathsGetter func() *config.Paths
+
+// NewJarsCmd creates the jars command with all subcommands.
+func NewJarsCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jars",
+		Short: "Manage extra jars for Hive, Spark and Hadoop",
+		Long: `Manage the jar repository at $BASE_DIR/jars.
+
+Registered jars are added to the classpaths of their targets without
+touching Hive, Spark or Hadoop install directories:
+  hive    HIVE_AUX_JARS_PATH
+  spark   spark.jars and spark.driver.extraClassPath in spark-defaults.conf
+  hadoop  HADOOP_CLASSPATH
+
+The runtime overlay and environment pick up changes on the next command;
+restart running services to apply them there.`,
+	}
+
+	cmd.AddCommand(newAddCmd(pathsGetter))
+	cmd.AddCommand(newListCmd(pathsGetter))
+	cmd.AddCommand(newRemoveCmd(pathsGetter))
+
+	return cmd
+}
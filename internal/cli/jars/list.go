@@ -0,0 +1,47 @@
+package jars
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newListCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List registered jars",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo := config.NewJarRepository(pathsGetter())
+			jars, err := repo.List()
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(jars) == 0 {
+				fmt.Fprintln(out, "No jars registered.")
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tTARGETS\tSIZE")
+			for _, jar := range jars {
+				size := "(missing)"
+				if info, err := os.Stat(repo.Path(jar.Name)); err == nil {
+					size = util.FormatSize(info.Size())
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", jar.Name, strings.Join(jar.Targets, ","), size)
+			}
+			return tw.Flush()
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,30 @@
+package jars
+
+import (
+	"fmt"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newRemoveCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove <jar>...",
+		Aliases: []string{"rm"},
+		Short:   "Unregister jars and delete them from the repository",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo := config.NewJarRepository(pathsGetter())
+			for _, name := range args {
+				jar, err := repo.Remove(name)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Removed %s\n", jar.Name)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/danieljhkim/local-data-platform/internal/config"
 	"github.com/danieljhkim/local-data-platform/internal/service/hdfs"
 	"github.com/danieljhkim/local-data-platform/internal/service/hive"
+	"github.com/danieljhkim/local-data-platform/internal/service/metastoredb"
+	"github.com/danieljhkim/local-data-platform/internal/service/notebook"
+	"github.com/danieljhkim/local-data-platform/internal/service/s3"
 	"github.com/danieljhkim/local-data-platform/internal/service/yarn"
 	"github.com/danieljhkim/local-data-platform/internal/util"
 	"github.com/spf13/cobra"
@@ -55,6 +58,33 @@ This command tails the last 120 lines from each service's log files.`,
 				}
 			}
 
+			// Show managed metastore DB logs
+			dbSvc, err := metastoredb.NewMetastoreDBService(paths)
+			if err == nil && dbSvc.Enabled() {
+				util.Section("metastore-db Logs")
+				if err := dbSvc.Logs(); err != nil {
+					fmt.Printf("Error showing metastore-db logs: %v\n", err)
+				}
+			}
+
+			// Show local S3 server logs
+			s3Svc, err := s3.NewS3Service(paths)
+			if err == nil && s3Svc.Enabled() {
+				util.Section("s3 Logs")
+				if err := s3Svc.Logs(); err != nil {
+					fmt.Printf("Error showing s3 logs: %v\n", err)
+				}
+			}
+
+			// Show Jupyter logs
+			nbSvc, err := notebook.NewNotebookService(paths)
+			if err == nil && nbSvc.IsRunning() {
+				util.Section("notebook Logs")
+				if err := nbSvc.Logs(); err != nil {
+					fmt.Printf("Error showing notebook logs: %v\n", err)
+				}
+			}
+
 			return nil
 		},
 	}
@@ -0,0 +1,92 @@
+package metastore
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+	ms "github.com/danieljhkim/local-data-platform/internal/metastore"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd(pathsGetter PathsGetter) *cobra.Command {
+	var (
+		output    string
+		databases []string
+		noDDL     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export metastore definitions to a portable bundle",
+		Long: `Export databases, tables, views, partitions and storage descriptors to a
+portable bundle directory:
+
+  metastore.json  full definitions (read by 'metastore import')
+  ddl.sql         HiveQL script (SHOW CREATE TABLE via HiveServer2)
+
+Only metadata is exported; table data stays where it is.
+
+Examples:
+  local-data metastore export
+  local-data metastore export -o ./ms-backup --database sales --database hr`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			ctx := cmd.Context()
+
+			if output == "" {
+				output = "metastore-export-" + time.Now().Format("20060102-150405")
+			}
+
+			catalog, err := connectCatalog(paths)
+			if err != nil {
+				return err
+			}
+			defer catalog.Close()
+
+			opts := ms.ExportOptions{Databases: databases}
+			if settings, err := config.NewSettingsManager(paths).LoadOrDefault(); err == nil {
+				opts.SourceDBType = settings.DBType
+			}
+
+			if !noDDL {
+				client, err := hs2.Connect(ctx, hs2.OptionsFromConf(paths.CurrentHiveConf()))
+				if err != nil {
+					util.Warn("DDL will not be captured: %v", err)
+				} else {
+					defer client.Close()
+					opts.DDL = client
+				}
+			}
+
+			util.Log("Exporting metastore definitions...")
+			bundle, warnings, err := ms.Export(ctx, catalog, opts)
+			if err != nil {
+				return err
+			}
+			for _, w := range warnings {
+				util.Warn("%s", w)
+			}
+
+			if err := ms.WriteBundle(output, bundle); err != nil {
+				return err
+			}
+
+			dbs, tables, partitions := bundle.Counts()
+			abs, _ := filepath.Abs(output)
+			util.Success("Exported %d databases, %d tables, %d partitions to %s", dbs, tables, partitions, abs)
+			fmt.Fprintf(cmd.OutOrStdout(), "Import with: local-data metastore import %s\n", util.ShellEscape(abs))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Bundle directory (default: ./metastore-export-<timestamp>)")
+	cmd.Flags().StringArrayVar(&databases, "database", nil, "Database to export (repeatable; default: all)")
+	cmd.Flags().BoolVar(&noDDL, "no-ddl", false, "Skip SHOW CREATE TABLE (no HiveServer2 needed)")
+
+	return cmd
+}
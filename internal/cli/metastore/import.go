@@ -0,0 +1,82 @@
+package metastore
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	ms "github.com/danieljhkim/local-data-platform/internal/metastore"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd(pathsGetter PathsGetter) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import <bundle>",
+		Short: "Import a metastore bundle into the configured backend",
+		Long: `Replay a bundle created by 'metastore export' into the currently configured
+metastore (e.g. after 'local-data setting set db-type postgres').
+
+Existing databases are reused; existing tables and views are skipped.
+Tables are created before views, then partitions are added.
+
+Examples:
+  local-data metastore import ./metastore-export-20260101-120000 --dry-run
+  local-data metastore import ./metastore-export-20260101-120000`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			ctx := cmd.Context()
+
+			bundle, err := ms.ReadBundle(args[0])
+			if err != nil {
+				return err
+			}
+
+			catalog, err := connectCatalog(paths)
+			if err != nil {
+				return err
+			}
+			defer catalog.Close()
+
+			if dryRun {
+				util.Log("Dry run: no changes will be made")
+			}
+			report, err := ms.Import(ctx, catalog, bundle, dryRun)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "KIND\tNAME\tSTATUS")
+			for _, a := range report.Actions {
+				name := a.Name
+				if a.Kind == "partitions" {
+					name = fmt.Sprintf("%s (%d)", a.Name, a.Count)
+				}
+				status := string(a.Status)
+				if a.Err != nil {
+					status = fmt.Sprintf("%s: %v", a.Status, a.Err)
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", a.Kind, name, status)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+
+			if failed := report.Failed(); len(failed) > 0 {
+				return fmt.Errorf("%d of %d import actions failed", len(failed), len(report.Actions))
+			}
+			if !dryRun {
+				util.Success("Metastore import complete")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be created without changing the metastore")
+
+	return cmd
+}
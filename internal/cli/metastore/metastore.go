@@ -0,0 +1,39 @@
+package metastore
+
+import (
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	ms "github.com/danieljhkim/local-data-platform/internal/metastore"
+	"github.com/spf13/cobra"
+)
+
+// PathsGetter is a function that returns the Paths instance.
+type PathsGetter func() *config.Paths
+
+// NewMetastoreCmd creates the metastore command with all subcommands.
+func NewMetastoreCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metastore",
+		Short: "Manage the Hive metastore contents",
+		Long: `Manage the Hive metastore contents.
+
+Export and import move database, table and partition definitions between
+metastore backends (derby, postgres, mysql), e.g. before switching db-type;
+Hive must be running for both. Upgrade migrates the metastore schema after a
+Hive upgrade; Hive must be stopped.`,
+	}
+
+	cmd.AddCommand(newExportCmd(pathsGetter))
+	cmd.AddCommand(newImportCmd(pathsGetter))
+	cmd.AddCommand(newUpgradeCmd(pathsGetter))
+
+	return cmd
+}
+
+// connectCatalog connects to the metastore configured in the runtime overlay.
+func connectCatalog(paths *config.Paths) (*ms.ThriftCatalog, error) {
+	host, port, err := ms.ThriftEndpoint(paths.CurrentHiveConf())
+	if err != nil {
+		return nil, err
+	}
+	return ms.ConnectThrift(host, port)
+}
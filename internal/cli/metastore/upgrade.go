@@ -0,0 +1,60 @@
+package metastore
+
+import (
+	"fmt"
+
+	"github.com/danieljhkim/local-data-platform/internal/service/hive"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newUpgradeCmd(pathsGetter PathsGetter) *cobra.Command {
+	var backupDir string
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the metastore schema to the installed Hive version",
+		Long: `Upgrade the metastore schema after upgrading Hive (e.g. brew upgrade hive).
+
+The metastore database is backed up first, then 'schematool -upgradeSchema'
+is run:
+  derby     copy of the metastore_db directory
+  postgres  pg_dump (custom format)
+  mysql     mysqldump
+
+Backups are written to $BASE_DIR/backups by default. Hive must be stopped.
+
+Examples:
+  local-data stop hive
+  local-data metastore upgrade
+  local-data start hive`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			if backupDir == "" {
+				backupDir = paths.BackupsDir()
+			}
+
+			service, err := hive.NewHiveService(paths)
+			if err != nil {
+				return fmt.Errorf("failed to create Hive service: %w", err)
+			}
+
+			result, err := service.UpgradeMetastoreSchema(backupDir)
+			if err != nil {
+				return err
+			}
+			if result == nil {
+				return nil
+			}
+
+			util.Success("Metastore schema upgraded %s -> %s", result.From, result.To)
+			fmt.Fprintf(cmd.OutOrStdout(), "Backup: %s\n", result.BackupPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&backupDir, "backup-dir", "", "Directory for the pre-upgrade backup (default: $BASE_DIR/backups)")
+
+	return cmd
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/service"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateBaseDirCmd(pathsGetter func() *config.Paths) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-base-dir <new-base-dir>",
+		Short: "Move all local-data state to a new base directory",
+		Long: `Move all local-data state (conf, settings, service state) to a new base directory.
+
+Absolute paths in generated configs (profiles and the runtime overlay),
+overrides.yaml and settings (including the Derby metastore URL) are rewritten
+to point at the new location. All services must be stopped first.
+
+After migrating, point the CLI at the new location with either:
+  export ` + config.BaseDirEnvVar + `=<new-base-dir>
+  local-data --base-dir <new-base-dir> ...
+
+Examples:
+  local-data migrate-base-dir /Volumes/data/local-data-platform
+  local-data --base-dir ~/ldp-old migrate-base-dir ~/ldp-new`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+
+			newBase, err := config.ResolveBaseDir(args[0])
+			if err != nil {
+				return err
+			}
+
+			running, err := service.RunningDaemons(paths.StateDir())
+			if err != nil {
+				return fmt.Errorf("failed to check running services: %w", err)
+			}
+			if len(running) > 0 {
+				names := make([]string, 0, len(running))
+				for _, s := range running {
+					names = append(names, fmt.Sprintf("%s (pid %d)", s.Name, s.PID))
+				}
+				return fmt.Errorf("services are still running: %s\n\nRun: local-data stop", strings.Join(names, ", "))
+			}
+
+			newPaths, err := config.MigrateBaseDir(paths, newBase)
+			if err != nil {
+				return err
+			}
+			util.Success("Base dir migrated to %s", newPaths.BaseDir)
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "\nPoint local-data at the new location:\n")
+			fmt.Fprintf(out, "  export %s=%s\n", config.BaseDirEnvVar, util.ShellEscape(newPaths.BaseDir))
+			fmt.Fprintf(out, "\nTables created with absolute locations still reference the old path in the metastore.\n")
+			fmt.Fprintf(out, "To update them, start Hive and run:\n")
+			fmt.Fprintf(out, "  local-data env exec -- hive --service metatool -updateLocation file:%s file:%s\n",
+				util.ShellEscape(newPaths.BaseDir), util.ShellEscape(paths.BaseDir))
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,67 @@
+package mounts
+
+import (
+	"fmt"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newAddCmd(pathsGetter PathsGetter) *cobra.Command {
+	var onHDFS bool
+
+	cmd := &cobra.Command{
+		Use:   "add <uri> [target]",
+		Short: "Mount a cloud bucket or prefix onto a directory",
+		Long: `Mount a cloud bucket or prefix onto a directory.
+
+The target is a local path, a file:// or an hdfs:// URI. Without one, the
+mount gets its own directory under $BASE_DIR/state/mounts, or with --hdfs
+under /mounts on fs.defaultFS of the active profile (or of the hdfs
+profile). Adding an existing URI again replaces its target.
+
+Examples:
+  local-data mounts add gs://prod-events
+  local-data mounts add s3://lake/raw ./fixtures/raw
+  local-data mounts add abfss://lake@acct.dfs.core.windows.net/curated --hdfs`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			target := ""
+			if len(args) == 2 {
+				target = args[1]
+			}
+
+			mount, err := config.NewMount(paths, args[0], target, onHDFS)
+			if err != nil {
+				return err
+			}
+
+			settings, err := config.NewSettingsManager(paths).LoadOrDefault()
+			if err != nil {
+				return err
+			}
+			if err := settings.AddMount(mount); err != nil {
+				return err
+			}
+
+			if dir := mount.LocalDir(); dir != "" {
+				if err := util.MkdirAll(dir); err != nil {
+					return fmt.Errorf("failed to create mount directory: %w", err)
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			if err := save(out, paths, settings); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Mounted %s -> %s\n", mount.URI, mount.Target)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&onHDFS, "hdfs", false, "Default the target to /mounts on HDFS instead of $BASE_DIR/state/mounts")
+
+	return cmd
+}
@@ -0,0 +1,39 @@
+package mounts
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newListCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List mounts",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := config.NewSettingsManager(pathsGetter()).LoadOrDefault()
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(settings.Mounts) == 0 {
+				fmt.Fprintln(out, "No mounts configured.")
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "URI\tTARGET")
+			for _, m := range settings.Mounts {
+				fmt.Fprintf(tw, "%s\t%s\n", m.URI, m.Target)
+			}
+			return tw.Flush()
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,53 @@
+package mounts
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// PathsGetter is a function that returns the Paths instance.
+type PathsGetter func() *config.Paths
+
+// NewMountsCmd creates the mounts command with all subcommands.
+func NewMountsCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mounts",
+		Short: "Redirect cloud URIs (gs://, s3://, abfss://) to local storage",
+		Long: `Redirect cloud buckets, or prefixes of them, to local or HDFS directories.
+
+Mounts are persisted in $BASE_DIR/settings/setting.json and rendered into the
+generated core-site.xml, hive-site.xml and spark-defaults.conf as a ViewFS
+mount table: fs.<scheme>.impl becomes Hadoop's ViewFileSystemOverloadScheme
+(Hadoop 3.3+), so gs://bucket/raw/x.csv resolves to <target>/x.csv while
+buckets without a mount still go to the real connector.
+
+Supported schemes: gs, s3, s3a, abfs, abfss. Hadoop keys mount tables by
+host, so for abfs(s) the table is the storage account host, and mounts of one
+table cannot nest (a whole-bucket mount is the table's fallback).`,
+	}
+
+	cmd.AddCommand(newAddCmd(pathsGetter))
+	cmd.AddCommand(newListCmd(pathsGetter))
+	cmd.AddCommand(newRemoveCmd(pathsGetter))
+
+	return cmd
+}
+
+// save regenerates profiles from settings (which also saves them) and
+// reports whether Hive needs a restart.
+func save(out io.Writer, paths *config.Paths, settings *config.Settings) error {
+	pm := config.NewProfileManager(paths)
+	changes, err := pm.Regenerate(settings, false)
+	if err != nil {
+		return err
+	}
+	if !pm.IsInitialized() || len(changes) == 0 {
+		return nil
+	}
+	fmt.Fprintf(out, "Regenerated profiles (%d properties changed)\n", len(changes))
+	fmt.Fprintln(out, "Restart hive to apply: local-data stop hive && local-data start hive")
+	return nil
+}
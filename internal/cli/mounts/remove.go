@@ -0,0 +1,37 @@
+package mounts
+
+import (
+	"fmt"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newRemoveCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove <uri>",
+		Aliases: []string{"rm"},
+		Short:   "Remove a mount (the target directory is kept)",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			settings, err := config.NewSettingsManager(paths).LoadOrDefault()
+			if err != nil {
+				return err
+			}
+			mount, err := settings.RemoveMount(args[0])
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if err := save(out, paths, settings); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Removed mount %s (data kept in %s)\n", mount.URI, mount.Target)
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,124 @@
+package notebook
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/service/notebook"
+	"github.com/spf13/cobra"
+)
+
+// PathsGetter is a function that returns the Paths instance.
+type PathsGetter func() *config.Paths
+
+// NewNotebookCmd creates the notebook command with all subcommands.
+func NewNotebookCmd(pathsGetter PathsGetter) *cobra.Command {
+	var (
+		port int
+		dir  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "notebook",
+		Short: "Run JupyterLab with a PySpark kernel for the active profile",
+		Long: `Start JupyterLab in the background with the computed local-data environment.
+
+Jupyter comes from the active profile's virtualenv ('local-data python init')
+or PATH and listens on 127.0.0.1. A kernel spec, "PySpark (local-data,
+<profile>)", is generated under $BASE_DIR/state/notebook: it runs the PySpark
+driver python with SPARK_HOME and SPARK_CONF_DIR set and starts each notebook
+with 'spark' and 'sc' configured from spark-defaults.conf and hive-site.xml,
+so tables in the Hive metastore are available. The kernel needs ipykernel.
+
+The server is also managed with 'local-data start|stop|status notebook' and
+its log is included in 'local-data logs'.
+
+Examples:
+  local-data notebook                     # notebooks in $BASE_DIR/notebooks
+  local-data notebook --dir . --port 8899
+  local-data notebook status
+  local-data notebook stop`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := notebook.NewNotebookService(pathsGetter())
+			if err != nil {
+				return err
+			}
+			if port < 1 || port > 65535 {
+				return fmt.Errorf("invalid --port %d", port)
+			}
+			svc.Port = port
+			if dir != "" {
+				if svc.NotebookDir, err = filepath.Abs(dir); err != nil {
+					return err
+				}
+			}
+			return svc.Start()
+		},
+	}
+
+	cmd.Flags().IntVar(&port, "port", notebook.DefaultPort, "Port for JupyterLab")
+	cmd.Flags().StringVar(&dir, "dir", "", "Notebook directory (default: $BASE_DIR/notebooks)")
+
+	cmd.AddCommand(newStopCmd(pathsGetter))
+	cmd.AddCommand(newStatusCmd(pathsGetter))
+	cmd.AddCommand(newLogsCmd(pathsGetter))
+
+	return cmd
+}
+
+func newStopCmd(pathsGetter PathsGetter) *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop JupyterLab and its kernels",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := notebook.NewNotebookService(pathsGetter())
+			if err != nil {
+				return err
+			}
+			return svc.Stop()
+		},
+	}
+}
+
+func newStatusCmd(pathsGetter PathsGetter) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether JupyterLab is running and its URL",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := notebook.NewNotebookService(pathsGetter())
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if !svc.IsRunning() {
+				fmt.Fprintln(out, "notebook: stopped (start with: local-data notebook)")
+				return nil
+			}
+			url, err := svc.URL()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "notebook: running at %s\n", url)
+			return nil
+		},
+	}
+}
+
+func newLogsCmd(pathsGetter PathsGetter) *cobra.Command {
+	return &cobra.Command{
+		Use:   "logs",
+		Short: "Show the JupyterLab log",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := notebook.NewNotebookService(pathsGetter())
+			if err != nil {
+				return err
+			}
+			return svc.Logs()
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package project
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	proj "github.com/danieljhkim/local-data-platform/internal/project"
+	"github.com/spf13/cobra"
+)
+
+// PathsGetter is a function that returns the Paths instance.
+type PathsGetter func() *config.Paths
+
+// NewProjectCmd creates the project command with all subcommands.
+func NewProjectCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "project",
+		Short: "Inspect and sync the project configuration (.local-data.yaml)",
+		Long: `Inspect and sync the project configuration.
+
+A .local-data.yaml file is looked up from the working directory up to the
+filesystem root. It can select a profile, layer property overrides on top of
+it and declare the databases and jars the project needs:
+
+  profile: hdfs
+  overrides:
+    spark:
+      spark.sql.shuffle.partitions: 8
+    hive:
+      hive.exec.dynamic.partition.mode: nonstrict
+  databases: [raw, staging]
+  jars: [lib/udfs.jar]
+
+'local-data env print|exec' and the hive/pyspark/spark-submit wrappers use
+the project overlay in $BASE_DIR/conf/projects/<id>; services keep using the
+active profile.`,
+	}
+
+	cmd.AddCommand(newShowCmd(pathsGetter))
+	cmd.AddCommand(newSyncCmd(pathsGetter))
+
+	return cmd
+}
+
+// discover returns the project for the working directory or an error if none
+func discover() (*proj.Project, error) {
+	p, err := proj.Discover()
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		cwd, _ := os.Getwd()
+		return nil, fmt.Errorf("no %s found in %s or its parent directories", proj.FileName, cwd)
+	}
+	return p, nil
+}
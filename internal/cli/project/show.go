@@ -0,0 +1,82 @@
+package project
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+func newShowCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the project file in effect",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			p, err := discover()
+			if err != nil {
+				return err
+			}
+
+			profile := p.Profile
+			if profile == "" {
+				active, err := paths.ActiveProfile()
+				if err != nil {
+					return err
+				}
+				profile = active + " (active profile)"
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Project file: %s\n", p.Path)
+			fmt.Fprintf(out, "Profile:      %s\n", profile)
+			fmt.Fprintf(out, "Overlay:      %s\n", paths.ProjectConfDir(p.ID()))
+
+			if o := p.Overrides; o != nil {
+				fmt.Fprintln(out, "Overrides:")
+				printSection(out, "hive", o.Hive)
+				printSection(out, "spark", o.Spark)
+				if h := o.Hadoop; h != nil {
+					printSection(out, "core-site", h.CoreSite)
+					printSection(out, "hdfs-site", h.HDFSSite)
+					printSection(out, "yarn-site", h.YarnSite)
+					printSection(out, "mapred-site", h.MapredSite)
+					printSection(out, "capacity-scheduler", h.CapacityScheduler)
+				}
+			}
+			if len(p.Databases) > 0 {
+				fmt.Fprintln(out, "Databases:")
+				for _, db := range p.Databases {
+					fmt.Fprintf(out, "  - %s\n", db)
+				}
+			}
+			if len(p.Jars) > 0 {
+				fmt.Fprintln(out, "Jars:")
+				for _, jar := range p.Jars {
+					fmt.Fprintf(out, "  - %s\n", jar)
+				}
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func printSection(out io.Writer, section string, props map[string]interface{}) {
+	if len(props) == 0 {
+		return
+	}
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(out, "  %s:\n", section)
+	for _, name := range names {
+		fmt.Fprintf(out, "    %s = %v\n", name, props[name])
+	}
+}
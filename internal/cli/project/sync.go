@@ -0,0 +1,68 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	ms "github.com/danieljhkim/local-data-platform/internal/metastore"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newSyncCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Create the databases the project declares",
+		Long: `Create the databases listed under 'databases:' in .local-data.yaml that do
+not exist yet. Existing databases are left untouched. Hive must be running.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			p, err := discover()
+			if err != nil {
+				return err
+			}
+			if len(p.Databases) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s declares no databases.\n", p.Path)
+				return nil
+			}
+
+			host, port, err := ms.ThriftEndpoint(paths.CurrentHiveConf())
+			if err != nil {
+				return err
+			}
+			cat, err := ms.ConnectThrift(host, port)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the metastore (is Hive running?): %w", err)
+			}
+			defer cat.Close()
+
+			ctx := context.Background()
+			existing, err := cat.Databases(ctx)
+			if err != nil {
+				return err
+			}
+			have := make(map[string]bool, len(existing))
+			for _, db := range existing {
+				have[db] = true
+			}
+
+			created := 0
+			for _, db := range p.Databases {
+				if have[db] {
+					util.Log("Database exists: %s", db)
+					continue
+				}
+				if err := cat.CreateDatabase(ctx, &ms.Database{Name: db}); err != nil {
+					return fmt.Errorf("failed to create database %s: %w", db, err)
+				}
+				util.Success("Created database: %s", db)
+				created++
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%d database(s) created, %d already present.\n", created, len(p.Databases)-created)
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,143 @@
+package python
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newInitCmd(pathsGetter PathsGetter) *cobra.Command {
+	var (
+		profile      string
+		requirements string
+		python       string
+		archive      bool
+		force        bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a profile's virtualenv and use it for PySpark",
+		Long: `Create a virtualenv under $BASE_DIR/python/<profile> with --python, install
+--requirements into it and make it the profile's PySpark interpreter. Running
+init again on an existing virtualenv only installs the requirements; use
+--force to recreate it.
+
+With --archive (the default for profiles with Hadoop) the virtualenv is
+packed with venv-pack into $BASE_DIR/python/<profile>.tar.gz and shipped to
+YARN containers as ./environment.
+
+Examples:
+  local-data python init -r requirements.txt
+  local-data python init --profile hdfs --python python3.11 -r requirements.txt
+  local-data python init --profile local --force`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			profile, err := resolveProfile(paths, profile)
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("archive") {
+				archive = config.NewProfileManager(paths).HasHadoop(profile)
+			}
+			if requirements != "" && !util.FileExists(requirements) {
+				return fmt.Errorf("requirements file %s does not exist", requirements)
+			}
+
+			out := cmd.OutOrStdout()
+			dir := paths.PythonEnvDir(profile)
+			if force && util.DirExists(dir) {
+				if err := os.RemoveAll(dir); err != nil {
+					return fmt.Errorf("failed to remove virtualenv: %w", err)
+				}
+			}
+			if !util.DirExists(dir) {
+				if err := util.MkdirAll(filepath.Dir(dir)); err != nil {
+					return err
+				}
+				if err := run(cmd, python, "-m", "venv", dir); err != nil {
+					return fmt.Errorf("failed to create virtualenv: %w", err)
+				}
+				fmt.Fprintf(out, "Created virtualenv %s\n", dir)
+			}
+
+			venvPython := config.VenvPython(dir)
+			if requirements != "" {
+				if err := run(cmd, venvPython, "-m", "pip", "install", "-r", requirements); err != nil {
+					return fmt.Errorf("failed to install %s: %w", requirements, err)
+				}
+			}
+
+			sm := config.NewSettingsManager(paths)
+			settings, err := sm.LoadOrDefault()
+			if err != nil {
+				return err
+			}
+			pyEnv, _ := settings.PythonEnvFor(profile)
+			pyEnv.Python = venvPython
+			pyEnv.Archive = ""
+			if archive {
+				pyEnv.Archive = paths.PythonArchive(profile)
+				if err := pack(cmd, dir, pyEnv.Archive); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "Packed %s\n", pyEnv.Archive)
+			}
+			settings.SetPythonEnv(profile, pyEnv)
+			if err := sm.Save(settings); err != nil {
+				return err
+			}
+
+			printEnv(out, profile, pyEnv)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile to create the virtualenv for (default: active profile)")
+	cmd.Flags().StringVarP(&requirements, "requirements", "r", "", "Requirements file to install into the virtualenv")
+	cmd.Flags().StringVar(&python, "python", "python3", "Interpreter to create the virtualenv with")
+	cmd.Flags().BoolVar(&archive, "archive", false, "Pack the virtualenv and ship it to YARN containers (default: on for profiles with Hadoop)")
+	cmd.Flags().BoolVar(&force, "force", false, "Recreate an existing virtualenv")
+
+	return cmd
+}
+
+// pack installs venv-pack into the virtualenv at dir and packs it into
+// archive, which stays usable after YARN unpacks it elsewhere.
+func pack(cmd *cobra.Command, dir, archive string) error {
+	if err := run(cmd, config.VenvPython(dir), "-m", "pip", "install", "--quiet", "venv-pack"); err != nil {
+		return fmt.Errorf("failed to install venv-pack: %w", err)
+	}
+	venvPack := filepath.Join(dir, "bin", "venv-pack")
+	if err := run(cmd, venvPack, "--prefix", dir, "--output", archive, "--force"); err != nil {
+		return fmt.Errorf("failed to pack virtualenv: %w", err)
+	}
+	return nil
+}
+
+// run runs a command with its output going to cmd's output.
+func run(cmd *cobra.Command, name string, args ...string) error {
+	util.Log("Running: %s %s", name, strings.Join(args, " "))
+	c := exec.Command(name, args...)
+	c.Stdout = cmd.OutOrStdout()
+	c.Stderr = cmd.ErrOrStderr()
+	return c.Run()
+}
+
+// printEnv prints the interpreters PySpark uses in profile.
+func printEnv(out io.Writer, profile string, env config.PythonEnv) {
+	fmt.Fprintf(out, "PySpark python for profile %s:\n", profile)
+	fmt.Fprintf(out, "  PYSPARK_PYTHON=%s\n", env.Python)
+	fmt.Fprintf(out, "  PYSPARK_DRIVER_PYTHON=%s\n", env.Driver())
+	if env.Archive != "" {
+		fmt.Fprintf(out, "  YARN archive: %s\n", env.Archive)
+	}
+}
@@ -0,0 +1,51 @@
+package python
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newListCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the PySpark interpreters of each profile",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := config.NewSettingsManager(pathsGetter()).LoadOrDefault()
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(settings.Python) == 0 {
+				fmt.Fprintln(out, "No python environments configured (PySpark uses python on PATH).")
+				return nil
+			}
+
+			profiles := make([]string, 0, len(settings.Python))
+			for profile := range settings.Python {
+				profiles = append(profiles, profile)
+			}
+			sort.Strings(profiles)
+
+			tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "PROFILE\tPYTHON\tDRIVER PYTHON\tYARN ARCHIVE")
+			for _, profile := range profiles {
+				env := settings.Python[profile]
+				archive := env.Archive
+				if archive == "" {
+					archive = "-"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", profile, env.Python, env.Driver(), archive)
+			}
+			return tw.Flush()
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,55 @@
+package python
+
+import (
+	"fmt"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// PathsGetter is a function that returns the Paths instance.
+type PathsGetter func() *config.Paths
+
+// NewPythonCmd creates the python command with all subcommands.
+func NewPythonCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "python",
+		Short: "Manage the Python environments PySpark uses per profile",
+		Long: `Manage the Python interpreters PySpark uses in each profile.
+
+A profile's environment is persisted in $BASE_DIR/settings/setting.json and
+exported by the pyspark, spark-submit and env commands as PYSPARK_PYTHON and
+PYSPARK_DRIVER_PYTHON, so the driver and executors run the same interpreter
+instead of whichever python is first on PATH.
+
+'local-data python init' creates a virtualenv under $BASE_DIR/python/<profile>.
+For profiles with Hadoop it also packs the virtualenv and ships it with
+applications submitted to YARN (spark.yarn.dist.archives), so drivers in
+cluster mode run from the archive as well.`,
+	}
+
+	cmd.AddCommand(newInitCmd(pathsGetter))
+	cmd.AddCommand(newListCmd(pathsGetter))
+	cmd.AddCommand(newSetCmd(pathsGetter))
+	cmd.AddCommand(newRemoveCmd(pathsGetter))
+
+	return cmd
+}
+
+// resolveProfile returns profile, or the active profile when it is empty,
+// after checking that it exists.
+func resolveProfile(paths *config.Paths, profile string) (string, error) {
+	if profile == "" {
+		return paths.ActiveProfile()
+	}
+	profiles, err := config.NewProfileManager(paths).List()
+	if err != nil {
+		return "", err
+	}
+	for _, p := range profiles {
+		if p == profile {
+			return profile, nil
+		}
+	}
+	return "", fmt.Errorf("unknown profile %q (see: local-data profile list)", profile)
+}
@@ -0,0 +1,66 @@
+package python
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newRemoveCmd(pathsGetter PathsGetter) *cobra.Command {
+	var profile string
+
+	cmd := &cobra.Command{
+		Use:     "remove",
+		Aliases: []string{"rm"},
+		Short:   "Remove a profile's Python environment",
+		Long: `Remove a profile's Python environment so PySpark uses python on PATH
+again. A virtualenv created by 'local-data python init' and its archive
+are deleted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			if profile == "" {
+				var err error
+				if profile, err = paths.ActiveProfile(); err != nil {
+					return err
+				}
+			}
+
+			sm := config.NewSettingsManager(paths)
+			settings, err := sm.LoadOrDefault()
+			if err != nil {
+				return err
+			}
+			env, err := settings.RemovePythonEnv(profile)
+			if err != nil {
+				return err
+			}
+			if err := sm.Save(settings); err != nil {
+				return err
+			}
+
+			dir := paths.PythonEnvDir(profile)
+			if strings.HasPrefix(env.Python, dir+string(filepath.Separator)) {
+				if err := os.RemoveAll(dir); err != nil {
+					return fmt.Errorf("failed to remove virtualenv: %w", err)
+				}
+			}
+			if env.Archive != "" {
+				if err := os.Remove(env.Archive); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to remove archive: %w", err)
+				}
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed python environment of profile %s\n", profile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile to remove the environment of (default: active profile)")
+
+	return cmd
+}
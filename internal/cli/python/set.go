@@ -0,0 +1,63 @@
+package python
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newSetCmd(pathsGetter PathsGetter) *cobra.Command {
+	var (
+		profile      string
+		driverPython string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set <python>",
+		Short: "Set a profile's PySpark interpreters",
+		Long: `Set PYSPARK_PYTHON (and, with --driver-python, PYSPARK_DRIVER_PYTHON) for a
+profile, e.g. to use an existing conda environment. A virtualenv archive
+from 'local-data python init' is no longer shipped to YARN.
+
+Examples:
+  local-data python set /opt/conda/envs/etl/bin/python
+  local-data python set python3.11 --driver-python ipython --profile hdfs`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			profile, err := resolveProfile(paths, profile)
+			if err != nil {
+				return err
+			}
+			env := config.PythonEnv{Python: args[0], DriverPython: driverPython}
+			for _, python := range []string{env.Python, env.DriverPython} {
+				if python == "" {
+					continue
+				}
+				if _, err := exec.LookPath(python); err != nil {
+					return fmt.Errorf("python interpreter %s not found: %w", python, err)
+				}
+			}
+
+			sm := config.NewSettingsManager(paths)
+			settings, err := sm.LoadOrDefault()
+			if err != nil {
+				return err
+			}
+			settings.SetPythonEnv(profile, env)
+			if err := sm.Save(settings); err != nil {
+				return err
+			}
+
+			printEnv(cmd.OutOrStdout(), profile, env)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile to configure (default: active profile)")
+	cmd.Flags().StringVar(&driverPython, "driver-python", "", "Interpreter of the PySpark driver (default: <python>)")
+
+	return cmd
+}
@@ -6,10 +6,22 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/danieljhkim/local-data-platform/internal/cli/catalog"
+	"github.com/danieljhkim/local-data-platform/internal/cli/data"
 	"github.com/danieljhkim/local-data-platform/internal/cli/env"
+	"github.com/danieljhkim/local-data-platform/internal/cli/jars"
+	"github.com/danieljhkim/local-data-platform/internal/cli/metastore"
+	"github.com/danieljhkim/local-data-platform/internal/cli/mounts"
+	"github.com/danieljhkim/local-data-platform/internal/cli/notebook"
 	"github.com/danieljhkim/local-data-platform/internal/cli/profile"
+	"github.com/danieljhkim/local-data-platform/internal/cli/project"
+	"github.com/danieljhkim/local-data-platform/internal/cli/python"
+	"github.com/danieljhkim/local-data-platform/internal/cli/s3"
+	"github.com/danieljhkim/local-data-platform/internal/cli/schema"
 	"github.com/danieljhkim/local-data-platform/internal/cli/service"
 	"github.com/danieljhkim/local-data-platform/internal/cli/setting"
+	"github.com/danieljhkim/local-data-platform/internal/cli/snapshot"
+	sqlcmd "github.com/danieljhkim/local-data-platform/internal/cli/sql"
 	"github.com/danieljhkim/local-data-platform/internal/cli/wrappers"
 	"github.com/danieljhkim/local-data-platform/internal/config"
 	"github.com/danieljhkim/local-data-platform/internal/util"
@@ -19,6 +31,9 @@ import (
 var (
 	// Global paths instance
 	paths *config.Paths
+
+	// baseDirFlag holds the value of the global --base-dir flag
+	baseDirFlag string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -73,6 +88,9 @@ func colorizeHelp(s string) string {
 func init() {
 	cobra.OnInitialize(initConfig)
 
+	rootCmd.PersistentFlags().StringVar(&baseDirFlag, "base-dir", "",
+		"Base directory for runtime state (default: $"+config.BaseDirEnvVar+" or $HOME/local-data-platform)")
+
 	// Custom help function to colorize section headers
 	defaultHelp := rootCmd.HelpFunc()
 	rootCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
@@ -98,19 +116,34 @@ func init() {
 	addCmdToGroup(rootCmd, service.NewStopCmd(getPaths), "cluster")
 	addCmdToGroup(rootCmd, service.NewStatusCmd(getPaths), "cluster")
 	addCmdToGroup(rootCmd, NewLogsCmd(getPaths), "cluster")
+	addCmdToGroup(rootCmd, snapshot.NewSnapshotCmd(getPaths), "cluster")
 
 	// Data Platform Commands
 	addCmdToGroup(rootCmd, wrappers.NewHadoopCmd(getPaths), "platform")
 	addCmdToGroup(rootCmd, wrappers.NewHDFSCmd(getPaths), "platform")
 	addCmdToGroup(rootCmd, wrappers.NewHiveCmd(getPaths), "platform")
+	addCmdToGroup(rootCmd, sqlcmd.NewSQLCmd(getPaths), "platform")
+	addCmdToGroup(rootCmd, newTestCmd(getPaths), "platform")
 	addCmdToGroup(rootCmd, wrappers.NewPySparkCmd(getPaths), "platform")
 	addCmdToGroup(rootCmd, wrappers.NewSparkSubmitCmd(getPaths), "platform")
+	addCmdToGroup(rootCmd, wrappers.NewSparkSQLCmd(getPaths), "platform")
 	addCmdToGroup(rootCmd, wrappers.NewYARNCmd(getPaths), "platform")
+	addCmdToGroup(rootCmd, metastore.NewMetastoreCmd(getPaths), "platform")
+	addCmdToGroup(rootCmd, data.NewDataCmd(getPaths), "platform")
+	addCmdToGroup(rootCmd, schema.NewSchemaCmd(getPaths), "platform")
+	addCmdToGroup(rootCmd, catalog.NewCatalogCmd(getPaths), "platform")
+	addCmdToGroup(rootCmd, s3.NewS3Cmd(getPaths), "platform")
+	addCmdToGroup(rootCmd, mounts.NewMountsCmd(getPaths), "platform")
+	addCmdToGroup(rootCmd, jars.NewJarsCmd(getPaths), "platform")
+	addCmdToGroup(rootCmd, python.NewPythonCmd(getPaths), "platform")
+	addCmdToGroup(rootCmd, notebook.NewNotebookCmd(getPaths), "platform")
 
 	// Configuration
 	addCmdToGroup(rootCmd, profile.NewProfileCmd(getPaths), "config")
 	addCmdToGroup(rootCmd, env.NewEnvCmd(getPaths), "config")
 	addCmdToGroup(rootCmd, setting.NewSettingCmd(getPaths), "config")
+	addCmdToGroup(rootCmd, project.NewProjectCmd(getPaths), "config")
+	addCmdToGroup(rootCmd, newMigrateBaseDirCmd(getPaths), "config")
 
 	// CLI Utilities
 	versionCmd := &cobra.Command{
@@ -134,7 +167,10 @@ func addCmdToGroup(parent *cobra.Command, cmd *cobra.Command, groupID string) {
 func initConfig() {
 	// Initialize paths
 	repoRoot := getRepoRoot()
-	baseDir := config.DefaultBaseDir()
+	baseDir, err := config.ResolveBaseDir(baseDirFlag)
+	if err != nil {
+		util.Die("%v", err)
+	}
 	paths = config.NewPaths(repoRoot, baseDir)
 }
 
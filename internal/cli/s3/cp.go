@@ -0,0 +1,210 @@
+package s3
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/objectstore"
+	"github.com/spf13/cobra"
+)
+
+func newCpCmd(pathsGetter PathsGetter) *cobra.Command {
+	var recursive bool
+
+	cmd := &cobra.Command{
+		Use:   "cp <src> <dst>",
+		Short: "Copy files to, from or between buckets",
+		Long: `Copy a local file or directory to a bucket, an object or prefix to a local
+path, or objects between buckets. A destination ending in "/" (or an
+existing local directory) receives the source's base name.
+
+Examples:
+  local-data s3 cp orders.csv s3://data/raw/orders/
+  local-data s3 cp -r exports/ s3://data/exports/
+  local-data s3 cp s3://data/raw/orders/orders.csv .
+  local-data s3 cp -r s3://data/raw/ s3://backup/raw/`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := &copier{store: newStore(pathsGetter()), out: cmd.OutOrStdout(), recursive: recursive}
+			return c.copy(args[0], args[1])
+		},
+	}
+
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Copy directories and all objects under a prefix")
+
+	return cmd
+}
+
+type copier struct {
+	store     *objectstore.Store
+	out       io.Writer
+	recursive bool
+}
+
+func (c *copier) copy(src, dst string) error {
+	srcBucket, srcKey, srcS3, err := parseURI(src)
+	if err != nil {
+		return err
+	}
+	dstBucket, dstKey, dstS3, err := parseURI(dst)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !srcS3 && !dstS3:
+		return fmt.Errorf("source or destination must be an s3:// URI")
+	case !srcS3:
+		return c.upload(src, dstBucket, dstKey)
+	default:
+		keys, err := c.sourceKeys(srcBucket, srcKey)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if dstS3 {
+				err = c.copyObject(srcBucket, srcKey, key, dstBucket, dstKey)
+			} else {
+				err = c.download(srcBucket, srcKey, key, dst)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// sourceKeys returns the object itself, or with --recursive the objects
+// under the prefix (directory markers excluded).
+func (c *copier) sourceKeys(bucket, key string) ([]string, error) {
+	if !c.recursive {
+		if _, err := c.store.Stat(bucket, key); err != nil {
+			return nil, fmt.Errorf("s3://%s/%s: %w", bucket, key, err)
+		}
+		return []string{key}, nil
+	}
+
+	prefix := key
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	var keys []string
+	opts := objectstore.ListOptions{Prefix: prefix}
+	for {
+		res, err := c.store.List(bucket, opts)
+		if err != nil {
+			return nil, fmt.Errorf("s3://%s/%s: %w", bucket, prefix, err)
+		}
+		for _, obj := range res.Objects {
+			if !strings.HasSuffix(obj.Key, "/") {
+				keys = append(keys, obj.Key)
+			}
+		}
+		if !res.Truncated {
+			break
+		}
+		opts.StartAfter = res.Last
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no objects under s3://%s/%s", bucket, prefix)
+	}
+	return keys, nil
+}
+
+// target returns the destination key or path of one source object: with
+// --recursive its path relative to the source prefix under dst, otherwise
+// dst itself or, for a directory-like dst, dst plus the base name.
+func (c *copier) target(srcPrefix, key, dst string, dirLike bool) string {
+	if c.recursive {
+		rel := strings.TrimPrefix(strings.TrimPrefix(key, srcPrefix), "/")
+		if dst == "" || strings.HasSuffix(dst, "/") {
+			return dst + rel
+		}
+		return dst + "/" + rel
+	}
+	if dirLike {
+		return strings.TrimSuffix(dst, "/") + "/" + path.Base(key)
+	}
+	return dst
+}
+
+func (c *copier) upload(src, bucket, key string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() && !c.recursive {
+		return fmt.Errorf("%s is a directory (use --recursive)", src)
+	}
+
+	return filepath.WalkDir(src, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		dstKey := key
+		if c.recursive {
+			rel, err := filepath.Rel(src, p)
+			if err != nil {
+				return err
+			}
+			dstKey = c.target("", filepath.ToSlash(rel), key, false)
+		} else if key == "" || strings.HasSuffix(key, "/") {
+			dstKey = key + filepath.Base(p)
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := c.store.Put(bucket, dstKey, f); err != nil {
+			return fmt.Errorf("failed to upload %s: %w", p, err)
+		}
+		fmt.Fprintf(c.out, "upload: %s to s3://%s/%s\n", p, bucket, dstKey)
+		return nil
+	})
+}
+
+func (c *copier) download(bucket, srcKey, key, dst string) error {
+	info, err := os.Stat(dst)
+	dirLike := strings.HasSuffix(dst, string(filepath.Separator)) || strings.HasSuffix(dst, "/") || (err == nil && info.IsDir())
+	target := filepath.FromSlash(c.target(srcKey, key, filepath.ToSlash(dst), dirLike))
+
+	r, _, err := c.store.Open(bucket, key)
+	if err != nil {
+		return fmt.Errorf("s3://%s/%s: %w", bucket, key, err)
+	}
+	defer r.Close()
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "download: s3://%s/%s to %s\n", bucket, key, target)
+	return nil
+}
+
+func (c *copier) copyObject(srcBucket, srcKey, key, dstBucket, dstKey string) error {
+	target := c.target(srcKey, key, dstKey, dstKey == "" || strings.HasSuffix(dstKey, "/"))
+	target = strings.TrimPrefix(target, "/")
+	if _, err := c.store.Copy(srcBucket, key, dstBucket, target); err != nil {
+		return fmt.Errorf("failed to copy s3://%s/%s: %w", srcBucket, key, err)
+	}
+	fmt.Fprintf(c.out, "copy: s3://%s/%s to s3://%s/%s\n", srcBucket, key, dstBucket, target)
+	return nil
+}
@@ -0,0 +1,82 @@
+package s3
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/objectstore"
+)
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		in          string
+		bucket, key string
+		ok          bool
+	}{
+		{"s3://data/raw/a.csv", "data", "raw/a.csv", true},
+		{"s3a://data", "data", "", true},
+		{"s3://data/", "data", "", true},
+		{"./a.csv", "", "", false},
+	}
+	for _, tt := range tests {
+		bucket, key, ok, err := parseURI(tt.in)
+		if err != nil || bucket != tt.bucket || key != tt.key || ok != tt.ok {
+			t.Errorf("parseURI(%q) = %q, %q, %v, %v", tt.in, bucket, key, ok, err)
+		}
+	}
+	if _, _, _, err := parseURI("s3:///x"); err == nil {
+		t.Error("parseURI(s3:///x) should fail without a bucket")
+	}
+}
+
+func TestCopier_RoundTrip(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "day=1"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "day=1", "part-0.csv"), []byte("1,x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := objectstore.NewStore(t.TempDir())
+	if err := store.CreateBucket("data"); err != nil {
+		t.Fatal(err)
+	}
+	c := &copier{store: store, out: &bytes.Buffer{}, recursive: true}
+
+	if err := c.copy(src, "s3://data/raw/orders/"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Stat("data", "raw/orders/day=1/part-0.csv"); err != nil {
+		t.Fatalf("uploaded object: %v", err)
+	}
+
+	if err := c.copy("s3a://data/raw", "s3://data/backup"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Stat("data", "backup/orders/day=1/part-0.csv"); err != nil {
+		t.Fatalf("copied object: %v", err)
+	}
+
+	dst := t.TempDir()
+	if err := c.copy("s3://data/backup/orders/", dst); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dst, "day=1", "part-0.csv"))
+	if err != nil || string(data) != "1,x\n" {
+		t.Errorf("downloaded = %q, %v", data, err)
+	}
+
+	single := &copier{store: store, out: &bytes.Buffer{}}
+	if err := single.copy("s3://data/raw/orders/day=1/part-0.csv", dst+"/"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "part-0.csv")); err != nil {
+		t.Errorf("single download: %v", err)
+	}
+	if err := single.copy(src, "s3://data/x"); err == nil {
+		t.Error("copying a directory without --recursive should fail")
+	}
+}
@@ -0,0 +1,87 @@
+package s3
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/objectstore"
+	"github.com/spf13/cobra"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+func newLsCmd(pathsGetter PathsGetter) *cobra.Command {
+	var recursive bool
+
+	cmd := &cobra.Command{
+		Use:   "ls [s3://bucket[/prefix]]",
+		Short: "List buckets, or the objects under a prefix",
+		Long: `List buckets, or the objects and "directories" (PRE) under a prefix.
+
+Examples:
+  local-data s3 ls
+  local-data s3 ls s3://data/raw/
+  local-data s3 ls s3://data --recursive`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := newStore(pathsGetter())
+			out := cmd.OutOrStdout()
+
+			if len(args) == 0 {
+				buckets, err := store.Buckets()
+				if err != nil {
+					return err
+				}
+				for _, b := range buckets {
+					fmt.Fprintf(out, "%s %s\n", b.Created.Local().Format(timeLayout), b.Name)
+				}
+				return nil
+			}
+
+			bucket, prefix, ok, err := parseURI(args[0])
+			if err != nil {
+				return err
+			}
+			if !ok {
+				bucket, prefix, _ = strings.Cut(args[0], "/")
+			}
+			return listObjects(out, store, bucket, prefix, recursive)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "List all objects under the prefix with full keys")
+
+	return cmd
+}
+
+// listObjects prints objects like 'aws s3 ls': names relative to the
+// prefix's directory, or full keys when recursive.
+func listObjects(out io.Writer, store *objectstore.Store, bucket, prefix string, recursive bool) error {
+	opts := objectstore.ListOptions{Prefix: prefix}
+	if !recursive {
+		opts.Delimiter = "/"
+	}
+	dir := prefix[:strings.LastIndex(prefix, "/")+1]
+
+	for {
+		res, err := store.List(bucket, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list s3://%s/%s: %w", bucket, prefix, err)
+		}
+		for _, p := range res.CommonPrefixes {
+			fmt.Fprintf(out, "%30s %s\n", "PRE", strings.TrimPrefix(p, dir))
+		}
+		for _, obj := range res.Objects {
+			name := obj.Key
+			if !recursive {
+				name = strings.TrimPrefix(name, dir)
+			}
+			fmt.Fprintf(out, "%s %10d %s\n", obj.Modified.Local().Format(timeLayout), obj.Size, name)
+		}
+		if !res.Truncated {
+			return nil
+		}
+		opts.StartAfter = res.Last
+	}
+}
@@ -0,0 +1,35 @@
+package s3
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newMbCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mb <s3://bucket>",
+		Short: "Create a bucket",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucket, key, ok, err := parseURI(args[0])
+			if err != nil {
+				return err
+			}
+			if !ok {
+				bucket = args[0]
+			}
+			if key != "" {
+				return fmt.Errorf("mb takes a bucket, not an object: %s", args[0])
+			}
+
+			if err := newStore(pathsGetter()).CreateBucket(bucket); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", bucket, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "make_bucket: %s\n", bucket)
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+package s3
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/objectstore"
+	"github.com/spf13/cobra"
+)
+
+// PathsGetter is a function that returns the Paths instance.
+type PathsGetter func() *config.Paths
+
+// NewS3Cmd creates the s3 command with all subcommands.
+func NewS3Cmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "s3",
+		Short: "Manage buckets and objects of the local S3 server",
+		Long: `Manage buckets and objects of the local S3-compatible server.
+
+With 'local-data setting set s3 on', the generated core-site.xml,
+hive-site.xml and spark-defaults.conf point s3a:// and s3:// paths at the
+server ('local-data start s3'), so jobs written for S3 run unmodified
+against local buckets. Buckets are directories under $BASE_DIR/state/s3/data.
+
+The helpers below work on that directory directly and do not need the
+server to be running.`,
+	}
+
+	cmd.AddCommand(newServeCmd(pathsGetter))
+	cmd.AddCommand(newMbCmd(pathsGetter))
+	cmd.AddCommand(newLsCmd(pathsGetter))
+	cmd.AddCommand(newCpCmd(pathsGetter))
+
+	return cmd
+}
+
+func newStore(paths *config.Paths) *objectstore.Store {
+	return objectstore.NewStore(paths.S3Paths().DataDir)
+}
+
+// parseURI splits s3://bucket/key (or s3a://) into bucket and key; ok is
+// false for local paths.
+func parseURI(s string) (bucket, key string, ok bool, err error) {
+	rest, found := strings.CutPrefix(s, "s3://")
+	if !found {
+		rest, found = strings.CutPrefix(s, "s3a://")
+	}
+	if !found {
+		return "", "", false, nil
+	}
+	bucket, key, _ = strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", true, fmt.Errorf("missing bucket in %q", s)
+	}
+	return bucket, key, true, nil
+}
@@ -0,0 +1,57 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/objectstore"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newServeCmd(pathsGetter PathsGetter) *cobra.Command {
+	var port int
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the S3 server in the foreground",
+		Long: `Run the S3-compatible server in the foreground on 127.0.0.1.
+
+'local-data start s3' runs this command in the background; use it directly
+to watch requests while debugging a job. Only path-style requests are
+supported and credentials are not checked.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			if !cmd.Flags().Changed("port") {
+				settings, err := config.NewSettingsManager(paths).LoadOrDefault()
+				if err != nil {
+					return err
+				}
+				port = settings.S3ServerPort()
+			}
+
+			dir := paths.S3Paths().DataDir
+			if err := util.MkdirAll(dir); err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			logger := log.New(cmd.OutOrStdout(), "", log.LstdFlags)
+			addr := fmt.Sprintf("127.0.0.1:%d", port)
+			logger.Printf("Serving %s on http://%s", dir, addr)
+			return objectstore.ListenAndServe(ctx, addr, objectstore.NewStore(dir), logger.Printf)
+		},
+	}
+
+	cmd.Flags().IntVar(&port, "port", objectstore.DefaultPort, "Port to listen on (default: the s3-port setting)")
+
+	return cmd
+}
@@ -0,0 +1,89 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danieljhkim/local-data-platform/internal/ddl"
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd(pathsGetter PathsGetter) *cobra.Command {
+	var (
+		database string
+		dryRun   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <ddl-dir>",
+		Short: "Create tables and views from SHOW CREATE TABLE dumps",
+		Long: `Create the databases, tables and views in a directory of DDL files
+(*.sql, *.hql, *.ddl), e.g. SHOW CREATE TABLE output from a production
+metastore. Tables are created empty.
+
+Statements are adapted for the local platform:
+  - s3://, s3a://, gs://, abfs(s)://, wasb(s):// and remote hdfs:// locations
+    are rewritten to <warehouse>/<db>.db/<table> of the active profile
+  - statistics, Glue/Impala/Trino properties and properties pointing at
+    remote storage are removed from TBLPROPERTIES
+  - SerDes and input formats that are not on the Hive classpath are replaced
+    (JSON SerDes) or removed
+  - names are qualified and IF NOT EXISTS is added, so imports can be re-run
+
+Databases are created first (also those only referenced by tables), then
+tables and views after the objects they select from. Temporary tables,
+functions, storage-handler tables (HBase, Kafka, JDBC, Druid), ALTER and
+other statements are skipped. Every statement is reported as created,
+adapted, skipped or failed. HiveServer2 must be running unless --dry-run.
+
+Examples:
+  local-data schema import prod-ddl/ --dry-run
+  local-data schema import prod-ddl/ --database analytics`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			scripts, err := ddl.ReadScripts(args[0])
+			if err != nil {
+				return err
+			}
+
+			target := ddl.TargetFromConf(paths.CurrentHiveConf(), paths.CurrentHadoopConf())
+			target.Database = database
+			if target.Warehouse == "" {
+				util.Warn("hive.metastore.warehouse.dir is not set; remote locations will be removed")
+			}
+			items := ddl.Plan(scripts, target)
+
+			out := cmd.OutOrStdout()
+			if dryRun {
+				return ddl.WriteScript(out, items)
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			client, err := hs2.Connect(ctx, hs2.OptionsFromConf(paths.CurrentHiveConf()))
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			ddl.Apply(ctx, client, items)
+			if err := ddl.WriteReport(out, items); err != nil {
+				return err
+			}
+			if _, _, _, failed := ddl.Counts(items); failed > 0 {
+				return fmt.Errorf("%d statement(s) failed", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&database, "database", "d", "default", "Database for unqualified names before any USE statement")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the adapted DDL without running it")
+
+	return cmd
+}
@@ -0,0 +1,22 @@
+package schema
+
+import (
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// PathsGetter is a function that returns the Paths instance.
+type PathsGetter func() *config.Paths
+
+// NewSchemaCmd creates the schema command with all subcommands.
+func NewSchemaCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Mirror production table schemas",
+		Long:  `Create databases, tables and views from DDL dumps of another metastore.`,
+	}
+
+	cmd.AddCommand(newImportCmd(pathsGetter))
+
+	return cmd
+}
@@ -22,3 +22,15 @@ func NewStopCmd(pathsGetter PathsGetter) *cobra.Command {
 func NewStatusCmd(pathsGetter PathsGetter) *cobra.Command {
 	return newStatusCmd(pathsGetter)
 }
+
+// StartAll starts all services for the active profile
+func StartAll(paths *config.Paths) error {
+	profile, _ := paths.ActiveProfile()
+	return startAll(paths, profile)
+}
+
+// StopAll stops all services for the active profile
+func StopAll(paths *config.Paths) error {
+	profile, _ := paths.ActiveProfile()
+	return stopAll(paths, profile)
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/danieljhkim/local-data-platform/internal/config"
 	"github.com/danieljhkim/local-data-platform/internal/service/hdfs"
 	"github.com/danieljhkim/local-data-platform/internal/service/hive"
+	"github.com/danieljhkim/local-data-platform/internal/service/metastoredb"
+	"github.com/danieljhkim/local-data-platform/internal/service/notebook"
+	"github.com/danieljhkim/local-data-platform/internal/service/s3"
 	"github.com/danieljhkim/local-data-platform/internal/service/yarn"
 	"github.com/danieljhkim/local-data-platform/internal/util"
 	"github.com/spf13/cobra"
@@ -23,11 +26,19 @@ With no arguments:
 
 With a service name, starts only that service.
 
+If the metastore database is managed (setting set db-type postgres --managed),
+metastore-db is started before Hive. With 'setting set s3 on', the local S3
+server is started first. The Jupyter notebook server is only started on
+request ('local-data start notebook' or 'local-data notebook').
+
 Examples:
   local-data start           # Start all services for current profile
   local-data start hdfs      # Start HDFS only
   local-data start yarn      # Start YARN only
-  local-data start hive      # Start Hive only`,
+  local-data start hive      # Start Hive only
+  local-data start metastore-db  # Start the managed Postgres metastore DB
+  local-data start s3        # Start the local S3 server
+  local-data start notebook  # Start JupyterLab with the PySpark kernel`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			paths := pathsGetter()
@@ -42,32 +53,7 @@ Examples:
 
 			switch target {
 			case "":
-				// Start services based on profile
-				if profile == "local" {
-					// Local profile: only start Hive (uses local filesystem)
-					util.Section("start hive (local profile - no HDFS/YARN needed)")
-					if err := startHive(paths); err != nil {
-						return err
-					}
-				} else {
-					// HDFS profile: start all services in order
-					util.Section("start hdfs")
-					if err := startHDFS(paths); err != nil {
-						return err
-					}
-
-					fmt.Println()
-					util.Section("start yarn")
-					if err := startYARN(paths); err != nil {
-						return err
-					}
-
-					fmt.Println()
-					util.Section("start hive")
-					if err := startHive(paths); err != nil {
-						return err
-					}
-				}
+				return startAll(paths, profile)
 
 			case "hdfs":
 				return startHDFS(paths)
@@ -78,17 +64,55 @@ Examples:
 			case "hive":
 				return startHive(paths)
 
+			case "metastore-db":
+				return startMetastoreDB(paths)
+
+			case "s3":
+				return startS3(paths)
+
+			case "notebook":
+				return startNotebook(paths)
+
 			default:
-				return fmt.Errorf("unknown service: %s (valid: hdfs, yarn, hive)", target)
+				return fmt.Errorf("unknown service: %s (valid: hdfs, yarn, hive, metastore-db, s3, notebook)", target)
 			}
-
-			return nil
 		},
 	}
 
 	return cmd
 }
 
+// startAll starts the services used by a profile
+// local profile: Hive only; other profiles: HDFS → YARN → Hive
+// An enabled s3 server is started first
+func startAll(paths *config.Paths, profile string) error {
+	if err := startS3IfEnabled(paths); err != nil {
+		return err
+	}
+
+	if profile == "local" {
+		// Local profile: only start Hive (uses local filesystem)
+		util.Section("start hive (local profile - no HDFS/YARN needed)")
+		return startHive(paths)
+	}
+
+	// HDFS profile: start all services in order
+	util.Section("start hdfs")
+	if err := startHDFS(paths); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	util.Section("start yarn")
+	if err := startYARN(paths); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	util.Section("start hive")
+	return startHive(paths)
+}
+
 func startHDFS(paths *config.Paths) error {
 	svc, err := hdfs.NewHDFSService(paths)
 	if err != nil {
@@ -108,6 +132,17 @@ func startYARN(paths *config.Paths) error {
 }
 
 func startHive(paths *config.Paths) error {
+	// A managed metastore DB must be up before the Hive metastore connects
+	db, err := metastoredb.NewMetastoreDBService(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create metastore-db service: %w", err)
+	}
+	if db.Enabled() {
+		if err := db.Start(); err != nil {
+			return err
+		}
+	}
+
 	svc, err := hive.NewHiveService(paths)
 	if err != nil {
 		return fmt.Errorf("failed to create Hive service: %w", err)
@@ -115,3 +150,48 @@ func startHive(paths *config.Paths) error {
 
 	return svc.Start()
 }
+
+func startMetastoreDB(paths *config.Paths) error {
+	svc, err := metastoredb.NewMetastoreDBService(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create metastore-db service: %w", err)
+	}
+
+	return svc.Start()
+}
+
+func startS3(paths *config.Paths) error {
+	svc, err := s3.NewS3Service(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create s3 service: %w", err)
+	}
+
+	return svc.Start()
+}
+
+func startNotebook(paths *config.Paths) error {
+	svc, err := notebook.NewNotebookService(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create notebook service: %w", err)
+	}
+
+	return svc.Start()
+}
+
+// startS3IfEnabled starts the s3 server when settings turn it on
+func startS3IfEnabled(paths *config.Paths) error {
+	svc, err := s3.NewS3Service(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create s3 service: %w", err)
+	}
+	if !svc.Enabled() {
+		return nil
+	}
+
+	util.Section("start s3")
+	if err := svc.Start(); err != nil {
+		return err
+	}
+	fmt.Println()
+	return nil
+}
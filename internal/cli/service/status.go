@@ -1,13 +1,18 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/danieljhkim/local-data-platform/internal/config"
 	svc "github.com/danieljhkim/local-data-platform/internal/service"
 	"github.com/danieljhkim/local-data-platform/internal/service/hdfs"
 	"github.com/danieljhkim/local-data-platform/internal/service/hive"
+	"github.com/danieljhkim/local-data-platform/internal/service/metastoredb"
+	"github.com/danieljhkim/local-data-platform/internal/service/notebook"
+	"github.com/danieljhkim/local-data-platform/internal/service/s3"
 	"github.com/danieljhkim/local-data-platform/internal/service/yarn"
 	"github.com/danieljhkim/local-data-platform/internal/util"
 	"github.com/spf13/cobra"
@@ -24,12 +29,17 @@ With no arguments:
   - local profile: shows only Hive status
 
 With a service name, shows status of only that service.
+A managed metastore-db and the s3 server are shown whenever they are
+enabled or running, the notebook server whenever it is running.
 
 Examples:
   local-data status           # Show services for current profile
   local-data status hdfs      # Show HDFS only
   local-data status yarn      # Show YARN only
-  local-data status hive      # Show Hive only`,
+  local-data status hive      # Show Hive only
+  local-data status metastore-db  # Show the managed Postgres metastore DB
+  local-data status s3        # Show the local S3 server
+  local-data status notebook  # Show the JupyterLab server`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			paths := pathsGetter()
@@ -71,6 +81,16 @@ Examples:
 					}
 				}
 
+				if err := statusMetastoreDBIfUsed(paths); err != nil {
+					return err
+				}
+				if err := statusS3IfUsed(paths); err != nil {
+					return err
+				}
+				if err := statusNotebookIfRunning(paths); err != nil {
+					return err
+				}
+
 			case "hdfs":
 				return statusHDFS(paths)
 
@@ -80,8 +100,17 @@ Examples:
 			case "hive":
 				return statusHive(paths)
 
+			case "metastore-db":
+				return statusMetastoreDB(paths)
+
+			case "s3":
+				return statusS3(paths)
+
+			case "notebook":
+				return statusNotebook(paths)
+
 			default:
-				return fmt.Errorf("unknown service: %s (valid: hdfs, yarn, hive)", target)
+				return fmt.Errorf("unknown service: %s (valid: hdfs, yarn, hive, metastore-db, s3, notebook)", target)
 			}
 
 			return nil
@@ -168,6 +197,160 @@ func statusHive(paths *config.Paths) error {
 		rows = append(rows, row)
 	}
 
+	rows = append(rows, schemaRow(service))
+
+	util.StatusTable(rows)
+	return nil
+}
+
+func statusMetastoreDB(paths *config.Paths) error {
+	service, err := metastoredb.NewMetastoreDBService(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create metastore-db service: %w", err)
+	}
+
+	statuses, err := service.Status()
+	if err != nil {
+		return err
+	}
+
+	rows := statusRows(statuses)
+	switch {
+	case !service.Enabled():
+		rows[0].Detail = "not managed (enable: local-data setting set db-type postgres --managed)"
+	case rows[0].Detail != "":
+		rows[0].Detail += fmt.Sprintf(", port %d", service.Port())
+	default:
+		rows[0].Detail = fmt.Sprintf("port %d", service.Port())
+	}
+
 	util.StatusTable(rows)
 	return nil
 }
+
+// statusMetastoreDBIfUsed shows metastore-db in the overview when it is
+// enabled or still running
+func statusMetastoreDBIfUsed(paths *config.Paths) error {
+	service, err := metastoredb.NewMetastoreDBService(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create metastore-db service: %w", err)
+	}
+	if !service.Enabled() && !service.IsRunning() {
+		return nil
+	}
+
+	fmt.Println()
+	util.Section("metastore-db")
+	return statusMetastoreDB(paths)
+}
+
+func statusS3(paths *config.Paths) error {
+	service, err := s3.NewS3Service(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create s3 service: %w", err)
+	}
+
+	statuses, err := service.Status()
+	if err != nil {
+		return err
+	}
+
+	rows := statusRows(statuses)
+	switch {
+	case !service.Enabled():
+		rows[0].Detail = "not enabled (enable: local-data setting set s3 on)"
+	case rows[0].Detail != "":
+		rows[0].Detail += fmt.Sprintf(", port %d", service.Port())
+	default:
+		rows[0].Detail = fmt.Sprintf("port %d", service.Port())
+	}
+
+	util.StatusTable(rows)
+	return nil
+}
+
+// statusS3IfUsed shows s3 in the overview when it is enabled or still running
+func statusS3IfUsed(paths *config.Paths) error {
+	service, err := s3.NewS3Service(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create s3 service: %w", err)
+	}
+	if !service.Enabled() && !service.IsRunning() {
+		return nil
+	}
+
+	fmt.Println()
+	util.Section("s3")
+	return statusS3(paths)
+}
+
+func statusNotebook(paths *config.Paths) error {
+	service, err := notebook.NewNotebookService(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create notebook service: %w", err)
+	}
+
+	statuses, err := service.Status()
+	if err != nil {
+		return err
+	}
+
+	rows := statusRows(statuses)
+	if rows[0].Ok {
+		if url, err := service.URL(); err == nil {
+			rows[0].Detail += ", " + url
+		}
+	}
+
+	util.StatusTable(rows)
+	return nil
+}
+
+// statusNotebookIfRunning shows the notebook server in the overview while it runs
+func statusNotebookIfRunning(paths *config.Paths) error {
+	service, err := notebook.NewNotebookService(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create notebook service: %w", err)
+	}
+	if !service.IsRunning() {
+		return nil
+	}
+
+	fmt.Println()
+	util.Section("notebook")
+	return statusNotebook(paths)
+}
+
+// schemaRow reports whether the metastore schema matches the installed Hive.
+func schemaRow(service *hive.HiveService) util.StatusTableRow {
+	row := util.StatusTableRow{Name: "metastore schema"}
+
+	status, info, err := service.MetastoreSchema()
+	switch {
+	case errors.Is(err, hive.ErrDerbyInUse):
+		row.Status = "not checked"
+		row.Detail = err.Error()
+		row.Ok = true
+	case err != nil:
+		row.Status = "unknown"
+		row.Detail = firstLine(err.Error())
+	case status == hive.SchemaVersionMismatch:
+		row.Status = "version mismatch"
+		row.Detail = fmt.Sprintf("schema %s, hive %s (run: local-data metastore upgrade)", info.SchemaVersion, info.HiveVersion)
+	case status == hive.SchemaNotInitialized:
+		row.Status = "not initialized"
+		row.Detail = "run: local-data init"
+	default:
+		row.Status = "ok"
+		row.Detail = "version " + info.SchemaVersion
+		row.Ok = true
+	}
+	return row
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
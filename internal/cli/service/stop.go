@@ -6,6 +6,9 @@ import (
 	"github.com/danieljhkim/local-data-platform/internal/config"
 	"github.com/danieljhkim/local-data-platform/internal/service/hdfs"
 	"github.com/danieljhkim/local-data-platform/internal/service/hive"
+	"github.com/danieljhkim/local-data-platform/internal/service/metastoredb"
+	"github.com/danieljhkim/local-data-platform/internal/service/notebook"
+	"github.com/danieljhkim/local-data-platform/internal/service/s3"
 	"github.com/danieljhkim/local-data-platform/internal/service/yarn"
 	"github.com/danieljhkim/local-data-platform/internal/util"
 	"github.com/spf13/cobra"
@@ -23,11 +26,17 @@ With no arguments:
 
 With a service name, stops only that service.
 
+A running notebook server is stopped first, a running managed metastore-db
+after Hive, and a running s3 server last.
+
 Examples:
   local-data stop           # Stop all services for current profile
   local-data stop hdfs      # Stop HDFS only
   local-data stop yarn      # Stop YARN only
-  local-data stop hive      # Stop Hive only`,
+  local-data stop hive      # Stop Hive only
+  local-data stop metastore-db  # Stop the managed Postgres metastore DB
+  local-data stop s3        # Stop the local S3 server
+  local-data stop notebook  # Stop JupyterLab and its kernels`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			paths := pathsGetter()
@@ -42,32 +51,7 @@ Examples:
 
 			switch target {
 			case "":
-				// Stop services based on profile
-				if profile == "local" {
-					// Local profile: only stop Hive
-					util.Section("stop hive (local profile)")
-					if err := stopHive(paths); err != nil {
-						return err
-					}
-				} else {
-					// HDFS profile: stop all services in reverse order
-					util.Section("stop hive")
-					if err := stopHive(paths); err != nil {
-						return err
-					}
-
-					fmt.Println()
-					util.Section("stop yarn")
-					if err := stopYARN(paths); err != nil {
-						return err
-					}
-
-					fmt.Println()
-					util.Section("stop hdfs")
-					if err := stopHDFS(paths); err != nil {
-						return err
-					}
-				}
+				return stopAll(paths, profile)
 
 			case "hdfs":
 				return stopHDFS(paths)
@@ -78,17 +62,68 @@ Examples:
 			case "hive":
 				return stopHive(paths)
 
+			case "metastore-db":
+				return stopMetastoreDB(paths)
+
+			case "s3":
+				return stopS3(paths)
+
+			case "notebook":
+				return stopNotebook(paths)
+
 			default:
-				return fmt.Errorf("unknown service: %s (valid: hdfs, yarn, hive)", target)
+				return fmt.Errorf("unknown service: %s (valid: hdfs, yarn, hive, metastore-db, s3, notebook)", target)
 			}
-
-			return nil
 		},
 	}
 
 	return cmd
 }
 
+// stopAll stops the services used by a profile
+// local profile: Hive only; other profiles: Hive → YARN → HDFS
+// A running notebook is stopped first, a running metastore-db right after
+// Hive, a running s3 server last
+func stopAll(paths *config.Paths, profile string) error {
+	if err := stopNotebookIfRunning(paths); err != nil {
+		return err
+	}
+
+	if profile == "local" {
+		// Local profile: only stop Hive
+		util.Section("stop hive (local profile)")
+		if err := stopHive(paths); err != nil {
+			return err
+		}
+		if err := stopMetastoreDBIfRunning(paths); err != nil {
+			return err
+		}
+		return stopS3IfRunning(paths)
+	}
+
+	// HDFS profile: stop all services in reverse order
+	util.Section("stop hive")
+	if err := stopHive(paths); err != nil {
+		return err
+	}
+	if err := stopMetastoreDBIfRunning(paths); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	util.Section("stop yarn")
+	if err := stopYARN(paths); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	util.Section("stop hdfs")
+	if err := stopHDFS(paths); err != nil {
+		return err
+	}
+	return stopS3IfRunning(paths)
+}
+
 func stopHDFS(paths *config.Paths) error {
 	svc, err := hdfs.NewHDFSService(paths)
 	if err != nil {
@@ -115,3 +150,79 @@ func stopHive(paths *config.Paths) error {
 
 	return svc.Stop()
 }
+
+func stopMetastoreDB(paths *config.Paths) error {
+	svc, err := metastoredb.NewMetastoreDBService(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create metastore-db service: %w", err)
+	}
+
+	return svc.Stop()
+}
+
+// stopMetastoreDBIfRunning stops metastore-db even if it is no longer
+// enabled in settings, so switching db-type never leaves it behind
+func stopMetastoreDBIfRunning(paths *config.Paths) error {
+	svc, err := metastoredb.NewMetastoreDBService(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create metastore-db service: %w", err)
+	}
+	if !svc.IsRunning() {
+		return nil
+	}
+
+	fmt.Println()
+	util.Section("stop metastore-db")
+	return svc.Stop()
+}
+
+func stopS3(paths *config.Paths) error {
+	svc, err := s3.NewS3Service(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create s3 service: %w", err)
+	}
+
+	return svc.Stop()
+}
+
+// stopS3IfRunning stops the s3 server even if it is no longer enabled
+func stopS3IfRunning(paths *config.Paths) error {
+	svc, err := s3.NewS3Service(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create s3 service: %w", err)
+	}
+	if !svc.IsRunning() {
+		return nil
+	}
+
+	fmt.Println()
+	util.Section("stop s3")
+	return svc.Stop()
+}
+
+func stopNotebook(paths *config.Paths) error {
+	svc, err := notebook.NewNotebookService(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create notebook service: %w", err)
+	}
+
+	return svc.Stop()
+}
+
+// stopNotebookIfRunning stops Jupyter before the services its kernels use
+func stopNotebookIfRunning(paths *config.Paths) error {
+	svc, err := notebook.NewNotebookService(paths)
+	if err != nil {
+		return fmt.Errorf("failed to create notebook service: %w", err)
+	}
+	if !svc.IsRunning() {
+		return nil
+	}
+
+	util.Section("stop notebook")
+	if err := svc.Stop(); err != nil {
+		return err
+	}
+	fmt.Println()
+	return nil
+}
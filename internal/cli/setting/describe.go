@@ -0,0 +1,75 @@
+package setting
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newDescribeCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe [key]",
+		Short: "Describe user settings",
+		Long: `Describe one or all user settings: type, allowed values, default, current
+value, the generated config properties it drives and the services that must
+be restarted after a change.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+
+			defs := config.SettingDefs()
+			if len(args) == 1 {
+				def, err := config.LookupSetting(args[0])
+				if err != nil {
+					return err
+				}
+				defs = []*config.SettingDef{def}
+			}
+
+			settings, err := config.NewSettingsManager(paths).LoadOrDefault()
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			for i, def := range defs {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				describeSetting(out, def, paths, settings)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func describeSetting(out io.Writer, def *config.SettingDef, paths *config.Paths, settings *config.Settings) {
+	fmt.Fprintf(out, "%s\n", def.Key)
+	fmt.Fprintf(out, "  %s\n", def.Description)
+	fmt.Fprintf(out, "  type:     %s\n", def.Type)
+	if len(def.Values) > 0 {
+		fmt.Fprintf(out, "  values:   %s\n", strings.Join(def.Values, ", "))
+	}
+	fmt.Fprintf(out, "  default:  %s\n", displayValue(def, def.Default(paths, settings)))
+	fmt.Fprintf(out, "  current:  %s\n", displayValue(def, def.Get(settings)))
+	for i, t := range def.Targets {
+		label := "affects: "
+		if i > 0 {
+			label = "         "
+		}
+		fmt.Fprintf(out, "  %s %s: %s\n", label, t.Path(), t.Property)
+	}
+	restart := "none"
+	if len(def.Restart) > 0 {
+		restart = strings.Join(def.Restart, ", ")
+	}
+	fmt.Fprintf(out, "  restart:  %s\n", restart)
+	if def.ReadOnly != "" {
+		fmt.Fprintf(out, "  read-only: %s\n", def.ReadOnly)
+	}
+}
@@ -0,0 +1,44 @@
+package setting
+
+import (
+	"fmt"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newGetCmd(pathsGetter PathsGetter) *cobra.Command {
+	var reveal bool
+
+	cmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the value of a user setting",
+		Long: `Print the current value of a user setting.
+
+An empty line means the setting is unset and profiles keep their own value.
+Literal secrets are masked unless --reveal is given.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			def, err := config.LookupSetting(args[0])
+			if err != nil {
+				return err
+			}
+
+			settings, err := config.NewSettingsManager(pathsGetter()).LoadOrDefault()
+			if err != nil {
+				return err
+			}
+
+			value := def.Get(settings)
+			if def.Type == config.SettingSecret && !reveal {
+				value = maskedPassword(value)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&reveal, "reveal", false, "Print literal secrets in cleartext")
+
+	return cmd
+}
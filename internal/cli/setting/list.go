@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/secret"
 	"github.com/spf13/cobra"
 )
 
@@ -11,7 +12,7 @@ func newListCmd(pathsGetter PathsGetter) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List configurable user settings",
-		Long:  `List all configurable user settings and current values.`,
+		Long:  `List all configurable user settings and current values. Secrets are masked.`,
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			sm := config.NewSettingsManager(pathsGetter())
@@ -21,11 +22,12 @@ func newListCmd(pathsGetter PathsGetter) *cobra.Command {
 			}
 
 			out := cmd.OutOrStdout()
-			fmt.Fprintf(out, "  - user: %s\n", settings.User)
-			fmt.Fprintf(out, "  - base-dir: %s\n", settings.BaseDir)
-			fmt.Fprintf(out, "  - db-type: %s\n", settings.DBType)
-			fmt.Fprintf(out, "  - db-url: %s\n", settings.DBURL)
-			fmt.Fprintf(out, "  - db-password: %s\n", maskedPassword(settings.DBPassword))
+			for _, def := range config.SettingDefs() {
+				fmt.Fprintf(out, "  - %s: %s\n", def.Key, displayValue(def, def.Get(settings)))
+			}
+			if settings.DBManaged {
+				fmt.Fprintf(out, "  - db-managed: true (port %d)\n", settings.DBManagedPort)
+			}
 			return nil
 		},
 	}
@@ -33,9 +35,24 @@ func newListCmd(pathsGetter PathsGetter) *cobra.Command {
 	return cmd
 }
 
+// displayValue formats a setting value for humans
+func displayValue(def *config.SettingDef, value string) string {
+	if value == "" {
+		return "(profile default)"
+	}
+	if def.Type == config.SettingSecret {
+		return maskedPassword(value)
+	}
+	return value
+}
+
+// maskedPassword hides literal passwords; env:/file: references are shown as-is
 func maskedPassword(value string) string {
 	if value == "" {
 		return ""
 	}
+	if secret.IsRef(value) {
+		return value
+	}
 	return "********"
 }
@@ -2,96 +2,230 @@ package setting
 
 import (
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/danieljhkim/local-data-platform/internal/config"
 	"github.com/danieljhkim/local-data-platform/internal/metastore"
+	"github.com/danieljhkim/local-data-platform/internal/secret"
 	"github.com/spf13/cobra"
 )
 
 func newSetCmd(pathsGetter PathsGetter) *cobra.Command {
+	var (
+		managed bool
+		port    int
+		dryRun  bool
+	)
+
 	cmd := &cobra.Command{
 		Use:   "set <key> <value>",
 		Short: "Set a configurable user setting",
 		Long: `Set a configurable user setting.
 
-Supported keys: user, db-type, db-url, db-password.
-Note: base-dir is static and cannot be changed via this command.`,
+Supported keys: user, db-type, db-url, db-password, db-password-store,
+spark-driver-memory, yarn-memory-mb, hs2-port, s3, s3-port, iceberg,
+iceberg-catalog, iceberg-catalog-type, iceberg-warehouse, iceberg-jar and
+delta. See 'local-data setting describe' for types, defaults and affected
+config properties.
+
+'s3 on' points s3a:// and s3:// paths in the generated configs at the local
+S3-compatible server (the s3 service, 'local-data start s3').
+
+'iceberg on' adds an Iceberg catalog (spark_catalog, backed by the Hive
+metastore, unless iceberg-catalog/iceberg-catalog-type say otherwise) and the
+iceberg-spark-runtime jar from $BASE_DIR/jars to spark-defaults.conf, so
+spark-sql can CREATE TABLE ... USING iceberg.
+
+'delta on' does the same for Delta Lake with DeltaCatalog as spark_catalog
+and the delta-spark and delta-storage jars; Delta tables are registered in
+the Hive metastore. To use both formats, give Iceberg its own catalog name.
+
+db-password accepts a literal, env:NAME or file:/path. References are kept in
+setting.json and resolved whenever configs are rendered.
+
+db-password-store selects where rendered configs keep the password:
+  plain  javax.jdo.option.ConnectionPassword in hive-site.xml (mode 0600)
+  jceks  Hadoop credential store $BASE_DIR/settings/secrets/metastore.jceks,
+         referenced by hadoop.security.credential.provider.path
+
+With --managed, 'db-type postgres' makes local-data run its own Postgres
+server (the metastore-db service) under $BASE_DIR/state/postgres on --port
+(default 5433) and points db-url at it. 'local-data start' starts it before
+Hive and creates the metastore role and database.
+
+Each change regenerates the built-in profiles under $BASE_DIR/conf/profiles
+from settings and overrides.yaml (user-defined profiles are left alone),
+prints the resulting config changes and reapplies the active profile. Use
+--dry-run to preview the changes without saving.
+
+Note: base-dir cannot be changed via this command. Use the global --base-dir
+flag or $LOCAL_DATA_BASE_DIR to select a base dir, and 'local-data
+migrate-base-dir <new>' to move existing state.`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key := args[0]
 			value := args[1]
 			paths := pathsGetter()
 
-			sm := config.NewSettingsManager(paths)
-			settings, err := sm.LoadOrDefault()
-			if err != nil {
-				return err
+			if (managed || cmd.Flags().Changed("port")) && key != "db-type" {
+				return fmt.Errorf("--managed and --port only apply to db-type")
 			}
-			oldValue := settingValue(settings, key)
-
-			switch key {
-			case "user":
-				settings.User = value
-			case "base-dir":
-				return fmt.Errorf("base-dir is static and cannot be changed via 'local-data setting set'")
-			case "db-type":
-				dbType, err := metastore.NormalizeDBType(value)
-				if err != nil {
-					return err
-				}
-				settings.DBType = string(dbType)
-				if metastore.InferDBTypeFromURL(settings.DBURL) != dbType {
-					fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: db-url %q does not match db-type %q; resetting db-url to default.\n", settings.DBURL, settings.DBType)
-					settings.DBURL = metastore.DefaultDBURLForBase(dbType, paths.BaseDir)
-				}
-			case "db-url":
-				settings.DBURL = value
-			case "db-password":
-				settings.DBPassword = value
-			default:
-				return fmt.Errorf("unknown setting key %q (supported: user, db-type, db-url, db-password)", key)
+			if cmd.Flags().Changed("port") && !managed {
+				return fmt.Errorf("--port requires --managed")
+			}
+			if port < 1 || port > 65535 {
+				return fmt.Errorf("invalid --port %d", port)
 			}
 
-			dbType, err := metastore.NormalizeDBType(settings.DBType)
+			def, err := config.LookupSetting(key)
 			if err != nil {
 				return err
 			}
-			if err := metastore.ValidateURL(dbType, settings.DBURL); err != nil {
-				fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: %v\n", err)
-				return fmt.Errorf("db-type and db-url must match")
-			}
 
-			if err := sm.Save(settings); err != nil {
+			sm := config.NewSettingsManager(paths)
+			settings, err := sm.LoadOrDefault()
+			if err != nil {
 				return err
 			}
+			oldURL := settings.DBURL
 
-			applier := config.NewSettingsApplier(paths)
-			if err := applier.Apply(key, oldValue, value); err != nil {
+			if err := def.Set(settings, value); err != nil {
 				return err
 			}
+			if managed {
+				if settings.DBType != string(metastore.Postgres) {
+					return fmt.Errorf("--managed is only supported for db-type %s", metastore.Postgres)
+				}
+				settings.DBManaged = true
+				settings.DBManagedPort = port
+				settings.DBURL = metastore.ManagedDBURL(port)
+			} else {
+				reconcileDBSettings(cmd, paths, settings, key, oldURL)
+			}
 
-			fmt.Fprintf(cmd.OutOrStdout(), "Updated %s in %s\n", key, sm.Path())
-			fmt.Fprintln(cmd.ErrOrStderr(), "WARNING: Run 'local-data init --force' to ensure regenerated profiles fully reflect updated settings.")
+			if err := saveSetting(cmd, paths, settings, def, dryRun); err != nil {
+				return err
+			}
+			if dryRun {
+				return nil
+			}
+			if key == "db-password-store" && !secret.IsRef(settings.DBPassword) {
+				fmt.Fprintln(cmd.ErrOrStderr(), "WARNING: db-password is still a literal in setting.json; use env:NAME or file:/path to keep it out of settings.")
+			}
+			if settings.DBManaged && key == "db-type" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Managed metastore-db enabled on port %d (start with: local-data start metastore-db)\n", settings.DBManagedPort)
+			}
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the generated config changes without saving")
+	cmd.Flags().BoolVar(&managed, "managed", false, "Run a local-data managed Postgres server for the metastore (db-type postgres only)")
+	cmd.Flags().IntVar(&port, "port", metastore.DefaultManagedPort, "Port of the managed Postgres server (with --managed)")
+
 	return cmd
 }
 
-func settingValue(settings *config.Settings, key string) string {
+// reconcileDBSettings keeps db-type, db-url and the managed metastore-db
+// consistent after key changed.
+func reconcileDBSettings(cmd *cobra.Command, paths *config.Paths, settings *config.Settings, key, oldURL string) {
 	switch key {
-	case "user":
-		return settings.User
-	case "base-dir":
-		return settings.BaseDir
 	case "db-type":
-		return settings.DBType
+		settings.DBManaged = false
+		dbType := metastore.DBType(settings.DBType)
+		if metastore.InferDBTypeFromURL(settings.DBURL) != dbType {
+			fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: db-url %q does not match db-type %q; resetting db-url to default.\n", settings.DBURL, settings.DBType)
+			settings.DBURL = metastore.DefaultDBURLForBase(dbType, paths.BaseDir)
+		}
 	case "db-url":
-		return settings.DBURL
-	case "db-password":
-		return settings.DBPassword
-	default:
-		return ""
+		if settings.DBManaged && settings.DBURL != oldURL {
+			fmt.Fprintln(cmd.ErrOrStderr(), "WARNING: db-url no longer points at the managed metastore-db; disabling it.")
+			settings.DBManaged = false
+		}
+	}
+}
+
+// saveSetting checks db-type/db-url agreement and table formats, then
+// regenerates profiles from the updated settings (or, with dryRun, only
+// previews the changes) and prints the resulting config diff.
+func saveSetting(cmd *cobra.Command, paths *config.Paths, settings *config.Settings, def *config.SettingDef, dryRun bool) error {
+	dbType, err := metastore.NormalizeDBType(settings.DBType)
+	if err != nil {
+		return err
+	}
+	if err := metastore.ValidateURL(dbType, settings.DBURL); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: %v\n", err)
+		return fmt.Errorf("db-type and db-url must match")
+	}
+	if err := settings.ValidateTableFormats(); err != nil {
+		return err
+	}
+
+	pm := config.NewProfileManager(paths)
+	changes, err := pm.Regenerate(settings, dryRun)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if dryRun {
+		fmt.Fprintf(out, "Dry run: %s would be updated\n", def.Key)
+	} else {
+		fmt.Fprintf(out, "Updated %s in %s\n", def.Key, paths.SettingsFile())
+		if def.Key == "s3" && settings.S3Enabled {
+			fmt.Fprintf(out, "Local S3 server enabled on port %d (start with: local-data start s3)\n", settings.S3ServerPort())
+		}
+		if strings.HasPrefix(def.Key, "iceberg") || def.Key == "delta" {
+			printSparkJars(out, pm, settings)
+		}
+	}
+	if !pm.IsInitialized() {
+		return nil
+	}
+	printChanges(out, changes)
+	if dryRun || len(changes) == 0 {
+		return nil
+	}
+	for _, svc := range def.Restart {
+		fmt.Fprintf(out, "Restart %s to apply: local-data stop %s && local-data start %s\n", svc, svc, svc)
+	}
+	return nil
+}
+
+// printSparkJars reports the table format jars added to spark.jars;
+// regenerating already warned about jars that were not found.
+func printSparkJars(out io.Writer, pm *config.ProfileManager, settings *config.Settings) {
+	for _, jar := range pm.SparkRuntime(settings).Jars {
+		fmt.Fprintf(out, "Spark runtime jar: %s\n", jar)
+	}
+}
+
+// printChanges prints regenerated config changes grouped by file
+func printChanges(out io.Writer, changes []config.ConfigChange) {
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "Generated config unchanged.")
+		return
+	}
+
+	fmt.Fprintln(out, "Generated config changes:")
+	file := ""
+	for _, c := range changes {
+		if c.File != file {
+			file = c.File
+			fmt.Fprintf(out, "  %s\n", file)
+		}
+		oldValue, newValue := c.Old, c.New
+		if c.Secret() {
+			oldValue, newValue = maskedPassword(oldValue), maskedPassword(newValue)
+		}
+		switch {
+		case c.Old == "":
+			fmt.Fprintf(out, "    + %s = %s\n", c.Property, newValue)
+		case c.New == "":
+			fmt.Fprintf(out, "    - %s = %s\n", c.Property, oldValue)
+		default:
+			fmt.Fprintf(out, "    ~ %s: %s -> %s\n", c.Property, oldValue, newValue)
+		}
 	}
 }
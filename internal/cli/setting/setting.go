@@ -19,7 +19,10 @@ Settings are persisted at $BASE_DIR/settings/setting.json.`,
 	}
 
 	cmd.AddCommand(newListCmd(pathsGetter))
+	cmd.AddCommand(newGetCmd(pathsGetter))
 	cmd.AddCommand(newSetCmd(pathsGetter))
+	cmd.AddCommand(newUnsetCmd(pathsGetter))
+	cmd.AddCommand(newDescribeCmd(pathsGetter))
 	cmd.AddCommand(newShowCmd(pathsGetter))
 
 	return cmd
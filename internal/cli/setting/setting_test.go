@@ -75,8 +75,8 @@ func TestSettingSet_UpdatesValueInSettingsFile(t *testing.T) {
 	if settings.DBURL != "jdbc:postgresql://localhost:5432/metastore" {
 		t.Fatalf("DBURL = %q", settings.DBURL)
 	}
-	if !strings.Contains(errBuf.String(), "Run 'local-data init --force'") {
-		t.Fatalf("expected profile init warning, got: %s", errBuf.String())
+	if strings.Contains(errBuf.String(), "init --force") {
+		t.Fatalf("settings changes should not ask for init --force, got: %s", errBuf.String())
 	}
 }
 
@@ -102,6 +102,62 @@ func TestSettingSet_DBURLMismatchIsRejected(t *testing.T) {
 	}
 }
 
+func TestSettingSet_ManagedPostgres(t *testing.T) {
+	baseDir := t.TempDir()
+	paths := config.NewPaths("", baseDir)
+	cmd := NewSettingCmd(func() *config.Paths { return paths })
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"set", "db-type", "postgres", "--managed", "--port", "5544"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("setting set --managed returned error: %v", err)
+	}
+
+	settings, err := config.NewSettingsManager(paths).Load()
+	if err != nil {
+		t.Fatalf("failed to load settings: %v", err)
+	}
+	if !settings.DBManaged || settings.DBManagedPort != 5544 {
+		t.Fatalf("managed = %v, port = %d", settings.DBManaged, settings.DBManagedPort)
+	}
+	if settings.DBURL != "jdbc:postgresql://localhost:5544/metastore" {
+		t.Fatalf("DBURL = %q", settings.DBURL)
+	}
+	if !strings.Contains(out.String(), "local-data start metastore-db") {
+		t.Fatalf("expected start hint, got: %s", out.String())
+	}
+
+	// Switching db-type without --managed turns the managed server off
+	cmd = NewSettingCmd(func() *config.Paths { return paths })
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"set", "db-type", "derby"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("setting set returned error: %v", err)
+	}
+	settings, err = config.NewSettingsManager(paths).Load()
+	if err != nil {
+		t.Fatalf("failed to load settings: %v", err)
+	}
+	if settings.DBManaged {
+		t.Fatalf("DBManaged should be cleared")
+	}
+}
+
+func TestSettingSet_ManagedRequiresPostgres(t *testing.T) {
+	_, err := executeCommand(t, "set", "db-type", "mysql", "--managed")
+	if err == nil || !strings.Contains(err.Error(), "only supported for db-type postgres") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = executeCommand(t, "set", "user", "bob", "--managed")
+	if err == nil || !strings.Contains(err.Error(), "only apply to db-type") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestSettingSet_RejectsUnknownKey(t *testing.T) {
 	_, err := executeCommand(t, "set", "unknown", "value")
 	if err == nil {
@@ -198,3 +254,109 @@ func TestSettingShow_Spark_PrintsSparkDefaultsAndHiveSite(t *testing.T) {
 		t.Fatalf("expected spark hive-site content in output:\n%s", out)
 	}
 }
+
+func TestSettingGetUnsetDescribe_HS2Port(t *testing.T) {
+	baseDir := t.TempDir()
+	paths := config.NewPaths("", baseDir)
+	pm := config.NewProfileManager(paths)
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := pm.Set("local"); err != nil {
+		t.Fatalf("set local: %v", err)
+	}
+	run := func(args ...string) (string, error) {
+		cmd := NewSettingCmd(func() *config.Paths { return paths })
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	if _, err := run("set", "hs2-port", "70000"); err == nil || !strings.Contains(err.Error(), "invalid hs2-port") {
+		t.Fatalf("expected invalid port error, got: %v", err)
+	}
+
+	out, err := run("set", "hs2-port", "10001", "--dry-run")
+	if err != nil {
+		t.Fatalf("setting set --dry-run returned error: %v", err)
+	}
+	if !strings.Contains(out, "~ hive.server2.thrift.port: 10000 -> 10001") {
+		t.Fatalf("expected config diff, got: %s", out)
+	}
+	if out, _ := run("get", "hs2-port"); out != "\n" {
+		t.Fatalf("dry run saved hs2-port: %q", out)
+	}
+
+	out, err = run("set", "hs2-port", "10001")
+	if err != nil {
+		t.Fatalf("setting set returned error: %v", err)
+	}
+	if !strings.Contains(out, "local/hive/hive-site.xml") || !strings.Contains(out, "Restart hive to apply") {
+		t.Fatalf("expected diff and restart hint, got: %s", out)
+	}
+
+	out, err = run("get", "hs2-port")
+	if err != nil || out != "10001\n" {
+		t.Fatalf("get hs2-port = %q, %v", out, err)
+	}
+
+	out, err = run("describe", "hs2-port")
+	if err != nil {
+		t.Fatalf("describe returned error: %v", err)
+	}
+	for _, want := range []string{"type:     port", "current:  10001", "hive/hive-site.xml: hive.server2.thrift.port", "restart:  hive"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("describe output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = run("unset", "hs2-port")
+	if err != nil {
+		t.Fatalf("unset returned error: %v", err)
+	}
+	if !strings.Contains(out, "~ hive.server2.thrift.port: 10001 -> 10000") {
+		t.Fatalf("expected profile value restored, got: %s", out)
+	}
+	out, err = run("get", "hs2-port")
+	if err != nil || out != "\n" {
+		t.Fatalf("get hs2-port after unset = %q, %v", out, err)
+	}
+}
+
+func TestSettingUnset_DBTypeResetsURL(t *testing.T) {
+	baseDir := t.TempDir()
+	paths := config.NewPaths("", baseDir)
+	sm := config.NewSettingsManager(paths)
+	if err := sm.Save(&config.Settings{DBType: "postgres", DBURL: "jdbc:postgresql://localhost:5432/metastore"}); err != nil {
+		t.Fatalf("save settings: %v", err)
+	}
+
+	cmd := NewSettingCmd(func() *config.Paths { return paths })
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"unset", "db-type"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unset db-type returned error: %v", err)
+	}
+
+	settings, err := sm.Load()
+	if err != nil {
+		t.Fatalf("failed to load settings: %v", err)
+	}
+	if settings.DBType != "derby" || !strings.HasPrefix(settings.DBURL, "jdbc:derby:") {
+		t.Fatalf("DBType = %q, DBURL = %q", settings.DBType, settings.DBURL)
+	}
+}
+
+func TestSettingGet_MasksLiteralPassword(t *testing.T) {
+	out, err := executeCommand(t, "get", "db-password")
+	if err != nil {
+		t.Fatalf("get returned error: %v", err)
+	}
+	if out != "********\n" {
+		t.Fatalf("get db-password = %q", out)
+	}
+}
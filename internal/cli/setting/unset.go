@@ -0,0 +1,47 @@
+package setting
+
+import (
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newUnsetCmd(pathsGetter PathsGetter) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Reset a user setting to its default",
+		Long: `Reset a user setting to its default.
+
+Settings without a fixed default (e.g. spark-driver-memory) go back to the
+value defined by each profile. Profiles are regenerated as with 'setting set'. Unsetting db-type also resets db-url and
+disables the managed metastore-db.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := args[0]
+			paths := pathsGetter()
+
+			def, err := config.LookupSetting(key)
+			if err != nil {
+				return err
+			}
+
+			settings, err := config.NewSettingsManager(paths).LoadOrDefault()
+			if err != nil {
+				return err
+			}
+			oldURL := settings.DBURL
+
+			if err := def.Unset(settings, paths); err != nil {
+				return err
+			}
+			reconcileDBSettings(cmd, paths, settings, key, oldURL)
+
+			return saveSetting(cmd, paths, settings, def, dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the generated config changes without saving")
+
+	return cmd
+}
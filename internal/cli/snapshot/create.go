@@ -0,0 +1,75 @@
+package snapshot
+
+import (
+	"fmt"
+	"time"
+
+	envpkg "github.com/danieljhkim/local-data-platform/internal/env"
+	snap "github.com/danieljhkim/local-data-platform/internal/snapshot"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newCreateCmd(pathsGetter PathsGetter) *cobra.Command {
+	var (
+		format    string
+		noRestart bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create [name]",
+		Short: "Capture the current local data state",
+		Long: `Capture the current local data state into a snapshot.
+
+The name defaults to a timestamp (YYYYMMDD-HHMMSS).
+
+Formats:
+  tar.zst  single compressed archive (default, works everywhere)
+  clone    copy-on-write reflink copies (fast, needs APFS/btrfs/XFS)
+
+Examples:
+  local-data snapshot create
+  local-data snapshot create before-backfill
+  local-data snapshot create before-backfill --format clone`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+
+			f, err := snap.ParseFormat(format)
+			if err != nil {
+				return err
+			}
+
+			name := snap.DefaultName(time.Now())
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			// Component versions are informational; missing installs are fine.
+			var versions map[string]string
+			if detection, err := envpkg.DetectEnvironment(); err == nil {
+				versions = envpkg.ComponentVersions(detection)
+			}
+
+			var meta *snap.Metadata
+			err = withServicesStopped(paths, !noRestart, func() error {
+				util.Log("Creating snapshot '%s' (%s)...", name, f)
+				var err error
+				meta, err = snap.NewManager(paths).Create(name, f, versions)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			util.Success("Snapshot '%s' created (%s)", meta.Name, formatSize(meta.SizeBytes))
+			fmt.Fprintf(cmd.OutOrStdout(), "Restore with: local-data snapshot restore %s\n", meta.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", string(snap.FormatArchive), "Snapshot format (tar.zst, clone)")
+	cmd.Flags().BoolVar(&noRestart, "no-restart", false, "Do not restart services that were stopped for the snapshot")
+
+	return cmd
+}
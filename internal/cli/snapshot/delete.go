@@ -0,0 +1,25 @@
+package snapshot
+
+import (
+	"fmt"
+
+	snap "github.com/danieljhkim/local-data-platform/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+func newDeleteCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a snapshot",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := snap.NewManager(pathsGetter()).Delete(args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted snapshot '%s'\n", args[0])
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,65 @@
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	snap "github.com/danieljhkim/local-data-platform/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+func newListCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List snapshots",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			snapshots, err := snap.NewManager(pathsGetter()).List()
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(snapshots) == 0 {
+				fmt.Fprintln(out, "No snapshots found.")
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tCREATED\tPROFILE\tDB-TYPE\tFORMAT\tSIZE\tVERSIONS")
+			for _, s := range snapshots {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					s.Name,
+					s.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+					s.Profile,
+					s.DBType,
+					s.Format,
+					formatSize(s.SizeBytes),
+					formatVersions(s.Versions),
+				)
+			}
+			return tw.Flush()
+		},
+	}
+
+	return cmd
+}
+
+func formatVersions(versions map[string]string) string {
+	if len(versions) == 0 {
+		return "-"
+	}
+	keys := make([]string, 0, len(versions))
+	for k := range versions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+versions[k])
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,66 @@
+package snapshot
+
+import (
+	"fmt"
+
+	snap "github.com/danieljhkim/local-data-platform/internal/snapshot"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newRestoreCmd(pathsGetter PathsGetter) *cobra.Command {
+	var (
+		yes       bool
+		noRestart bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "restore <name>",
+		Short: "Restore local data state from a snapshot",
+		Long: `Restore local data state from a snapshot.
+
+The current metastore, warehouse, HDFS data, settings and profiles are
+replaced by the snapshot contents. Data created after the snapshot is
+discarded. The swap is all-or-nothing: if any step fails, the previous
+state is put back.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			manager := snap.NewManager(paths)
+
+			meta, err := manager.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			if !yes {
+				prompt := fmt.Sprintf("Restore snapshot '%s' (profile %s, db-type %s)? Current state will be replaced. [y/N]: ",
+					meta.Name, meta.Profile, meta.DBType)
+				ok, err := confirm(cmd.InOrStdin(), cmd.OutOrStdout(), prompt)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return fmt.Errorf("restore cancelled")
+				}
+			}
+
+			err = withServicesStopped(paths, !noRestart, func() error {
+				util.Log("Restoring snapshot '%s'...", meta.Name)
+				_, err := manager.Restore(meta.Name)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			util.Success("Snapshot '%s' restored", meta.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation")
+	cmd.Flags().BoolVar(&noRestart, "no-restart", false, "Do not restart services that were stopped for the restore")
+
+	return cmd
+}
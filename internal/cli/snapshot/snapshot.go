@@ -0,0 +1,110 @@
+package snapshot
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/cli/service"
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	svc "github.com/danieljhkim/local-data-platform/internal/service"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// PathsGetter is a function that returns the Paths instance.
+type PathsGetter func() *config.Paths
+
+// NewSnapshotCmd creates the snapshot command with all subcommands.
+func NewSnapshotCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Snapshot and restore local data state",
+		Long: `Capture and restore the entire local data state.
+
+A snapshot contains the metastore DB, warehouse, HDFS namenode/datanode
+directories and all other $BASE_DIR/state data (except logs and pids), plus
+settings, profiles and overrides. Snapshots are stored under $BASE_DIR/snapshots.
+
+Running services are stopped before create/restore and restarted afterwards.`,
+	}
+
+	cmd.AddCommand(newCreateCmd(pathsGetter))
+	cmd.AddCommand(newListCmd(pathsGetter))
+	cmd.AddCommand(newRestoreCmd(pathsGetter))
+	cmd.AddCommand(newDeleteCmd(pathsGetter))
+
+	return cmd
+}
+
+// withServicesStopped stops running services, runs fn, then restarts them if
+// they were running before (and restart is true).
+func withServicesStopped(paths *config.Paths, restart bool, fn func() error) error {
+	running, err := svc.RunningDaemons(paths.StateDir())
+	if err != nil {
+		return fmt.Errorf("failed to check running services: %w", err)
+	}
+
+	if len(running) > 0 {
+		util.Log("Stopping running services before touching state...")
+		if err := service.StopAll(paths); err != nil {
+			return fmt.Errorf("failed to stop services: %w", err)
+		}
+		stillRunning, err := svc.RunningDaemons(paths.StateDir())
+		if err != nil {
+			return fmt.Errorf("failed to check running services: %w", err)
+		}
+		if len(stillRunning) > 0 {
+			names := make([]string, 0, len(stillRunning))
+			for _, s := range stillRunning {
+				names = append(names, s.Name)
+			}
+			return fmt.Errorf("services are still running: %s (stop them with: local-data stop <service>)", strings.Join(names, ", "))
+		}
+	}
+
+	opErr := fn()
+
+	if len(running) > 0 {
+		if !restart {
+			util.Log("Services were stopped; restart with: local-data start")
+		} else if err := service.StartAll(paths); err != nil {
+			if opErr != nil {
+				return opErr
+			}
+			return fmt.Errorf("failed to restart services: %w", err)
+		}
+	}
+
+	return opErr
+}
+
+func confirm(in io.Reader, out io.Writer, prompt string) (bool, error) {
+	fmt.Fprint(out, prompt)
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// formatSize renders a byte count for humans (e.g. 12.3 MiB).
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
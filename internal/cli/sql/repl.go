@@ -0,0 +1,146 @@
+package sql
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+	"github.com/danieljhkim/local-data-platform/internal/sqlscript"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"golang.org/x/term"
+)
+
+const (
+	prompt             = "local-data> "
+	continuationPrompt = "         -> "
+
+	maxHistory = 1000
+)
+
+// runREPL reads statements from the terminal until 'quit' or Ctrl-D.
+func runREPL(ctx context.Context, q sqlscript.Querier, format, historyPath string) error {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, prompt)
+	if width, height, err := term.GetSize(fd); err == nil {
+		t.SetSize(width, height)
+	}
+	t.History = loadHistory(historyPath)
+
+	var pending strings.Builder
+	for {
+		line, err := t.ReadLine()
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(t)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if pending.Len() == 0 && isQuit(line) {
+			return nil
+		}
+		pending.WriteString(line)
+		pending.WriteString("\n")
+
+		statements, remainder := hs2.Split(pending.String())
+		for _, stmt := range statements {
+			if err := runStatement(ctx, q, stmt, t, format); err != nil {
+				fmt.Fprintf(t, "Error: %v\n", err)
+			}
+		}
+
+		pending.Reset()
+		if remainder != "" {
+			pending.WriteString(remainder)
+			pending.WriteString("\n")
+			t.SetPrompt(continuationPrompt)
+		} else {
+			t.SetPrompt(prompt)
+		}
+	}
+}
+
+func isQuit(line string) bool {
+	switch strings.ToLower(strings.TrimSuffix(strings.TrimSpace(line), ";")) {
+	case "quit", "exit", "!quit", "!q", `\q`:
+		return true
+	}
+	return false
+}
+
+// fileHistory is the shell history, persisted one line per entry.
+type fileHistory struct {
+	path    string
+	entries []string // oldest first
+}
+
+func loadHistory(path string) *fileHistory {
+	h := &fileHistory{path: path}
+	f, err := os.Open(path)
+	if err != nil {
+		return h
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[len(h.entries)-maxHistory:]
+	}
+	return h
+}
+
+// Add records a line and appends it to the history file. Write errors are
+// ignored; history is best effort.
+func (h *fileHistory) Add(entry string) {
+	if strings.TrimSpace(entry) == "" {
+		return
+	}
+	if n := len(h.entries); n > 0 && h.entries[n-1] == entry {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[1:]
+	}
+
+	if h.path == "" || util.MkdirAll(filepath.Dir(h.path)) != nil {
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, entry)
+}
+
+// Len returns the number of entries.
+func (h *fileHistory) Len() int {
+	return len(h.entries)
+}
+
+// At returns an entry; 0 is the most recent.
+func (h *fileHistory) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
@@ -0,0 +1,142 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+	"github.com/danieljhkim/local-data-platform/internal/sqlscript"
+	"github.com/spf13/cobra"
+)
+
+func newRunCmd(pathsGetter PathsGetter) *cobra.Command {
+	var (
+		hivevars        []string
+		defines         []string
+		continueOnError bool
+		engine          string
+		report          string
+		reportFile      string
+		conn            connFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run <file>...",
+		Short: "Run HiveQL scripts and report per-statement results",
+		Long: `Run HiveQL scripts statement by statement and report each statement's
+status, duration and row count.
+
+Statements are split on ';' outside quotes and comments. ${name},
+${hivevar:name} and ${define:name} are replaced with --hivevar/--define
+values (and values set in the script with SET hivevar:name=value);
+${env:NAME} is replaced from the environment.
+
+By default the run stops at the first failing statement and reports the rest
+as skipped; --continue-on-error runs every statement. The command fails if any
+statement failed.
+
+Examples:
+  local-data sql run etl/daily.hql --hivevar ds=2024-01-01
+  local-data sql run checks/*.sql --continue-on-error --report junit --report-file report.xml
+  local-data sql run job.hql --engine spark`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+
+			vars, err := sqlscript.ParseVariables(append(append([]string{}, defines...), hivevars...))
+			if err != nil {
+				return err
+			}
+			if report != sqlscript.ReportText && report != sqlscript.ReportJUnit {
+				return fmt.Errorf("unsupported report format %q (supported: %s, %s)", report, sqlscript.ReportText, sqlscript.ReportJUnit)
+			}
+
+			statements, err := loadStatements(args, cmd.InOrStdin(), vars)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			var eng sqlscript.Engine
+			switch engine {
+			case sqlscript.EngineHive:
+				client, err := hs2.Connect(ctx, conn.options(paths))
+				if err != nil {
+					return err
+				}
+				defer client.Close()
+				eng = &sqlscript.HS2Engine{Client: client}
+			case sqlscript.EngineSpark:
+				eng = &sqlscript.SparkEngine{Paths: paths}
+			default:
+				return fmt.Errorf("unsupported engine %q (supported: %s, %s)", engine, sqlscript.EngineHive, sqlscript.EngineSpark)
+			}
+
+			return runScripts(ctx, eng, statements, continueOnError, report, reportFile, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&hivevars, "hivevar", nil, "Variable substitution name=value (repeatable)")
+	cmd.Flags().StringArrayVar(&defines, "define", nil, "Same as --hivevar (repeatable)")
+	cmd.Flags().BoolVar(&continueOnError, "continue-on-error", false, "Run all statements even after a failure")
+	cmd.Flags().StringVar(&engine, "engine", sqlscript.EngineHive, "Execution engine: hive (HiveServer2) or spark (spark-submit)")
+	cmd.Flags().StringVar(&report, "report", sqlscript.ReportText, "Report format: text or junit")
+	cmd.Flags().StringVar(&reportFile, "report-file", "", "Write the report to a file instead of stdout")
+	conn.register(cmd)
+
+	return cmd
+}
+
+// loadStatements reads and parses script files in order; "-" reads stdin,
+// which can only be read once.
+func loadStatements(files []string, stdin io.Reader, vars sqlscript.Variables) ([]sqlscript.Statement, error) {
+	var statements []sqlscript.Statement
+	readStdin := false
+	for _, file := range files {
+		if file == "-" {
+			if readStdin {
+				return nil, fmt.Errorf(`"-" (stdin) can only be given once`)
+			}
+			readStdin = true
+		}
+		content, err := readScript(file, stdin)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, sqlscript.Parse(file, content, vars).Statements...)
+	}
+	return statements, nil
+}
+
+// runScripts runs statements and writes the report; it returns an error if
+// any statement failed.
+func runScripts(ctx context.Context, eng sqlscript.Engine, statements []sqlscript.Statement, continueOnError bool, report, reportFile string, stdout io.Writer) error {
+	results, err := eng.Run(ctx, statements, continueOnError)
+	if err != nil {
+		return err
+	}
+
+	out := stdout
+	if reportFile != "" {
+		f, err := os.Create(reportFile)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := sqlscript.WriteReport(out, results, report); err != nil {
+		return err
+	}
+
+	if _, failed, _ := sqlscript.Counts(results); failed > 0 {
+		return fmt.Errorf("%d of %d statement(s) failed", failed, len(results))
+	}
+	return nil
+}
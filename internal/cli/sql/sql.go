@@ -0,0 +1,163 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+	"github.com/danieljhkim/local-data-platform/internal/sqlscript"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// PathsGetter is a function that returns the Paths instance.
+type PathsGetter func() *config.Paths
+
+// NewSQLCmd creates the sql command.
+func NewSQLCmd(pathsGetter PathsGetter) *cobra.Command {
+	var (
+		execute string
+		file    string
+		format  string
+		conn    connFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sql",
+		Short: "Run HiveQL against HiveServer2 without starting beeline",
+		Long: `Run HiveQL against HiveServer2 with a built-in Thrift client.
+
+Host, port, transport and authentication are read from the active
+hive-site.xml (binary transport; NOSASL or SASL PLAIN).
+
+Examples:
+  local-data sql -e "SHOW DATABASES"
+  local-data sql -f queries.sql --format csv > out.csv
+  local-data sql -d sales            # interactive shell with history
+
+Statements are separated by ';'. Without -e or -f, an interactive shell is
+started when stdin is a terminal; otherwise the script is read from stdin.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			if execute != "" && file != "" {
+				return fmt.Errorf("-e and -f cannot be used together")
+			}
+			if err := hs2.ValidateFormat(format); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			opts := conn.options(paths)
+			client, err := hs2.Connect(ctx, opts)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			out := cmd.OutOrStdout()
+			switch {
+			case execute != "":
+				return runScript(ctx, client, execute, out, format)
+			case file != "":
+				script, err := readScript(file, cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				return runScript(ctx, client, script, out, format)
+			case !term.IsTerminal(int(os.Stdin.Fd())):
+				script, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("failed to read stdin: %w", err)
+				}
+				return runScript(ctx, client, string(script), out, format)
+			default:
+				fmt.Fprintf(out, "Connected to HiveServer2 at %s:%d. End statements with ';', type 'quit' to exit.\n", opts.Host, opts.Port)
+				return runREPL(ctx, client, format, historyFile(paths))
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&execute, "execute", "e", "", "Statements to run")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Script file to run ('-' for stdin)")
+	cmd.Flags().StringVar(&format, "format", hs2.FormatTable, "Output format: "+strings.Join(hs2.Formats, ", "))
+	conn.register(cmd)
+
+	cmd.AddCommand(newRunCmd(pathsGetter))
+
+	return cmd
+}
+
+// connFlags are the HiveServer2 connection flags shared by sql subcommands.
+type connFlags struct {
+	database string
+	username string
+	password string
+}
+
+func (c *connFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&c.database, "database", "d", "", "Initial database")
+	cmd.Flags().StringVarP(&c.username, "user", "n", "", "User name (default: current OS user)")
+	cmd.Flags().StringVarP(&c.password, "password", "p", "", "Password for SASL PLAIN (LDAP/CUSTOM) authentication")
+}
+
+// options returns connection options from the active hive-site.xml and flags.
+func (c *connFlags) options(paths *config.Paths) hs2.Options {
+	opts := hs2.OptionsFromConf(paths.CurrentHiveConf())
+	opts.Database = c.database
+	opts.Username = c.username
+	opts.Password = c.password
+	return opts
+}
+
+// historyFile returns the interactive shell history file: $BASE_DIR/state/sql_history
+func historyFile(paths *config.Paths) string {
+	return filepath.Join(paths.StateDir(), "sql_history")
+}
+
+func readScript(file string, stdin io.Reader) (string, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read script: %w", err)
+	}
+	return string(data), nil
+}
+
+// runScript runs every statement of script, stopping at the first failure.
+func runScript(ctx context.Context, q sqlscript.Querier, script string, out io.Writer, format string) error {
+	for _, stmt := range hs2.SplitStatements(script) {
+		if err := runStatement(ctx, q, stmt, out, format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runStatement runs one statement and writes its result set, if any.
+func runStatement(ctx context.Context, q sqlscript.Querier, stmt string, out io.Writer, format string) error {
+	res, err := q.Query(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	if len(res.Columns) == 0 {
+		if format == hs2.FormatTable {
+			fmt.Fprintln(out, "OK")
+		}
+		return nil
+	}
+	return hs2.WriteResult(out, res, format)
+}
@@ -0,0 +1,119 @@
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+	"github.com/danieljhkim/local-data-platform/internal/sqlscript"
+)
+
+type fakeQuerier struct {
+	statements []string
+	fail       string // statement that returns an error
+}
+
+func (f *fakeQuerier) Query(_ context.Context, stmt string) (*hs2.Result, error) {
+	f.statements = append(f.statements, stmt)
+	if stmt == f.fail {
+		return nil, fmt.Errorf("query failed: boom")
+	}
+	if stmt == "SHOW DATABASES" {
+		return &hs2.Result{
+			Columns: []hs2.Column{{Name: "database_name", Type: "string"}},
+			Rows:    [][]any{{"default"}},
+		}, nil
+	}
+	return &hs2.Result{}, nil
+}
+
+func TestRunScript(t *testing.T) {
+	q := &fakeQuerier{}
+	var out bytes.Buffer
+	if err := runScript(context.Background(), q, "USE default;\nSHOW DATABASES;", &out, hs2.FormatCSV); err != nil {
+		t.Fatalf("runScript: %v", err)
+	}
+	if want := []string{"USE default", "SHOW DATABASES"}; !reflect.DeepEqual(q.statements, want) {
+		t.Fatalf("statements = %q, want %q", q.statements, want)
+	}
+	if out.String() != "database_name\ndefault\n" {
+		t.Fatalf("output = %q", out.String())
+	}
+}
+
+func TestRunScript_StopsAtFirstError(t *testing.T) {
+	q := &fakeQuerier{fail: "SELECT broken"}
+	var out bytes.Buffer
+	err := runScript(context.Background(), q, "SELECT broken; SHOW DATABASES", &out, hs2.FormatTable)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(q.statements) != 1 {
+		t.Fatalf("statements after failure were run: %q", q.statements)
+	}
+}
+
+func TestFileHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "sql_history")
+
+	h := loadHistory(path)
+	h.Add("SHOW DATABASES;")
+	h.Add("SHOW DATABASES;")
+	h.Add("  ")
+	h.Add("SELECT 1;")
+
+	reloaded := loadHistory(path)
+	if reloaded.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", reloaded.Len())
+	}
+	if reloaded.At(0) != "SELECT 1;" || reloaded.At(1) != "SHOW DATABASES;" {
+		t.Fatalf("history = %q", reloaded.entries)
+	}
+}
+
+func TestRunScripts_FailsWhenAStatementFails(t *testing.T) {
+	eng := &sqlscript.HS2Engine{Client: &fakeQuerier{fail: "SELECT broken"}}
+	stmts := sqlscript.Parse("checks.sql", "SHOW DATABASES; SELECT broken; SHOW DATABASES;", nil).Statements
+	reportFile := filepath.Join(t.TempDir(), "report.xml")
+
+	var out bytes.Buffer
+	err := runScripts(context.Background(), eng, stmts, true, sqlscript.ReportJUnit, reportFile, &out)
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 statement(s) failed") {
+		t.Fatalf("runScripts error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("report written to stdout: %q", out.String())
+	}
+	data, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	if !strings.Contains(string(data), `<testsuite name="checks.sql" tests="3" failures="1"`) {
+		t.Fatalf("unexpected report:\n%s", data)
+	}
+}
+
+func TestLoadStatements_StdinOnce(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.sql")
+	if err := os.WriteFile(file, []byte("SHOW TABLES;"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stmts, err := loadStatements([]string{file, "-"}, strings.NewReader("SHOW DATABASES;"), nil)
+	if err != nil {
+		t.Fatalf("loadStatements error = %v", err)
+	}
+	if len(stmts) != 2 || stmts[1].File != "-" || stmts[1].SQL != "SHOW DATABASES" {
+		t.Fatalf("statements = %+v", stmts)
+	}
+
+	if _, err := loadStatements([]string{"-", file, "-"}, strings.NewReader("SHOW DATABASES;"), nil); err == nil {
+		t.Fatal("loadStatements with stdin twice: expected error")
+	}
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	envpkg "github.com/danieljhkim/local-data-platform/internal/env"
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+	"github.com/danieljhkim/local-data-platform/internal/junit"
+	"github.com/danieljhkim/local-data-platform/internal/sqlscript"
+	"github.com/danieljhkim/local-data-platform/internal/sqltest"
+	"github.com/spf13/cobra"
+)
+
+func newTestCmd(pathsGetter func() *config.Paths) *cobra.Command {
+	var (
+		junitFile     string
+		filter        string
+		hivevars      []string
+		keepDatabases bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "test [path]...",
+		Short: "Run SQL assertion tests against the local stack",
+		Long: `Run data-driven SQL tests against HiveServer2.
+
+Test files are discovered under the given paths (default: tests):
+  *.test.yaml, *.test.yml   setup DDL, fixture rows, a query and expectations
+                            (rows, row_count, not_null, unique)
+  *.test.sql                setup statements followed by a query that must
+                            return no rows
+
+Each test runs in its own database (ldt_<name>_<random>, available as
+${test_db}) which is dropped afterwards unless --keep-databases is set. The
+HiveServer2 endpoint comes from the environment the wrappers use, including
+the project's .local-data.yaml.
+
+Example test (tests/orders.test.yaml):
+  name: orders are deduplicated
+  setup: |
+    CREATE TABLE orders (id INT, amount DOUBLE);
+  fixtures:
+    orders:
+      - {id: 1, amount: 10.5}
+      - {id: 1, amount: 10.5}
+  query: SELECT DISTINCT id, amount FROM orders
+  expect:
+    rows: [[1, 10.5]]
+    unique: [id]
+
+Examples:
+  local-data test
+  local-data test tests/orders.test.yaml --junit report.xml
+  local-data test -k dedup --hivevar ds=2024-01-01`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+			if len(args) == 0 {
+				args = []string{"tests"}
+			}
+
+			vars, err := sqlscript.ParseVariables(hivevars)
+			if err != nil {
+				return err
+			}
+
+			files, err := sqltest.Discover(args)
+			if err != nil {
+				return err
+			}
+			var cases []*sqltest.Case
+			for _, file := range files {
+				loaded, err := sqltest.Load(file)
+				if err != nil {
+					return err
+				}
+				for _, c := range loaded {
+					if filter == "" || strings.Contains(strings.ToLower(c.Name), strings.ToLower(filter)) {
+						cases = append(cases, c)
+					}
+				}
+			}
+			if len(cases) == 0 {
+				return fmt.Errorf("no tests found in %s", strings.Join(args, ", "))
+			}
+
+			env, err := envpkg.ComputeForProject(paths)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			client, err := hs2.Connect(ctx, hs2.OptionsFromConf(env.HiveConfDir))
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			runner := &sqltest.Runner{Client: client, Vars: vars, KeepDatabases: keepDatabases}
+			return runTests(ctx, runner, cases, junitFile, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVar(&junitFile, "junit", "", "Write a JUnit XML report to this file")
+	cmd.Flags().StringVarP(&filter, "filter", "k", "", "Only run tests whose name contains this text")
+	cmd.Flags().StringArrayVar(&hivevars, "hivevar", nil, "Variable substitution name=value (repeatable)")
+	cmd.Flags().BoolVar(&keepDatabases, "keep-databases", false, "Keep the per-test databases for debugging")
+
+	return cmd
+}
+
+// runTests runs cases in order, prints a line per test and writes the JUnit
+// report; it returns an error if any test failed.
+func runTests(ctx context.Context, runner *sqltest.Runner, cases []*sqltest.Case, junitFile string, out io.Writer) error {
+	results := make([]sqltest.Result, 0, len(cases))
+	failed := 0
+	for _, c := range cases {
+		r := runner.Run(ctx, c)
+		results = append(results, r)
+
+		if r.Passed() {
+			fmt.Fprintf(out, "PASS  %s (%.2fs)\n", c.Name, r.Duration.Seconds())
+			continue
+		}
+		failed++
+		fmt.Fprintf(out, "FAIL  %s (%.2fs)\n", c.Name, r.Duration.Seconds())
+		fmt.Fprintf(out, "      %s\n", c.File)
+		if runner.KeepDatabases {
+			fmt.Fprintf(out, "      database: %s\n", r.Database)
+		}
+		for _, f := range r.Failures {
+			fmt.Fprintf(out, "      %s\n", strings.ReplaceAll(f, "\n", "\n      "))
+		}
+	}
+	fmt.Fprintf(out, "\n%d tests: %d passed, %d failed\n", len(results), len(results)-failed, failed)
+
+	if junitFile != "" {
+		f, err := os.Create(junitFile)
+		if err != nil {
+			return fmt.Errorf("failed to create JUnit report: %w", err)
+		}
+		defer f.Close()
+		if err := junit.Write(f, sqltest.JUnitSuites(results)); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d test(s) failed", failed, len(results))
+	}
+	return nil
+}
@@ -1,7 +1,10 @@
 package wrappers
 
 import (
+	"fmt"
+
 	envpkg "github.com/danieljhkim/local-data-platform/internal/env"
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
 	"github.com/spf13/cobra"
 )
 
@@ -14,7 +17,8 @@ func NewHiveCmd(pathsGetter PathsGetter) *cobra.Command {
 		DisableFlagParsing: true, // Critical: pass all args through
 		RunE: func(cmd *cobra.Command, args []string) error {
 			paths := pathsGetter()
-			beelineBase := []string{"beeline", "-u", "jdbc:hive2://localhost:10000"}
+			opts := hs2.OptionsFromConf(paths.CurrentHiveConf())
+			beelineBase := []string{"beeline", "-u", fmt.Sprintf("jdbc:hive2://%s:%d", opts.Host, opts.Port)}
 			cmdArgs := append(beelineBase, args...)
 
 			// Set TERM=dumb to work around JNA/JLine terminal issues on Apple Silicon
@@ -0,0 +1,37 @@
+package wrappers
+
+import (
+	envpkg "github.com/danieljhkim/local-data-platform/internal/env"
+	"github.com/danieljhkim/local-data-platform/internal/service/hdfs"
+	"github.com/spf13/cobra"
+)
+
+// NewSparkSQLCmd creates the spark-sql wrapper command
+func NewSparkSQLCmd(pathsGetter PathsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                "spark-sql [args...]",
+		Short:              "Run spark-sql with local-data environment",
+		Long:               `Run spark-sql with the computed local-data-platform environment.`,
+		DisableFlagParsing: true, // Critical: pass all args through
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsGetter()
+
+			// Compute environment
+			env, err := envpkg.ComputeForProject(paths)
+			if err != nil {
+				return err
+			}
+
+			// Ensure /spark-history directory exists in HDFS before running spark-sql
+			// This is needed for Spark event logging
+			if env.ActiveProfile == "hdfs" {
+				hdfs.EnsureSparkHistoryDir(env.MergeWithCurrent())
+			}
+
+			cmdArgs := append([]string{"spark-sql"}, args...)
+			return envpkg.Exec(paths, cmdArgs)
+		},
+	}
+
+	return cmd
+}
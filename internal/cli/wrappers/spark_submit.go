@@ -17,15 +17,14 @@ func NewSparkSubmitCmd(pathsGetter PathsGetter) *cobra.Command {
 			paths := pathsGetter()
 
 			// Compute environment
-			env, err := envpkg.Compute(paths)
+			env, err := envpkg.ComputeForProject(paths)
 			if err != nil {
 				return err
 			}
 
 			// Ensure /spark-history directory exists in HDFS before running spark-submit
 			// This is needed for Spark event logging
-			profile, _ := paths.ActiveProfile()
-			if profile == "hdfs" {
+			if env.ActiveProfile == "hdfs" {
 				hdfs.EnsureSparkHistoryDir(env.MergeWithCurrent())
 			}
 
@@ -16,6 +16,24 @@ type InitOptions struct {
 	DBType     string // Override metastore DB type
 	DBUrl      string // Override database connection URL
 	DBPassword string // Override database password
+
+	// CredentialProviders replaces DBPassword with a credential provider path
+	// (hadoop.security.credential.provider.path) when set
+	CredentialProviders string
+
+	// Iceberg, if set, adds an Iceberg catalog and its SQL extensions to
+	// the Spark config. An empty hadoop warehouse defaults to Spark's.
+	Iceberg *schema.IcebergCatalog
+
+	// Delta configures Delta Lake's SQL extensions and session catalog
+	Delta bool
+
+	// SparkJars are appended to spark.jars
+	SparkJars []string
+
+	// Properties are setting-derived properties applied to every profile
+	// after overrides.yaml
+	Properties []PropertyOverride
 }
 
 // ConfigGenerator generates configuration files for profiles
@@ -144,6 +162,32 @@ func (g *ConfigGenerator) applyInitOptions(configSet *schema.ConfigSet, opts *In
 		if opts.DBPassword != "" {
 			result.Hive.ConnectionPassword = opts.DBPassword
 		}
+		if opts.CredentialProviders != "" {
+			result.Hive.ConnectionPassword = ""
+			result.Hive.CredentialProviders = opts.CredentialProviders
+		}
+	}
+
+	if result.Spark != nil {
+		if opts.Iceberg != nil {
+			iceberg := *opts.Iceberg
+			if iceberg.Type == schema.IcebergCatalogHadoop && iceberg.Warehouse == "" {
+				iceberg.Warehouse = result.Spark.WarehouseDir
+			}
+			result.Spark.Iceberg = &iceberg
+			result.Spark.Extensions = appendUnique(result.Spark.Extensions, schema.IcebergExtensions)
+		}
+		if opts.Delta {
+			result.Spark.Extensions = appendUnique(result.Spark.Extensions, schema.DeltaExtensions)
+			result.Spark.SessionCatalog = schema.DeltaCatalog
+		}
+		for _, jar := range opts.SparkJars {
+			result.Spark.Jars = appendUnique(result.Spark.Jars, jar)
+		}
+	}
+
+	if len(opts.Properties) > 0 {
+		result = MergeOverrides(result, propertyOverrides(opts.Properties))
 	}
 
 	return result
@@ -260,7 +304,16 @@ func (g *ConfigGenerator) generateHive(cfg *schema.HiveConfig, ctx *schema.Templ
 	}
 
 	props := cfg.ToProperties(ctx)
-	return WriteHadoopXML(props, filepath.Join(hiveDir, "hive-site.xml"))
+	path := filepath.Join(hiveDir, "hive-site.xml")
+	if err := WriteHadoopXML(props, path); err != nil {
+		return err
+	}
+
+	// hive-site.xml carries the metastore password unless a credential provider is used
+	if cfg.ConnectionPassword != "" {
+		return os.Chmod(path, 0600)
+	}
+	return nil
 }
 
 func (g *ConfigGenerator) generateSpark(cfg *schema.SparkConfig, ctx *schema.TemplateContext, destDir string) error {
@@ -274,3 +327,12 @@ func (g *ConfigGenerator) generateSpark(cfg *schema.SparkConfig, ctx *schema.Tem
 	props := cfg.ToProperties(ctx)
 	return WriteSparkConf(props, filepath.Join(sparkDir, "spark-defaults.conf"))
 }
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
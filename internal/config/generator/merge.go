@@ -30,6 +30,46 @@ type HadoopOverride struct {
 	CapacityScheduler map[string]interface{} `yaml:"capacity-scheduler"`
 }
 
+// PropertyOverride sets a single property in one generated file.
+// File uses the overrides.yaml section names: core-site, hdfs-site, yarn-site,
+// mapred-site, capacity-scheduler, hive or spark.
+type PropertyOverride struct {
+	File  string
+	Name  string
+	Value string
+}
+
+// propertyOverrides groups single-property overrides into a ProfileOverride
+func propertyOverrides(props []PropertyOverride) *ProfileOverride {
+	po := &ProfileOverride{Hadoop: &HadoopOverride{}}
+	set := func(m *map[string]interface{}, name, value string) {
+		if *m == nil {
+			*m = make(map[string]interface{})
+		}
+		(*m)[name] = value
+	}
+
+	for _, p := range props {
+		switch p.File {
+		case "core-site":
+			set(&po.Hadoop.CoreSite, p.Name, p.Value)
+		case "hdfs-site":
+			set(&po.Hadoop.HDFSSite, p.Name, p.Value)
+		case "yarn-site":
+			set(&po.Hadoop.YarnSite, p.Name, p.Value)
+		case "mapred-site":
+			set(&po.Hadoop.MapredSite, p.Name, p.Value)
+		case "capacity-scheduler":
+			set(&po.Hadoop.CapacityScheduler, p.Name, p.Value)
+		case "hive":
+			set(&po.Hive, p.Name, p.Value)
+		case "spark":
+			set(&po.Spark, p.Name, p.Value)
+		}
+	}
+	return po
+}
+
 // LoadOverrides loads user overrides from the override file
 func LoadOverrides(baseDir string) (*OverrideConfig, error) {
 	overridePath := filepath.Join(baseDir, "conf", "overrides.yaml")
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// HadoopInstall contains Hadoop installation paths
+type HadoopInstall struct {
+	Prefix string // Brew prefix (for bin/sbin in PATH)
+	Home   string // HADOOP_HOME (libexec for Homebrew)
+}
+
+// FindHadoopInstall finds Hadoop installation paths
+func FindHadoopInstall() *HadoopInstall {
+	// Check environment variable first
+	if hadoopHome := os.Getenv("HADOOP_HOME"); hadoopHome != "" {
+		return &HadoopInstall{
+			Prefix: hadoopHome,
+			Home:   hadoopHome,
+		}
+	}
+
+	// Try Homebrew
+	if prefix := util.BrewPrefix("hadoop"); prefix != "" {
+		return &HadoopInstall{
+			Prefix: prefix,
+			// Homebrew Hadoop needs /libexec suffix for proper library resolution
+			Home: prefix + "/libexec",
+		}
+	}
+
+	return nil
+}
+
+// HadoopToolsLib returns share/hadoop/tools/lib of the Hadoop install,
+// which holds optional modules such as hadoop-aws and the AWS SDK bundle,
+// or "" without Hadoop.
+func HadoopToolsLib() string {
+	install := FindHadoopInstall()
+	if install == nil {
+		return ""
+	}
+	return filepath.Join(install.Home, "share", "hadoop", "tools", "lib")
+}
+
+// sparkHadoopJar matches the Hadoop client Spark is built with, e.g.
+// hadoop-client-api-3.3.4.jar
+var sparkHadoopJar = regexp.MustCompile(`^hadoop-client-api-([0-9]+\.[0-9]+\.[0-9]+)\.jar$`)
+
+// SparkHadoopVersion returns the Hadoop version bundled with the Spark at
+// sparkHome, or "" for Spark built without Hadoop.
+func SparkHadoopVersion(sparkHome string) string {
+	if sparkHome == "" {
+		return ""
+	}
+	matches, _ := filepath.Glob(filepath.Join(sparkHome, "jars", "hadoop-client-api-*.jar"))
+	sort.Strings(matches)
+	for _, m := range matches {
+		if sub := sparkHadoopJar.FindStringSubmatch(filepath.Base(m)); sub != nil {
+			return sub[1]
+		}
+	}
+	return ""
+}
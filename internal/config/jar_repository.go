@@ -0,0 +1,212 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// Jar targets: the classpaths a repository jar is added to
+const (
+	JarTargetHive   = "hive"
+	JarTargetSpark  = "spark"
+	JarTargetHadoop = "hadoop"
+)
+
+// JarTargets lists all jar targets.
+var JarTargets = []string{JarTargetHive, JarTargetSpark, JarTargetHadoop}
+
+// Jar is a jar registered in the jar repository.
+type Jar struct {
+	Name    string   `json:"name"`    // file name in $BASE_DIR/jars
+	Targets []string `json:"targets"` // hive, spark and/or hadoop
+}
+
+// HasTarget reports whether the jar is added to target's classpath.
+func (j Jar) HasTarget(target string) bool {
+	for _, t := range j.Targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+// JarRepository manages $BASE_DIR/jars: jar files plus jars.json, which
+// registers each jar with the classpaths it is added to. Registered jars
+// reach Hive through HIVE_AUX_JARS_PATH, Spark through spark.jars and
+// spark.driver.extraClassPath and Hadoop through HADOOP_CLASSPATH, so
+// install directories are never modified.
+type JarRepository struct {
+	paths *Paths
+}
+
+// NewJarRepository creates a jar repository.
+func NewJarRepository(paths *Paths) *JarRepository {
+	return &JarRepository{paths: paths}
+}
+
+// manifest returns the path of jars.json.
+func (r *JarRepository) manifest() string {
+	return filepath.Join(r.paths.JarsDir(), "jars.json")
+}
+
+// Path returns the location of a jar in the repository.
+func (r *JarRepository) Path(name string) string {
+	return filepath.Join(r.paths.JarsDir(), name)
+}
+
+// List returns the registered jars sorted by name.
+func (r *JarRepository) List() ([]Jar, error) {
+	data, err := os.ReadFile(r.manifest())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var jars []Jar
+	if err := json.Unmarshal(data, &jars); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", r.manifest(), err)
+	}
+	sort.Slice(jars, func(i, j int) bool { return jars[i].Name < jars[j].Name })
+	return jars, nil
+}
+
+func (r *JarRepository) save(jars []Jar) error {
+	if err := util.MkdirAll(r.paths.JarsDir()); err != nil {
+		return err
+	}
+	sort.Slice(jars, func(i, j int) bool { return jars[i].Name < jars[j].Name })
+	data, err := json.MarshalIndent(jars, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal jars: %w", err)
+	}
+	return os.WriteFile(r.manifest(), append(data, '\n'), 0644)
+}
+
+// NormalizeJarTargets validates targets and returns them sorted without
+// duplicates.
+func NormalizeJarTargets(targets []string) ([]string, error) {
+	seen := map[string]bool{}
+	var out []string
+	for _, t := range targets {
+		t = strings.ToLower(strings.TrimSpace(t))
+		valid := false
+		for _, known := range JarTargets {
+			valid = valid || t == known
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid jar target %q (valid: %s)", t, strings.Join(JarTargets, ", "))
+		}
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("at least one jar target is required (%s)", strings.Join(JarTargets, ", "))
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// Add copies src into the repository (unless it is already there) and
+// registers it for targets, replacing the targets of a jar of the same name.
+func (r *JarRepository) Add(src string, targets []string) (Jar, error) {
+	targets, err := NormalizeJarTargets(targets)
+	if err != nil {
+		return Jar{}, err
+	}
+	if !strings.HasSuffix(src, ".jar") {
+		return Jar{}, fmt.Errorf("%s is not a .jar file", src)
+	}
+	if !util.FileExists(src) {
+		return Jar{}, fmt.Errorf("%s does not exist", src)
+	}
+
+	jars, err := r.List()
+	if err != nil {
+		return Jar{}, err
+	}
+
+	jar := Jar{Name: filepath.Base(src), Targets: targets}
+	dst := r.Path(jar.Name)
+	srcAbs, err := filepath.Abs(src)
+	if err != nil {
+		return Jar{}, err
+	}
+	if srcAbs != dst {
+		if err := util.MkdirAll(r.paths.JarsDir()); err != nil {
+			return Jar{}, err
+		}
+		if err := util.CopyFile(srcAbs, dst); err != nil {
+			return Jar{}, fmt.Errorf("failed to copy %s: %w", src, err)
+		}
+	}
+
+	replaced := false
+	for i := range jars {
+		if jars[i].Name == jar.Name {
+			jars[i] = jar
+			replaced = true
+		}
+	}
+	if !replaced {
+		jars = append(jars, jar)
+	}
+	return jar, r.save(jars)
+}
+
+// Remove unregisters a jar by name (or path) and deletes its file.
+func (r *JarRepository) Remove(name string) (Jar, error) {
+	name = filepath.Base(name)
+	jars, err := r.List()
+	if err != nil {
+		return Jar{}, err
+	}
+	for i, jar := range jars {
+		if jar.Name != name {
+			continue
+		}
+		if err := os.Remove(r.Path(name)); err != nil && !os.IsNotExist(err) {
+			return Jar{}, err
+		}
+		return jar, r.save(append(jars[:i], jars[i+1:]...))
+	}
+	return Jar{}, fmt.Errorf("jar %s is not registered (see: local-data jars list)", name)
+}
+
+// Classpath returns the paths of registered jars added to target.
+func (r *JarRepository) Classpath(target string) ([]string, error) {
+	jars, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, jar := range jars {
+		if jar.HasTarget(target) && util.FileExists(r.Path(jar.Name)) {
+			paths = append(paths, r.Path(jar.Name))
+		}
+	}
+	return paths, nil
+}
+
+// Find returns the first registered jar whose name matches pattern.
+func (r *JarRepository) Find(pattern string) (Jar, bool) {
+	jars, err := r.List()
+	if err != nil {
+		return Jar{}, false
+	}
+	for _, jar := range jars {
+		if ok, _ := filepath.Match(pattern, jar.Name); ok {
+			return jar, true
+		}
+	}
+	return Jar{}, false
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+func writeJar(t *testing.T, dir, name string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("PK"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestJarRepository_AddListRemove(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	repo := NewJarRepository(paths)
+
+	src := writeJar(t, filepath.Join(tmpDir, "downloads"), "udfs.jar")
+	jar, err := repo.Add(src, []string{"Spark", "hive", "spark"})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if !reflect.DeepEqual(jar.Targets, []string{"hive", "spark"}) {
+		t.Errorf("targets = %v", jar.Targets)
+	}
+	if !util.FileExists(repo.Path("udfs.jar")) {
+		t.Error("jar should be copied into the repository")
+	}
+
+	// Re-adding replaces the targets; jars already in the repository are not copied
+	if _, err := repo.Add(repo.Path("udfs.jar"), []string{"hadoop"}); err != nil {
+		t.Fatalf("re-Add: %v", err)
+	}
+	if _, err := repo.Add(src, []string{"yarn"}); err == nil || !strings.Contains(err.Error(), "invalid jar target") {
+		t.Errorf("Add with bad target = %v", err)
+	}
+
+	jars, err := repo.List()
+	if err != nil || len(jars) != 1 || !reflect.DeepEqual(jars[0].Targets, []string{"hadoop"}) {
+		t.Fatalf("List = %+v, %v", jars, err)
+	}
+	if cp, _ := repo.Classpath(JarTargetSpark); len(cp) != 0 {
+		t.Errorf("spark classpath = %v", cp)
+	}
+	if cp, _ := repo.Classpath(JarTargetHadoop); !reflect.DeepEqual(cp, []string{repo.Path("udfs.jar")}) {
+		t.Errorf("hadoop classpath = %v", cp)
+	}
+
+	if _, err := repo.Remove("udfs.jar"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if util.FileExists(repo.Path("udfs.jar")) {
+		t.Error("removed jar should be deleted")
+	}
+	if _, err := repo.Remove("udfs.jar"); err == nil {
+		t.Error("removing an unregistered jar should fail")
+	}
+}
+
+func TestApply_InjectsRepositorySparkJars(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	pm := NewProfileManager(paths)
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	repo := NewJarRepository(paths)
+	for _, name := range []string{"postgresql-42.7.4.jar", "hive-only.jar"} {
+		targets := []string{"spark"}
+		if name == "hive-only.jar" {
+			targets = []string{"hive"}
+		}
+		if _, err := repo.Add(writeJar(t, tmpDir, name), targets); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pm.Apply("local"); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	props, err := readConfigProperties(filepath.Join(paths.CurrentConfDir(), "spark", "spark-defaults.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, p := range props {
+		got[p.Name] = p.Value
+	}
+	jar := repo.Path("postgresql-42.7.4.jar")
+	if got["spark.jars"] != jar || got["spark.driver.extraClassPath"] != jar {
+		t.Errorf("spark.jars = %q, spark.driver.extraClassPath = %q, want %q", got["spark.jars"], got["spark.driver.extraClassPath"], jar)
+	}
+
+	// The generated profile itself is unchanged
+	profileProps, err := readConfigProperties(filepath.Join(paths.UserProfilesDir(), "local", "spark", "spark-defaults.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range profileProps {
+		if p.Name == "spark.jars" {
+			t.Errorf("profile spark.jars = %q", p.Value)
+		}
+	}
+}
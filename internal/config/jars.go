@@ -0,0 +1,247 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// IcebergJarPattern matches Iceberg's Spark runtime jar, e.g.
+// iceberg-spark-runtime-3.5_2.12-1.5.2.jar
+const IcebergJarPattern = "iceberg-spark-runtime-*.jar"
+
+// deltaArtifacts are the names of Delta Lake's Spark jar: delta-spark, or
+// delta-core before Delta 3.0. Either needs delta-storage of its version.
+var deltaArtifacts = []string{"delta-spark", "delta-core"}
+
+// deltaSparkJar matches delta-spark_<scala>-<version>.jar and the
+// delta-core equivalent; the submatch is the Delta version
+var deltaSparkJar = regexp.MustCompile(`^delta-(?:spark|core)_[0-9.]+-(.+)\.jar$`)
+
+// hadoopAWSv1 matches hadoop-aws releases before 3.4, which use AWS SDK v1
+// (aws-java-sdk-bundle) rather than v2 (bundle)
+var hadoopAWSv1 = regexp.MustCompile(`^hadoop-aws-(?:[0-2]|3\.[0-3])\.`)
+
+// JarsDir returns the local jar directory
+// $BASE_DIR/jars
+func (p *Paths) JarsDir() string {
+	return filepath.Join(p.BaseDir, "jars")
+}
+
+// JarSearchDirs returns the directories searched for runtime jars: the local
+// jar directory, then the Ivy cache that 'spark-submit --packages' fills.
+func (p *Paths) JarSearchDirs() []string {
+	dirs := []string{p.JarsDir()}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".ivy2", "jars"))
+	}
+	return dirs
+}
+
+// FindJar returns the most recently modified jar matching pattern in the
+// first directory that has one, or "" if none does.
+func FindJar(pattern string, dirs ...string) string {
+	for _, dir := range dirs {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		newest := ""
+		var newestTime int64
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			if t := info.ModTime().UnixNano(); newest == "" || t > newestTime {
+				newest, newestTime = m, t
+			}
+		}
+		if newest != "" {
+			return newest
+		}
+	}
+	return ""
+}
+
+// findJars returns the jars matching pattern in dirs, newest first. A jar
+// name found in several directories is listed once, from the first.
+func findJars(pattern string, dirs ...string) []string {
+	type found struct {
+		path string
+		mod  int64
+	}
+	var jars []found
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil || info.IsDir() || seen[filepath.Base(m)] {
+				continue
+			}
+			seen[filepath.Base(m)] = true
+			jars = append(jars, found{m, info.ModTime().UnixNano()})
+		}
+	}
+	sort.SliceStable(jars, func(i, j int) bool { return jars[i].mod > jars[j].mod })
+	paths := make([]string, len(jars))
+	for i, j := range jars {
+		paths[i] = j.path
+	}
+	return paths
+}
+
+// InSparkHome reports whether sparkHome/jars already provides a jar
+// matching pattern, so it needs no spark.jars entry.
+func InSparkHome(sparkHome, pattern string) bool {
+	return sparkHome != "" && FindJar(pattern, filepath.Join(sparkHome, "jars")) != ""
+}
+
+// SparkRuntime is what the enabled table formats and S3A add to Spark. A
+// format whose jars cannot be resolved is left out of the generated Spark
+// config, with the reason in Warnings.
+type SparkRuntime struct {
+	Jars     []string // appended to spark.jars
+	Iceberg  bool     // Iceberg's runtime jar is available
+	Delta    bool     // Delta's jars are available
+	Warnings []string
+}
+
+// SparkRuntime resolves the jars of the enabled table formats for the Spark
+// at sparkHome. Jars come from the jar search directories and must match
+// its Spark and Scala versions; jars sparkHome/jars already has are not
+// added. The Iceberg jar is the iceberg-jar setting when set.
+func (s *Settings) SparkRuntime(paths *Paths, sparkHome string) *SparkRuntime {
+	rt := &SparkRuntime{}
+	build, known := DetectSparkBuild(sparkHome)
+
+	if s.IcebergEnabled {
+		rt.Iceberg = rt.addIceberg(s.IcebergJar, sparkHome, build, known, paths)
+	}
+	if s.DeltaEnabled {
+		rt.Delta = rt.addDelta(sparkHome, build, known, paths)
+	}
+	if s.UsesS3A() {
+		rt.addS3A(sparkHome, paths)
+	}
+	return rt
+}
+
+// addIceberg adds the Iceberg runtime jar built for the Spark version and
+// Scala version of build, and reports whether Iceberg has its jar. With
+// several candidates it requires the iceberg-jar setting rather than guess.
+func (rt *SparkRuntime) addIceberg(jar, sparkHome string, build SparkBuild, known bool, paths *Paths) bool {
+	if jar != "" {
+		rt.Jars = append(rt.Jars, jar)
+		return true
+	}
+	pattern := IcebergJarPattern
+	if known {
+		pattern = fmt.Sprintf("iceberg-spark-runtime-%s_%s-*.jar", build.Version, build.Scala)
+	}
+	if InSparkHome(sparkHome, pattern) {
+		return true
+	}
+
+	switch jars := findJars(pattern, paths.JarSearchDirs()...); len(jars) {
+	case 1:
+		rt.Jars = append(rt.Jars, jars[0])
+		return true
+	case 0:
+		rt.Warnings = append(rt.Warnings, missingJarWarning(pattern, paths)+"; Iceberg is not configured")
+	default:
+		names := make([]string, len(jars))
+		for i, j := range jars {
+			names[i] = filepath.Base(j)
+		}
+		rt.Warnings = append(rt.Warnings, fmt.Sprintf(
+			"several Iceberg runtime jars match %s (%s); choose one with: local-data setting set iceberg-jar <path>; Iceberg is not configured",
+			pattern, strings.Join(names, ", ")))
+	}
+	return false
+}
+
+// addDelta adds the newest delta-spark (or delta-core) jar built for the
+// Scala version of build and the delta-storage jar of the same Delta
+// version, and reports whether Delta has its jars.
+func (rt *SparkRuntime) addDelta(sparkHome string, build SparkBuild, known bool, paths *Paths) bool {
+	var candidates []string
+	for _, artifact := range deltaArtifacts {
+		pattern := artifact + "_*.jar"
+		if known {
+			pattern = artifact + "_" + build.Scala + "-*.jar"
+		}
+		if InSparkHome(sparkHome, pattern) {
+			return true
+		}
+		candidates = append(candidates, findJars(pattern, paths.JarSearchDirs()...)...)
+	}
+	var sparkJar, version string
+	for _, jar := range candidates {
+		if sub := deltaSparkJar.FindStringSubmatch(filepath.Base(jar)); sub != nil {
+			sparkJar, version = jar, sub[1]
+			break
+		}
+	}
+	if sparkJar == "" {
+		pattern := "delta-spark_*.jar"
+		if known {
+			pattern = "delta-spark_" + build.Scala + "-*.jar"
+		}
+		rt.Warnings = append(rt.Warnings, missingJarWarning(pattern, paths)+"; Delta is not configured")
+		return false
+	}
+
+	storage := "delta-storage-" + version + ".jar"
+	storageJars := findJars(storage, paths.JarSearchDirs()...)
+	if len(storageJars) == 0 {
+		rt.Warnings = append(rt.Warnings, fmt.Sprintf("%s needs %s, which is not in %s; Delta is not configured",
+			filepath.Base(sparkJar), storage, strings.Join(paths.JarSearchDirs(), ", ")))
+		return false
+	}
+	rt.Jars = append(rt.Jars, sparkJar, storageJars[0])
+	return true
+}
+
+// addS3A adds hadoop-aws of the Hadoop version Spark is built with and
+// the AWS SDK bundle next to it, which Spark distributions leave out. They
+// are looked for in the jar directories and Hadoop's share/hadoop/tools/lib.
+func (rt *SparkRuntime) addS3A(sparkHome string, paths *Paths) {
+	if InSparkHome(sparkHome, "hadoop-aws-*.jar") {
+		return
+	}
+	pattern := "hadoop-aws-*.jar"
+	if version := SparkHadoopVersion(sparkHome); version != "" {
+		pattern = "hadoop-aws-" + version + ".jar"
+	}
+	dirs := paths.JarSearchDirs()
+	if toolsLib := HadoopToolsLib(); toolsLib != "" {
+		dirs = append(dirs, toolsLib)
+	}
+
+	awsJars := findJars(pattern, dirs...)
+	if len(awsJars) == 0 {
+		rt.Warnings = append(rt.Warnings, fmt.Sprintf("no %s found in %s; s3a:// and s3:// paths fail in Spark until it and its AWS SDK bundle are downloaded into %s",
+			pattern, strings.Join(dirs, ", "), paths.JarsDir()))
+		return
+	}
+	// Prefer the bundle shipped alongside hadoop-aws
+	bundle := "bundle-2.*.jar"
+	if hadoopAWSv1.MatchString(filepath.Base(awsJars[0])) {
+		bundle = "aws-java-sdk-bundle-*.jar"
+	}
+	bundles := findJars(bundle, append([]string{filepath.Dir(awsJars[0])}, dirs...)...)
+	if len(bundles) == 0 {
+		rt.Warnings = append(rt.Warnings, fmt.Sprintf("%s needs an AWS SDK %s, which is not in %s; s3a:// and s3:// paths fail in Spark until it is downloaded into %s",
+			filepath.Base(awsJars[0]), bundle, strings.Join(dirs, ", "), paths.JarsDir()))
+		return
+	}
+	rt.Jars = append(rt.Jars, awsJars[0], bundles[0])
+}
+
+// missingJarWarning says where a jar matching pattern was looked for
+func missingJarWarning(pattern string, paths *Paths) string {
+	return fmt.Sprintf("no %s found in %s or Spark's jars; download the one matching your Spark and Scala versions into %s",
+		pattern, strings.Join(paths.JarSearchDirs(), ", "), paths.JarsDir())
+}
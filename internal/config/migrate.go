@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// rewriteExtensions lists the generated/persisted file types that may embed
+// absolute base-dir paths (Hadoop XML, spark-defaults.conf, overrides, settings).
+var rewriteExtensions = map[string]bool{
+	".xml":  true,
+	".conf": true,
+	".yaml": true,
+	".yml":  true,
+	".json": true,
+}
+
+// MigrateBaseDir moves all local-data state from p.BaseDir to newBaseDir and
+// rewrites absolute base-dir references in generated configs and settings
+// (templated paths such as hadoop.tmp.dir, warehouse dirs and Derby URLs).
+// Callers must ensure no services are running before calling this.
+// Returns Paths rooted at the new base directory.
+func MigrateBaseDir(p *Paths, newBaseDir string) (*Paths, error) {
+	oldBase := filepath.Clean(p.BaseDir)
+	newBase, err := filepath.Abs(newBaseDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base dir %q: %w", newBaseDir, err)
+	}
+
+	if newBase == oldBase {
+		return nil, fmt.Errorf("new base dir is the same as the current one: %s", oldBase)
+	}
+	if strings.HasPrefix(newBase+string(filepath.Separator), oldBase+string(filepath.Separator)) {
+		return nil, fmt.Errorf("new base dir %s must not be inside the current base dir %s", newBase, oldBase)
+	}
+	if !util.DirExists(oldBase) {
+		return nil, fmt.Errorf("current base dir does not exist: %s", oldBase)
+	}
+	if util.FileExists(newBase) {
+		empty, err := util.IsDirEmpty(newBase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect %s: %w", newBase, err)
+		}
+		if !empty {
+			return nil, fmt.Errorf("new base dir already exists and is not empty: %s", newBase)
+		}
+		if err := os.Remove(newBase); err != nil {
+			return nil, fmt.Errorf("failed to replace empty directory %s: %w", newBase, err)
+		}
+	}
+
+	util.Log("Moving %s -> %s", oldBase, newBase)
+	if err := util.MoveDir(oldBase, newBase); err != nil {
+		return nil, err
+	}
+
+	newPaths := NewPaths(p.RepoRoot, newBase)
+	for _, dir := range []string{newPaths.ConfRootDir(), newPaths.SettingsDir()} {
+		if err := rewriteBaseDirRefs(dir, oldBase, newBase); err != nil {
+			return nil, err
+		}
+	}
+
+	return newPaths, nil
+}
+
+// rewriteBaseDirRefs replaces oldBase with newBase in all config files under root.
+func rewriteBaseDirRefs(root, oldBase, newBase string) error {
+	if !util.DirExists(root) {
+		return nil
+	}
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !rewriteExtensions[filepath.Ext(path)] {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		rewritten, changed := rewriteBaseDir(string(data), oldBase, newBase)
+		if !changed {
+			return nil
+		}
+
+		if err := os.WriteFile(path, []byte(rewritten), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to rewrite %s: %w", path, err)
+		}
+		util.Log("  rewrote paths in %s", path)
+		return nil
+	})
+}
+
+// rewriteBaseDir replaces references to oldBase that are followed by a path
+// separator, so that sibling directories sharing a prefix are left untouched.
+func rewriteBaseDir(content, oldBase, newBase string) (string, bool) {
+	oldPrefix := filepath.ToSlash(oldBase) + "/"
+	newPrefix := filepath.ToSlash(newBase) + "/"
+	if !strings.Contains(content, oldPrefix) {
+		return content, false
+	}
+	return strings.ReplaceAll(content, oldPrefix, newPrefix), true
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+func TestMigrateBaseDir_MovesStateAndRewritesPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldBase := filepath.Join(tmpDir, "old")
+	newBase := filepath.Join(tmpDir, "new")
+	paths := NewPaths("", oldBase)
+
+	pm := NewProfileManager(paths)
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := pm.Set("hdfs"); err != nil {
+		t.Fatalf("set hdfs: %v", err)
+	}
+	markerFile := filepath.Join(paths.StateDir(), "hive", "metastore_db", "marker")
+	if err := os.MkdirAll(filepath.Dir(markerFile), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(markerFile, []byte("x"), 0644); err != nil {
+		t.Fatalf("write marker: %v", err)
+	}
+
+	newPaths, err := MigrateBaseDir(paths, newBase)
+	if err != nil {
+		t.Fatalf("MigrateBaseDir() error: %v", err)
+	}
+
+	if newPaths.BaseDir != newBase {
+		t.Fatalf("BaseDir = %q, want %q", newPaths.BaseDir, newBase)
+	}
+	if util.DirExists(oldBase) {
+		t.Fatalf("old base dir should be removed")
+	}
+	if !util.FileExists(filepath.Join(newPaths.StateDir(), "hive", "metastore_db", "marker")) {
+		t.Fatalf("state was not moved")
+	}
+
+	for _, path := range []string{
+		filepath.Join(newPaths.UserProfilesDir(), "hdfs", "hadoop", "core-site.xml"),
+		filepath.Join(newPaths.UserProfilesDir(), "local", "spark", "spark-defaults.conf"),
+		filepath.Join(newPaths.CurrentHadoopConf(), "hdfs-site.xml"),
+		newPaths.SettingsFile(),
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		if strings.Contains(string(data), oldBase+"/") {
+			t.Errorf("%s still references old base dir:\n%s", path, data)
+		}
+		if !strings.Contains(string(data), newBase+"/") {
+			t.Errorf("%s does not reference new base dir:\n%s", path, data)
+		}
+	}
+
+	settings, err := NewSettingsManager(newPaths).Load()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	if !strings.Contains(settings.DBURL, filepath.Join(newBase, "state", "hive", "metastore_db")) {
+		t.Errorf("DBURL = %q, want Derby path under new base", settings.DBURL)
+	}
+}
+
+func TestMigrateBaseDir_RejectsNonEmptyTarget(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldBase := filepath.Join(tmpDir, "old")
+	newBase := filepath.Join(tmpDir, "new")
+	if err := os.MkdirAll(oldBase, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.MkdirAll(newBase, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(newBase, "existing"), []byte("x"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	_, err := MigrateBaseDir(NewPaths("", oldBase), newBase)
+	if err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Fatalf("expected non-empty target error, got %v", err)
+	}
+}
+
+func TestRewriteBaseDir_IgnoresSiblingPrefix(t *testing.T) {
+	content := "a=/data/ldp/state b=/data/ldp2/state"
+
+	got, changed := rewriteBaseDir(content, "/data/ldp", "/mnt/ldp")
+	if !changed {
+		t.Fatalf("expected content to change")
+	}
+	if want := "a=/mnt/ldp/state b=/data/ldp2/state"; got != want {
+		t.Fatalf("rewriteBaseDir() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,242 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config/generator"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// overloadSchemeImpl serves a scheme through a ViewFS mount table (Hadoop 3.3+)
+const overloadSchemeImpl = "org.apache.hadoop.fs.viewfs.ViewFileSystemOverloadScheme"
+
+// mountSchemes maps the cloud schemes that can be mounted to the connector
+// that still serves their unmounted buckets.
+var mountSchemes = map[string]string{
+	"gs":    "com.google.cloud.hadoop.fs.gcs.GoogleHadoopFileSystem",
+	"s3":    "org.apache.hadoop.fs.s3a.S3AFileSystem",
+	"s3a":   "org.apache.hadoop.fs.s3a.S3AFileSystem",
+	"abfs":  "org.apache.hadoop.fs.azurebfs.AzureBlobFileSystem",
+	"abfss": "org.apache.hadoop.fs.azurebfs.SecureAzureBlobFileSystem",
+}
+
+// Mount redirects a cloud URI (a bucket or a prefix of one) to a directory.
+type Mount struct {
+	URI    string `json:"uri"`    // e.g. gs://bucket/raw
+	Target string `json:"target"` // file:///... or hdfs://...
+}
+
+// MountSchemes returns the schemes that can be mounted.
+func MountSchemes() []string {
+	schemes := make([]string, 0, len(mountSchemes))
+	for scheme := range mountSchemes {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// mountURI is a parsed mount URI. Hadoop names mount tables after the URI
+// host, so abfss://container@account.dfs.core.windows.net uses the account
+// host and containers of one account share a table.
+type mountURI struct {
+	scheme string
+	table  string // URI host
+	path   string // "/" mounts the whole bucket
+}
+
+func parseMountURI(raw string) (mountURI, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return mountURI{}, fmt.Errorf("invalid mount URI %q: %w", raw, err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if _, ok := mountSchemes[scheme]; !ok {
+		return mountURI{}, fmt.Errorf("unsupported mount URI %q (schemes: %s)", raw, strings.Join(MountSchemes(), ", "))
+	}
+	if u.Hostname() == "" {
+		return mountURI{}, fmt.Errorf("mount URI %q has no bucket", raw)
+	}
+	return mountURI{scheme: scheme, table: u.Hostname(), path: path.Clean("/" + u.Path)}, nil
+}
+
+// canonical returns raw with a lowercase scheme and a clean path.
+func (m mountURI) canonical(raw string) string {
+	u, _ := url.Parse(raw)
+	s := m.scheme + "://" + u.Host
+	if u.User != nil {
+		s = m.scheme + "://" + u.User.String() + "@" + u.Host
+	}
+	if m.path != "/" {
+		s += m.path
+	}
+	return s
+}
+
+// overlaps reports whether two mounts would need nested or identical links
+// in one mount table. A whole-bucket mount is the table's fallback and
+// coexists with links.
+func (m mountURI) overlaps(o mountURI) bool {
+	if m.table != o.table {
+		return false
+	}
+	if m.path == o.path {
+		return true
+	}
+	if m.path == "/" || o.path == "/" {
+		return false
+	}
+	return strings.HasPrefix(m.path+"/", o.path+"/") || strings.HasPrefix(o.path+"/", m.path+"/")
+}
+
+// NewMount validates a mount of uri onto target. An empty target selects a
+// directory under $BASE_DIR/state/mounts, or under hdfsMountRoot with
+// onHDFS. Local targets are made absolute file:// URIs.
+func NewMount(paths *Paths, uri, target string, onHDFS bool) (Mount, error) {
+	mu, err := parseMountURI(uri)
+	if err != nil {
+		return Mount{}, err
+	}
+
+	rel := path.Join(mu.scheme, mu.table, mu.path)
+	switch {
+	case target == "" && onHDFS:
+		root, err := hdfsMountRoot(paths)
+		if err != nil {
+			return Mount{}, err
+		}
+		target = root + "/" + rel
+	case target == "":
+		target = "file://" + filepath.Join(paths.MountsDir(), filepath.FromSlash(rel))
+	case strings.Contains(target, "://"):
+		t, err := url.Parse(target)
+		if err != nil {
+			return Mount{}, fmt.Errorf("invalid mount target %q: %w", target, err)
+		}
+		if t.Scheme != "file" && t.Scheme != "hdfs" {
+			return Mount{}, fmt.Errorf("mount target %q must be a local path, file:// or hdfs:// URI", target)
+		}
+	default:
+		abs, err := filepath.Abs(target)
+		if err != nil {
+			return Mount{}, err
+		}
+		target = "file://" + abs
+	}
+
+	return Mount{URI: mu.canonical(uri), Target: target}, nil
+}
+
+// hdfsMountRoot returns where --hdfs mounts live: /mounts on fs.defaultFS
+// of the active profile, or of the hdfs profile when the active one is not
+// on HDFS.
+func hdfsMountRoot(paths *Paths) (string, error) {
+	profiles := []string{"hdfs"}
+	if active, err := paths.ActiveProfile(); err == nil && active != "hdfs" {
+		profiles = append([]string{active}, profiles...)
+	}
+	for _, profile := range profiles {
+		cfg, err := util.ParseHadoopXML(filepath.Join(paths.ProfilesDir(), profile, "hadoop", "core-site.xml"))
+		if err != nil {
+			continue
+		}
+		defaultFS := strings.TrimRight(strings.TrimSpace(cfg.GetProperty("fs.defaultFS")), "/")
+		if strings.HasPrefix(defaultFS, "hdfs://") {
+			return defaultFS + "/mounts", nil
+		}
+	}
+	return "", fmt.Errorf("no profile with an hdfs:// fs.defaultFS found in %s (run 'local-data init', or pass an hdfs:// target)", paths.ProfilesDir())
+}
+
+// LocalDir returns the directory of a file:// target, or "" for HDFS.
+func (m Mount) LocalDir() string {
+	if dir, ok := strings.CutPrefix(m.Target, "file://"); ok {
+		return dir
+	}
+	return ""
+}
+
+// AddMount adds m, replacing the target of an existing mount of the same
+// URI. Mounts that would nest within one mount table are rejected.
+func (s *Settings) AddMount(m Mount) error {
+	mu, err := parseMountURI(m.URI)
+	if err != nil {
+		return err
+	}
+	for i, existing := range s.Mounts {
+		if existing.URI == m.URI {
+			s.Mounts[i] = m
+			return nil
+		}
+		eu, err := parseMountURI(existing.URI)
+		if err != nil {
+			return err
+		}
+		if mu.overlaps(eu) {
+			return fmt.Errorf("%s conflicts with mount %s (mount tables are keyed by host and cannot nest)", m.URI, existing.URI)
+		}
+	}
+	s.Mounts = append(s.Mounts, m)
+	return nil
+}
+
+// RemoveMount removes the mount of uri and returns it.
+func (s *Settings) RemoveMount(uri string) (Mount, error) {
+	mu, err := parseMountURI(uri)
+	if err != nil {
+		return Mount{}, err
+	}
+	canonical := mu.canonical(uri)
+	for i, m := range s.Mounts {
+		if m.URI == canonical {
+			s.Mounts = append(s.Mounts[:i], s.Mounts[i+1:]...)
+			return m, nil
+		}
+	}
+	return Mount{}, fmt.Errorf("no mount for %s (see: local-data mounts list)", canonical)
+}
+
+// UsesS3A reports whether s3a:// or s3:// paths go through Hadoop's S3A
+// connector: the local S3 server is on or such a bucket is mounted.
+func (s *Settings) UsesS3A() bool {
+	if s.S3Enabled {
+		return true
+	}
+	for _, m := range s.Mounts {
+		if mu, err := parseMountURI(m.URI); err == nil && mountSchemes[mu.scheme] == mountSchemes["s3a"] {
+			return true
+		}
+	}
+	return false
+}
+
+// mountProperties overloads every mounted scheme with ViewFS and renders
+// each mount as a link, or as the fallback of its table for whole buckets.
+func (s *Settings) mountProperties() []generator.PropertyOverride {
+	var pairs []string
+	seen := map[string]bool{}
+	for _, m := range s.Mounts {
+		mu, err := parseMountURI(m.URI)
+		if err != nil {
+			continue
+		}
+		if !seen[mu.scheme] {
+			seen[mu.scheme] = true
+			pairs = append(pairs,
+				"fs."+mu.scheme+".impl", overloadSchemeImpl,
+				"fs.viewfs.overload.scheme.target."+mu.scheme+".impl", mountSchemes[mu.scheme],
+			)
+		}
+		link := "fs.viewfs.mounttable." + mu.table + ".link." + mu.path
+		if mu.path == "/" {
+			link = "fs.viewfs.mounttable." + mu.table + ".linkFallback"
+		}
+		pairs = append(pairs, link, m.Target)
+	}
+	return fsOverrides(pairs...)
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeCoreSite(t *testing.T, paths *Paths, profile, defaultFS string) {
+	t.Helper()
+	dir := filepath.Join(paths.UserProfilesDir(), profile, "hadoop")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	xml := `<configuration><property><name>fs.defaultFS</name><value>` + defaultFS + `</value></property></configuration>`
+	if err := os.WriteFile(filepath.Join(dir, "core-site.xml"), []byte(xml), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewMount(t *testing.T) {
+	base := t.TempDir()
+	paths := NewPaths("/tmp/repo", base)
+	writeCoreSite(t, paths, "hdfs", "hdfs://localhost:8020/")
+
+	tests := []struct {
+		uri, target string
+		onHDFS      bool
+		want        Mount
+	}{
+		{"GS://bucket/raw/", "", false, Mount{"gs://bucket/raw", "file://" + base + "/state/mounts/gs/bucket/raw"}},
+		{"s3a://bucket", "", true, Mount{"s3a://bucket", "hdfs://localhost:8020/mounts/s3a/bucket"}},
+		{"abfss://c@acct.dfs.core.windows.net/x", "/data/x", false, Mount{"abfss://c@acct.dfs.core.windows.net/x", "file:///data/x"}},
+		{"gs://bucket", "hdfs://nn:8020/gs", false, Mount{"gs://bucket", "hdfs://nn:8020/gs"}},
+	}
+	for _, tt := range tests {
+		got, err := NewMount(paths, tt.uri, tt.target, tt.onHDFS)
+		if err != nil {
+			t.Errorf("NewMount(%q) error: %v", tt.uri, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NewMount(%q) = %+v, want %+v", tt.uri, got, tt.want)
+		}
+	}
+
+	for _, uri := range []string{"hdfs://nn/x", "gs:///x", "/local/path"} {
+		if _, err := NewMount(paths, uri, "", false); err == nil {
+			t.Errorf("NewMount(%q) should fail", uri)
+		}
+	}
+	if _, err := NewMount(paths, "gs://b", "s3://other", false); err == nil {
+		t.Error("NewMount with a cloud target should fail")
+	}
+}
+
+func TestSettings_AddRemoveMount(t *testing.T) {
+	s := &Settings{}
+	for _, m := range []Mount{
+		{"gs://data", "file:///d"},
+		{"gs://data/raw", "file:///raw"},
+		{"s3://logs/app", "file:///logs"},
+	} {
+		if err := s.AddMount(m); err != nil {
+			t.Fatalf("AddMount(%s): %v", m.URI, err)
+		}
+	}
+
+	// Same URI replaces the target
+	if err := s.AddMount(Mount{"gs://data/raw", "file:///raw2"}); err != nil || len(s.Mounts) != 3 || s.Mounts[1].Target != "file:///raw2" {
+		t.Errorf("re-adding a mount: %v %+v", err, s.Mounts)
+	}
+	// Tables are keyed by host across schemes, and links cannot nest
+	for _, uri := range []string{"s3a://data/raw", "gs://data/raw/2024", "gs://logs/app/x"} {
+		if err := s.AddMount(Mount{uri, "file:///x"}); err == nil || !strings.Contains(err.Error(), "conflicts") {
+			t.Errorf("AddMount(%s) = %v, want conflict", uri, err)
+		}
+	}
+
+	if _, err := s.RemoveMount("GS://data/raw/"); err != nil {
+		t.Fatalf("RemoveMount: %v", err)
+	}
+	if _, err := s.RemoveMount("gs://data/raw"); err == nil {
+		t.Error("removing a missing mount should fail")
+	}
+	if len(s.Mounts) != 2 {
+		t.Errorf("mounts = %+v", s.Mounts)
+	}
+}
+
+func TestSettings_MountProperties(t *testing.T) {
+	s := &Settings{
+		S3Enabled: true,
+		Mounts: []Mount{
+			{"gs://data", "file:///d"},
+			{"gs://data/raw", "hdfs://localhost:8020/raw"},
+			{"s3://logs", "file:///logs"},
+		},
+	}
+
+	hive := map[string]string{}
+	spark := map[string]string{}
+	for _, p := range s.PropertyOverrides() {
+		switch p.File {
+		case "hive":
+			hive[p.Name] = p.Value
+		case "spark":
+			spark[p.Name] = p.Value
+		}
+	}
+
+	want := map[string]string{
+		"fs.gs.impl": overloadSchemeImpl,
+		"fs.viewfs.overload.scheme.target.gs.impl": "com.google.cloud.hadoop.fs.gcs.GoogleHadoopFileSystem",
+		"fs.viewfs.mounttable.data.linkFallback":   "file:///d",
+		"fs.viewfs.mounttable.data.link./raw":      "hdfs://localhost:8020/raw",
+		"fs.s3.impl":                               overloadSchemeImpl, // over the s3 setting
+		"fs.viewfs.mounttable.logs.linkFallback":   "file:///logs",
+	}
+	for name, value := range want {
+		if hive[name] != value {
+			t.Errorf("hive %s = %q, want %q", name, hive[name], value)
+		}
+		if spark["spark.hadoop."+name] != value {
+			t.Errorf("spark spark.hadoop.%s = %q, want %q", name, spark["spark.hadoop."+name], value)
+		}
+	}
+}
+
+func TestNewMount_HDFSRoot(t *testing.T) {
+	paths := NewPaths("/tmp/repo", t.TempDir())
+	if _, err := NewMount(paths, "gs://bucket", "", true); err == nil {
+		t.Error("NewMount --hdfs without an HDFS profile should fail")
+	}
+
+	// The active profile's NameNode wins over the hdfs profile's
+	writeCoreSite(t, paths, "hdfs", "hdfs://localhost:8020")
+	writeCoreSite(t, paths, "cluster", "hdfs://nn.example:9000")
+	if err := paths.SetActiveProfile("cluster"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewMount(paths, "gs://bucket", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "hdfs://nn.example:9000/mounts/gs/bucket"; got.Target != want {
+		t.Errorf("target = %q, want %q", got.Target, want)
+	}
+
+	// A profile not on HDFS falls back to the hdfs profile
+	writeCoreSite(t, paths, "cluster", "file:///")
+	if got, err := NewMount(paths, "gs://bucket", "", true); err != nil || got.Target != "hdfs://localhost:8020/mounts/gs/bucket" {
+		t.Errorf("NewMount() = %+v, %v", got, err)
+	}
+}
@@ -14,6 +14,9 @@ import (
 // ProfileManager handles profile initialization, listing, setting, and overlay application
 type ProfileManager struct {
 	paths *Paths
+
+	// detectedSparkHome caches FindSparkHome; see sparkHome
+	detectedSparkHome *string
 }
 
 // NewProfileManager creates a new profile manager
@@ -28,42 +31,27 @@ func (pm *ProfileManager) IsInitialized() bool {
 	return util.DirExists(pm.paths.UserProfilesDir())
 }
 
-// Init initializes profiles using the Go struct generator
+// Init initializes profiles using the Go struct generator.
+// When profiles already exist and force is false, built-in profiles are
+// regenerated from settings (see Regenerate) and user-defined ones are kept.
 func (pm *ProfileManager) Init(force bool, opts *generator.InitOptions) error {
 	dst := pm.paths.UserProfilesDir()
 	sm := NewSettingsManager(pm.paths)
 
-	effectiveOpts, settingsToPersist, err := pm.resolveInitOptions(sm, opts)
+	settings, err := pm.resolveInitSettings(sm, opts)
 	if err != nil {
 		return err
 	}
 
 	// Check if destination already exists
 	if util.DirExists(dst) {
-		if force {
-			util.Log("Re-initializing profiles (overwriting): %s", dst)
-			if err := os.RemoveAll(dst); err != nil {
-				return fmt.Errorf("failed to remove existing profiles: %w", err)
-			}
-		} else {
-			// Keep existing files, but sync mutable settings into generated Hive XML.
-			applier := NewSettingsApplier(pm.paths)
-			if err := applier.Apply("db-type", "", effectiveOpts.DBType); err != nil {
-				return fmt.Errorf("failed to sync db-type setting: %w", err)
-			}
-			if err := applier.Apply("user", "", effectiveOpts.User); err != nil {
-				return fmt.Errorf("failed to sync user setting: %w", err)
-			}
-			if err := applier.Apply("db-url", "", effectiveOpts.DBUrl); err != nil {
-				return fmt.Errorf("failed to sync db-url setting: %w", err)
-			}
-			if err := applier.Apply("db-password", "", effectiveOpts.DBPassword); err != nil {
-				return fmt.Errorf("failed to sync db-password setting: %w", err)
-			}
-			if err := sm.Save(settingsToPersist); err != nil {
-				return fmt.Errorf("failed to save settings: %w", err)
-			}
-			return nil
+		if !force {
+			_, err := pm.Regenerate(settings, false)
+			return err
+		}
+		util.Log("Re-initializing profiles (overwriting): %s", dst)
+		if err := os.RemoveAll(dst); err != nil {
+			return fmt.Errorf("failed to remove existing profiles: %w", err)
 		}
 	}
 
@@ -74,12 +62,11 @@ func (pm *ProfileManager) Init(force bool, opts *generator.InitOptions) error {
 
 	util.Log("Generating profiles under: %s", dst)
 
-	gen := generator.NewConfigGenerator()
-	if err := gen.InitProfiles(pm.paths.BaseDir, dst, effectiveOpts); err != nil {
-		return fmt.Errorf("failed to generate profiles: %w", err)
+	if err := pm.generate(settings, dst, true); err != nil {
+		return err
 	}
 
-	if err := sm.Save(settingsToPersist); err != nil {
+	if err := sm.Save(settings); err != nil {
 		return fmt.Errorf("failed to save settings: %w", err)
 	}
 
@@ -89,52 +76,96 @@ func (pm *ProfileManager) Init(force bool, opts *generator.InitOptions) error {
 	return nil
 }
 
-func (pm *ProfileManager) resolveInitOptions(sm *SettingsManager, opts *generator.InitOptions) (*generator.InitOptions, *Settings, error) {
-	settings, err := sm.LoadOrDefault()
+// generate renders all built-in profiles for settings into dst. Profiles get
+// the resolved password (or a credential provider) while settings keep the
+// env:/file: reference; storeSecrets=false skips writing the JCEKS store.
+func (pm *ProfileManager) generate(settings *Settings, dst string, storeSecrets bool) error {
+	render := renderDBPassword
+	if !storeSecrets {
+		render = previewDBPassword
+	}
+	rendered, err := render(pm.paths, settings.DBPassword, settings.DBPasswordStore)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to load settings: %w", err)
+		return err
+	}
+
+	opts := settings.initOptions()
+	opts.DBPassword = rendered.Password
+	opts.CredentialProviders = rendered.ProviderPath
+	rt := pm.SparkRuntime(settings)
+	for _, w := range rt.Warnings {
+		util.Warn("%s", w)
+	}
+	opts.SparkJars = rt.Jars
+	if !rt.Iceberg {
+		opts.Iceberg = nil
+	}
+	// DeltaCatalog as spark_catalog without its jars breaks every session
+	opts.Delta = rt.Delta
+
+	gen := generator.NewConfigGenerator()
+	if err := gen.InitProfiles(pm.paths.BaseDir, dst, opts); err != nil {
+		return fmt.Errorf("failed to generate profiles: %w", err)
+	}
+	return nil
+}
+
+// SparkRuntime resolves the table format jars of settings for the detected
+// Spark installation.
+func (pm *ProfileManager) SparkRuntime(settings *Settings) *SparkRuntime {
+	return settings.SparkRuntime(pm.paths, pm.sparkHome())
+}
+
+// sparkHome returns the detected Spark installation, looked up once, so
+// every command that regenerates profiles renders the same spark.jars.
+func (pm *ProfileManager) sparkHome() string {
+	if pm.detectedSparkHome == nil {
+		home := FindSparkHome()
+		pm.detectedSparkHome = &home
 	}
+	return *pm.detectedSparkHome
+}
 
-	effective := &generator.InitOptions{
-		User:       settings.User,
-		DBType:     settings.DBType,
-		DBUrl:      settings.DBURL,
-		DBPassword: settings.DBPassword,
+// resolveInitSettings merges init options over persisted settings.
+func (pm *ProfileManager) resolveInitSettings(sm *SettingsManager, opts *generator.InitOptions) (*Settings, error) {
+	settings, err := sm.LoadOrDefault()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
 	}
 
+	resolved := *settings
+	resolved.BaseDir = pm.paths.BaseDir
 	if opts != nil {
 		if opts.User != "" {
-			effective.User = opts.User
+			resolved.User = opts.User
 		}
 		if opts.DBType != "" {
-			effective.DBType = opts.DBType
+			resolved.DBType = opts.DBType
 		}
 		if opts.DBUrl != "" {
-			effective.DBUrl = opts.DBUrl
+			resolved.DBURL = opts.DBUrl
 		}
 		if opts.DBPassword != "" {
-			effective.DBPassword = opts.DBPassword
+			resolved.DBPassword = opts.DBPassword
 		}
 	}
 
-	dbType, err := metastore.NormalizeDBType(effective.DBType)
+	dbType, err := metastore.NormalizeDBType(resolved.DBType)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	effective.DBType = string(dbType)
-	if err := metastore.ValidateURL(dbType, effective.DBUrl); err != nil {
-		return nil, nil, err
+	resolved.DBType = string(dbType)
+	if err := metastore.ValidateURL(dbType, resolved.DBURL); err != nil {
+		return nil, err
 	}
 
-	persisted := &Settings{
-		User:       effective.User,
-		BaseDir:    pm.paths.BaseDir,
-		DBType:     effective.DBType,
-		DBURL:      effective.DBUrl,
-		DBPassword: effective.DBPassword,
+	// Keep the managed server only while the URL still points at it
+	if !(settings.DBManaged && dbType == metastore.Postgres && resolved.DBURL == settings.DBURL) {
+		resolved.DBManaged = false
+		resolved.DBManagedPort = 0
 	}
 
-	return effective, persisted, nil
+	return &resolved, nil
 }
 
 // List returns a sorted list of available profile names
@@ -202,6 +233,14 @@ func (pm *ProfileManager) Apply(profile string) error {
 	}
 
 	dstRoot := pm.paths.CurrentConfDir()
+	util.Log("Applying runtime config overlay for profile '%s'", profile)
+	util.Log("  to: %s", dstRoot)
+
+	return pm.writeOverlay(profile, dstRoot)
+}
+
+// writeOverlay replaces dstRoot with a copy of a profile's configs
+func (pm *ProfileManager) writeOverlay(profile, dstRoot string) error {
 	srcRoot := filepath.Join(pm.paths.UserProfilesDir(), profile)
 
 	// Check if profile exists in user's profiles directory
@@ -209,9 +248,6 @@ func (pm *ProfileManager) Apply(profile string) error {
 		return fmt.Errorf("profile '%s' not found in %s (run: local-data init)", profile, pm.paths.UserProfilesDir())
 	}
 
-	util.Log("Applying runtime config overlay for profile '%s'", profile)
-	util.Log("  to: %s", dstRoot)
-
 	// Remove existing overlay to ensure clean state
 	if util.DirExists(dstRoot) {
 		if err := os.RemoveAll(dstRoot); err != nil {
@@ -234,6 +270,26 @@ func (pm *ProfileManager) Apply(profile string) error {
 		}
 	}
 
+	// Add jars registered for Spark in $BASE_DIR/jars
+	sparkJars, err := NewJarRepository(pm.paths).Classpath(JarTargetSpark)
+	if err != nil {
+		return err
+	}
+	if err := addSparkJars(dstRoot, sparkJars, true); err != nil {
+		return err
+	}
+
+	// Ship the profile's packed virtualenv ('local-data python init') to YARN
+	settings, err := NewSettingsManager(pm.paths).LoadOrDefault()
+	if err != nil {
+		return err
+	}
+	if pyEnv, ok := settings.PythonEnvFor(profile); ok {
+		if err := addPythonArchive(dstRoot, pyEnv.Archive); err != nil {
+			return err
+		}
+	}
+
 	// Write marker file
 	markerPath := filepath.Join(dstRoot, ".profile")
 	if err := os.WriteFile(markerPath, []byte(profile), 0644); err != nil {
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -29,6 +30,36 @@ func NewPaths(repoRoot, baseDir string) *Paths {
 	}
 }
 
+// BaseDirEnvVar is the environment variable that overrides the default base directory.
+const BaseDirEnvVar = "LOCAL_DATA_BASE_DIR"
+
+// ResolveBaseDir returns the base directory for this invocation.
+// Precedence: explicit override (--base-dir) > $LOCAL_DATA_BASE_DIR > DefaultBaseDir().
+// A leading "~/" is expanded and the result is made absolute.
+func ResolveBaseDir(override string) (string, error) {
+	dir := strings.TrimSpace(override)
+	if dir == "" {
+		dir = strings.TrimSpace(os.Getenv(BaseDirEnvVar))
+	}
+	if dir == "" {
+		return DefaultBaseDir(), nil
+	}
+
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to expand %q: %w", dir, err)
+		}
+		dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("invalid base dir %q: %w", dir, err)
+	}
+	return abs, nil
+}
+
 // DefaultBaseDir returns the default base directory
 // Mirrors ld_default_base_dir: ${BASE_DIR:-$HOME/local-data-platform}
 func DefaultBaseDir() string {
@@ -59,6 +90,38 @@ func (p *Paths) SettingsFile() string {
 	return filepath.Join(p.SettingsDir(), "setting.json")
 }
 
+// SecretsDir returns the secrets directory: $BASE_DIR/settings/secrets
+func (p *Paths) SecretsDir() string {
+	return filepath.Join(p.SettingsDir(), "secrets")
+}
+
+// CredentialStoreFile returns the metastore JCEKS keystore path:
+// $BASE_DIR/settings/secrets/metastore.jceks
+func (p *Paths) CredentialStoreFile() string {
+	return filepath.Join(p.SecretsDir(), "metastore.jceks")
+}
+
+// SnapshotsDir returns the snapshots directory: $BASE_DIR/snapshots
+func (p *Paths) SnapshotsDir() string {
+	return filepath.Join(p.BaseDir, "snapshots")
+}
+
+// BackupsDir returns the backups directory: $BASE_DIR/backups
+func (p *Paths) BackupsDir() string {
+	return filepath.Join(p.BaseDir, "backups")
+}
+
+// PythonEnvDir returns the virtualenv of a profile: $BASE_DIR/python/<profile>
+func (p *Paths) PythonEnvDir(profile string) string {
+	return filepath.Join(p.BaseDir, "python", profile)
+}
+
+// PythonArchive returns the packed virtualenv of a profile shipped to YARN
+// containers: $BASE_DIR/python/<profile>.tar.gz
+func (p *Paths) PythonArchive(profile string) string {
+	return p.PythonEnvDir(profile) + ".tar.gz"
+}
+
 // ConfRootDir returns the configuration root directory: $BASE_DIR/conf
 // Mirrors ld_conf_root_dir
 func (p *Paths) ConfRootDir() string {
@@ -72,6 +135,12 @@ func (p *Paths) CurrentConfDir() string {
 	return filepath.Join(p.ConfRootDir(), "current")
 }
 
+// ProjectConfDir returns the runtime config overlay of a project
+// (.local-data.yaml): $BASE_DIR/conf/projects/<id>
+func (p *Paths) ProjectConfDir(id string) string {
+	return filepath.Join(p.ConfRootDir(), "projects", id)
+}
+
 // ActiveProfileFile returns the path to the active profile marker file
 // $BASE_DIR/conf/active_profile
 // Mirrors ld_active_profile_file
@@ -130,7 +199,7 @@ type ServicePaths struct {
 }
 
 // ServiceStateDir returns paths for a specific service
-// service: "hdfs", "yarn", or "hive"
+// service: "hdfs", "yarn", "hive", "s3" or "notebook"
 func (p *Paths) ServiceStateDir(service string) *ServicePaths {
 	baseStateDir := filepath.Join(p.StateDir(), service)
 	return &ServicePaths{
@@ -159,6 +228,29 @@ func (p *Paths) HivePaths() *ServicePaths {
 	return sp
 }
 
+// S3Paths returns paths of the local S3 server; buckets live in DataDir
+func (p *Paths) S3Paths() *ServicePaths {
+	return p.ServiceStateDir("s3")
+}
+
+// NotebookPaths returns paths of the Jupyter server; the generated kernel
+// spec and server state live in DataDir
+func (p *Paths) NotebookPaths() *ServicePaths {
+	return p.ServiceStateDir("notebook")
+}
+
+// NotebooksDir returns the default Jupyter notebook directory
+// $BASE_DIR/notebooks
+func (p *Paths) NotebooksDir() string {
+	return filepath.Join(p.BaseDir, "notebooks")
+}
+
+// MountsDir returns the default root of mount targets
+// $BASE_DIR/state/mounts
+func (p *Paths) MountsDir() string {
+	return filepath.Join(p.StateDir(), "mounts")
+}
+
 // HadoopTmpDir returns the Hadoop temporary directory
 // $BASE_DIR/state/hadoop/tmp
 func (p *Paths) HadoopTmpDir() string {
@@ -133,3 +133,53 @@ func TestDefaultBaseDir(t *testing.T) {
 		}
 	})
 }
+
+func TestResolveBaseDir(t *testing.T) {
+	t.Run("flag takes precedence over env", func(t *testing.T) {
+		t.Setenv(BaseDirEnvVar, "/from/env")
+
+		got, err := ResolveBaseDir("/from/flag")
+		if err != nil {
+			t.Fatalf("ResolveBaseDir() error: %v", err)
+		}
+		if got != "/from/flag" {
+			t.Errorf("ResolveBaseDir() = %v, want /from/flag", got)
+		}
+	})
+
+	t.Run("env used when flag empty", func(t *testing.T) {
+		t.Setenv(BaseDirEnvVar, "/from/env")
+
+		got, err := ResolveBaseDir("")
+		if err != nil {
+			t.Fatalf("ResolveBaseDir() error: %v", err)
+		}
+		if got != "/from/env" {
+			t.Errorf("ResolveBaseDir() = %v, want /from/env", got)
+		}
+	})
+
+	t.Run("default when neither set", func(t *testing.T) {
+		t.Setenv(BaseDirEnvVar, "")
+
+		got, err := ResolveBaseDir("")
+		if err != nil {
+			t.Fatalf("ResolveBaseDir() error: %v", err)
+		}
+		if got != DefaultBaseDir() {
+			t.Errorf("ResolveBaseDir() = %v, want %v", got, DefaultBaseDir())
+		}
+	})
+
+	t.Run("expands tilde", func(t *testing.T) {
+		homeDir, _ := os.UserHomeDir()
+
+		got, err := ResolveBaseDir("~/ldp")
+		if err != nil {
+			t.Fatalf("ResolveBaseDir() error: %v", err)
+		}
+		if want := filepath.Join(homeDir, "ldp"); got != want {
+			t.Errorf("ResolveBaseDir() = %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config/generator"
+	"github.com/danieljhkim/local-data-platform/internal/config/schema"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// ApplyProjectOverlay writes a project's runtime overlay to dstRoot: a copy of
+// the profile with the project's overrides layered on top and its jars added
+// to spark.jars. Overrides for files the profile does not have are ignored.
+func (pm *ProfileManager) ApplyProjectOverlay(profile, dstRoot string, overrides *generator.ProfileOverride, jars []string) error {
+	if err := pm.writeOverlay(profile, dstRoot); err != nil {
+		return err
+	}
+
+	sections := map[string]map[string]interface{}{}
+	if overrides != nil {
+		sections["hive"] = overrides.Hive
+		sections["spark"] = overrides.Spark
+		if h := overrides.Hadoop; h != nil {
+			sections["core-site"] = h.CoreSite
+			sections["hdfs-site"] = h.HDFSSite
+			sections["yarn-site"] = h.YarnSite
+			sections["mapred-site"] = h.MapredSite
+			sections["capacity-scheduler"] = h.CapacityScheduler
+		}
+	}
+
+	for section, props := range sections {
+		if len(props) == 0 {
+			continue
+		}
+		values := make(map[string]string, len(props))
+		for name, value := range props {
+			values[name] = fmt.Sprint(value)
+		}
+
+		paths := []string{filepath.Join(dstRoot, SettingTarget{File: section}.Path())}
+		if section == "hive" {
+			paths = append(paths, filepath.Join(dstRoot, "spark", "hive-site.xml"))
+		}
+		for _, path := range paths {
+			if err := setConfProperties(path, values); err != nil {
+				return err
+			}
+		}
+	}
+
+	return addSparkJars(dstRoot, jars, false)
+}
+
+// addSparkJars appends jars to spark.jars in dstRoot's spark-defaults.conf
+// and, with driverClassPath, to spark.driver.extraClassPath so classes the
+// driver needs at startup (such as JDBC drivers) are found.
+func addSparkJars(dstRoot string, jars []string, driverClassPath bool) error {
+	sparkConf := filepath.Join(dstRoot, "spark", "spark-defaults.conf")
+	if len(jars) == 0 || !util.FileExists(sparkConf) {
+		return nil
+	}
+	props, err := readConfigProperties(sparkConf)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]string{}
+	join := func(name, sep string) {
+		var all []string
+		for _, p := range props {
+			if p.Name == name && p.Value != "" {
+				all = strings.Split(p.Value, sep)
+			}
+		}
+		for _, jar := range jars {
+			if !containsString(all, jar) {
+				all = append(all, jar)
+			}
+		}
+		values[name] = strings.Join(all, sep)
+	}
+	join("spark.jars", ",")
+	if driverClassPath {
+		join("spark.driver.extraClassPath", string(os.PathListSeparator))
+	}
+	return setConfProperties(sparkConf, values)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// setConfProperties sets properties in a Hadoop XML or spark-defaults.conf
+// file, keeping other properties. Missing files are skipped.
+func setConfProperties(path string, values map[string]string) error {
+	if !util.FileExists(path) {
+		return nil
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if strings.HasSuffix(path, ".xml") {
+		cfg, err := util.ParseHadoopXML(path)
+		if err != nil {
+			return fmt.Errorf("failed parsing %s: %w", path, err)
+		}
+		for _, name := range names {
+			cfg.SetProperty(name, values[name])
+		}
+		if err := cfg.WriteXML(path); err != nil {
+			return fmt.Errorf("failed writing %s: %w", path, err)
+		}
+		return nil
+	}
+
+	props, err := readConfigProperties(path)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		replaced := false
+		for i := range props {
+			if props[i].Name == name {
+				props[i].Value = values[name]
+				replaced = true
+			}
+		}
+		if !replaced {
+			props = append(props, util.HadoopProperty{Name: name, Value: values[name]})
+		}
+	}
+
+	schemaProps := make([]schema.Property, len(props))
+	for i, p := range props {
+		schemaProps[i] = schema.Property{Name: p.Name, Value: p.Value}
+	}
+	return generator.WriteSparkConf(schemaProps, path)
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/config/generator"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+func TestApplyProjectOverlay(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	pm := NewProfileManager(paths)
+
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := pm.Set("local"); err != nil {
+		t.Fatalf("set local: %v", err)
+	}
+
+	dst := paths.ProjectConfDir("demo-0000")
+	overrides := &generator.ProfileOverride{
+		Hive:  map[string]interface{}{"hive.exec.dynamic.partition.mode": "nonstrict"},
+		Spark: map[string]interface{}{"spark.sql.shuffle.partitions": 8},
+	}
+	jars := []string{"/opt/jars/a.jar", "/opt/jars/b.jar"}
+	if err := pm.ApplyProjectOverlay("local", dst, overrides, jars); err != nil {
+		t.Fatalf("apply project overlay: %v", err)
+	}
+
+	for _, path := range []string{
+		filepath.Join(dst, "hive", "hive-site.xml"),
+		filepath.Join(dst, "spark", "hive-site.xml"),
+	} {
+		cfg, err := util.ParseHadoopXML(path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		if got := cfg.GetProperty("hive.exec.dynamic.partition.mode"); got != "nonstrict" {
+			t.Fatalf("%s dynamic.partition.mode = %q", path, got)
+		}
+	}
+
+	props, err := readConfigProperties(filepath.Join(dst, "spark", "spark-defaults.conf"))
+	if err != nil {
+		t.Fatalf("read spark-defaults: %v", err)
+	}
+	values := map[string]string{}
+	for _, p := range props {
+		values[p.Name] = p.Value
+	}
+	if values["spark.sql.shuffle.partitions"] != "8" {
+		t.Fatalf("spark.sql.shuffle.partitions = %q", values["spark.sql.shuffle.partitions"])
+	}
+	if values["spark.jars"] != "/opt/jars/a.jar,/opt/jars/b.jar" {
+		t.Fatalf("spark.jars = %q", values["spark.jars"])
+	}
+
+	// The shared overlay used by services is untouched.
+	cfg, err := util.ParseHadoopXML(filepath.Join(paths.CurrentConfDir(), "hive", "hive-site.xml"))
+	if err != nil {
+		t.Fatalf("parse current hive-site: %v", err)
+	}
+	if got := cfg.GetProperty("hive.exec.dynamic.partition.mode"); got == "nonstrict" {
+		t.Fatal("project override leaked into the shared overlay")
+	}
+}
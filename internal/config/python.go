@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// pythonArchiveAlias is the directory YARN unpacks a profile's packed
+// virtualenv into, relative to each container's working directory.
+const pythonArchiveAlias = "environment"
+
+// PythonEnv is the Python interpreter PySpark uses in one profile.
+type PythonEnv struct {
+	Python       string `json:"python"`                  // PYSPARK_PYTHON
+	DriverPython string `json:"driver-python,omitempty"` // PYSPARK_DRIVER_PYTHON; Python when empty
+	Archive      string `json:"archive,omitempty"`       // packed virtualenv shipped to YARN containers
+}
+
+// Driver returns the interpreter of the PySpark driver.
+func (e PythonEnv) Driver() string {
+	if e.DriverPython != "" {
+		return e.DriverPython
+	}
+	return e.Python
+}
+
+// VenvPython returns the interpreter of the virtualenv at dir.
+func VenvPython(dir string) string {
+	return filepath.Join(dir, "bin", "python")
+}
+
+// PythonEnvFor returns the Python environment configured for profile.
+func (s *Settings) PythonEnvFor(profile string) (PythonEnv, bool) {
+	env, ok := s.Python[profile]
+	return env, ok
+}
+
+// SetPythonEnv sets the Python environment of profile.
+func (s *Settings) SetPythonEnv(profile string, env PythonEnv) {
+	if s.Python == nil {
+		s.Python = map[string]PythonEnv{}
+	}
+	s.Python[profile] = env
+}
+
+// RemovePythonEnv removes the Python environment of profile and returns it.
+func (s *Settings) RemovePythonEnv(profile string) (PythonEnv, error) {
+	env, ok := s.Python[profile]
+	if !ok {
+		return PythonEnv{}, fmt.Errorf("no python environment for profile %q (see: local-data python list)", profile)
+	}
+	delete(s.Python, profile)
+	return env, nil
+}
+
+// HasHadoop reports whether profile includes Hadoop configs, i.e. can
+// submit Spark applications to YARN.
+func (pm *ProfileManager) HasHadoop(profile string) bool {
+	return util.DirExists(filepath.Join(pm.paths.UserProfilesDir(), profile, "hadoop"))
+}
+
+// addPythonArchive ships a profile's packed virtualenv with Spark
+// applications submitted to YARN (spark.yarn.dist.archives) and points the
+// application master, which runs the driver in cluster mode, at its
+// interpreter. Other masters ignore these properties.
+func addPythonArchive(dstRoot, archive string) error {
+	sparkConf := filepath.Join(dstRoot, "spark", "spark-defaults.conf")
+	if archive == "" || !util.FileExists(sparkConf) {
+		return nil
+	}
+	props, err := readConfigProperties(sparkConf)
+	if err != nil {
+		return err
+	}
+
+	entry := archive + "#" + pythonArchiveAlias
+	var archives []string
+	for _, p := range props {
+		if p.Name == "spark.yarn.dist.archives" && p.Value != "" {
+			archives = strings.Split(p.Value, ",")
+		}
+	}
+	if !containsString(archives, entry) {
+		archives = append(archives, entry)
+	}
+
+	python := "./" + pythonArchiveAlias + "/bin/python"
+	return setConfProperties(sparkConf, map[string]string{
+		"spark.yarn.dist.archives":                      strings.Join(archives, ","),
+		"spark.yarn.appMasterEnv.PYSPARK_PYTHON":        python,
+		"spark.yarn.appMasterEnv.PYSPARK_DRIVER_PYTHON": python,
+	})
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestApply_ShipsPythonArchive(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	pm := NewProfileManager(paths)
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if !pm.HasHadoop("hdfs") || pm.HasHadoop("local") {
+		t.Fatalf("HasHadoop(hdfs) = %v, HasHadoop(local) = %v", pm.HasHadoop("hdfs"), pm.HasHadoop("local"))
+	}
+
+	sm := NewSettingsManager(paths)
+	settings, err := sm.LoadOrDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	venvPython := VenvPython(paths.PythonEnvDir("hdfs"))
+	settings.SetPythonEnv("hdfs", PythonEnv{Python: venvPython, Archive: paths.PythonArchive("hdfs")})
+	settings.SetPythonEnv("local", PythonEnv{Python: "python3.11"})
+	if err := sm.Save(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	sparkProps := func(profile string) map[string]string {
+		t.Helper()
+		if err := pm.Apply(profile); err != nil {
+			t.Fatalf("apply %s: %v", profile, err)
+		}
+		props, err := readConfigProperties(filepath.Join(paths.CurrentConfDir(), "spark", "spark-defaults.conf"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]string{}
+		for _, p := range props {
+			got[p.Name] = p.Value
+		}
+		return got
+	}
+
+	got := sparkProps("hdfs")
+	want := map[string]string{
+		"spark.yarn.dist.archives":                      filepath.Join(tmpDir, "base", "python", "hdfs.tar.gz") + "#environment",
+		"spark.yarn.appMasterEnv.PYSPARK_PYTHON":        "./environment/bin/python",
+		"spark.yarn.appMasterEnv.PYSPARK_DRIVER_PYTHON": "./environment/bin/python",
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("hdfs %s = %q, want %q", name, got[name], value)
+		}
+	}
+
+	// Profiles without an archive get no YARN properties
+	if got := sparkProps("local"); got["spark.yarn.dist.archives"] != "" {
+		t.Errorf("local spark.yarn.dist.archives = %q", got["spark.yarn.dist.archives"])
+	}
+
+	env, err := settings.RemovePythonEnv("hdfs")
+	if err != nil || env.Driver() != venvPython {
+		t.Errorf("RemovePythonEnv(hdfs) = %+v, %v", env, err)
+	}
+	if _, err := settings.RemovePythonEnv("hdfs"); err == nil {
+		t.Error("RemovePythonEnv(hdfs) again: expected error")
+	}
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// ConfigChange is a property that differs between the generated profile
+// files on disk and what the current settings produce.
+type ConfigChange struct {
+	File     string // relative to the profiles directory, e.g. hdfs/hive/hive-site.xml
+	Property string
+	Old      string // empty when the property is added
+	New      string // empty when the property is removed
+}
+
+// Secret reports whether the values of the change must not be displayed.
+func (c ConfigChange) Secret() bool {
+	return c.Property == passwordProperty
+}
+
+// Regenerate re-runs the generator for every built-in profile from settings
+// and overrides.yaml, saves settings and reapplies the runtime overlay, so
+// generated config cannot drift from settings. User-defined profiles and
+// files the generator does not produce are left untouched.
+//
+// With dryRun nothing is written and the returned changes describe what
+// would change. Before 'local-data init' only the settings are saved.
+func (pm *ProfileManager) Regenerate(settings *Settings, dryRun bool) ([]ConfigChange, error) {
+	sm := NewSettingsManager(pm.paths)
+	dst := pm.paths.UserProfilesDir()
+
+	if !util.DirExists(dst) {
+		if dryRun {
+			return nil, nil
+		}
+		return nil, sm.Save(settings)
+	}
+
+	if err := util.MkdirAll(pm.paths.ConfRootDir()); err != nil {
+		return nil, err
+	}
+	staging, err := os.MkdirTemp(pm.paths.ConfRootDir(), ".profiles-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := pm.generate(settings, staging, !dryRun); err != nil {
+		return nil, err
+	}
+
+	changes, err := diffProfiles(dst, staging)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return changes, nil
+	}
+
+	if err := util.CopyDir(staging, dst); err != nil {
+		return nil, fmt.Errorf("failed to update profiles: %w", err)
+	}
+	if err := sm.Save(settings); err != nil {
+		return nil, fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	if util.DirExists(pm.paths.CurrentConfDir()) {
+		if err := pm.Apply(""); err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+// diffProfiles compares every generated file under newRoot with its
+// counterpart under oldRoot.
+func diffProfiles(oldRoot, newRoot string) ([]ConfigChange, error) {
+	var files []string
+	err := filepath.Walk(newRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			rel, err := filepath.Rel(newRoot, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list generated files: %w", err)
+	}
+	sort.Strings(files)
+
+	var changes []ConfigChange
+	for _, rel := range files {
+		newProps, err := readConfigProperties(filepath.Join(newRoot, rel))
+		if err != nil {
+			return nil, err
+		}
+		oldProps, err := readConfigProperties(filepath.Join(oldRoot, rel))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		changes = append(changes, diffProperties(rel, oldProps, newProps)...)
+	}
+	return changes, nil
+}
+
+func diffProperties(file string, oldProps, newProps []util.HadoopProperty) []ConfigChange {
+	oldValues := make(map[string]string, len(oldProps))
+	for _, p := range oldProps {
+		oldValues[p.Name] = p.Value
+	}
+
+	var changes []ConfigChange
+	seen := make(map[string]bool, len(newProps))
+	for _, p := range newProps {
+		seen[p.Name] = true
+		old, ok := oldValues[p.Name]
+		if ok && old == p.Value {
+			continue
+		}
+		changes = append(changes, ConfigChange{File: file, Property: p.Name, Old: old, New: p.Value})
+	}
+	for _, p := range oldProps {
+		if !seen[p.Name] {
+			changes = append(changes, ConfigChange{File: file, Property: p.Name, Old: p.Value})
+		}
+	}
+	return changes
+}
+
+// readConfigProperties reads a Hadoop XML file or a spark-defaults.conf file
+// as an ordered property list.
+func readConfigProperties(path string) ([]util.HadoopProperty, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(path, ".xml") {
+		cfg, err := util.ParseHadoopXML(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing %s: %w", path, err)
+		}
+		return cfg.Properties, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var props []util.HadoopProperty
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, _ := strings.Cut(line, " ")
+		props = append(props, util.HadoopProperty{Name: name, Value: strings.TrimSpace(value)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading %s: %w", path, err)
+	}
+	return props, nil
+}
@@ -0,0 +1,831 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/config/generator"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+func TestRegenerate_DBURLAndPasswordAndUser(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	pm := NewProfileManager(paths)
+
+	if err := pm.Init(false, &generator.InitOptions{
+		DBType:     "postgres",
+		DBUrl:      "jdbc:postgresql://localhost:5432/metastore",
+		DBPassword: "password",
+		User:       "old-user",
+	}); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := pm.Set("hdfs"); err != nil {
+		t.Fatalf("set hdfs: %v", err)
+	}
+
+	settings, err := NewSettingsManager(paths).Load()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	settings.DBURL = "jdbc:postgresql://new-host:5432/newdb"
+	settings.DBPassword = "new-secret"
+	settings.User = "new-user"
+
+	changes, err := pm.Regenerate(settings, false)
+	if err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+	found := false
+	for _, c := range changes {
+		if c.File == filepath.Join("hdfs", "hive", "hive-site.xml") && c.Property == "javax.jdo.option.ConnectionURL" {
+			found = c.Old == "jdbc:postgresql://localhost:5432/metastore" && c.New == "jdbc:postgresql://new-host:5432/newdb"
+		}
+	}
+	if !found {
+		t.Fatalf("ConnectionURL change not reported: %+v", changes)
+	}
+
+	checkHive := func(path string) {
+		cfg, err := util.ParseHadoopXML(path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		if got := cfg.GetProperty("javax.jdo.option.ConnectionURL"); got != "jdbc:postgresql://new-host:5432/newdb" {
+			t.Fatalf("%s ConnectionURL = %q", path, got)
+		}
+		if got := cfg.GetProperty("javax.jdo.option.ConnectionPassword"); got != "new-secret" {
+			t.Fatalf("%s ConnectionPassword = %q", path, got)
+		}
+		if got := cfg.GetProperty("javax.jdo.option.ConnectionUserName"); got != "new-user" {
+			t.Fatalf("%s ConnectionUserName = %q", path, got)
+		}
+	}
+
+	checkHive(filepath.Join(paths.UserProfilesDir(), "hdfs", "hive", "hive-site.xml"))
+	checkHive(filepath.Join(paths.UserProfilesDir(), "local", "hive", "hive-site.xml"))
+	checkHive(filepath.Join(paths.CurrentHiveConf(), "hive-site.xml"))
+	checkHive(filepath.Join(paths.CurrentSparkConf(), "hive-site.xml"))
+}
+
+func TestRegenerate_PasswordReferenceIsResolvedAndRestricted(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	pm := NewProfileManager(paths)
+
+	t.Setenv("LD_TEST_METASTORE_PW", "from-env")
+	if err := pm.Init(false, &generator.InitOptions{
+		DBType:     "postgres",
+		DBUrl:      "jdbc:postgresql://localhost:5432/metastore",
+		DBPassword: "env:LD_TEST_METASTORE_PW",
+	}); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := pm.Set("local"); err != nil {
+		t.Fatalf("set local: %v", err)
+	}
+
+	settings, err := NewSettingsManager(paths).Load()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	if settings.DBPassword != "env:LD_TEST_METASTORE_PW" {
+		t.Fatalf("settings should keep the reference, got %q", settings.DBPassword)
+	}
+
+	for _, path := range []string{
+		filepath.Join(paths.UserProfilesDir(), "local", "hive", "hive-site.xml"),
+		filepath.Join(paths.CurrentHiveConf(), "hive-site.xml"),
+		filepath.Join(paths.CurrentSparkConf(), "hive-site.xml"),
+	} {
+		cfg, err := util.ParseHadoopXML(path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		if got := cfg.GetProperty("javax.jdo.option.ConnectionPassword"); got != "from-env" {
+			t.Fatalf("%s ConnectionPassword = %q", path, got)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Fatalf("%s mode = %v, want 0600", path, info.Mode().Perm())
+		}
+	}
+
+	settings.DBPassword = "env:LD_TEST_METASTORE_PW_UNSET"
+	if _, err := pm.Regenerate(settings, false); err == nil {
+		t.Fatalf("expected error for unset environment variable")
+	}
+}
+
+func TestRegenerate_JCEKSStoreUsesCredentialProvider(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	pm := NewProfileManager(paths)
+
+	// Fake `hadoop credential create ... -provider jceks://file/<path>`
+	binDir := filepath.Join(tmpDir, "bin")
+	script := "#!/bin/sh\nfor last; do :; done\nprintf keystore > \"${last#jceks://file}\"\n"
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "hadoop"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if err := pm.Init(false, &generator.InitOptions{
+		DBType:     "postgres",
+		DBUrl:      "jdbc:postgresql://localhost:5432/metastore",
+		DBPassword: "secret",
+	}); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := pm.Set("local"); err != nil {
+		t.Fatalf("set local: %v", err)
+	}
+
+	settings, err := NewSettingsManager(paths).Load()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	settings.DBPasswordStore = PasswordStoreJCEKS
+	if _, err := pm.Regenerate(settings, false); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+
+	cfg, err := util.ParseHadoopXML(filepath.Join(paths.CurrentHiveConf(), "hive-site.xml"))
+	if err != nil {
+		t.Fatalf("parse hive-site: %v", err)
+	}
+	if got := cfg.GetProperty("javax.jdo.option.ConnectionPassword"); got != "" {
+		t.Fatalf("cleartext password should be removed, got %q", got)
+	}
+	provider := cfg.GetProperty("hadoop.security.credential.provider.path")
+	if !strings.HasSuffix(provider, "/settings/secrets/metastore.jceks") || !strings.HasPrefix(provider, "jceks://file/") {
+		t.Fatalf("provider path = %q", provider)
+	}
+	info, err := os.Stat(paths.CredentialStoreFile())
+	if err != nil {
+		t.Fatalf("credential store not written: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("credential store mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestRegenerate_PreservesOverridesAndUserProfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	pm := NewProfileManager(paths)
+
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := pm.Set("hdfs"); err != nil {
+		t.Fatalf("set hdfs: %v", err)
+	}
+
+	overrides := "profiles:\n  hdfs:\n    hive:\n      hive.exec.parallel: \"true\"\n"
+	if err := os.WriteFile(filepath.Join(paths.ConfRootDir(), "overrides.yaml"), []byte(overrides), 0644); err != nil {
+		t.Fatal(err)
+	}
+	custom := filepath.Join(paths.UserProfilesDir(), "custom", "hive", "hive-site.xml")
+	if err := os.MkdirAll(filepath.Dir(custom), 0755); err != nil {
+		t.Fatal(err)
+	}
+	customXML := "<configuration><property><name>x</name><value>1</value></property></configuration>\n"
+	if err := os.WriteFile(custom, []byte(customXML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	extraFile := filepath.Join(paths.UserProfilesDir(), "hdfs", "hive", "hive-log4j2.properties")
+	if err := os.WriteFile(extraFile, []byte("status = error\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := NewSettingsManager(paths).LoadOrDefault()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	settings.HS2Port = 10001
+
+	// A dry run reports changes without touching files or settings
+	changes, err := pm.Regenerate(settings, true)
+	if err != nil {
+		t.Fatalf("regenerate dry run: %v", err)
+	}
+	if len(changes) == 0 {
+		t.Fatalf("expected changes from dry run")
+	}
+	cfg, err := util.ParseHadoopXML(filepath.Join(paths.CurrentHiveConf(), "hive-site.xml"))
+	if err != nil {
+		t.Fatalf("parse hive-site: %v", err)
+	}
+	if got := cfg.GetProperty("hive.server2.thrift.port"); got != "10000" {
+		t.Fatalf("dry run modified hive-site: port = %q", got)
+	}
+
+	if _, err := pm.Regenerate(settings, false); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+
+	cfg, err = util.ParseHadoopXML(filepath.Join(paths.CurrentHiveConf(), "hive-site.xml"))
+	if err != nil {
+		t.Fatalf("parse hive-site: %v", err)
+	}
+	if got := cfg.GetProperty("hive.server2.thrift.port"); got != "10001" {
+		t.Fatalf("hive.server2.thrift.port = %q", got)
+	}
+	if got := cfg.GetProperty("hive.exec.parallel"); got != "true" {
+		t.Fatalf("overrides.yaml not applied: hive.exec.parallel = %q", got)
+	}
+	if data, err := os.ReadFile(custom); err != nil || string(data) != customXML {
+		t.Fatalf("user-defined profile modified: %q, %v", data, err)
+	}
+	if !util.FileExists(extraFile) {
+		t.Fatalf("non-generated profile file removed")
+	}
+	if !util.FileExists(filepath.Join(paths.CurrentHiveConf(), "hive-log4j2.properties")) {
+		t.Fatalf("overlay not reapplied")
+	}
+}
+
+func TestRegenerate_BeforeInitSavesSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	pm := NewProfileManager(paths)
+
+	settings := defaultSettings(paths.BaseDir)
+	settings.HS2Port = 10001
+	changes, err := pm.Regenerate(settings, false)
+	if err != nil {
+		t.Fatalf("expected no error when profiles are missing: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("changes = %+v", changes)
+	}
+	saved, err := NewSettingsManager(paths).Load()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	if saved.HS2Port != 10001 {
+		t.Fatalf("HS2Port = %d", saved.HS2Port)
+	}
+	if pm.IsInitialized() {
+		t.Fatalf("regenerate should not initialize profiles")
+	}
+}
+
+func TestRegenerate_UserStaysAPPForDerby(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	pm := NewProfileManager(paths)
+
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := pm.Set("hdfs"); err != nil {
+		t.Fatalf("set hdfs: %v", err)
+	}
+
+	settings, err := NewSettingsManager(paths).Load()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	settings.User = "new-user"
+	if _, err := pm.Regenerate(settings, false); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+
+	cfg, err := util.ParseHadoopXML(filepath.Join(paths.CurrentHiveConf(), "hive-site.xml"))
+	if err != nil {
+		t.Fatalf("parse hive-site: %v", err)
+	}
+	if got := cfg.GetProperty("javax.jdo.option.ConnectionUserName"); got != "APP" {
+		t.Fatalf("ConnectionUserName = %q, want APP", got)
+	}
+}
+
+func TestRegenerate_DBTypeUpdatesDriverAndURL(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	pm := NewProfileManager(paths)
+
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := pm.Set("hdfs"); err != nil {
+		t.Fatalf("set hdfs: %v", err)
+	}
+
+	if _, err := pm.Regenerate(&Settings{
+		User:       "daniel",
+		DBType:     "postgres",
+		DBURL:      "jdbc:postgresql://localhost:5432/metastore",
+		DBPassword: "password",
+	}, false); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+
+	checkHive := func(path string) {
+		cfg, err := util.ParseHadoopXML(path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		if got := cfg.GetProperty("javax.jdo.option.ConnectionDriverName"); got != "org.postgresql.Driver" {
+			t.Fatalf("%s ConnectionDriverName = %q", path, got)
+		}
+		if got := cfg.GetProperty("javax.jdo.option.ConnectionURL"); got != "jdbc:postgresql://localhost:5432/metastore" {
+			t.Fatalf("%s ConnectionURL = %q", path, got)
+		}
+	}
+
+	checkHive(filepath.Join(paths.UserProfilesDir(), "hdfs", "hive", "hive-site.xml"))
+	checkHive(filepath.Join(paths.UserProfilesDir(), "local", "hive", "hive-site.xml"))
+	checkHive(filepath.Join(paths.CurrentHiveConf(), "hive-site.xml"))
+	checkHive(filepath.Join(paths.CurrentSparkConf(), "hive-site.xml"))
+}
+
+func TestRegenerate_DeclarativeSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+
+	// Settings present at init time are rendered into every profile
+	sm := NewSettingsManager(paths)
+	if err := sm.Save(&Settings{HS2Port: 10001, YarnMemoryMB: 4096}); err != nil {
+		t.Fatalf("save settings: %v", err)
+	}
+	pm := NewProfileManager(paths)
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := pm.Set("hdfs"); err != nil {
+		t.Fatalf("set hdfs: %v", err)
+	}
+
+	hiveSite := filepath.Join(paths.UserProfilesDir(), "local", "hive", "hive-site.xml")
+	data, err := os.ReadFile(hiveSite)
+	if err != nil {
+		t.Fatalf("read hive-site: %v", err)
+	}
+	if n := strings.Count(string(data), "<name>hive.server2.thrift.port</name>"); n != 1 {
+		t.Fatalf("hive.server2.thrift.port appears %d times", n)
+	}
+	cfg, err := util.ParseHadoopXML(hiveSite)
+	if err != nil {
+		t.Fatalf("parse hive-site: %v", err)
+	}
+	if got := cfg.GetProperty("hive.server2.thrift.port"); got != "10001" {
+		t.Fatalf("hive.server2.thrift.port = %q", got)
+	}
+	yarnSite, err := util.ParseHadoopXML(filepath.Join(paths.CurrentHadoopConf(), "yarn-site.xml"))
+	if err != nil {
+		t.Fatalf("parse yarn-site: %v", err)
+	}
+	if got := yarnSite.GetProperty("yarn.nodemanager.resource.memory-mb"); got != "4096" {
+		t.Fatalf("yarn.nodemanager.resource.memory-mb = %q", got)
+	}
+
+	// Later changes regenerate existing profiles
+	settings, err := sm.Load()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	settings.SparkDriverMemory = "2g"
+	if _, err := pm.Regenerate(settings, false); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+	for _, path := range []string{
+		filepath.Join(paths.CurrentSparkConf(), "spark-defaults.conf"),
+		filepath.Join(paths.UserProfilesDir(), "local", "spark", "spark-defaults.conf"),
+	} {
+		props, err := readConfigProperties(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		for _, p := range props {
+			if p.Name == "spark.driver.memory" && p.Value != "2g" {
+				t.Fatalf("%s spark.driver.memory = %q", path, p.Value)
+			}
+		}
+	}
+}
+
+func TestRegenerate_S3Settings(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+
+	pm := NewProfileManager(paths)
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	sm := NewSettingsManager(paths)
+	settings, err := sm.LoadOrDefault()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	settings.S3Enabled = true
+	settings.S3Port = 9999
+	if _, err := pm.Regenerate(settings, false); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+
+	coreSite, err := util.ParseHadoopXML(filepath.Join(paths.UserProfilesDir(), "hdfs", "hadoop", "core-site.xml"))
+	if err != nil {
+		t.Fatalf("parse core-site: %v", err)
+	}
+	if got := coreSite.GetProperty("fs.s3a.endpoint"); got != "http://127.0.0.1:9999" {
+		t.Errorf("core-site fs.s3a.endpoint = %q", got)
+	}
+	hiveSite, err := util.ParseHadoopXML(filepath.Join(paths.UserProfilesDir(), "local", "hive", "hive-site.xml"))
+	if err != nil {
+		t.Fatalf("parse hive-site: %v", err)
+	}
+	if got := hiveSite.GetProperty("fs.s3a.path.style.access"); got != "true" {
+		t.Errorf("hive-site fs.s3a.path.style.access = %q", got)
+	}
+	sparkConf := filepath.Join(paths.UserProfilesDir(), "local", "spark", "spark-defaults.conf")
+	props, err := readConfigProperties(sparkConf)
+	if err != nil {
+		t.Fatalf("read spark-defaults: %v", err)
+	}
+	found := false
+	for _, p := range props {
+		if p.Name == "spark.hadoop.fs.s3.impl" {
+			found = p.Value == "org.apache.hadoop.fs.s3a.S3AFileSystem"
+		}
+	}
+	if !found {
+		t.Errorf("spark.hadoop.fs.s3.impl not set in %s", sparkConf)
+	}
+
+	// Turning s3 off removes the properties again
+	settings.S3Enabled = false
+	if _, err := pm.Regenerate(settings, false); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+	hiveSite, err = util.ParseHadoopXML(filepath.Join(paths.UserProfilesDir(), "local", "hive", "hive-site.xml"))
+	if err != nil {
+		t.Fatalf("parse hive-site: %v", err)
+	}
+	if got := hiveSite.GetProperty("fs.s3a.endpoint"); got != "" {
+		t.Errorf("fs.s3a.endpoint = %q after disabling s3", got)
+	}
+}
+
+func TestRegenerate_IcebergSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("SPARK_HOME", filepath.Join(tmpDir, "spark"))
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+
+	pm := NewProfileManager(paths)
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	jar := filepath.Join(paths.JarsDir(), "iceberg-spark-runtime-3.5_2.12-1.5.2.jar")
+	if err := os.MkdirAll(paths.JarsDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(jar, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := NewSettingsManager(paths).LoadOrDefault()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	settings.IcebergEnabled = true
+	if _, err := pm.Regenerate(settings, false); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+
+	sparkProps := func(profile string) map[string]string {
+		t.Helper()
+		props, err := readConfigProperties(filepath.Join(paths.UserProfilesDir(), profile, "spark", "spark-defaults.conf"))
+		if err != nil {
+			t.Fatalf("read spark-defaults: %v", err)
+		}
+		m := map[string]string{}
+		for _, p := range props {
+			m[p.Name] = p.Value
+		}
+		return m
+	}
+
+	props := sparkProps("local")
+	want := map[string]string{
+		"spark.sql.extensions":                 "org.apache.iceberg.spark.extensions.IcebergSparkSessionExtensions",
+		"spark.sql.catalog.spark_catalog":      "org.apache.iceberg.spark.SparkSessionCatalog",
+		"spark.sql.catalog.spark_catalog.type": "hive",
+		"spark.jars":                           jar,
+	}
+	for name, value := range want {
+		if props[name] != value {
+			t.Errorf("%s = %q, want %q", name, props[name], value)
+		}
+	}
+
+	// A named hadoop catalog defaults to Spark's warehouse
+	settings.IcebergCatalog = "lake"
+	settings.IcebergCatalogType = "hadoop"
+	if _, err := pm.Regenerate(settings, false); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+	props = sparkProps("hdfs")
+	if got := props["spark.sql.catalog.lake"]; got != "org.apache.iceberg.spark.SparkCatalog" {
+		t.Errorf("spark.sql.catalog.lake = %q", got)
+	}
+	if got := props["spark.sql.catalog.lake.warehouse"]; got != props["spark.sql.warehouse.dir"] || got == "" {
+		t.Errorf("spark.sql.catalog.lake.warehouse = %q, want %q", got, props["spark.sql.warehouse.dir"])
+	}
+	if _, ok := props["spark.sql.catalog.spark_catalog"]; ok {
+		t.Error("spark_catalog should not be configured for a named catalog")
+	}
+}
+
+func TestSparkRuntime_Iceberg(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	paths := NewPaths("", filepath.Join(tmpDir, "base"))
+	touch := func(dir, name string) string {
+		t.Helper()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	sparkHome := filepath.Join(tmpDir, "spark")
+	touch(filepath.Join(sparkHome, "jars"), "spark-core_2.12-3.5.1.jar")
+	if build, ok := DetectSparkBuild(sparkHome); !ok || build != (SparkBuild{Version: "3.5", Scala: "2.12"}) {
+		t.Fatalf("DetectSparkBuild() = %+v, %v", build, ok)
+	}
+
+	// Builds for other Spark or Scala versions are ignored
+	touch(paths.JarsDir(), "iceberg-spark-runtime-3.4_2.12-1.5.2.jar")
+	touch(paths.JarsDir(), "iceberg-spark-runtime-3.5_2.13-1.5.2.jar")
+	want := touch(filepath.Join(tmpDir, ".ivy2", "jars"), "iceberg-spark-runtime-3.5_2.12-1.5.0.jar")
+	settings := &Settings{IcebergEnabled: true}
+	rt := settings.SparkRuntime(paths, sparkHome)
+	if !rt.Iceberg || len(rt.Jars) != 1 || rt.Jars[0] != want || len(rt.Warnings) != 0 {
+		t.Errorf("SparkRuntime() = %+v, want %s", rt, want)
+	}
+
+	// Two matching builds are ambiguous without iceberg-jar
+	touch(paths.JarsDir(), "iceberg-spark-runtime-3.5_2.12-1.5.2.jar")
+	if rt := settings.SparkRuntime(paths, sparkHome); rt.Iceberg || len(rt.Jars) != 0 || len(rt.Warnings) != 1 {
+		t.Errorf("ambiguous: SparkRuntime() = %+v", rt)
+	}
+	settings.IcebergJar = want
+	if rt := settings.SparkRuntime(paths, sparkHome); !rt.Iceberg || len(rt.Jars) != 1 || rt.Jars[0] != want {
+		t.Errorf("with iceberg-jar: SparkRuntime() = %+v", rt)
+	}
+	settings.IcebergJar = ""
+
+	// A jar in the given Spark home needs no spark.jars entry, whatever
+	// $SPARK_HOME of the calling shell is
+	t.Setenv("SPARK_HOME", filepath.Join(tmpDir, "other"))
+	touch(filepath.Join(sparkHome, "jars"), "iceberg-spark-runtime-3.5_2.12-1.4.3.jar")
+	if rt := settings.SparkRuntime(paths, sparkHome); !rt.Iceberg || len(rt.Jars) != 0 {
+		t.Errorf("in Spark home: SparkRuntime() = %+v", rt)
+	}
+}
+
+func TestRegenerate_IcebergJarMissing(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("SPARK_HOME", filepath.Join(tmpDir, "spark"))
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+
+	pm := NewProfileManager(paths)
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	settings, err := NewSettingsManager(paths).LoadOrDefault()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	settings.IcebergEnabled = true
+	if _, err := pm.Regenerate(settings, false); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+
+	props, err := readConfigProperties(filepath.Join(paths.UserProfilesDir(), "local", "spark", "spark-defaults.conf"))
+	if err != nil {
+		t.Fatalf("read spark-defaults: %v", err)
+	}
+	for _, p := range props {
+		if strings.HasPrefix(p.Name, "spark.sql.catalog.spark_catalog") || p.Name == "spark.sql.extensions" {
+			t.Errorf("%s = %q generated without the Iceberg jar", p.Name, p.Value)
+		}
+	}
+}
+
+func TestRegenerate_DeltaSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("SPARK_HOME", filepath.Join(tmpDir, "spark"))
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+
+	pm := NewProfileManager(paths)
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := os.MkdirAll(paths.JarsDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	var jars []string
+	for _, name := range []string{"delta-spark_2.12-3.2.0.jar", "delta-storage-3.2.0.jar", "iceberg-spark-runtime-3.5_2.12-1.5.2.jar"} {
+		jar := filepath.Join(paths.JarsDir(), name)
+		if err := os.WriteFile(jar, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		jars = append(jars, jar)
+	}
+
+	settings, err := NewSettingsManager(paths).LoadOrDefault()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	settings.DeltaEnabled = true
+	settings.IcebergEnabled = true
+	if err := settings.ValidateTableFormats(); err == nil {
+		t.Error("delta and iceberg on spark_catalog should conflict")
+	}
+	settings.IcebergCatalog = "iceberg"
+	if err := settings.ValidateTableFormats(); err != nil {
+		t.Fatalf("ValidateTableFormats: %v", err)
+	}
+	if _, err := pm.Regenerate(settings, false); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+
+	props, err := readConfigProperties(filepath.Join(paths.UserProfilesDir(), "local", "spark", "spark-defaults.conf"))
+	if err != nil {
+		t.Fatalf("read spark-defaults: %v", err)
+	}
+	got := map[string]string{}
+	for _, p := range props {
+		got[p.Name] = p.Value
+	}
+	want := map[string]string{
+		"spark.sql.extensions":            "org.apache.iceberg.spark.extensions.IcebergSparkSessionExtensions,io.delta.sql.DeltaSparkSessionExtension",
+		"spark.sql.catalog.spark_catalog": "org.apache.spark.sql.delta.catalog.DeltaCatalog",
+		"spark.sql.catalog.iceberg":       "org.apache.iceberg.spark.SparkCatalog",
+		"spark.sql.catalogImplementation": "hive",
+		"spark.jars":                      jars[2] + "," + jars[0] + "," + jars[1],
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %q, want %q", name, got[name], value)
+		}
+	}
+}
+
+func TestSparkRuntime_Delta(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	touch := func(dir, name string, age time.Duration) string {
+		t.Helper()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := time.Now().Add(-age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	sparkHome := filepath.Join(tmpDir, "spark")
+	touch(filepath.Join(sparkHome, "jars"), "spark-core_2.12-3.5.1.jar", 0)
+
+	// delta-storage follows the chosen delta-spark, not the newest file;
+	// delta-spark for another Scala version is ignored
+	touch(paths.JarsDir(), "delta-spark_2.13-3.3.0.jar", 0)
+	deltaSpark := touch(paths.JarsDir(), "delta-spark_2.12-3.2.0.jar", time.Hour)
+	deltaStorage := touch(paths.JarsDir(), "delta-storage-3.2.0.jar", 2*time.Hour)
+	touch(paths.JarsDir(), "delta-storage-3.3.0.jar", 0)
+	settings := &Settings{DeltaEnabled: true}
+	rt := settings.SparkRuntime(paths, sparkHome)
+	if !rt.Delta || len(rt.Jars) != 2 || rt.Jars[0] != deltaSpark || rt.Jars[1] != deltaStorage || len(rt.Warnings) != 0 {
+		t.Errorf("SparkRuntime() = %+v, want %s and %s", rt, deltaSpark, deltaStorage)
+	}
+
+	// Without delta-storage of the same version Delta is not configured
+	if err := os.Remove(deltaStorage); err != nil {
+		t.Fatal(err)
+	}
+	if rt := settings.SparkRuntime(paths, sparkHome); rt.Delta || len(rt.Jars) != 0 || len(rt.Warnings) != 1 {
+		t.Errorf("missing delta-storage: SparkRuntime() = %+v", rt)
+	}
+}
+
+func TestRegenerate_DeltaJarMissing(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("SPARK_HOME", filepath.Join(tmpDir, "spark"))
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+
+	pm := NewProfileManager(paths)
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	settings, err := NewSettingsManager(paths).LoadOrDefault()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	settings.DeltaEnabled = true
+	if _, err := pm.Regenerate(settings, false); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+
+	props, err := readConfigProperties(filepath.Join(paths.UserProfilesDir(), "local", "spark", "spark-defaults.conf"))
+	if err != nil {
+		t.Fatalf("read spark-defaults: %v", err)
+	}
+	for _, p := range props {
+		if p.Name == "spark.sql.catalog.spark_catalog" || p.Name == "spark.sql.extensions" {
+			t.Errorf("%s = %q generated without the Delta jars", p.Name, p.Value)
+		}
+	}
+}
+
+func TestSparkRuntime_S3A(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	hadoopHome := filepath.Join(tmpDir, "hadoop")
+	t.Setenv("HADOOP_HOME", hadoopHome)
+	paths := NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	touch := func(dir, name string) string {
+		t.Helper()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	settings := &Settings{}
+	if settings.UsesS3A() {
+		t.Error("UsesS3A() without s3 or mounts")
+	}
+	if err := settings.AddMount(Mount{URI: "gs://bucket", Target: "file:///tmp/gs"}); err != nil {
+		t.Fatal(err)
+	}
+	if settings.UsesS3A() {
+		t.Error("UsesS3A() with only a gs:// mount")
+	}
+	if err := settings.AddMount(Mount{URI: "s3://landing", Target: "file:///tmp/s3"}); err != nil {
+		t.Fatal(err)
+	}
+	if !settings.UsesS3A() {
+		t.Error("UsesS3A() with an s3:// mount = false")
+	}
+
+	// hadoop-aws must match Spark's Hadoop, not the installed Hadoop
+	sparkHome := filepath.Join(tmpDir, "spark")
+	touch(filepath.Join(sparkHome, "jars"), "hadoop-client-api-3.3.4.jar")
+	toolsLib := filepath.Join(hadoopHome, "share", "hadoop", "tools", "lib")
+	touch(toolsLib, "hadoop-aws-3.4.1.jar")
+	touch(toolsLib, "bundle-2.24.6.jar")
+	if rt := settings.SparkRuntime(paths, sparkHome); len(rt.Jars) != 0 || len(rt.Warnings) != 1 {
+		t.Errorf("mismatched hadoop-aws: SparkRuntime() = %+v", rt)
+	}
+
+	awsJar := touch(paths.JarsDir(), "hadoop-aws-3.3.4.jar")
+	bundle := touch(paths.JarsDir(), "aws-java-sdk-bundle-1.12.262.jar")
+	rt := settings.SparkRuntime(paths, sparkHome)
+	if len(rt.Jars) != 2 || rt.Jars[0] != awsJar || rt.Jars[1] != bundle || len(rt.Warnings) != 0 {
+		t.Errorf("SparkRuntime() = %+v, want %s and %s", rt, awsJar, bundle)
+	}
+
+	// Spark built with Hadoop 3.4 takes the v2 SDK bundle from tools/lib
+	if err := os.Remove(filepath.Join(sparkHome, "jars", "hadoop-client-api-3.3.4.jar")); err != nil {
+		t.Fatal(err)
+	}
+	touch(filepath.Join(sparkHome, "jars"), "hadoop-client-api-3.4.1.jar")
+	rt = settings.SparkRuntime(paths, sparkHome)
+	if len(rt.Jars) != 2 || rt.Jars[0] != filepath.Join(toolsLib, "hadoop-aws-3.4.1.jar") || rt.Jars[1] != filepath.Join(toolsLib, "bundle-2.24.6.jar") {
+		t.Errorf("Hadoop 3.4: SparkRuntime() = %+v", rt)
+	}
+}
@@ -212,12 +212,24 @@ func boolToString(b bool) string {
 	return "false"
 }
 
+// appendExtraProperties appends extra properties; an extra property with the
+// same name as a typed one replaces it in place so each name is emitted once.
 func appendExtraProperties(props []Property, extra []Property, ctx *TemplateContext) []Property {
+	index := make(map[string]int, len(props))
+	for i, p := range props {
+		index[p.Name] = i
+	}
 	for _, p := range extra {
-		props = append(props, Property{
+		prop := Property{
 			Name:  p.Name,
 			Value: ctx.Substitute(p.Value),
-		})
+		}
+		if i, ok := index[p.Name]; ok {
+			props[i] = prop
+			continue
+		}
+		index[p.Name] = len(props)
+		props = append(props, prop)
 	}
 	return props
 }
@@ -8,7 +8,8 @@ type HiveConfig struct {
 	ConnectionURL        string // javax.jdo.option.ConnectionURL
 	ConnectionDriverName string // javax.jdo.option.ConnectionDriverName
 	ConnectionUserName   string // javax.jdo.option.ConnectionUserName (templated)
-	ConnectionPassword   string // javax.jdo.option.ConnectionPassword
+	ConnectionPassword   string // javax.jdo.option.ConnectionPassword (omitted when empty)
+	CredentialProviders  string // hadoop.security.credential.provider.path (omitted when empty)
 	MetastoreURIs        string // hive.metastore.uris (for HS2 -> metastore service)
 
 	// Warehouse
@@ -44,7 +45,14 @@ func (c *HiveConfig) ToProperties(ctx *TemplateContext) []Property {
 		{Name: "javax.jdo.option.ConnectionURL", Value: c.ConnectionURL},
 		{Name: "javax.jdo.option.ConnectionDriverName", Value: c.ConnectionDriverName},
 		{Name: "javax.jdo.option.ConnectionUserName", Value: ctx.Substitute(c.ConnectionUserName)},
-		{Name: "javax.jdo.option.ConnectionPassword", Value: c.ConnectionPassword},
+	}
+	if c.ConnectionPassword != "" {
+		props = append(props, Property{Name: "javax.jdo.option.ConnectionPassword", Value: c.ConnectionPassword})
+	}
+	if c.CredentialProviders != "" {
+		props = append(props, Property{Name: "hadoop.security.credential.provider.path", Value: c.CredentialProviders})
+	}
+	props = append(props, []Property{
 		{Name: "hive.metastore.uris", Value: c.MetastoreURIs},
 
 		// Warehouse
@@ -59,6 +67,6 @@ func (c *HiveConfig) ToProperties(ctx *TemplateContext) []Property {
 		// Schema
 		{Name: "hive.metastore.schema.verification", Value: boolToString(c.SchemaVerification)},
 		{Name: "datanucleus.schema.autoCreateAll", Value: boolToString(c.AutoCreateSchema)},
-	}
+	}...)
 	return appendExtraProperties(props, c.Extra, ctx)
 }
@@ -1,6 +1,42 @@
 package schema
 
-import "strconv"
+import (
+	"strconv"
+	"strings"
+)
+
+// Iceberg catalog types and classes
+const (
+	IcebergCatalogHive   = "hive"
+	IcebergCatalogHadoop = "hadoop"
+
+	IcebergExtensions     = "org.apache.iceberg.spark.extensions.IcebergSparkSessionExtensions"
+	IcebergSparkCatalog   = "org.apache.iceberg.spark.SparkCatalog"
+	IcebergSessionCatalog = "org.apache.iceberg.spark.SparkSessionCatalog"
+
+	DeltaExtensions = "io.delta.sql.DeltaSparkSessionExtension"
+	DeltaCatalog    = "org.apache.spark.sql.delta.catalog.DeltaCatalog"
+
+	// SessionCatalogName is Spark's built-in catalog; an Iceberg catalog of
+	// this name wraps it so Hive and Iceberg tables share one namespace
+	SessionCatalogName = "spark_catalog"
+)
+
+// IcebergCatalog represents an Apache Iceberg catalog in Spark
+// (spark.sql.catalog.<Name>.*)
+type IcebergCatalog struct {
+	Name      string // catalog name
+	Type      string // hive or hadoop
+	Warehouse string // warehouse location (templated); required for hadoop
+}
+
+// CatalogClass returns the Spark catalog implementation for the catalog
+func (c *IcebergCatalog) CatalogClass() string {
+	if c.Name == SessionCatalogName {
+		return IcebergSessionCatalog
+	}
+	return IcebergSparkCatalog
+}
 
 // SparkConfig represents spark-defaults.conf properties
 type SparkConfig struct {
@@ -20,6 +56,14 @@ type SparkConfig struct {
 	CatalogImplementation string // spark.sql.catalogImplementation
 	WarehouseDir          string // spark.sql.warehouse.dir (templated)
 
+	// Extensions and catalogs
+	Extensions     []string        // spark.sql.extensions
+	SessionCatalog string          // spark.sql.catalog.spark_catalog
+	Iceberg        *IcebergCatalog // spark.sql.catalog.<name>.*
+
+	// Jars added to driver and executor classpaths
+	Jars []string // spark.jars (templated)
+
 	// Event logging
 	EventLogEnabled bool   // spark.eventLog.enabled
 	EventLogDir     string // spark.eventLog.dir
@@ -44,6 +88,12 @@ func (c *SparkConfig) Clone() *SparkConfig {
 		return nil
 	}
 	clone := *c
+	clone.Extensions = append([]string{}, c.Extensions...)
+	clone.Jars = append([]string{}, c.Jars...)
+	if c.Iceberg != nil {
+		iceberg := *c.Iceberg
+		clone.Iceberg = &iceberg
+	}
 	clone.Extra = append([]Property{}, c.Extra...)
 	return &clone
 }
@@ -84,6 +134,25 @@ func (c *SparkConfig) ToProperties(ctx *TemplateContext) []Property {
 		props = append(props, Property{Name: "spark.sql.warehouse.dir", Value: ctx.Substitute(c.WarehouseDir)})
 	}
 
+	// Extensions and catalogs
+	if len(c.Extensions) > 0 {
+		props = append(props, Property{Name: "spark.sql.extensions", Value: strings.Join(c.Extensions, ",")})
+	}
+	if c.SessionCatalog != "" && (c.Iceberg == nil || c.Iceberg.Name != SessionCatalogName) {
+		props = append(props, Property{Name: "spark.sql.catalog." + SessionCatalogName, Value: c.SessionCatalog})
+	}
+	if ic := c.Iceberg; ic != nil {
+		prefix := "spark.sql.catalog." + ic.Name
+		props = append(props, Property{Name: prefix, Value: ic.CatalogClass()})
+		props = append(props, Property{Name: prefix + ".type", Value: ic.Type})
+		if ic.Warehouse != "" {
+			props = append(props, Property{Name: prefix + ".warehouse", Value: ctx.Substitute(ic.Warehouse)})
+		}
+	}
+	if len(c.Jars) > 0 {
+		props = append(props, Property{Name: "spark.jars", Value: ctx.Substitute(strings.Join(c.Jars, ","))})
+	}
+
 	// Event logging
 	props = append(props, Property{Name: "spark.eventLog.enabled", Value: boolToString(c.EventLogEnabled)})
 	if c.EventLogEnabled && c.EventLogDir != "" {
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/secret"
+)
+
+// db-password-store values
+const (
+	// PasswordStorePlain writes the resolved password into hive-site.xml (0600).
+	PasswordStorePlain = "plain"
+	// PasswordStoreJCEKS keeps the password in a Hadoop JCEKS credential store
+	// referenced by hadoop.security.credential.provider.path.
+	PasswordStoreJCEKS = "jceks"
+)
+
+// passwordProperty is both the hive-site.xml property and the credential alias.
+const passwordProperty = "javax.jdo.option.ConnectionPassword"
+
+// NormalizePasswordStore parses and validates db-password-store values.
+func NormalizePasswordStore(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", PasswordStorePlain:
+		return PasswordStorePlain, nil
+	case PasswordStoreJCEKS:
+		return PasswordStoreJCEKS, nil
+	default:
+		return "", fmt.Errorf("unknown db-password-store %q (supported: %s, %s)", value, PasswordStorePlain, PasswordStoreJCEKS)
+	}
+}
+
+// ResolveDBPassword returns the metastore password, resolving env:/file: references.
+func (s *Settings) ResolveDBPassword() (string, error) {
+	password, err := secret.Resolve(s.DBPassword)
+	if err != nil {
+		return "", fmt.Errorf("db-password: %w", err)
+	}
+	return password, nil
+}
+
+// renderedPassword is how the metastore password appears in generated config.
+type renderedPassword struct {
+	Password     string // cleartext javax.jdo.option.ConnectionPassword (plain store)
+	ProviderPath string // hadoop.security.credential.provider.path (jceks store)
+}
+
+// renderDBPassword resolves a db-password value and, for the jceks store,
+// writes it to the credential store.
+func renderDBPassword(paths *Paths, value, store string) (*renderedPassword, error) {
+	password, err := secret.Resolve(value)
+	if err != nil {
+		return nil, fmt.Errorf("db-password: %w", err)
+	}
+
+	if store != PasswordStoreJCEKS {
+		return &renderedPassword{Password: password}, nil
+	}
+
+	path := paths.CredentialStoreFile()
+	if err := secret.StoreCredential(path, passwordProperty, password); err != nil {
+		return nil, fmt.Errorf("failed to store db-password in %s: %w", path, err)
+	}
+	return &renderedPassword{ProviderPath: secret.ProviderURI(path)}, nil
+}
+
+// previewDBPassword renders like renderDBPassword without writing the
+// credential store.
+func previewDBPassword(paths *Paths, value, store string) (*renderedPassword, error) {
+	if store == PasswordStoreJCEKS {
+		return &renderedPassword{ProviderPath: secret.ProviderURI(paths.CredentialStoreFile())}, nil
+	}
+	password, err := secret.Resolve(value)
+	if err != nil {
+		return nil, fmt.Errorf("db-password: %w", err)
+	}
+	return &renderedPassword{Password: password}, nil
+}
@@ -8,17 +8,55 @@ import (
 	"strings"
 
 	"github.com/danieljhkim/local-data-platform/internal/metastore"
+	"github.com/danieljhkim/local-data-platform/internal/secret"
 )
 
-const defaultDBPassword = "password"
-
 // Settings holds persisted user-configurable settings.
 type Settings struct {
 	User       string `json:"user"`
 	BaseDir    string `json:"base-dir"`
 	DBType     string `json:"db-type"`
 	DBURL      string `json:"db-url"`
-	DBPassword string `json:"db-password"`
+	DBPassword string `json:"db-password"` // literal, env:NAME or file:/path
+
+	// DBPasswordStore is where rendered configs keep the password: plain
+	// (hive-site.xml) or jceks (Hadoop credential store).
+	DBPasswordStore string `json:"db-password-store,omitempty"`
+
+	// DBManaged is set when local-data runs the Postgres metastore server
+	// itself (the metastore-db service) on DBManagedPort.
+	DBManaged     bool `json:"db-managed,omitempty"`
+	DBManagedPort int  `json:"db-managed-port,omitempty"`
+
+	// S3Enabled runs the local S3-compatible server (the s3 service) on
+	// S3Port and points s3a:// and s3:// paths at it.
+	S3Enabled bool `json:"s3,omitempty"`
+	S3Port    int  `json:"s3-port,omitempty"`
+
+	// IcebergEnabled adds an Iceberg catalog to the Spark config and the
+	// Iceberg runtime jar (IcebergJar, or one discovered) to spark.jars.
+	IcebergEnabled     bool   `json:"iceberg,omitempty"`
+	IcebergCatalog     string `json:"iceberg-catalog,omitempty"`
+	IcebergCatalogType string `json:"iceberg-catalog-type,omitempty"`
+	IcebergWarehouse   string `json:"iceberg-warehouse,omitempty"`
+	IcebergJar         string `json:"iceberg-jar,omitempty"`
+
+	// DeltaEnabled configures Delta Lake's extensions and spark_catalog and
+	// adds the Delta jars found in the jar directories to spark.jars.
+	DeltaEnabled bool `json:"delta,omitempty"`
+
+	// Mounts redirect cloud URIs (gs://, s3://, abfss://, ...) to local or
+	// HDFS directories; managed with 'local-data mounts'.
+	Mounts []Mount `json:"mounts,omitempty"`
+
+	// Python maps profiles to the interpreters PySpark uses there
+	// (PYSPARK_PYTHON, PYSPARK_DRIVER_PYTHON); managed with 'local-data python'.
+	Python map[string]PythonEnv `json:"python,omitempty"`
+
+	// Resource and port settings; unset (zero) keeps the profile's value.
+	SparkDriverMemory string `json:"spark-driver-memory,omitempty"`
+	YarnMemoryMB      int    `json:"yarn-memory-mb,omitempty"`
+	HS2Port           int    `json:"hs2-port,omitempty"`
 }
 
 // SettingsManager handles settings persistence.
@@ -50,7 +88,7 @@ func (sm *SettingsManager) Load() (*Settings, error) {
 	if err := sm.sanitize(&settings); err != nil {
 		return nil, err
 	}
-	// base-dir is static and derived from runtime paths.
+	// base-dir is derived from runtime paths (--base-dir / $LOCAL_DATA_BASE_DIR).
 	settings.BaseDir = sm.paths.BaseDir
 
 	return &settings, nil
@@ -61,7 +99,7 @@ func (sm *SettingsManager) Save(settings *Settings) error {
 	if settings == nil {
 		return fmt.Errorf("settings required")
 	}
-	// base-dir is static and derived from runtime paths.
+	// base-dir is derived from runtime paths (--base-dir / $LOCAL_DATA_BASE_DIR).
 	settings.BaseDir = sm.paths.BaseDir
 	if err := sm.sanitize(settings); err != nil {
 		return err
@@ -76,11 +114,16 @@ func (sm *SettingsManager) Save(settings *Settings) error {
 		return fmt.Errorf("failed to marshal settings: %w", err)
 	}
 
-	if err := os.WriteFile(sm.Path(), append(data, '\n'), 0644); err != nil {
+	// Literal passwords are secrets; env:/file: references are not
+	mode := os.FileMode(0644)
+	if !secret.IsRef(settings.DBPassword) {
+		mode = 0600
+	}
+	if err := os.WriteFile(sm.Path(), append(data, '\n'), mode); err != nil {
 		return err
 	}
 
-	return nil
+	return os.Chmod(sm.Path(), mode)
 }
 
 // LoadOrDefault reads settings if available, otherwise returns runtime defaults.
@@ -103,7 +146,7 @@ func defaultSettings(baseDir string) *Settings {
 		BaseDir:    baseDir,
 		DBType:     string(dbType),
 		DBURL:      metastore.DefaultDBURLForBase(dbType, baseDir),
-		DBPassword: defaultDBPassword,
+		DBPassword: secret.NewPassword(),
 	}
 }
 
@@ -119,8 +162,16 @@ func (sm *SettingsManager) sanitize(settings *Settings) error {
 	settings.DBURL = strings.TrimSpace(settings.DBURL)
 	settings.DBPassword = strings.TrimSpace(settings.DBPassword)
 	if settings.DBPassword == "" {
-		settings.DBPassword = defaultDBPassword
+		settings.DBPassword = secret.NewPassword()
+	}
+	store, err := NormalizePasswordStore(settings.DBPasswordStore)
+	if err != nil {
+		return err
+	}
+	if store == PasswordStorePlain {
+		store = "" // default; omitted from setting.json
 	}
+	settings.DBPasswordStore = store
 
 	rawType := strings.TrimSpace(settings.DBType)
 	if rawType == "" {
@@ -134,6 +185,15 @@ func (sm *SettingsManager) sanitize(settings *Settings) error {
 	}
 	settings.DBType = string(dbType)
 
+	if dbType != metastore.Postgres {
+		settings.DBManaged = false
+	}
+	if !settings.DBManaged {
+		settings.DBManagedPort = 0
+	} else if settings.DBManagedPort == 0 {
+		settings.DBManagedPort = metastore.DefaultManagedPort
+	}
+
 	if settings.DBURL == "" {
 		settings.DBURL = metastore.DefaultDBURLForBase(dbType, sm.paths.BaseDir)
 		return nil
@@ -0,0 +1,663 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config/generator"
+	"github.com/danieljhkim/local-data-platform/internal/config/schema"
+	"github.com/danieljhkim/local-data-platform/internal/metastore"
+	"github.com/danieljhkim/local-data-platform/internal/objectstore"
+	"github.com/danieljhkim/local-data-platform/internal/secret"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// Setting value types, as shown by 'local-data setting describe'.
+const (
+	SettingString = "string"
+	SettingEnum   = "enum"
+	SettingSecret = "secret"
+	SettingPath   = "path"
+	SettingInt    = "int"
+	SettingPort   = "port"
+	SettingMemory = "memory"
+)
+
+// SettingTarget is a generated config property derived from a setting.
+type SettingTarget struct {
+	File     string // overrides.yaml section: core-site, yarn-site, hive, spark, ...
+	Property string
+}
+
+// Path returns the target file relative to a profile directory.
+func (t SettingTarget) Path() string {
+	switch t.File {
+	case "hive":
+		return filepath.Join("hive", "hive-site.xml")
+	case "spark":
+		return filepath.Join("spark", "spark-defaults.conf")
+	default:
+		return filepath.Join("hadoop", t.File+".xml")
+	}
+}
+
+// SettingDef declares a user setting: its type, default, validation, the
+// generated properties it drives and the services that must be restarted.
+type SettingDef struct {
+	Key         string
+	Type        string
+	Description string
+	Values      []string // allowed values of enum settings
+	Targets     []SettingTarget
+	Restart     []string // services to restart after a change
+	ReadOnly    string   // why the setting cannot be changed; empty if it can
+
+	defaultValue func(paths *Paths, s *Settings) string // nil: the profile's own value
+	validate     func(value string) error
+	get          func(s *Settings) string
+	set          func(s *Settings, value string) error
+
+	// initOption settings reach the generator through dedicated
+	// InitOptions fields instead of their Targets
+	initOption bool
+
+	// properties, if set, returns the generated properties of a setting
+	// whose targets do not all take the setting's value
+	properties func(s *Settings) []generator.PropertyOverride
+}
+
+var settingDefs = []*SettingDef{
+	{
+		Key:         "user",
+		Type:        SettingString,
+		Description: "User name; the metastore connection user for non-Derby databases.",
+		Targets:     []SettingTarget{{"hive", "javax.jdo.option.ConnectionUserName"}},
+		Restart:     []string{"hive"},
+		defaultValue: func(*Paths, *Settings) string {
+			return runtimeUser()
+		},
+		get: func(s *Settings) string { return s.User },
+		set: func(s *Settings, v string) error {
+			s.User = v
+			return nil
+		},
+		initOption: true,
+	},
+	{
+		Key:         "base-dir",
+		Type:        SettingPath,
+		Description: "Base directory for configs and runtime state.",
+		ReadOnly: fmt.Sprintf("base-dir is static and cannot be changed via 'local-data setting set' (use --base-dir, $%s, or 'local-data migrate-base-dir')",
+			BaseDirEnvVar),
+		defaultValue: func(paths *Paths, _ *Settings) string { return paths.BaseDir },
+		get:          func(s *Settings) string { return s.BaseDir },
+		initOption:   true,
+	},
+	{
+		Key:         "db-type",
+		Type:        SettingEnum,
+		Description: "Hive metastore database.",
+		Values:      []string{string(metastore.Derby), string(metastore.Postgres), string(metastore.MySQL)},
+		Targets: []SettingTarget{
+			{"hive", "javax.jdo.option.ConnectionDriverName"},
+			{"hive", "javax.jdo.option.ConnectionURL"},
+			{"hive", "javax.jdo.option.ConnectionUserName"},
+		},
+		Restart:      []string{"hive"},
+		defaultValue: func(*Paths, *Settings) string { return string(metastore.Derby) },
+		validate: func(v string) error {
+			_, err := metastore.NormalizeDBType(v)
+			return err
+		},
+		get: func(s *Settings) string { return s.DBType },
+		set: func(s *Settings, v string) error {
+			dbType, err := metastore.NormalizeDBType(v)
+			if err != nil {
+				return err
+			}
+			s.DBType = string(dbType)
+			return nil
+		},
+		initOption: true,
+	},
+	{
+		Key:         "db-url",
+		Type:        SettingString,
+		Description: "JDBC URL of the metastore database; must match db-type.",
+		Targets:     []SettingTarget{{"hive", "javax.jdo.option.ConnectionURL"}},
+		Restart:     []string{"hive"},
+		defaultValue: func(paths *Paths, s *Settings) string {
+			dbType, err := metastore.NormalizeDBType(s.DBType)
+			if err != nil {
+				dbType = metastore.Derby
+			}
+			return metastore.DefaultDBURLForBase(dbType, paths.BaseDir)
+		},
+		get: func(s *Settings) string { return s.DBURL },
+		set: func(s *Settings, v string) error {
+			s.DBURL = v
+			return nil
+		},
+		initOption: true,
+	},
+	{
+		Key:         "db-password",
+		Type:        SettingSecret,
+		Description: "Metastore database password: a literal, env:NAME or file:/path.",
+		Targets: []SettingTarget{
+			{"hive", passwordProperty},
+			{"hive", "hadoop.security.credential.provider.path"},
+		},
+		Restart:      []string{"hive"},
+		defaultValue: func(*Paths, *Settings) string { return secret.NewPassword() },
+		get:          func(s *Settings) string { return s.DBPassword },
+		set: func(s *Settings, v string) error {
+			s.DBPassword = v
+			return nil
+		},
+		initOption: true,
+	},
+	{
+		Key:         "db-password-store",
+		Type:        SettingEnum,
+		Description: "Where generated configs keep db-password: hive-site.xml (plain) or a JCEKS credential store.",
+		Values:      []string{PasswordStorePlain, PasswordStoreJCEKS},
+		Targets: []SettingTarget{
+			{"hive", passwordProperty},
+			{"hive", "hadoop.security.credential.provider.path"},
+		},
+		Restart:      []string{"hive"},
+		defaultValue: func(*Paths, *Settings) string { return PasswordStorePlain },
+		validate: func(v string) error {
+			_, err := NormalizePasswordStore(v)
+			return err
+		},
+		get: func(s *Settings) string {
+			store, _ := NormalizePasswordStore(s.DBPasswordStore)
+			return store
+		},
+		set: func(s *Settings, v string) error {
+			store, err := NormalizePasswordStore(v)
+			if err != nil {
+				return err
+			}
+			s.DBPasswordStore = store
+			return nil
+		},
+		initOption: true,
+	},
+	{
+		Key:         "spark-driver-memory",
+		Type:        SettingMemory,
+		Description: "Spark driver heap size (e.g. 2g, 512m).",
+		Targets:     []SettingTarget{{"spark", "spark.driver.memory"}},
+		validate:    validateMemory,
+		get:         func(s *Settings) string { return s.SparkDriverMemory },
+		set: func(s *Settings, v string) error {
+			s.SparkDriverMemory = strings.ToLower(v)
+			return nil
+		},
+	},
+	{
+		Key:         "yarn-memory-mb",
+		Type:        SettingInt,
+		Description: "Memory in MB the YARN NodeManager offers to containers.",
+		Targets:     []SettingTarget{{"yarn-site", "yarn.nodemanager.resource.memory-mb"}},
+		Restart:     []string{"yarn"},
+		validate:    validatePositiveInt,
+		get:         func(s *Settings) string { return formatInt(s.YarnMemoryMB) },
+		set: func(s *Settings, v string) error {
+			n, err := parseOptionalInt(v)
+			s.YarnMemoryMB = n
+			return err
+		},
+	},
+	{
+		Key:         "hs2-port",
+		Type:        SettingPort,
+		Description: "HiveServer2 Thrift port.",
+		Targets:     []SettingTarget{{"hive", "hive.server2.thrift.port"}},
+		Restart:     []string{"hive"},
+		validate:    validatePort,
+		get:         func(s *Settings) string { return formatInt(s.HS2Port) },
+		set: func(s *Settings, v string) error {
+			n, err := parseOptionalInt(v)
+			s.HS2Port = n
+			return err
+		},
+	},
+	{
+		Key:         "s3",
+		Type:        SettingEnum,
+		Description: "Run the local S3-compatible server (the s3 service) and point s3a:// and s3:// paths at it.",
+		Values:      []string{"off", "on"},
+		Targets: fsTargets(
+			"fs.s3a.endpoint.region",
+			"fs.s3a.path.style.access",
+			"fs.s3a.connection.ssl.enabled",
+			"fs.s3a.access.key",
+			"fs.s3a.secret.key",
+			"fs.s3a.aws.credentials.provider",
+			"fs.s3.impl",
+			"fs.AbstractFileSystem.s3.impl",
+		),
+		Restart:      []string{"hive"},
+		defaultValue: func(*Paths, *Settings) string { return "off" },
+		validate:     validateOnOff,
+		get: func(s *Settings) string {
+			if s.S3Enabled {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(s *Settings, v string) error {
+			s.S3Enabled = v == "on"
+			return nil
+		},
+		properties: func(s *Settings) []generator.PropertyOverride {
+			if !s.S3Enabled {
+				return nil
+			}
+			return fsOverrides(
+				"fs.s3a.endpoint.region", objectstore.Region,
+				"fs.s3a.path.style.access", "true",
+				"fs.s3a.connection.ssl.enabled", "false",
+				"fs.s3a.access.key", objectstore.AccessKey,
+				"fs.s3a.secret.key", objectstore.SecretKey,
+				"fs.s3a.aws.credentials.provider", "org.apache.hadoop.fs.s3a.SimpleAWSCredentialsProvider",
+				"fs.s3.impl", "org.apache.hadoop.fs.s3a.S3AFileSystem",
+				"fs.AbstractFileSystem.s3.impl", "org.apache.hadoop.fs.s3a.S3A",
+			)
+		},
+	},
+	{
+		Key:         "s3-port",
+		Type:        SettingPort,
+		Description: "Port of the local S3 server (with s3 on).",
+		Targets:     fsTargets("fs.s3a.endpoint"),
+		Restart:     []string{"s3", "hive"},
+		defaultValue: func(*Paths, *Settings) string {
+			return strconv.Itoa(objectstore.DefaultPort)
+		},
+		validate: validatePort,
+		get:      func(s *Settings) string { return formatInt(s.S3Port) },
+		set: func(s *Settings, v string) error {
+			n, err := parseOptionalInt(v)
+			s.S3Port = n
+			return err
+		},
+		properties: func(s *Settings) []generator.PropertyOverride {
+			if !s.S3Enabled {
+				return nil
+			}
+			return fsOverrides("fs.s3a.endpoint", s.S3Endpoint())
+		},
+	},
+	{
+		Key:         "iceberg",
+		Type:        SettingEnum,
+		Description: "Add an Apache Iceberg catalog, its SQL extensions and runtime jar to the Spark config.",
+		Values:      []string{"off", "on"},
+		Targets: []SettingTarget{
+			{"spark", "spark.sql.extensions"},
+			{"spark", "spark.sql.catalog.<iceberg-catalog>"},
+			{"spark", "spark.jars"},
+		},
+		defaultValue: func(*Paths, *Settings) string { return "off" },
+		validate:     validateOnOff,
+		get: func(s *Settings) string {
+			if s.IcebergEnabled {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(s *Settings, v string) error {
+			s.IcebergEnabled = v == "on"
+			return nil
+		},
+		initOption: true,
+	},
+	{
+		Key:         "iceberg-catalog",
+		Type:        SettingString,
+		Description: "Name of the Iceberg catalog; spark_catalog wraps Spark's session catalog so USING iceberg works without a prefix.",
+		Targets:     []SettingTarget{{"spark", "spark.sql.catalog.<iceberg-catalog>"}},
+		defaultValue: func(*Paths, *Settings) string {
+			return schema.SessionCatalogName
+		},
+		validate: validateIdentifier,
+		get:      func(s *Settings) string { return s.IcebergCatalog },
+		set: func(s *Settings, v string) error {
+			s.IcebergCatalog = v
+			return nil
+		},
+		initOption: true,
+	},
+	{
+		Key:         "iceberg-catalog-type",
+		Type:        SettingEnum,
+		Description: "Where the Iceberg catalog keeps table metadata: the Hive metastore or a Hadoop warehouse directory.",
+		Values:      []string{schema.IcebergCatalogHive, schema.IcebergCatalogHadoop},
+		Targets:     []SettingTarget{{"spark", "spark.sql.catalog.<iceberg-catalog>.type"}},
+		defaultValue: func(*Paths, *Settings) string {
+			return schema.IcebergCatalogHive
+		},
+		validate: func(v string) error {
+			if v != schema.IcebergCatalogHive && v != schema.IcebergCatalogHadoop {
+				return fmt.Errorf("%q is not hive or hadoop", v)
+			}
+			return nil
+		},
+		get: func(s *Settings) string { return s.IcebergCatalogType },
+		set: func(s *Settings, v string) error {
+			s.IcebergCatalogType = v
+			return nil
+		},
+		initOption: true,
+	},
+	{
+		Key:         "iceberg-warehouse",
+		Type:        SettingPath,
+		Description: "Warehouse location of the Iceberg catalog (default for hadoop catalogs: spark.sql.warehouse.dir).",
+		Targets:     []SettingTarget{{"spark", "spark.sql.catalog.<iceberg-catalog>.warehouse"}},
+		get:         func(s *Settings) string { return s.IcebergWarehouse },
+		set: func(s *Settings, v string) error {
+			s.IcebergWarehouse = v
+			return nil
+		},
+		initOption: true,
+	},
+	{
+		Key:         "iceberg-jar",
+		Type:        SettingPath,
+		Description: "Iceberg Spark runtime jar (default: the newest iceberg-spark-runtime-*.jar in $BASE_DIR/jars or ~/.ivy2/jars).",
+		Targets:     []SettingTarget{{"spark", "spark.jars"}},
+		validate: func(v string) error {
+			if !strings.HasSuffix(v, ".jar") {
+				return fmt.Errorf("%q is not a .jar file", v)
+			}
+			if !util.FileExists(v) {
+				return fmt.Errorf("%s does not exist", v)
+			}
+			return nil
+		},
+		get: func(s *Settings) string { return s.IcebergJar },
+		set: func(s *Settings, v string) error {
+			if v != "" {
+				abs, err := filepath.Abs(v)
+				if err != nil {
+					return err
+				}
+				v = abs
+			}
+			s.IcebergJar = v
+			return nil
+		},
+		initOption: true,
+	},
+	{
+		Key:         "delta",
+		Type:        SettingEnum,
+		Description: "Add Delta Lake's SQL extensions, DeltaCatalog as spark_catalog and the delta-spark/delta-storage jars to the Spark config.",
+		Values:      []string{"off", "on"},
+		Targets: []SettingTarget{
+			{"spark", "spark.sql.extensions"},
+			{"spark", "spark.sql.catalog.spark_catalog"},
+			{"spark", "spark.jars"},
+		},
+		defaultValue: func(*Paths, *Settings) string { return "off" },
+		validate:     validateOnOff,
+		get: func(s *Settings) string {
+			if s.DeltaEnabled {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(s *Settings, v string) error {
+			s.DeltaEnabled = v == "on"
+			return nil
+		},
+		initOption: true,
+	},
+}
+
+// fsFiles are the generated files that carry fs.* properties: core-site.xml
+// for Hadoop clients, hive-site.xml for Hive (including profiles without
+// Hadoop configs) and spark-defaults.conf with the spark.hadoop. prefix.
+var fsFiles = []string{"core-site", "hive", "spark"}
+
+func fsProperty(file, name string) string {
+	if file == "spark" {
+		return "spark.hadoop." + name
+	}
+	return name
+}
+
+// fsTargets returns the targets of fs.* properties in every fs file.
+func fsTargets(names ...string) []SettingTarget {
+	var targets []SettingTarget
+	for _, file := range fsFiles {
+		for _, name := range names {
+			targets = append(targets, SettingTarget{file, fsProperty(file, name)})
+		}
+	}
+	return targets
+}
+
+// fsOverrides sets name/value pairs of fs.* properties in every fs file.
+func fsOverrides(pairs ...string) []generator.PropertyOverride {
+	var props []generator.PropertyOverride
+	for _, file := range fsFiles {
+		for i := 0; i+1 < len(pairs); i += 2 {
+			props = append(props, generator.PropertyOverride{File: file, Name: fsProperty(file, pairs[i]), Value: pairs[i+1]})
+		}
+	}
+	return props
+}
+
+// S3ServerPort returns the port of the local S3 server.
+func (s *Settings) S3ServerPort() int {
+	if s.S3Port > 0 {
+		return s.S3Port
+	}
+	return objectstore.DefaultPort
+}
+
+// S3Endpoint returns the URL of the local S3 server.
+func (s *Settings) S3Endpoint() string {
+	return fmt.Sprintf("http://127.0.0.1:%d", s.S3ServerPort())
+}
+
+// SettingDefs returns all settings in display order.
+func SettingDefs() []*SettingDef {
+	return settingDefs
+}
+
+// SettingKeys returns all setting keys in display order.
+func SettingKeys() []string {
+	keys := make([]string, len(settingDefs))
+	for i, def := range settingDefs {
+		keys[i] = def.Key
+	}
+	return keys
+}
+
+// LookupSetting returns the definition of a setting key.
+func LookupSetting(key string) (*SettingDef, error) {
+	for _, def := range settingDefs {
+		if def.Key == key {
+			return def, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown setting key %q (supported: %s)", key, strings.Join(SettingKeys(), ", "))
+}
+
+// Get returns the current value; empty means the profile's own value.
+func (d *SettingDef) Get(s *Settings) string {
+	return d.get(s)
+}
+
+// Default returns the value used when the setting is unset, which may depend
+// on other settings (db-url follows db-type); empty means the profile's own value.
+func (d *SettingDef) Default(paths *Paths, s *Settings) string {
+	if d.defaultValue == nil {
+		return ""
+	}
+	return d.defaultValue(paths, s)
+}
+
+// Validate checks a value without changing settings.
+func (d *SettingDef) Validate(value string) error {
+	if d.ReadOnly != "" {
+		return fmt.Errorf("%s", d.ReadOnly)
+	}
+	if d.validate == nil {
+		return nil
+	}
+	if err := d.validate(value); err != nil {
+		return fmt.Errorf("invalid %s: %w", d.Key, err)
+	}
+	return nil
+}
+
+// Set validates value and stores it in s.
+func (d *SettingDef) Set(s *Settings, value string) error {
+	value = strings.TrimSpace(value)
+	if err := d.Validate(value); err != nil {
+		return err
+	}
+	return d.set(s, value)
+}
+
+// Unset restores the default value in s.
+func (d *SettingDef) Unset(s *Settings, paths *Paths) error {
+	if d.ReadOnly != "" {
+		return fmt.Errorf("%s", d.ReadOnly)
+	}
+	return d.set(s, d.Default(paths, s))
+}
+
+// PropertyOverrides returns the generated properties driven by settings that
+// map directly onto config properties. Unset settings keep the profile's values.
+func (s *Settings) PropertyOverrides() []generator.PropertyOverride {
+	var props []generator.PropertyOverride
+	for _, def := range settingDefs {
+		if def.initOption {
+			continue
+		}
+		if def.properties != nil {
+			props = append(props, def.properties(s)...)
+			continue
+		}
+		value := def.Get(s)
+		if value == "" {
+			continue
+		}
+		for _, t := range def.Targets {
+			props = append(props, generator.PropertyOverride{File: t.File, Name: t.Property, Value: value})
+		}
+	}
+	// Mounts come last so they take over fs.s3.impl from the s3 setting
+	return append(props, s.mountProperties()...)
+}
+
+// initOptions returns generator options for settings. DBPassword is the raw
+// setting value; callers render it first.
+func (s *Settings) initOptions() *generator.InitOptions {
+	return &generator.InitOptions{
+		User:       s.User,
+		DBType:     s.DBType,
+		DBUrl:      s.DBURL,
+		DBPassword: s.DBPassword,
+		Iceberg:    s.icebergCatalog(),
+		Delta:      s.DeltaEnabled,
+		Properties: s.PropertyOverrides(),
+	}
+}
+
+// ValidateTableFormats checks that enabled table formats do not both claim
+// Spark's session catalog.
+func (s *Settings) ValidateTableFormats() error {
+	if catalog := s.icebergCatalog(); catalog != nil && s.DeltaEnabled && catalog.Name == schema.SessionCatalogName {
+		return fmt.Errorf("iceberg and delta both use %s; set iceberg-catalog to another name (e.g. iceberg) to enable both",
+			schema.SessionCatalogName)
+	}
+	return nil
+}
+
+// icebergCatalog returns the Iceberg catalog of settings, nil when disabled.
+func (s *Settings) icebergCatalog() *schema.IcebergCatalog {
+	if !s.IcebergEnabled {
+		return nil
+	}
+	catalog := &schema.IcebergCatalog{
+		Name:      s.IcebergCatalog,
+		Type:      s.IcebergCatalogType,
+		Warehouse: s.IcebergWarehouse,
+	}
+	if catalog.Name == "" {
+		catalog.Name = schema.SessionCatalogName
+	}
+	if catalog.Type == "" {
+		catalog.Type = schema.IcebergCatalogHive
+	}
+	return catalog
+}
+
+var memoryPattern = regexp.MustCompile(`(?i)^[0-9]+([kmgt]b?)?$`)
+
+func validateMemory(v string) error {
+	if !memoryPattern.MatchString(v) {
+		return fmt.Errorf("%q is not a memory size (e.g. 512m, 2g)", v)
+	}
+	return nil
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func validateIdentifier(v string) error {
+	if !identifierPattern.MatchString(v) {
+		return fmt.Errorf("%q is not a valid identifier", v)
+	}
+	return nil
+}
+
+func validateOnOff(v string) error {
+	if v != "on" && v != "off" {
+		return fmt.Errorf("%q is not on or off", v)
+	}
+	return nil
+}
+
+func validatePositiveInt(v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return fmt.Errorf("%q is not a positive integer", v)
+	}
+	return nil
+}
+
+func validatePort(v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("%q is not a port (1-65535)", v)
+	}
+	return nil
+}
+
+func formatInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func parseOptionalInt(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(v)
+}
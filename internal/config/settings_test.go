@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -38,8 +39,8 @@ func TestSettingsManager_LoadOrDefault_MissingFile(t *testing.T) {
 	if got.DBURL != wantDerbyURL {
 		t.Errorf("DBURL = %q", got.DBURL)
 	}
-	if got.DBPassword != "password" {
-		t.Errorf("DBPassword = %q", got.DBPassword)
+	if got.DBPassword == "" || got.DBPassword == "password" {
+		t.Errorf("DBPassword = %q, want a generated password", got.DBPassword)
 	}
 	if strings.TrimSpace(got.User) == "" {
 		t.Errorf("User should not be empty")
@@ -57,6 +58,8 @@ func TestSettingsManager_SaveAndLoad(t *testing.T) {
 		DBType:     "postgres",
 		DBURL:      "jdbc:postgresql://localhost:5432/custom",
 		DBPassword: "secret",
+		Mounts:     []Mount{{URI: "gs://bucket/raw", Target: "file:///tmp/raw"}},
+		Python:     map[string]PythonEnv{"hdfs": {Python: "/tmp/venv/bin/python", Archive: "/tmp/venv.tar.gz"}},
 	}
 
 	if err := sm.Save(want); err != nil {
@@ -72,11 +75,58 @@ func TestSettingsManager_SaveAndLoad(t *testing.T) {
 		t.Fatalf("Load() error: %v", err)
 	}
 
-	if *got != *want {
+	if !reflect.DeepEqual(got, want) {
 		t.Fatalf("Load() = %+v, want %+v", *got, *want)
 	}
 }
 
+func TestSettingsManager_Save_ManagedOnlyForPostgres(t *testing.T) {
+	paths := NewPaths("/tmp/repo", t.TempDir())
+	sm := NewSettingsManager(paths)
+
+	settings := &Settings{DBType: "postgres", DBURL: "jdbc:postgresql://localhost:5433/metastore", DBManaged: true}
+	if err := sm.Save(settings); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if settings.DBManagedPort != 5433 {
+		t.Fatalf("DBManagedPort = %d, want default 5433", settings.DBManagedPort)
+	}
+
+	settings = &Settings{DBType: "mysql", DBURL: "jdbc:mysql://localhost:3306/metastore", DBManaged: true, DBManagedPort: 3307}
+	if err := sm.Save(settings); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if settings.DBManaged || settings.DBManagedPort != 0 {
+		t.Fatalf("managed should be cleared for mysql: %+v", *settings)
+	}
+}
+
+func TestSettingsManager_Save_RestrictsLiteralPasswords(t *testing.T) {
+	paths := NewPaths("/tmp/repo", t.TempDir())
+	sm := NewSettingsManager(paths)
+
+	tests := []struct {
+		password string
+		want     os.FileMode
+	}{
+		{"secret", 0600},
+		{"env:METASTORE_PW", 0644},
+		{"file:/tmp/pw", 0644},
+	}
+	for _, tt := range tests {
+		if err := sm.Save(&Settings{DBPassword: tt.password}); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+		info, err := os.Stat(sm.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != tt.want {
+			t.Fatalf("db-password %q: mode = %v, want %v", tt.password, info.Mode().Perm(), tt.want)
+		}
+	}
+}
+
 func TestSettingsManager_Load_MigratesMissingDBTypeFromURL(t *testing.T) {
 	baseDir := t.TempDir()
 	paths := NewPaths("/tmp/repo", baseDir)
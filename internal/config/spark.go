@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// FindSparkHome finds the Spark installation: $SPARK_HOME, else Homebrew's
+// apache-spark (or spark) formula with the /libexec suffix
+func FindSparkHome() string {
+	if sparkHome := os.Getenv("SPARK_HOME"); sparkHome != "" {
+		return sparkHome
+	}
+
+	prefix := util.BrewPrefix("apache-spark")
+	if prefix == "" {
+		prefix = util.BrewPrefix("spark")
+	}
+	if prefix != "" {
+		return prefix + "/libexec"
+	}
+	return ""
+}
+
+// SparkBuild is the Spark version and Scala binary version of an install,
+// which runtime jars such as iceberg-spark-runtime-3.5_2.12 must match
+type SparkBuild struct {
+	Version string // major.minor, e.g. 3.5
+	Scala   string // e.g. 2.12
+}
+
+// sparkCoreJar matches spark-core_<scala>-<version>.jar, e.g.
+// spark-core_2.12-3.5.1.jar
+var sparkCoreJar = regexp.MustCompile(`^spark-core_([0-9]+\.[0-9]+)-([0-9]+\.[0-9]+)[.\-]`)
+
+// DetectSparkBuild reads the build of the Spark at sparkHome from its
+// spark-core jar; ok is false when it cannot be determined.
+func DetectSparkBuild(sparkHome string) (build SparkBuild, ok bool) {
+	if sparkHome == "" {
+		return SparkBuild{}, false
+	}
+	matches, _ := filepath.Glob(filepath.Join(sparkHome, "jars", "spark-core_*.jar"))
+	sort.Strings(matches)
+	for _, m := range matches {
+		if sub := sparkCoreJar.FindStringSubmatch(filepath.Base(m)); sub != nil {
+			return SparkBuild{Version: sub[2], Scala: sub[1]}, true
+		}
+	}
+	return SparkBuild{}, false
+}
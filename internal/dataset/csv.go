@@ -0,0 +1,147 @@
+package dataset
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/metastore"
+)
+
+// DefaultSampleRows is the number of rows per file read for type inference.
+const DefaultSampleRows = 1000
+
+// CSVOptions describes delimited text files.
+type CSVOptions struct {
+	Delimiter rune // 0: ',' (or '\t' for .tsv files)
+	NoHeader  bool // columns are named col_1, col_2, ...
+	Sample    int  // rows sampled per file; 0: DefaultSampleRows
+}
+
+// delimiterFor returns the delimiter for a file.
+func (o CSVOptions) delimiterFor(path string) rune {
+	if o.Delimiter != 0 {
+		return o.Delimiter
+	}
+	if strings.HasSuffix(strings.TrimSuffix(strings.ToLower(path), ".gz"), ".tsv") {
+		return '\t'
+	}
+	return ','
+}
+
+// InferCSV infers column names from the header of the first file and column
+// types from sampled rows of every file.
+func InferCSV(files []string, opts CSVOptions) (*Schema, error) {
+	sample := opts.Sample
+	if sample <= 0 {
+		sample = DefaultSampleRows
+	}
+
+	var (
+		names  []string
+		types  []string
+		quoted bool
+	)
+	for _, path := range files {
+		f, err := openData(path)
+		if err != nil {
+			return nil, err
+		}
+		r := csv.NewReader(&quoteDetector{r: f, quoted: &quoted})
+		r.Comma = opts.delimiterFor(path)
+		r.FieldsPerRecord = -1
+		r.ReuseRecord = true
+
+		first := true
+		for n := 0; n < sample; {
+			record, err := r.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			if first && !opts.NoHeader {
+				first = false
+				if names == nil {
+					names = columnNames(record)
+					types = make([]string, len(names))
+				}
+				continue
+			}
+			first = false
+			if names == nil {
+				headers := make([]string, len(record))
+				names = columnNames(headers)
+				types = make([]string, len(names))
+			}
+			for i, v := range record {
+				if i < len(types) {
+					types[i] = mergeTypes(types[i], inferValueType(v))
+				}
+			}
+			n++
+		}
+		f.Close()
+	}
+	if names == nil {
+		return nil, fmt.Errorf("no rows found in %s", strings.Join(files, ", "))
+	}
+
+	schema := &Schema{Quoted: quoted}
+	for i, name := range names {
+		t := types[i]
+		if t == "" || quoted {
+			t = TypeString
+		}
+		schema.Columns = append(schema.Columns, metastore.Column{Name: name, Type: t})
+	}
+	return schema, nil
+}
+
+// quoteDetector records whether a double quote appears in the sampled input.
+type quoteDetector struct {
+	r      io.Reader
+	quoted *bool
+}
+
+func (q *quoteDetector) Read(p []byte) (int, error) {
+	n, err := q.r.Read(p)
+	if !*q.quoted && strings.ContainsRune(string(p[:n]), '"') {
+		*q.quoted = true
+	}
+	return n, err
+}
+
+// openData opens a data file, decompressing .gz files.
+func openData(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(strings.ToLower(path), ".gz") {
+		return f, nil
+	}
+	gz, err := gzip.NewReader(bufio.NewReader(f))
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return &gzipFile{Reader: gz, f: f}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g *gzipFile) Close() error {
+	g.Reader.Close()
+	return g.f.Close()
+}
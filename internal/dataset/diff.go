@@ -0,0 +1,347 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/env"
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+)
+
+// DefaultDiffSample is the number of differing rows reported per category.
+const DefaultDiffSample = 10
+
+// DiffSource is one side of a diff: a metastore table or local files.
+type DiffSource struct {
+	Name   string `json:"name"`             // as given on the command line
+	Table  string `json:"table,omitempty"`  // db.table
+	Path   string `json:"path,omitempty"`   // file: URI of a file or directory
+	Format string `json:"format,omitempty"` // csv, json or parquet for paths
+}
+
+// ParseDiffSource resolves an argument to a local file or directory if it
+// exists, and to a db.table name otherwise.
+func ParseDiffSource(arg string) (DiffSource, error) {
+	if _, err := os.Stat(arg); err == nil {
+		layout, err := Scan(arg)
+		if err != nil {
+			return DiffSource{}, err
+		}
+		format, err := DetectFormat(layout.Files[0].Path)
+		if err != nil {
+			return DiffSource{}, err
+		}
+		abs, err := filepath.Abs(arg)
+		if err != nil {
+			return DiffSource{}, err
+		}
+		return DiffSource{Name: arg, Path: "file://" + abs, Format: format}, nil
+	}
+
+	db, table, err := ParseTableName(arg)
+	if err != nil || strings.ContainsAny(arg, `/\`) {
+		return DiffSource{}, fmt.Errorf("%s is neither an existing file nor a db.table name", arg)
+	}
+	return DiffSource{Name: arg, Table: db + "." + table}, nil
+}
+
+// DiffOptions configures a diff.
+type DiffOptions struct {
+	Left      DiffSource `json:"left"`
+	Right     DiffSource `json:"right"`
+	Keys      []string   `json:"keys"`      // rows are matched on these; empty compares whole rows
+	Ignore    []string   `json:"ignore"`    // columns left out of the comparison
+	Tolerance float64    `json:"tolerance"` // absolute tolerance for numeric columns (needs Keys)
+	Sample    int        `json:"sample"`    // differing rows reported per category
+}
+
+// DiffField is a column value in a reported row.
+type DiffField struct {
+	Name  string `json:"name"`
+	Value any    `json:"value"`
+}
+
+// DiffChange is a column whose value differs between matched rows.
+type DiffChange struct {
+	Column string `json:"column"`
+	Left   any    `json:"left"`
+	Right  any    `json:"right"`
+}
+
+// ChangedRow is a sample row present on both sides with different values.
+type ChangedRow struct {
+	Key     []DiffField  `json:"key"`
+	Changes []DiffChange `json:"changes"`
+}
+
+// DiffResult is the summary written by the diff job.
+type DiffResult struct {
+	LeftRows           int64         `json:"left_rows"`
+	RightRows          int64         `json:"right_rows"`
+	Matched            int64         `json:"matched"`
+	Changed            int64         `json:"changed"`
+	OnlyLeft           int64         `json:"only_left"`
+	OnlyRight          int64         `json:"only_right"`
+	ColumnsOnlyLeft    []string      `json:"columns_only_left"`
+	ColumnsOnlyRight   []string      `json:"columns_only_right"`
+	DuplicateKeysLeft  int64         `json:"duplicate_keys_left"`
+	DuplicateKeysRight int64         `json:"duplicate_keys_right"`
+	OnlyLeftSample     [][]DiffField `json:"only_left_sample"`
+	OnlyRightSample    [][]DiffField `json:"only_right_sample"`
+	ChangedSample      []ChangedRow  `json:"changed_sample"`
+}
+
+// Equal reports whether both sides hold the same rows and columns. A key
+// repeated on either side is a difference: the join pairs up every copy, so
+// the counts no longer say the sides match row for row.
+func (r *DiffResult) Equal() bool {
+	return r.Changed == 0 && r.OnlyLeft == 0 && r.OnlyRight == 0 &&
+		len(r.ColumnsOnlyLeft) == 0 && len(r.ColumnsOnlyRight) == 0 &&
+		r.DuplicateKeysLeft == 0 && r.DuplicateKeysRight == 0
+}
+
+// sparkDiff is the PySpark job run by Diff. It reads the options from
+// argv[1] and writes a DiffResult to argv[2]. With keys, rows are matched by
+// a null-safe full outer join; without keys both sides are compared as
+// multisets with exceptAll.
+const sparkDiff = `import json
+import sys
+
+from pyspark.sql import SparkSession
+from pyspark.sql import functions as F
+from pyspark.sql.types import NumericType
+
+opts = json.load(open(sys.argv[1]))
+spark = SparkSession.builder.appName("local-data-diff").enableHiveSupport().getOrCreate()
+
+
+def load(src):
+    if src.get("table"):
+        df = spark.table(src["table"])
+    elif src["format"] == "csv":
+        df = spark.read.option("header", "true").option("inferSchema", "true").csv(src["path"])
+    else:
+        df = spark.read.format(src["format"]).load(src["path"])
+    return df.toDF(*[c.lower() for c in df.columns])
+
+
+def fields(row, cols):
+    return [{"name": c, "value": row[c]} for c in cols]
+
+
+left, right = load(opts["left"]), load(opts["right"])
+ignore = set(c.lower() for c in opts["ignore"] or [])
+keys = [k.lower() for k in opts["keys"] or []]
+sample = opts["sample"]
+
+left_cols = [c for c in left.columns if c not in ignore]
+right_cols = [c for c in right.columns if c not in ignore]
+cols = [c for c in left_cols if c in right_cols]
+missing = [k for k in keys if k not in cols]
+if missing:
+    raise SystemExit("key column(s) not found on both sides: " + ", ".join(missing))
+
+left, right = left.select(cols).cache(), right.select(cols).cache()
+result = {
+    "left_rows": left.count(),
+    "right_rows": right.count(),
+    "columns_only_left": [c for c in left_cols if c not in right_cols],
+    "columns_only_right": [c for c in right_cols if c not in left_cols],
+    "duplicate_keys_left": 0,
+    "duplicate_keys_right": 0,
+    "changed": 0,
+    "changed_sample": [],
+}
+
+if keys:
+    for side, df in (("left", left), ("right", right)):
+        result["duplicate_keys_" + side] = df.groupBy(*keys).count().filter("count > 1").count()
+
+    values = [c for c in cols if c not in keys]
+    numeric = set(f.name for f in left.schema.fields if isinstance(f.dataType, NumericType))
+    tol = opts["tolerance"]
+    l = left.select(*[F.col(c).alias("l_" + c) for c in cols], F.lit(True).alias("_in_l"))
+    r = right.select(*[F.col(c).alias("r_" + c) for c in cols], F.lit(True).alias("_in_r"))
+    cond = None
+    for k in keys:
+        c = l["l_" + k].eqNullSafe(r["r_" + k])
+        cond = c if cond is None else cond & c
+    joined = l.join(r, cond, "full_outer")
+
+    def differs(c):
+        lc, rc = F.col("l_" + c), F.col("r_" + c)
+        if tol > 0 and c in numeric:
+            both_null = lc.isNull() & rc.isNull()
+            one_null = lc.isNull() != rc.isNull()
+            return ~both_null & (one_null | (F.abs(lc.cast("double") - rc.cast("double")) > tol))
+        return ~lc.eqNullSafe(rc)
+
+    changed_cols = F.array(*[F.when(differs(c), F.lit(c)) for c in values]) if values else F.array()
+    joined = joined.withColumn("_changed", F.filter(changed_cols, lambda x: x.isNotNull()))
+    joined = joined.cache()
+
+    only_left = joined.filter(F.col("_in_r").isNull())
+    only_right = joined.filter(F.col("_in_l").isNull())
+    both = joined.filter(F.col("_in_l").isNotNull() & F.col("_in_r").isNotNull())
+    changed = both.filter(F.size("_changed") > 0)
+
+    result["only_left"] = only_left.count()
+    result["only_right"] = only_right.count()
+    result["changed"] = changed.count()
+    result["matched"] = both.count() - result["changed"]
+    result["only_left_sample"] = [
+        [{"name": c, "value": row["l_" + c]} for c in cols] for row in only_left.limit(sample).collect()
+    ]
+    result["only_right_sample"] = [
+        [{"name": c, "value": row["r_" + c]} for c in cols] for row in only_right.limit(sample).collect()
+    ]
+    for row in changed.limit(sample).collect():
+        result["changed_sample"].append({
+            "key": [{"name": k, "value": row["l_" + k]} for k in keys],
+            "changes": [{"column": c, "left": row["l_" + c], "right": row["r_" + c]} for c in row["_changed"]],
+        })
+else:
+    only_left = left.exceptAll(right).cache()
+    only_right = right.exceptAll(left).cache()
+    result["only_left"] = only_left.count()
+    result["only_right"] = only_right.count()
+    result["matched"] = result["left_rows"] - result["only_left"]
+    result["only_left_sample"] = [fields(row, cols) for row in only_left.limit(sample).collect()]
+    result["only_right_sample"] = [fields(row, cols) for row in only_right.limit(sample).collect()]
+
+with open(sys.argv[2], "w") as out:
+    json.dump(result, out, default=str)
+
+spark.stop()
+`
+
+// Diff compares two datasets in a spark-submit job.
+func Diff(ctx context.Context, paths *config.Paths, opts DiffOptions) (*DiffResult, error) {
+	if opts.Tolerance < 0 {
+		return nil, fmt.Errorf("tolerance must not be negative")
+	}
+	if opts.Tolerance > 0 && len(opts.Keys) == 0 {
+		return nil, fmt.Errorf("a numeric tolerance needs key columns to match rows")
+	}
+	if opts.Sample <= 0 {
+		opts.Sample = DefaultDiffSample
+	}
+
+	dir, err := os.MkdirTemp("", "local-data-diff-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	job := filepath.Join(dir, "diff.py")
+	input := filepath.Join(dir, "options.json")
+	output := filepath.Join(dir, "result.json")
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(input, data, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(job, []byte(sparkDiff), 0644); err != nil {
+		return nil, err
+	}
+
+	if _, err := env.SparkSubmit(ctx, paths, job, input, output); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(output)
+	if err != nil {
+		return nil, fmt.Errorf("diff job wrote no result: %w", err)
+	}
+	defer f.Close()
+	return readDiffResult(f)
+}
+
+func readDiffResult(r io.Reader) (*DiffResult, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var res DiffResult
+	if err := dec.Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to parse diff result: %w", err)
+	}
+	return &res, nil
+}
+
+// WriteDiffSummary writes a human-readable diff summary with sample rows.
+func WriteDiffSummary(w io.Writer, opts DiffOptions, res *DiffResult) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Left:   %s (%d rows)\n", opts.Left.Name, res.LeftRows)
+	fmt.Fprintf(&sb, "Right:  %s (%d rows)\n", opts.Right.Name, res.RightRows)
+	if len(opts.Keys) > 0 {
+		fmt.Fprintf(&sb, "Keys:   %s\n", strings.Join(opts.Keys, ", "))
+	}
+	if len(res.ColumnsOnlyLeft) > 0 {
+		fmt.Fprintf(&sb, "Columns only in left:  %s\n", strings.Join(res.ColumnsOnlyLeft, ", "))
+	}
+	if len(res.ColumnsOnlyRight) > 0 {
+		fmt.Fprintf(&sb, "Columns only in right: %s\n", strings.Join(res.ColumnsOnlyRight, ", "))
+	}
+	if res.DuplicateKeysLeft > 0 || res.DuplicateKeysRight > 0 {
+		fmt.Fprintf(&sb, "Duplicate keys: %d in left, %d in right\n", res.DuplicateKeysLeft, res.DuplicateKeysRight)
+	}
+
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "  matched     %d\n", res.Matched)
+	if len(opts.Keys) > 0 {
+		fmt.Fprintf(&sb, "  changed     %d\n", res.Changed)
+	}
+	fmt.Fprintf(&sb, "  only left   %d\n", res.OnlyLeft)
+	fmt.Fprintf(&sb, "  only right  %d\n", res.OnlyRight)
+
+	if len(res.ChangedSample) > 0 {
+		fmt.Fprintf(&sb, "\nChanged rows (%d of %d):\n", len(res.ChangedSample), res.Changed)
+		for _, row := range res.ChangedSample {
+			changes := make([]string, len(row.Changes))
+			for i, c := range row.Changes {
+				changes[i] = fmt.Sprintf("%s: %s -> %s", c.Column, diffValue(c.Left), diffValue(c.Right))
+			}
+			fmt.Fprintf(&sb, "  %s  %s\n", formatFields(row.Key), strings.Join(changes, ", "))
+		}
+	}
+	writeSample := func(title string, rows [][]DiffField, total int64) {
+		if len(rows) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "\n%s (%d of %d):\n", title, len(rows), total)
+		for _, row := range rows {
+			fmt.Fprintf(&sb, "  %s\n", formatFields(row))
+		}
+	}
+	writeSample("Only in left", res.OnlyLeftSample, res.OnlyLeft)
+	writeSample("Only in right", res.OnlyRightSample, res.OnlyRight)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func formatFields(fields []DiffField) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.Name + "=" + diffValue(f.Value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// diffValue renders a value; nested values are shown as JSON.
+func diffValue(v any) string {
+	switch v.(type) {
+	case map[string]any, []any:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return hs2.FormatValue(v)
+	}
+}
@@ -0,0 +1,99 @@
+package dataset
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDiffSource(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "golden", "orders.csv"), "id\n1\n")
+
+	src, err := ParseDiffSource(filepath.Join(dir, "golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.Table != "" || src.Format != FormatCSV || src.Path != "file://"+filepath.Join(dir, "golden") {
+		t.Fatalf("source = %+v", src)
+	}
+
+	src, err = ParseDiffSource("Sales.Orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.Table != "sales.orders" || src.Path != "" || src.Name != "Sales.Orders" {
+		t.Fatalf("source = %+v", src)
+	}
+
+	if _, err := ParseDiffSource(filepath.Join(dir, "missing.csv")); err == nil {
+		t.Fatal("expected error for a missing path")
+	}
+}
+
+func TestDiff_ToleranceNeedsKeys(t *testing.T) {
+	_, err := Diff(context.Background(), nil, DiffOptions{Tolerance: 0.1})
+	if err == nil || !strings.Contains(err.Error(), "key columns") {
+		t.Fatalf("expected key columns error, got %v", err)
+	}
+}
+
+const diffResultJSON = `{
+  "left_rows": 4, "right_rows": 4, "matched": 2, "changed": 1, "only_left": 1, "only_right": 1,
+  "columns_only_left": ["note"], "columns_only_right": [],
+  "duplicate_keys_left": 0, "duplicate_keys_right": 0,
+  "only_left_sample": [[{"name": "id", "value": 4}, {"name": "amount", "value": null}]],
+  "only_right_sample": [[{"name": "id", "value": 5}, {"name": "amount", "value": 1.5}]],
+  "changed_sample": [{"key": [{"name": "id", "value": 3}],
+                      "changes": [{"column": "amount", "left": 10.5, "right": 10.75}]}]
+}`
+
+func TestWriteDiffSummary(t *testing.T) {
+	res, err := readDiffResult(strings.NewReader(diffResultJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Equal() {
+		t.Fatal("result with differences reported as equal")
+	}
+
+	opts := DiffOptions{
+		Left:  DiffSource{Name: "sales.orders"},
+		Right: DiffSource{Name: "golden/orders.csv"},
+		Keys:  []string{"id"},
+	}
+	var sb strings.Builder
+	if err := WriteDiffSummary(&sb, opts, res); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+	for _, want := range []string{
+		"Left:   sales.orders (4 rows)",
+		"Keys:   id",
+		"Columns only in left:  note",
+		"  changed     1",
+		"  id=3  amount: 10.5 -> 10.75",
+		"Only in left (1 of 1):\n  id=4 amount=NULL",
+		"Only in right (1 of 1):\n  id=5 amount=1.5",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDiffResult_Equal(t *testing.T) {
+	res, err := readDiffResult(strings.NewReader(`{"left_rows": 3, "right_rows": 3, "matched": 3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Equal() {
+		t.Fatal("identical datasets reported as different")
+	}
+
+	res.DuplicateKeysRight = 1
+	if res.Equal() {
+		t.Error("result with duplicate keys reported as equal")
+	}
+}
@@ -0,0 +1,181 @@
+package dataset
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/env"
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+	"github.com/danieljhkim/local-data-platform/internal/sqlscript"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// ExportOptions configures an export.
+type ExportOptions struct {
+	Database string
+	Table    string
+	Format   string // csv, json or parquet
+	Where    string // optional filter expression
+	Limit    int    // 0: no limit
+}
+
+// Query returns the SELECT statement for the export.
+func (o ExportOptions) Query() string {
+	q := fmt.Sprintf("SELECT * FROM %s.%s", hs2.QuoteIdent(o.Database), hs2.QuoteIdent(o.Table))
+	if where := strings.TrimSpace(o.Where); where != "" {
+		q += " WHERE " + where
+	}
+	if o.Limit > 0 {
+		q += fmt.Sprintf(" LIMIT %d", o.Limit)
+	}
+	return q
+}
+
+// FileName returns the output file name: <table>.csv, <table>.jsonl or
+// <table>.parquet. JSON is written as one object per line, which data load
+// reads back.
+func (o ExportOptions) FileName() string {
+	ext := o.Format
+	if o.Format == FormatJSON {
+		ext = "jsonl"
+	}
+	return o.Table + "." + ext
+}
+
+// ExportHS2 runs the export query over HiveServer2 and writes CSV or JSON to
+// path. It returns the number of rows written. Parquet needs ExportSpark.
+func ExportHS2(ctx context.Context, client sqlscript.Querier, opts ExportOptions, path string) (int, error) {
+	if opts.Format != FormatCSV && opts.Format != FormatJSON {
+		return 0, fmt.Errorf("format %s is not supported with the hive engine (use --engine spark)", opts.Format)
+	}
+	res, err := client.Query(ctx, opts.Query())
+	if err != nil {
+		return 0, err
+	}
+	// SELECT * results name columns table.column
+	for i, c := range res.Columns {
+		if _, name, ok := strings.Cut(c.Name, "."); ok {
+			res.Columns[i].Name = name
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(f)
+	if opts.Format == FormatCSV {
+		err = hs2.WriteResult(w, res, hs2.FormatCSV)
+	} else {
+		err = writeNDJSON(w, res)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return len(res.Rows), nil
+}
+
+// writeNDJSON writes one JSON object per row with keys in column order.
+// Complex values, which HiveServer2 returns as JSON text, are embedded as
+// JSON.
+func writeNDJSON(w io.Writer, res *hs2.Result) error {
+	for _, row := range res.Rows {
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		for i, c := range res.Columns {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(c.Name)
+			buf.Write(key)
+			buf.WriteByte(':')
+
+			var v any
+			if i < len(row) {
+				v = row[i]
+			}
+			if s, ok := v.(string); ok && isComplexType(c.Type) && json.Valid([]byte(s)) {
+				buf.WriteString(s)
+				continue
+			}
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+			}
+			value, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			buf.Write(value)
+		}
+		buf.WriteString("}\n")
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isComplexType(t string) bool {
+	return strings.HasPrefix(t, "array") || strings.HasPrefix(t, "map") || strings.HasPrefix(t, "struct")
+}
+
+// sparkExporter is the PySpark job run by ExportSpark. It writes the query
+// result as a single file to the local directory argv[3].
+const sparkExporter = `import sys
+
+from pyspark.sql import SparkSession
+
+query, fmt, out = sys.argv[1], sys.argv[2], sys.argv[3]
+spark = SparkSession.builder.appName("local-data-export").enableHiveSupport().getOrCreate()
+
+writer = spark.sql(query).coalesce(1).write.mode("overwrite").format(fmt)
+if fmt == "csv":
+    writer = writer.option("header", "true").option("emptyValue", "")
+writer.save("file://" + out)
+
+spark.stop()
+`
+
+// ExportSpark runs the export query with spark.sql() in a spark-submit job
+// and copies its single output file to path.
+func ExportSpark(ctx context.Context, paths *config.Paths, opts ExportOptions, path string) error {
+	dir, err := os.MkdirTemp("", "local-data-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	job := filepath.Join(dir, "export.py")
+	if err := os.WriteFile(job, []byte(sparkExporter), 0644); err != nil {
+		return err
+	}
+	out := filepath.Join(dir, "out")
+
+	if _, err := env.SparkSubmit(ctx, paths, job, opts.Query(), opts.Format, out); err != nil {
+		return err
+	}
+
+	parts, err := filepath.Glob(filepath.Join(out, "part-*"))
+	if err != nil {
+		return err
+	}
+	if len(parts) != 1 {
+		return fmt.Errorf("spark job wrote %d output files, expected 1", len(parts))
+	}
+	// the temp dir may be on another file system than path
+	return util.CopyFile(parts[0], path)
+}
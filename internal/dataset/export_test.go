@@ -0,0 +1,84 @@
+package dataset
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+)
+
+type fakeQuerier struct {
+	result *hs2.Result
+	query  string
+}
+
+func (f *fakeQuerier) Query(_ context.Context, stmt string) (*hs2.Result, error) {
+	f.query = stmt
+	return f.result, nil
+}
+
+func exportResult() *hs2.Result {
+	return &hs2.Result{
+		Columns: []hs2.Column{
+			{Name: "orders.id", Type: "int"},
+			{Name: "orders.name", Type: "string"},
+			{Name: "orders.tags", Type: "array"},
+		},
+		Rows: [][]any{
+			{int32(1), "a,b", `["x","y"]`},
+			{int32(2), nil, nil},
+		},
+	}
+}
+
+func TestExportOptions_Query(t *testing.T) {
+	opts := ExportOptions{Database: "sales", Table: "orders", Format: FormatJSON, Where: "ds = '2024-01-01'", Limit: 10}
+	want := "SELECT * FROM `sales`.`orders` WHERE ds = '2024-01-01' LIMIT 10"
+	if got := opts.Query(); got != want {
+		t.Fatalf("Query() = %q, want %q", got, want)
+	}
+	if got := opts.FileName(); got != "orders.jsonl" {
+		t.Fatalf("FileName() = %q", got)
+	}
+}
+
+func TestExportHS2(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatCSV, "id,name,tags\n1,\"a,b\",\"[\"\"x\"\",\"\"y\"\"]\"\n2,,\n"},
+		{FormatJSON, `{"id":1,"name":"a,b","tags":["x","y"]}` + "\n" + `{"id":2,"name":null,"tags":null}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out")
+			q := &fakeQuerier{result: exportResult()}
+			opts := ExportOptions{Database: "sales", Table: "orders", Format: tt.format}
+
+			rows, err := ExportHS2(context.Background(), q, opts, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rows != 2 {
+				t.Fatalf("rows = %d", rows)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportHS2_RejectsParquet(t *testing.T) {
+	opts := ExportOptions{Database: "d", Table: "t", Format: FormatParquet}
+	if _, err := ExportHS2(context.Background(), &fakeQuerier{}, opts, filepath.Join(t.TempDir(), "out")); err == nil {
+		t.Fatal("expected error for parquet over HiveServer2")
+	}
+}
@@ -0,0 +1,216 @@
+package dataset
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/env"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// FileSystem is the warehouse file system a table location lives on.
+type FileSystem interface {
+	// Put copies a local file into dir as name, creating dir if needed.
+	Put(local, dir, name string) error
+	// Remove deletes dir recursively; a missing dir is not an error.
+	Remove(dir string) error
+	// Sizes returns the total size in bytes of the files under each
+	// location; missing locations are left out.
+	Sizes(locations []string) (map[string]int64, error)
+	// ChildSizes returns the total size in bytes under each entry directly
+	// in dir, keyed by the entry's location; a missing dir is empty.
+	ChildSizes(dir string) (map[string]int64, error)
+}
+
+// NewFileSystem returns the file system for a location URI: the local file
+// system for file: locations and the hdfs CLI for hdfs:// locations.
+func NewFileSystem(paths *config.Paths, location string) (FileSystem, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid location %q: %w", location, err)
+	}
+	switch u.Scheme {
+	case "", "file":
+		return localFS{}, nil
+	case "hdfs":
+		return &hdfsFS{paths: paths}, nil
+	default:
+		return nil, fmt.Errorf("unsupported location scheme %q in %s", u.Scheme, location)
+	}
+}
+
+// JoinLocation appends slash-separated path elements to a location URI.
+func JoinLocation(location string, elems ...string) string {
+	out := strings.TrimRight(location, "/")
+	for _, e := range elems {
+		if e = strings.Trim(e, "/"); e != "" {
+			out += "/" + e
+		}
+	}
+	return out
+}
+
+type localFS struct{}
+
+// localPath converts a file: URI to a path.
+func localPath(location string) string {
+	if u, err := url.Parse(location); err == nil && u.Scheme == "file" {
+		return u.Path
+	}
+	return location
+}
+
+func (localFS) Put(local, dir, name string) error {
+	dst := localPath(dir)
+	if err := util.MkdirAll(dst); err != nil {
+		return err
+	}
+	return util.CopyFile(local, filepath.Join(dst, name))
+}
+
+func (localFS) Remove(dir string) error {
+	return os.RemoveAll(localPath(dir))
+}
+
+func (localFS) Sizes(locations []string) (map[string]int64, error) {
+	sizes := map[string]int64{}
+	for _, loc := range locations {
+		root := localPath(loc)
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			continue
+		}
+		total, err := treeSize(root)
+		if err != nil {
+			return nil, err
+		}
+		sizes[loc] = total
+	}
+	return sizes, nil
+}
+
+func (localFS) ChildSizes(dir string) (map[string]int64, error) {
+	entries, err := os.ReadDir(localPath(dir))
+	if os.IsNotExist(err) {
+		return map[string]int64{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sizes := map[string]int64{}
+	for _, e := range entries {
+		total, err := treeSize(filepath.Join(localPath(dir), e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		sizes[JoinLocation(dir, e.Name())] = total
+	}
+	return sizes, nil
+}
+
+// treeSize returns the total size of the regular files under root.
+func treeSize(root string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// hdfsFS runs hdfs dfs commands with the local-data environment.
+type hdfsFS struct {
+	paths *config.Paths
+}
+
+func (h *hdfsFS) run(args ...string) error {
+	cmd, err := env.Command(h.paths, append([]string{"hdfs", "dfs"}, args...), nil)
+	if err != nil {
+		return err
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("hdfs dfs %s failed: %w\n%s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Sizes runs a single 'hdfs dfs -du -s' for all locations. du exits non-zero
+// if a location is missing but still reports the others.
+func (h *hdfsFS) Sizes(locations []string) (map[string]int64, error) {
+	if len(locations) == 0 {
+		return map[string]int64{}, nil
+	}
+	cmd, err := env.Command(h.paths, append([]string{"hdfs", "dfs", "-du", "-s"}, locations...), nil)
+	if err != nil {
+		return nil, err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	sizes := parseDu(string(out))
+	if err != nil && len(sizes) == 0 {
+		return nil, fmt.Errorf("hdfs dfs -du failed: %w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+	return sizes, nil
+}
+
+// ChildSizes runs a single 'hdfs dfs -du' on dir, which reports each entry
+// in it.
+func (h *hdfsFS) ChildSizes(dir string) (map[string]int64, error) {
+	cmd, err := env.Command(h.paths, []string{"hdfs", "dfs", "-du", dir}, nil)
+	if err != nil {
+		return nil, err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(stderr.String(), "No such file or directory") {
+			return map[string]int64{}, nil
+		}
+		return nil, fmt.Errorf("hdfs dfs -du failed: %w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+	return parseDu(string(out)), nil
+}
+
+// parseDu parses 'hdfs dfs -du -s' lines: <size> [<disk space consumed>] <path>.
+func parseDu(out string) map[string]int64 {
+	sizes := map[string]int64{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		sizes[fields[len(fields)-1]] = size
+	}
+	return sizes
+}
+
+func (h *hdfsFS) Put(local, dir, name string) error {
+	if err := h.run("-mkdir", "-p", dir); err != nil {
+		return err
+	}
+	return h.run("-put", "-f", local, JoinLocation(dir, name))
+}
+
+func (h *hdfsFS) Remove(dir string) error {
+	return h.run("-rm", "-r", "-f", "-skipTrash", dir)
+}
@@ -0,0 +1,64 @@
+package dataset
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseDu(t *testing.T) {
+	out := `1024  3072  hdfs://localhost:9000/user/hive/warehouse/sales.db
+0  0  hdfs://localhost:9000/user/hive/warehouse/empty
+du: cannot access missing: No such file or directory
+`
+	got := parseDu(out)
+	want := map[string]int64{
+		"hdfs://localhost:9000/user/hive/warehouse/sales.db": 1024,
+		"hdfs://localhost:9000/user/hive/warehouse/empty":    0,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseDu = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("size of %s = %d, want %d", k, got[k], v)
+		}
+	}
+}
+
+func TestLocalFS_Sizes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.csv"), "12345")
+	writeFile(t, filepath.Join(dir, "dt=2024-01-01", "b.csv"), "123")
+
+	loc := "file:" + dir
+	missing := "file:" + filepath.Join(dir, "missing")
+	sizes, err := localFS{}.Sizes([]string{loc, missing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sizes[loc] != 8 {
+		t.Errorf("size = %d, want 8", sizes[loc])
+	}
+	if _, ok := sizes[missing]; ok {
+		t.Error("missing location should be left out")
+	}
+}
+
+func TestLocalFS_ChildSizes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "orders", "a.csv"), "12345")
+	writeFile(t, filepath.Join(dir, "orders", "dt=2024-01-01", "b.csv"), "123")
+	writeFile(t, filepath.Join(dir, "users", "c.csv"), "12")
+
+	loc := "file:" + dir
+	sizes, err := localFS{}.ChildSizes(loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sizes) != 2 || sizes[loc+"/orders"] != 8 || sizes[loc+"/users"] != 2 {
+		t.Errorf("sizes = %v", sizes)
+	}
+	if sizes, err := (localFS{}).ChildSizes(loc + "/missing"); err != nil || len(sizes) != 0 {
+		t.Errorf("missing dir: %v, %v", sizes, err)
+	}
+}
@@ -0,0 +1,137 @@
+package dataset
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/metastore"
+)
+
+// InferJSON infers columns from newline-delimited JSON objects. Columns are
+// ordered by first appearance; nested objects and arrays become struct and
+// array types.
+func InferJSON(files []string, sample int) (*Schema, error) {
+	if sample <= 0 {
+		sample = DefaultSampleRows
+	}
+
+	var order []string
+	types := map[string]string{}
+	for _, path := range files {
+		f, err := openData(path)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for n, line := 0, 0; n < sample && scanner.Scan(); {
+			line++
+			text := bytes.TrimSpace(scanner.Bytes())
+			if len(text) == 0 {
+				continue
+			}
+			if text[0] == '[' && n == 0 {
+				f.Close()
+				return nil, fmt.Errorf("%s: JSON arrays are not supported; use one JSON object per line", path)
+			}
+
+			dec := json.NewDecoder(bytes.NewReader(text))
+			dec.UseNumber()
+			var obj map[string]any
+			if err := dec.Decode(&obj); err != nil {
+				f.Close()
+				return nil, fmt.Errorf("%s:%d: expected a JSON object: %w", path, line, err)
+			}
+			for key, value := range obj {
+				name := strings.ToLower(key)
+				if _, ok := types[name]; !ok {
+					order = append(order, name)
+					types[name] = ""
+				}
+				types[name] = mergeJSONTypes(types[name], jsonType(value))
+			}
+			n++
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	if len(order) == 0 {
+		return nil, fmt.Errorf("no JSON objects found in %s", strings.Join(files, ", "))
+	}
+
+	schema := &Schema{}
+	for _, name := range order {
+		t := types[name]
+		if t == "" {
+			t = TypeString
+		}
+		schema.Columns = append(schema.Columns, metastore.Column{Name: name, Type: t})
+	}
+	return schema, nil
+}
+
+// jsonType returns the Hive type of a decoded JSON value; "" for null.
+func jsonType(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case bool:
+		return TypeBoolean
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return TypeBigint
+		}
+		return TypeDouble
+	case string:
+		return TypeString
+	case []any:
+		elem := ""
+		for _, e := range v {
+			elem = mergeJSONTypes(elem, jsonType(e))
+		}
+		if elem == "" {
+			elem = TypeString
+		}
+		return "array<" + elem + ">"
+	case map[string]any:
+		names := make(map[string]string, len(v))
+		for k := range v {
+			names[strings.ToLower(k)] = k
+		}
+		keys := make([]string, 0, len(names))
+		for name := range names {
+			keys = append(keys, name)
+		}
+		sort.Strings(keys)
+		fields := make([]string, len(keys))
+		for i, name := range keys {
+			t := jsonType(v[names[name]])
+			if t == "" {
+				t = TypeString
+			}
+			fields[i] = name + ":" + t
+		}
+		return "struct<" + strings.Join(fields, ",") + ">"
+	default:
+		return TypeString
+	}
+}
+
+// mergeJSONTypes is mergeTypes for JSON values; differing complex types fall
+// back to string.
+func mergeJSONTypes(a, b string) string {
+	if a == "" || b == "" || a == b {
+		return mergeTypes(a, b)
+	}
+	if strings.ContainsAny(a, "<") || strings.ContainsAny(b, "<") {
+		return TypeString
+	}
+	return mergeTypes(a, b)
+}
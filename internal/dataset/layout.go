@@ -0,0 +1,134 @@
+// Package dataset moves local data files in and out of Hive tables: schema
+// inference for CSV, JSON and Parquet files, partition discovery from
+// key=value directory layouts and copying into the warehouse.
+package dataset
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DataFile is a file to load and the partition it belongs to.
+type DataFile struct {
+	Path      string
+	Partition []string // values for Layout.PartitionKeys, in order
+}
+
+// Layout is the set of data files under a load path.
+type Layout struct {
+	PartitionKeys []string
+	Files         []DataFile
+}
+
+// Partitions returns the distinct partition value lists, in file order.
+func (l *Layout) Partitions() [][]string {
+	seen := map[string]bool{}
+	var out [][]string
+	for _, f := range l.Files {
+		key := strings.Join(f.Partition, "\x00")
+		if len(f.Partition) > 0 && !seen[key] {
+			seen[key] = true
+			out = append(out, f.Partition)
+		}
+	}
+	return out
+}
+
+// PartitionPath returns the partition directory relative to the table
+// location, e.g. "ds=2024-01-01/region=us".
+func (l *Layout) PartitionPath(values []string) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = l.PartitionKeys[i] + "=" + escapePartitionValue(v)
+	}
+	return strings.Join(parts, "/")
+}
+
+// Scan lists the data files under root. A file is loaded as is; in a
+// directory, key=value subdirectories become partition columns, which must be
+// the same for every file. Hidden files and files starting with '_' or '.'
+// (e.g. _SUCCESS) are ignored.
+func Scan(root string) (*Layout, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return &Layout{Files: []DataFile{{Path: root}}}, nil
+	}
+
+	layout := &Layout{}
+	keysSet := false
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		var keys, values []string
+		if rel != "." {
+			for _, dir := range strings.Split(rel, string(filepath.Separator)) {
+				key, value, ok := strings.Cut(dir, "=")
+				if !ok || key == "" {
+					return fmt.Errorf("%s: directory %q is not a key=value partition directory", path, dir)
+				}
+				unescaped, err := url.PathUnescape(value)
+				if err != nil {
+					unescaped = value
+				}
+				keys = append(keys, strings.ToLower(key))
+				values = append(values, unescaped)
+			}
+		}
+
+		if !keysSet {
+			layout.PartitionKeys = keys
+			keysSet = true
+		} else if strings.Join(keys, "/") != strings.Join(layout.PartitionKeys, "/") {
+			return fmt.Errorf("%s: partition columns (%s) differ from other files (%s)",
+				path, strings.Join(keys, ", "), strings.Join(layout.PartitionKeys, ", "))
+		}
+		layout.Files = append(layout.Files, DataFile{Path: path, Partition: values})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(layout.Files) == 0 {
+		return nil, fmt.Errorf("no data files found in %s", root)
+	}
+
+	sort.SliceStable(layout.Files, func(i, j int) bool { return layout.Files[i].Path < layout.Files[j].Path })
+	return layout, nil
+}
+
+// escapePartitionValue escapes characters Hive escapes in partition paths.
+func escapePartitionValue(v string) string {
+	var sb strings.Builder
+	for _, r := range v {
+		switch r {
+		case '/', '=', '%', ':', '#', '?', '\\', '"', '\'', '*', '[', ']', '{', '}', '^', '\n', '\r', '\t', 0x7f:
+			fmt.Fprintf(&sb, "%%%02X", r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,82 @@
+package dataset
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan_SingleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.csv")
+	writeFile(t, path, "id\n1\n")
+
+	layout, err := Scan(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layout.Files) != 1 || layout.Files[0].Path != path || len(layout.PartitionKeys) != 0 {
+		t.Fatalf("unexpected layout: %+v", layout)
+	}
+}
+
+func TestScan_PartitionDirectories(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "DS=2024-01-02", "region=eu", "part-0.csv"), "id\n2\n")
+	writeFile(t, filepath.Join(root, "DS=2024-01-01", "region=us", "part-0.csv"), "id\n1\n")
+	writeFile(t, filepath.Join(root, "DS=2024-01-01", "region=us", "part-1.csv"), "id\n3\n")
+	writeFile(t, filepath.Join(root, "_SUCCESS"), "")
+	writeFile(t, filepath.Join(root, ".hidden", "x.csv"), "")
+
+	layout, err := Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(layout.PartitionKeys, []string{"ds", "region"}) {
+		t.Fatalf("partition keys = %v", layout.PartitionKeys)
+	}
+	if len(layout.Files) != 3 {
+		t.Fatalf("files = %+v", layout.Files)
+	}
+	want := [][]string{{"2024-01-01", "us"}, {"2024-01-02", "eu"}}
+	if got := layout.Partitions(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("partitions = %v, want %v", got, want)
+	}
+	if got := layout.PartitionPath([]string{"a/b", "us"}); got != "ds=a%2Fb/region=us" {
+		t.Fatalf("partition path = %q", got)
+	}
+}
+
+func TestScan_Errors(t *testing.T) {
+	t.Run("inconsistent keys", func(t *testing.T) {
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "ds=1", "a.csv"), "")
+		writeFile(t, filepath.Join(root, "region=us", "b.csv"), "")
+		if _, err := Scan(root); err == nil || !strings.Contains(err.Error(), "differ") {
+			t.Fatalf("expected partition mismatch error, got %v", err)
+		}
+	})
+	t.Run("plain subdirectory", func(t *testing.T) {
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "2024", "a.csv"), "")
+		if _, err := Scan(root); err == nil || !strings.Contains(err.Error(), "key=value") {
+			t.Fatalf("expected key=value error, got %v", err)
+		}
+	})
+	t.Run("empty directory", func(t *testing.T) {
+		if _, err := Scan(t.TempDir()); err == nil {
+			t.Fatal("expected error for empty directory")
+		}
+	})
+}
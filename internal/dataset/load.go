@@ -0,0 +1,376 @@
+package dataset
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/metastore"
+)
+
+// Hive storage formats used for loaded tables.
+const (
+	textInputFormat     = "org.apache.hadoop.mapred.TextInputFormat"
+	textOutputFormat    = "org.apache.hadoop.hive.ql.io.HiveIgnoreKeyTextOutputFormat"
+	lazySimpleSerDe     = "org.apache.hadoop.hive.serde2.lazy.LazySimpleSerDe"
+	openCSVSerDe        = "org.apache.hadoop.hive.serde2.OpenCSVSerde"
+	jsonSerDe           = "org.apache.hadoop.hive.serde2.JsonSerDe"
+	parquetInputFormat  = "org.apache.hadoop.hive.ql.io.parquet.MapredParquetInputFormat"
+	parquetOutputFormat = "org.apache.hadoop.hive.ql.io.parquet.MapredParquetOutputFormat"
+	parquetHiveSerDe    = "org.apache.hadoop.hive.ql.io.parquet.serde.ParquetHiveSerDe"
+)
+
+// Table parameters set on loaded tables.
+const (
+	externalTableParam   = "EXTERNAL"
+	externalPurgeParam   = "external.table.purge"
+	skipHeaderLinesParam = "skip.header.line.count"
+)
+
+const defaultDatabaseName = "default"
+
+// Catalog is the metastore access Load needs; *metastore.ThriftCatalog
+// implements it.
+type Catalog interface {
+	metastore.Catalog
+	DropTable(ctx context.Context, db, name string, deleteData bool) error
+}
+
+// LoadOptions configures Load.
+type LoadOptions struct {
+	Database string
+	Table    string
+	Format   string // csv, json or parquet; detected from the file name if empty
+	CSV      CSVOptions
+	Replace  bool // drop and recreate an existing table
+	Append   bool // add files (and partitions) to an existing table
+}
+
+// LoadResult describes a completed load.
+type LoadResult struct {
+	Table      *metastore.Table
+	Files      int
+	Partitions int // partitions added
+	Created    bool
+	Quoted     bool // CSV with quoted fields loaded with OpenCSVSerde
+}
+
+// ParseTableName splits db.table; a bare table name is in the default database.
+func ParseTableName(name string) (db, table string, err error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(name)), ".")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return defaultDatabaseName, parts[0], nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return parts[0], parts[1], nil
+	default:
+		return "", "", fmt.Errorf("invalid table name %q (expected db.table)", name)
+	}
+}
+
+// Load infers a schema for the files in layout, creates the database and
+// table in the metastore and copies the files into the table location (one
+// subdirectory per partition). Tables are created as external tables with
+// external.table.purge, which is how Hive stores non-transactional managed
+// tables.
+func Load(ctx context.Context, catalog Catalog, newFS func(location string) (FileSystem, error), layout *Layout, opts LoadOptions) (*LoadResult, error) {
+	format, schema, err := Infer(layout, opts.Format, opts.CSV)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range layout.PartitionKeys {
+		for _, c := range schema.Columns {
+			if c.Name == key {
+				return nil, fmt.Errorf("column %s is both a data column and a partition directory", key)
+			}
+		}
+	}
+
+	db, err := ensureDatabase(ctx, catalog, opts.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LoadResult{Files: len(layout.Files), Quoted: schema.Quoted}
+	table, exists, err := existingTable(ctx, catalog, opts.Database, opts.Table)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case exists && opts.Replace:
+		if err := catalog.DropTable(ctx, opts.Database, opts.Table, true); err != nil {
+			return nil, err
+		}
+		if table.Storage != nil && table.Storage.Location != "" {
+			fs, err := newFS(table.Storage.Location)
+			if err != nil {
+				return nil, err
+			}
+			if err := fs.Remove(table.Storage.Location); err != nil {
+				return nil, err
+			}
+		}
+		exists = false
+	case exists && !opts.Append:
+		return nil, fmt.Errorf("table %s.%s already exists (use --append to add files or --replace to recreate it)", opts.Database, opts.Table)
+	case exists:
+		if len(table.PartitionKeys) != len(layout.PartitionKeys) {
+			return nil, fmt.Errorf("table %s.%s has %d partition column(s), the files have %d", opts.Database, opts.Table, len(table.PartitionKeys), len(layout.PartitionKeys))
+		}
+		if err := checkAppend(table, format, schema); err != nil {
+			return nil, fmt.Errorf("cannot append to %s.%s: %w", opts.Database, opts.Table, err)
+		}
+	}
+
+	if !exists {
+		if db.Location == "" {
+			return nil, fmt.Errorf("database %s has no location", opts.Database)
+		}
+		table = newTable(opts, format, schema, layout, JoinLocation(db.Location, opts.Table))
+		if err := catalog.CreateTable(ctx, table); err != nil {
+			return nil, fmt.Errorf("failed to create table %s.%s: %w", opts.Database, opts.Table, err)
+		}
+		result.Created = true
+	}
+	result.Table = table
+
+	location := table.Storage.Location
+	fs, err := newFS(location)
+	if err != nil {
+		return nil, err
+	}
+	// Appended files get a per-load suffix so they never replace earlier ones
+	suffix := ""
+	if !result.Created {
+		suffix = loadSuffix()
+	}
+	for _, f := range layout.Files {
+		dir := location
+		if len(f.Partition) > 0 {
+			dir = JoinLocation(location, layout.PartitionPath(f.Partition))
+		}
+		if err := fs.Put(f.Path, dir, fileName(f.Path, suffix)); err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w", f.Path, err)
+		}
+	}
+
+	if len(layout.PartitionKeys) > 0 {
+		added, err := addPartitions(ctx, catalog, table, layout)
+		if err != nil {
+			return nil, err
+		}
+		result.Partitions = added
+	}
+	return result, nil
+}
+
+// Infer detects the format (unless given) and infers the schema of the
+// files in layout.
+func Infer(layout *Layout, format string, csvOpts CSVOptions) (string, *Schema, error) {
+	if format == "" {
+		var err error
+		if format, err = DetectFormat(layout.Files[0].Path); err != nil {
+			return "", nil, err
+		}
+	}
+
+	files := make([]string, len(layout.Files))
+	for i, f := range layout.Files {
+		files[i] = f.Path
+	}
+
+	var schema *Schema
+	var err error
+	switch format {
+	case FormatCSV:
+		schema, err = InferCSV(files, csvOpts)
+	case FormatJSON:
+		schema, err = InferJSON(files, csvOpts.Sample)
+	case FormatParquet:
+		schema, err = InferParquet(files)
+	default:
+		err = fmt.Errorf("unsupported format %q (supported: csv, json, parquet)", format)
+	}
+	return format, schema, err
+}
+
+// checkAppend fails unless files of format with schema can be added to
+// table: the same columns in the same order, each of a type the table's
+// column holds, stored the same way.
+func checkAppend(table *metastore.Table, format string, schema *Schema) error {
+	if got, want := storageFormat(table.Storage), format; got != want {
+		return fmt.Errorf("table stores %s files, the files are %s", got, want)
+	}
+	if format == FormatCSV && schema.Quoted && table.Storage.SerDe != openCSVSerDe {
+		return fmt.Errorf("the files have quoted fields, the table does not use OpenCSVSerde")
+	}
+
+	names := func(cols []metastore.Column) string {
+		s := make([]string, len(cols))
+		for i, c := range cols {
+			s[i] = c.Name
+		}
+		return strings.Join(s, ", ")
+	}
+	if len(table.Columns) != len(schema.Columns) {
+		return fmt.Errorf("table has columns (%s), the files have (%s)", names(table.Columns), names(schema.Columns))
+	}
+	for i, c := range schema.Columns {
+		tc := table.Columns[i]
+		if !strings.EqualFold(tc.Name, c.Name) {
+			return fmt.Errorf("table has columns (%s), the files have (%s)", names(table.Columns), names(schema.Columns))
+		}
+		tableType := strings.ToLower(tc.Type)
+		if mergeTypes(tableType, c.Type) != tableType {
+			return fmt.Errorf("column %s is %s in the table, %s in the files", c.Name, tc.Type, c.Type)
+		}
+	}
+	return nil
+}
+
+// storageFormat returns the load format a table's SerDe reads, or the SerDe
+// itself for tables not created by Load.
+func storageFormat(s *metastore.Storage) string {
+	if s == nil {
+		return "unknown"
+	}
+	switch s.SerDe {
+	case lazySimpleSerDe, openCSVSerDe:
+		return FormatCSV
+	case jsonSerDe:
+		return FormatJSON
+	case parquetHiveSerDe:
+		return FormatParquet
+	default:
+		return s.SerDe
+	}
+}
+
+// loadSuffix returns a suffix unique to one load: its UTC time and a random
+// part, e.g. 20240101T120000-1a2b3c4d.
+func loadSuffix() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(b)
+}
+
+// fileName returns the base name of path with suffix inserted before its
+// extensions (orders.csv.gz -> orders-<suffix>.csv.gz).
+func fileName(path, suffix string) string {
+	base := filepath.Base(path)
+	if suffix == "" {
+		return base
+	}
+	stem, ext, _ := strings.Cut(base, ".")
+	if ext != "" {
+		ext = "." + ext
+	}
+	return stem + "-" + suffix + ext
+}
+
+// ensureDatabase creates the database if it does not exist and returns it.
+func ensureDatabase(ctx context.Context, catalog Catalog, name string) (*metastore.Database, error) {
+	dbs, err := catalog.Databases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, db := range dbs {
+		found = found || strings.EqualFold(db, name)
+	}
+	if !found {
+		if err := catalog.CreateDatabase(ctx, &metastore.Database{Name: name}); err != nil {
+			return nil, fmt.Errorf("failed to create database %s: %w", name, err)
+		}
+	}
+	return catalog.Database(ctx, name)
+}
+
+func existingTable(ctx context.Context, catalog Catalog, db, name string) (*metastore.Table, bool, error) {
+	tables, err := catalog.Tables(ctx, db)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, t := range tables {
+		if strings.EqualFold(t, name) {
+			table, err := catalog.Table(ctx, db, name)
+			return table, err == nil, err
+		}
+	}
+	return nil, false, nil
+}
+
+// newTable builds the table definition for a format.
+func newTable(opts LoadOptions, format string, schema *Schema, layout *Layout, location string) *metastore.Table {
+	t := &metastore.Table{
+		Database:   opts.Database,
+		Name:       opts.Table,
+		Type:       metastore.ExternalTable,
+		Columns:    schema.Columns,
+		Parameters: map[string]string{externalTableParam: "TRUE", externalPurgeParam: "TRUE"},
+		Storage:    &metastore.Storage{Location: location},
+	}
+	for _, key := range layout.PartitionKeys {
+		t.PartitionKeys = append(t.PartitionKeys, metastore.Column{Name: key, Type: TypeString})
+	}
+
+	switch format {
+	case FormatCSV:
+		delim := string(opts.CSV.delimiterFor(layout.Files[0].Path))
+		t.Storage.InputFormat = textInputFormat
+		t.Storage.OutputFormat = textOutputFormat
+		if schema.Quoted {
+			t.Storage.SerDe = openCSVSerDe
+			t.Storage.SerDeParameters = map[string]string{"separatorChar": delim, "quoteChar": `"`, "escapeChar": `\`}
+		} else {
+			t.Storage.SerDe = lazySimpleSerDe
+			t.Storage.SerDeParameters = map[string]string{"field.delim": delim, "serialization.format": delim}
+		}
+		if !opts.CSV.NoHeader {
+			t.Parameters[skipHeaderLinesParam] = "1"
+		}
+	case FormatJSON:
+		t.Storage.InputFormat = textInputFormat
+		t.Storage.OutputFormat = textOutputFormat
+		t.Storage.SerDe = jsonSerDe
+	case FormatParquet:
+		t.Storage.InputFormat = parquetInputFormat
+		t.Storage.OutputFormat = parquetOutputFormat
+		t.Storage.SerDe = parquetHiveSerDe
+	}
+	return t
+}
+
+// addPartitions registers the layout's partitions that the table does not
+// have yet and returns how many were added.
+func addPartitions(ctx context.Context, catalog Catalog, table *metastore.Table, layout *Layout) (int, error) {
+	existing, err := catalog.Partitions(ctx, table.Database, table.Name)
+	if err != nil {
+		return 0, err
+	}
+	have := map[string]bool{}
+	for _, p := range existing {
+		have[strings.Join(p.Values, "\x00")] = true
+	}
+
+	var parts []*metastore.Partition
+	for _, values := range layout.Partitions() {
+		if have[strings.Join(values, "\x00")] {
+			continue
+		}
+		storage := *table.Storage
+		storage.Location = JoinLocation(table.Storage.Location, layout.PartitionPath(values))
+		parts = append(parts, &metastore.Partition{Values: values, Storage: &storage})
+	}
+	if len(parts) == 0 {
+		return 0, nil
+	}
+	if err := catalog.AddPartitions(ctx, table, parts); err != nil {
+		return 0, fmt.Errorf("failed to add partitions: %w", err)
+	}
+	return len(parts), nil
+}
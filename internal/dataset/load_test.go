@@ -0,0 +1,241 @@
+package dataset
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/metastore"
+)
+
+// fakeCatalog is an in-memory Catalog.
+type fakeCatalog struct {
+	dbs        map[string]*metastore.Database
+	tables     map[string]*metastore.Table
+	partitions map[string][]*metastore.Partition
+	warehouse  string
+}
+
+func newFakeCatalog(warehouse string) *fakeCatalog {
+	return &fakeCatalog{
+		dbs:        map[string]*metastore.Database{},
+		tables:     map[string]*metastore.Table{},
+		partitions: map[string][]*metastore.Partition{},
+		warehouse:  warehouse,
+	}
+}
+
+func (f *fakeCatalog) Databases(context.Context) ([]string, error) {
+	var names []string
+	for name := range f.dbs {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (f *fakeCatalog) Database(_ context.Context, name string) (*metastore.Database, error) {
+	db, ok := f.dbs[name]
+	if !ok {
+		return nil, errors.New("no such database")
+	}
+	return db, nil
+}
+
+func (f *fakeCatalog) Tables(_ context.Context, db string) ([]string, error) {
+	var names []string
+	for key := range f.tables {
+		if name, ok := strings.CutPrefix(key, db+"."); ok {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (f *fakeCatalog) Table(_ context.Context, db, name string) (*metastore.Table, error) {
+	t, ok := f.tables[db+"."+name]
+	if !ok {
+		return nil, errors.New("no such table")
+	}
+	return t, nil
+}
+
+func (f *fakeCatalog) Partitions(_ context.Context, db, table string) ([]*metastore.Partition, error) {
+	return f.partitions[db+"."+table], nil
+}
+
+func (f *fakeCatalog) CreateDatabase(_ context.Context, db *metastore.Database) error {
+	copied := *db
+	copied.Location = "file:" + filepath.Join(f.warehouse, db.Name+".db")
+	f.dbs[db.Name] = &copied
+	return nil
+}
+
+func (f *fakeCatalog) CreateTable(_ context.Context, t *metastore.Table) error {
+	f.tables[t.Database+"."+t.Name] = t
+	return nil
+}
+
+func (f *fakeCatalog) DropTable(_ context.Context, db, name string, _ bool) error {
+	delete(f.tables, db+"."+name)
+	delete(f.partitions, db+"."+name)
+	return nil
+}
+
+func (f *fakeCatalog) AddPartitions(_ context.Context, t *metastore.Table, parts []*metastore.Partition) error {
+	key := t.Database + "." + t.Name
+	f.partitions[key] = append(f.partitions[key], parts...)
+	return nil
+}
+
+func newLocalFS(string) (FileSystem, error) { return localFS{}, nil }
+
+func TestLoad_CreatesPartitionedTable(t *testing.T) {
+	src := t.TempDir()
+	warehouse := t.TempDir()
+	writeFile(t, filepath.Join(src, "ds=2024-01-01", "a.csv"), "id,amount\n1,2.5\n")
+	writeFile(t, filepath.Join(src, "ds=2024-01-02", "b.csv"), "id,amount\n2,3\n")
+
+	layout, err := Scan(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cat := newFakeCatalog(warehouse)
+	res, err := Load(context.Background(), cat, newLocalFS, layout, LoadOptions{Database: "sales", Table: "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Created || res.Files != 2 || res.Partitions != 2 {
+		t.Fatalf("result = %+v", res)
+	}
+
+	table := cat.tables["sales.orders"]
+	if table.Type != metastore.ExternalTable || table.Parameters[externalPurgeParam] != "TRUE" || table.Parameters[skipHeaderLinesParam] != "1" {
+		t.Fatalf("table = %+v", table)
+	}
+	if table.Storage.SerDe != lazySimpleSerDe || table.Storage.SerDeParameters["field.delim"] != "," {
+		t.Fatalf("storage = %+v", table.Storage)
+	}
+	if len(table.PartitionKeys) != 1 || table.PartitionKeys[0].Name != "ds" {
+		t.Fatalf("partition keys = %+v", table.PartitionKeys)
+	}
+	copied := filepath.Join(warehouse, "sales.db", "orders", "ds=2024-01-02", "b.csv")
+	if _, err := os.Stat(copied); err != nil {
+		t.Fatalf("file not copied: %v", err)
+	}
+	if loc := cat.partitions["sales.orders"][1].Storage.Location; !strings.HasSuffix(loc, "/orders/ds=2024-01-02") {
+		t.Fatalf("partition location = %s", loc)
+	}
+}
+
+func TestLoad_ExistingTable(t *testing.T) {
+	src := t.TempDir()
+	warehouse := t.TempDir()
+	file := filepath.Join(src, "a.jsonl")
+	writeFile(t, file, `{"id": 1}`+"\n")
+	layout, err := Scan(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cat := newFakeCatalog(warehouse)
+	ctx := context.Background()
+	opts := LoadOptions{Database: "raw", Table: "events"}
+	if _, err := Load(ctx, cat, newLocalFS, layout, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(ctx, cat, newLocalFS, layout, opts); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected already exists error, got %v", err)
+	}
+
+	opts.Append = true
+	res, err := Load(ctx, cat, newLocalFS, layout, opts)
+	if err != nil || res.Created {
+		t.Fatalf("append: %+v, %v", res, err)
+	}
+	files, _ := filepath.Glob(filepath.Join(warehouse, "raw.db", "events", "a*.jsonl"))
+	if len(files) != 2 {
+		t.Fatalf("appended file should not replace the loaded one, got %v", files)
+	}
+
+	// Appended files must match the table's columns and format
+	other := filepath.Join(src, "b.jsonl")
+	writeFile(t, other, `{"id": "x"}`+"\n")
+	bad, err := Scan(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(ctx, cat, newLocalFS, bad, opts); err == nil || !strings.Contains(err.Error(), "column id is bigint in the table, string in the files") {
+		t.Fatalf("expected column type error, got %v", err)
+	}
+	writeFile(t, other, `{"id": 2, "name": "x"}`+"\n")
+	if _, err := Load(ctx, cat, newLocalFS, bad, opts); err == nil || !strings.Contains(err.Error(), "table has columns (id), the files have (id, name)") {
+		t.Fatalf("expected columns error, got %v", err)
+	}
+	csvFile := filepath.Join(src, "c.csv")
+	writeFile(t, csvFile, "id\n1\n")
+	csvLayout, err := Scan(csvFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(ctx, cat, newLocalFS, csvLayout, opts); err == nil || !strings.Contains(err.Error(), "table stores json files") {
+		t.Fatalf("expected format error, got %v", err)
+	}
+
+	stale := filepath.Join(warehouse, "raw.db", "events", "stale.jsonl")
+	writeFile(t, stale, "{}\n")
+	opts = LoadOptions{Database: "raw", Table: "events", Replace: true}
+	res, err = Load(ctx, cat, newLocalFS, layout, opts)
+	if err != nil || !res.Created {
+		t.Fatalf("replace: %+v, %v", res, err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatal("replace should remove the old table data")
+	}
+	if cat.tables["raw.events"].Storage.SerDe != jsonSerDe {
+		t.Fatalf("storage = %+v", cat.tables["raw.events"].Storage)
+	}
+}
+
+func TestLoad_PartitionColumnClash(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "id=1", "a.csv"), "id\n1\n")
+	layout, err := Scan(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Load(context.Background(), newFakeCatalog(t.TempDir()), newLocalFS, layout, LoadOptions{Database: "d", Table: "t"})
+	if err == nil || !strings.Contains(err.Error(), "partition directory") {
+		t.Fatalf("expected clash error, got %v", err)
+	}
+}
+
+func TestParseTableName(t *testing.T) {
+	if db, tbl, err := ParseTableName("Sales.Orders"); err != nil || db != "sales" || tbl != "orders" {
+		t.Fatalf("got %s.%s, %v", db, tbl, err)
+	}
+	if db, _, err := ParseTableName("orders"); err != nil || db != defaultDatabaseName {
+		t.Fatalf("got %s, %v", db, err)
+	}
+	for _, bad := range []string{"", "a.", "a.b.c"} {
+		if _, _, err := ParseTableName(bad); err == nil {
+			t.Errorf("ParseTableName(%q) should fail", bad)
+		}
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := []struct{ path, suffix, want string }{
+		{"/src/a.csv", "", "a.csv"},
+		{"/src/a.csv", "x", "a-x.csv"},
+		{"/src/orders.csv.gz", "x", "orders-x.csv.gz"},
+		{"/src/events", "x", "events-x"},
+	}
+	for _, tt := range tests {
+		if got := fileName(tt.path, tt.suffix); got != tt.want {
+			t.Errorf("fileName(%q, %q) = %q, want %q", tt.path, tt.suffix, got, tt.want)
+		}
+	}
+}
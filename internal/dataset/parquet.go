@@ -0,0 +1,427 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/danieljhkim/local-data-platform/internal/metastore"
+)
+
+const parquetMagic = "PAR1"
+
+// Parquet physical types.
+const (
+	parquetBoolean = iota
+	parquetInt32
+	parquetInt64
+	parquetInt96
+	parquetFloat
+	parquetDouble
+	parquetByteArray
+	parquetFixedLenByteArray
+)
+
+// Parquet converted types (the legacy logical type annotation).
+const (
+	convertedUTF8            = 0
+	convertedMap             = 1
+	convertedMapKeyValue     = 2
+	convertedList            = 3
+	convertedEnum            = 4
+	convertedDecimal         = 5
+	convertedDate            = 6
+	convertedTimestampMillis = 9
+	convertedTimestampMicros = 10
+	convertedUint8           = 11
+	convertedUint16          = 12
+	convertedUint32          = 13
+	convertedUint64          = 14
+	convertedInt8            = 15
+	convertedInt16           = 16
+	convertedJSON            = 19
+)
+
+// LogicalType union members.
+const (
+	logicalString    = 1
+	logicalMap       = 2
+	logicalList      = 3
+	logicalEnum      = 4
+	logicalDecimal   = 5
+	logicalDate      = 6
+	logicalTimestamp = 8
+	logicalInteger   = 10
+	logicalJSON      = 12
+	logicalUUID      = 14
+)
+
+const repetitionRepeated = 2
+
+// schemaElement is the subset of the Parquet SchemaElement read from footers.
+type schemaElement struct {
+	Type        int32 // -1 for groups
+	Repetition  int32
+	Name        string
+	NumChildren int32
+	Converted   int32 // -1 if unset
+	Scale       int32
+	Precision   int32
+	Logical     int16 // LogicalType union field id; 0 if unset
+	BitWidth    int8
+	Signed      bool
+}
+
+type schemaNode struct {
+	schemaElement
+	children []*schemaNode
+}
+
+// InferParquet reads the column types from the footer of the first file.
+func InferParquet(files []string) (*Schema, error) {
+	elements, err := readParquetSchema(files[0])
+	if err != nil {
+		return nil, err
+	}
+	root, next, err := buildSchemaTree(elements, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", files[0], err)
+	}
+	if next != len(elements) {
+		return nil, fmt.Errorf("%s: malformed Parquet schema", files[0])
+	}
+
+	schema := &Schema{}
+	for _, child := range root.children {
+		schema.Columns = append(schema.Columns, metastore.Column{
+			Name: strings.ToLower(child.Name),
+			Type: parquetHiveType(child),
+		})
+	}
+	return schema, nil
+}
+
+// readParquetSchema reads the FileMetaData schema list from a Parquet footer:
+// <data> <footer> <4-byte little-endian footer length> "PAR1".
+func readParquetSchema(path string) ([]schemaElement, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < 12 {
+		return nil, fmt.Errorf("%s is not a Parquet file", path)
+	}
+	tail := make([]byte, 8)
+	if _, err := f.ReadAt(tail, info.Size()-8); err != nil {
+		return nil, err
+	}
+	if string(tail[4:]) != parquetMagic {
+		return nil, fmt.Errorf("%s is not a Parquet file", path)
+	}
+	size := int64(binary.LittleEndian.Uint32(tail[:4]))
+	if size <= 0 || size > info.Size()-12 {
+		return nil, fmt.Errorf("%s: invalid Parquet footer length", path)
+	}
+	footer := make([]byte, size)
+	if _, err := f.ReadAt(footer, info.Size()-8-size); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	elements, err := decodeFileMetaDataSchema(footer)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read Parquet footer: %w", path, err)
+	}
+	return elements, nil
+}
+
+// decodeFileMetaDataSchema decodes field 2 (schema) of a compact-protocol
+// FileMetaData struct and skips everything else.
+func decodeFileMetaDataSchema(footer []byte) ([]schemaElement, error) {
+	ctx := context.Background()
+	p := thrift.NewTCompactProtocol(&thrift.TMemoryBuffer{Buffer: bytes.NewBuffer(footer)})
+
+	var elements []schemaElement
+	if _, err := p.ReadStructBegin(ctx); err != nil {
+		return nil, err
+	}
+	for {
+		_, typ, id, err := p.ReadFieldBegin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if typ == thrift.STOP {
+			break
+		}
+		if id != 2 || typ != thrift.LIST {
+			if err := p.Skip(ctx, typ); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		_, n, err := p.ReadListBegin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < n; i++ {
+			el, err := readSchemaElement(ctx, p)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, el)
+		}
+		if err := p.ReadListEnd(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("no schema in footer")
+	}
+	return elements, nil
+}
+
+func readSchemaElement(ctx context.Context, p thrift.TProtocol) (schemaElement, error) {
+	el := schemaElement{Type: -1, Converted: -1}
+	if _, err := p.ReadStructBegin(ctx); err != nil {
+		return el, err
+	}
+	for {
+		_, typ, id, err := p.ReadFieldBegin(ctx)
+		if err != nil {
+			return el, err
+		}
+		if typ == thrift.STOP {
+			break
+		}
+		switch {
+		case id == 1 && typ == thrift.I32:
+			el.Type, err = p.ReadI32(ctx)
+		case id == 3 && typ == thrift.I32:
+			el.Repetition, err = p.ReadI32(ctx)
+		case id == 4 && typ == thrift.STRING:
+			el.Name, err = p.ReadString(ctx)
+		case id == 5 && typ == thrift.I32:
+			el.NumChildren, err = p.ReadI32(ctx)
+		case id == 6 && typ == thrift.I32:
+			el.Converted, err = p.ReadI32(ctx)
+		case id == 7 && typ == thrift.I32:
+			el.Scale, err = p.ReadI32(ctx)
+		case id == 8 && typ == thrift.I32:
+			el.Precision, err = p.ReadI32(ctx)
+		case id == 10 && typ == thrift.STRUCT:
+			err = readLogicalType(ctx, p, &el)
+		default:
+			err = p.Skip(ctx, typ)
+		}
+		if err != nil {
+			return el, err
+		}
+	}
+	return el, p.ReadStructEnd(ctx)
+}
+
+// readLogicalType reads the LogicalType union, keeping the member id and
+// the DECIMAL and INTEGER parameters.
+func readLogicalType(ctx context.Context, p thrift.TProtocol, el *schemaElement) error {
+	if _, err := p.ReadStructBegin(ctx); err != nil {
+		return err
+	}
+	for {
+		_, typ, id, err := p.ReadFieldBegin(ctx)
+		if err != nil {
+			return err
+		}
+		if typ == thrift.STOP {
+			break
+		}
+		el.Logical = id
+		if typ != thrift.STRUCT || (id != logicalDecimal && id != logicalInteger) {
+			if err := p.Skip(ctx, typ); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := p.ReadStructBegin(ctx); err != nil {
+			return err
+		}
+		for {
+			_, ftyp, fid, err := p.ReadFieldBegin(ctx)
+			if err != nil {
+				return err
+			}
+			if ftyp == thrift.STOP {
+				break
+			}
+			switch {
+			case id == logicalDecimal && fid == 1 && ftyp == thrift.I32:
+				el.Scale, err = p.ReadI32(ctx)
+			case id == logicalDecimal && fid == 2 && ftyp == thrift.I32:
+				el.Precision, err = p.ReadI32(ctx)
+			case id == logicalInteger && fid == 1 && ftyp == thrift.BYTE:
+				el.BitWidth, err = p.ReadByte(ctx)
+			case id == logicalInteger && fid == 2 && ftyp == thrift.BOOL:
+				el.Signed, err = p.ReadBool(ctx)
+			default:
+				err = p.Skip(ctx, ftyp)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err := p.ReadStructEnd(ctx); err != nil {
+			return err
+		}
+	}
+	return p.ReadStructEnd(ctx)
+}
+
+// buildSchemaTree rebuilds the schema tree from its depth-first flattening.
+func buildSchemaTree(elements []schemaElement, i int) (*schemaNode, int, error) {
+	if i >= len(elements) {
+		return nil, i, fmt.Errorf("malformed Parquet schema")
+	}
+	node := &schemaNode{schemaElement: elements[i]}
+	next := i + 1
+	for c := int32(0); c < node.NumChildren; c++ {
+		child, n, err := buildSchemaTree(elements, next)
+		if err != nil {
+			return nil, n, err
+		}
+		node.children = append(node.children, child)
+		next = n
+	}
+	return node, next, nil
+}
+
+// parquetHiveType maps a Parquet field to the Hive type Hive's Parquet SerDe reads it as.
+func parquetHiveType(n *schemaNode) string {
+	t := parquetBaseType(n)
+	if n.Repetition == repetitionRepeated {
+		return "array<" + t + ">"
+	}
+	return t
+}
+
+func parquetBaseType(n *schemaNode) string {
+	if len(n.children) > 0 || n.Type < 0 {
+		switch {
+		case n.Converted == convertedList || n.Logical == logicalList:
+			return "array<" + listElementType(n) + ">"
+		case n.Converted == convertedMap || n.Converted == convertedMapKeyValue || n.Logical == logicalMap:
+			if len(n.children) == 1 && len(n.children[0].children) == 2 {
+				kv := n.children[0].children
+				return "map<" + parquetBaseType(kv[0]) + "," + parquetHiveType(kv[1]) + ">"
+			}
+		}
+		return structType(n.children)
+	}
+
+	switch {
+	case n.Converted == convertedDecimal || n.Logical == logicalDecimal:
+		return fmt.Sprintf("decimal(%d,%d)", n.Precision, n.Scale)
+	case n.Converted == convertedDate || n.Logical == logicalDate:
+		return TypeDate
+	case n.Converted == convertedTimestampMillis || n.Converted == convertedTimestampMicros || n.Logical == logicalTimestamp:
+		return TypeTimestamp
+	case n.Converted == convertedUTF8 || n.Converted == convertedEnum || n.Converted == convertedJSON ||
+		n.Logical == logicalString || n.Logical == logicalEnum || n.Logical == logicalJSON || n.Logical == logicalUUID:
+		return TypeString
+	}
+
+	if bits, signed, ok := integerAnnotation(n); ok {
+		return integerType(bits, signed)
+	}
+
+	switch n.Type {
+	case parquetBoolean:
+		return TypeBoolean
+	case parquetInt32:
+		return "int"
+	case parquetInt64:
+		return TypeBigint
+	case parquetInt96:
+		return TypeTimestamp
+	case parquetFloat:
+		return "float"
+	case parquetDouble:
+		return TypeDouble
+	default:
+		return "binary"
+	}
+}
+
+// integerAnnotation returns the width and signedness of an annotated integer.
+func integerAnnotation(n *schemaNode) (bits int, signed, ok bool) {
+	if n.Logical == logicalInteger {
+		return int(n.BitWidth), n.Signed, true
+	}
+	switch n.Converted {
+	case convertedInt8:
+		return 8, true, true
+	case convertedInt16:
+		return 16, true, true
+	case convertedUint8:
+		return 8, false, true
+	case convertedUint16:
+		return 16, false, true
+	case convertedUint32:
+		return 32, false, true
+	case convertedUint64:
+		return 64, false, true
+	}
+	return 0, false, false
+}
+
+// integerType returns the smallest Hive integer type holding the values.
+func integerType(bits int, signed bool) string {
+	if !signed {
+		bits *= 2 // unsigned values need the next wider signed type
+	}
+	switch {
+	case bits <= 8:
+		return "tinyint"
+	case bits <= 16:
+		return "smallint"
+	case bits <= 32:
+		return "int"
+	case bits <= 64:
+		return TypeBigint
+	default:
+		return "decimal(20,0)"
+	}
+}
+
+// listElementType handles the standard three-level list (list > element)
+// and the legacy two-level forms.
+func listElementType(n *schemaNode) string {
+	if len(n.children) != 1 {
+		return structType(n.children)
+	}
+	repeated := n.children[0]
+	if len(repeated.children) == 0 {
+		return parquetBaseType(repeated)
+	}
+	if len(repeated.children) == 1 && repeated.Name != "array" && !strings.HasSuffix(repeated.Name, "_tuple") {
+		return parquetHiveType(repeated.children[0])
+	}
+	return structType(repeated.children)
+}
+
+func structType(fields []*schemaNode) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = strings.ToLower(f.Name) + ":" + parquetHiveType(f)
+	}
+	return "struct<" + strings.Join(parts, ",") + ">"
+}
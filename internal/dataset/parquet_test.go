@@ -0,0 +1,137 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/danieljhkim/local-data-platform/internal/metastore"
+)
+
+// testElement is a SchemaElement written by writeParquetFile.
+type testElement struct {
+	typ, repetition, children, converted int32 // -1: unset
+	name                                 string
+	logical                              int16
+	scale, precision                     int32
+}
+
+// writeParquetFile writes a file with no row groups and a compact-protocol
+// footer holding the version, the schema and num_rows.
+func writeParquetFile(t *testing.T, path string, elements []testElement) {
+	t.Helper()
+	ctx := context.Background()
+	buf := thrift.NewTMemoryBuffer()
+	p := thrift.NewTCompactProtocol(buf)
+
+	i32 := func(id int16, v int32) {
+		if v < 0 {
+			return
+		}
+		p.WriteFieldBegin(ctx, "", thrift.I32, id)
+		p.WriteI32(ctx, v)
+		p.WriteFieldEnd(ctx)
+	}
+
+	p.WriteStructBegin(ctx, "FileMetaData")
+	i32(1, 1)
+	p.WriteFieldBegin(ctx, "schema", thrift.LIST, 2)
+	p.WriteListBegin(ctx, thrift.STRUCT, len(elements))
+	for _, el := range elements {
+		p.WriteStructBegin(ctx, "SchemaElement")
+		i32(1, el.typ)
+		i32(3, el.repetition)
+		p.WriteFieldBegin(ctx, "name", thrift.STRING, 4)
+		p.WriteString(ctx, el.name)
+		p.WriteFieldEnd(ctx)
+		if el.children > 0 {
+			i32(5, el.children)
+		}
+		i32(6, el.converted)
+		if el.logical != 0 {
+			p.WriteFieldBegin(ctx, "logicalType", thrift.STRUCT, 10)
+			p.WriteStructBegin(ctx, "LogicalType")
+			p.WriteFieldBegin(ctx, "", thrift.STRUCT, el.logical)
+			p.WriteStructBegin(ctx, "")
+			if el.logical == logicalDecimal {
+				i32(1, el.scale)
+				i32(2, el.precision)
+			}
+			p.WriteFieldStop(ctx)
+			p.WriteStructEnd(ctx)
+			p.WriteFieldEnd(ctx)
+			p.WriteFieldStop(ctx)
+			p.WriteStructEnd(ctx)
+			p.WriteFieldEnd(ctx)
+		}
+		p.WriteFieldStop(ctx)
+		p.WriteStructEnd(ctx)
+	}
+	p.WriteListEnd(ctx)
+	p.WriteFieldEnd(ctx)
+	p.WriteFieldBegin(ctx, "num_rows", thrift.I64, 3)
+	p.WriteI64(ctx, 0)
+	p.WriteFieldEnd(ctx)
+	p.WriteFieldStop(ctx)
+	p.WriteStructEnd(ctx)
+	p.Flush(ctx)
+
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+	file.Write(buf.Bytes())
+	binary.Write(&file, binary.LittleEndian, uint32(buf.Len()))
+	file.WriteString(parquetMagic)
+	writeFile(t, path, file.String())
+}
+
+func TestInferParquet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.parquet")
+	writeParquetFile(t, path, []testElement{
+		{typ: -1, repetition: -1, converted: -1, name: "schema", children: 8},
+		{typ: parquetInt64, repetition: 0, converted: -1, name: "ID"},
+		{typ: parquetByteArray, repetition: 1, converted: convertedUTF8, name: "name"},
+		{typ: parquetInt32, repetition: 1, converted: -1, name: "day", logical: logicalDate},
+		{typ: parquetFixedLenByteArray, repetition: 1, converted: -1, name: "price", logical: logicalDecimal, scale: 2, precision: 10},
+		{typ: parquetInt96, repetition: 1, converted: -1, name: "ts"},
+		{typ: parquetInt32, repetition: 1, converted: convertedUint16, name: "small"},
+		// LIST: tags (LIST) -> list (repeated) -> element
+		{typ: -1, repetition: 1, converted: convertedList, name: "tags", children: 1},
+		{typ: -1, repetition: repetitionRepeated, converted: -1, name: "list", children: 1},
+		{typ: parquetByteArray, repetition: 1, converted: convertedUTF8, name: "element"},
+		// MAP: attrs (MAP) -> key_value (repeated) -> key, value
+		{typ: -1, repetition: 1, converted: convertedMap, name: "attrs", children: 1},
+		{typ: -1, repetition: repetitionRepeated, converted: -1, name: "key_value", children: 2},
+		{typ: parquetByteArray, repetition: 0, converted: convertedUTF8, name: "key"},
+		{typ: parquetDouble, repetition: 1, converted: -1, name: "value"},
+	})
+
+	schema, err := InferParquet([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []metastore.Column{
+		{Name: "id", Type: TypeBigint},
+		{Name: "name", Type: TypeString},
+		{Name: "day", Type: TypeDate},
+		{Name: "price", Type: "decimal(10,2)"},
+		{Name: "ts", Type: TypeTimestamp},
+		{Name: "small", Type: "int"},
+		{Name: "tags", Type: "array<string>"},
+		{Name: "attrs", Type: "map<string,double>"},
+	}
+	if !reflect.DeepEqual(schema.Columns, want) {
+		t.Fatalf("columns = %+v\nwant %+v", schema.Columns, want)
+	}
+}
+
+func TestInferParquet_NotParquet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.parquet")
+	writeFile(t, path, "id,name\n1,x\n")
+	if _, err := InferParquet([]string{path}); err == nil {
+		t.Fatal("expected error for a non-Parquet file")
+	}
+}
@@ -0,0 +1,127 @@
+package dataset
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/metastore"
+)
+
+// File formats.
+const (
+	FormatCSV     = "csv"
+	FormatJSON    = "json"
+	FormatParquet = "parquet"
+)
+
+// Hive column types produced by inference.
+const (
+	TypeBoolean   = "boolean"
+	TypeBigint    = "bigint"
+	TypeDouble    = "double"
+	TypeDate      = "date"
+	TypeTimestamp = "timestamp"
+	TypeString    = "string"
+)
+
+// DetectFormat returns the format for a file extension (.csv, .tsv, .json,
+// .jsonl, .ndjson, .parquet), ignoring a trailing .gz.
+func DetectFormat(path string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(strings.TrimSuffix(strings.ToLower(path), ".gz")))
+	switch ext {
+	case ".csv", ".tsv", ".txt":
+		return FormatCSV, nil
+	case ".json", ".jsonl", ".ndjson":
+		return FormatJSON, nil
+	case ".parquet", ".parq":
+		return FormatParquet, nil
+	default:
+		return "", fmt.Errorf("cannot detect the format of %s (use --format csv|json|parquet)", path)
+	}
+}
+
+// Schema is an inferred table layout.
+type Schema struct {
+	Columns []metastore.Column
+	// Quoted is set for CSV files with quoted fields, which need OpenCSVSerde
+	// (and string columns) instead of the delimited text format.
+	Quoted bool
+}
+
+var (
+	intPattern       = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	datePattern      = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+	timestampPattern = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}[ T][0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]{1,9})?$`)
+)
+
+// inferValueType returns the narrowest Hive type for a text value; "" for
+// empty values, which do not constrain the type.
+func inferValueType(v string) string {
+	v = strings.TrimSpace(v)
+	switch {
+	case v == "" || v == `\N`:
+		return ""
+	case strings.EqualFold(v, "true") || strings.EqualFold(v, "false"):
+		return TypeBoolean
+	case intPattern.MatchString(v):
+		if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return TypeBigint
+		}
+		return TypeDouble
+	case datePattern.MatchString(v):
+		return TypeDate
+	case timestampPattern.MatchString(v):
+		return TypeTimestamp
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil && !strings.ContainsAny(v, "xXpP") {
+		lower := strings.ToLower(v)
+		if !strings.Contains(lower, "inf") && !strings.Contains(lower, "nan") {
+			return TypeDouble
+		}
+	}
+	return TypeString
+}
+
+// mergeTypes returns a type that holds values of both a and b.
+func mergeTypes(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "" || a == b:
+		return a
+	case (a == TypeBigint && b == TypeDouble) || (a == TypeDouble && b == TypeBigint):
+		return TypeDouble
+	case (a == TypeDate && b == TypeTimestamp) || (a == TypeTimestamp && b == TypeDate):
+		return TypeTimestamp
+	default:
+		return TypeString
+	}
+}
+
+var nonIdentChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// columnNames turns headers into unique Hive column names: lower case,
+// non-identifier characters replaced by '_', col_<n> for empty names.
+func columnNames(headers []string) []string {
+	names := make([]string, len(headers))
+	used := map[string]bool{}
+	for i, h := range headers {
+		name := strings.Trim(nonIdentChars.ReplaceAllString(strings.ToLower(strings.TrimSpace(h)), "_"), "_")
+		if name == "" {
+			name = fmt.Sprintf("col_%d", i+1)
+		}
+		if name[0] >= '0' && name[0] <= '9' {
+			name = "c_" + name
+		}
+		base := name
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[name] = true
+		names[i] = name
+	}
+	return names
+}
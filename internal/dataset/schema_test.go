@@ -0,0 +1,164 @@
+package dataset
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/metastore"
+)
+
+func TestInferValueType(t *testing.T) {
+	tests := map[string]string{
+		"":                        "",
+		`\N`:                      "",
+		"TRUE":                    TypeBoolean,
+		"42":                      TypeBigint,
+		"-7":                      TypeBigint,
+		"99999999999999999999":    TypeDouble,
+		"3.14":                    TypeDouble,
+		"1e3":                     TypeDouble,
+		"2024-01-31":              TypeDate,
+		"2024-01-31 12:00:00":     TypeTimestamp,
+		"2024-01-31T12:00:00.123": TypeTimestamp,
+		"NaN":                     TypeString,
+		"0x1p3":                   TypeString,
+		"hello":                   TypeString,
+	}
+	for in, want := range tests {
+		if got := inferValueType(in); got != want {
+			t.Errorf("inferValueType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMergeTypes(t *testing.T) {
+	tests := []struct{ a, b, want string }{
+		{"", TypeBigint, TypeBigint},
+		{TypeBigint, TypeDouble, TypeDouble},
+		{TypeDate, TypeTimestamp, TypeTimestamp},
+		{TypeBigint, TypeBoolean, TypeString},
+	}
+	for _, tt := range tests {
+		if got := mergeTypes(tt.a, tt.b); got != tt.want {
+			t.Errorf("mergeTypes(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestColumnNames(t *testing.T) {
+	got := columnNames([]string{"Order ID", "", "2nd", "order id", "price($)"})
+	want := []string{"order_id", "col_2", "c_2nd", "order_id_2", "price"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("columnNames = %v, want %v", got, want)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	for path, want := range map[string]string{
+		"a.csv": FormatCSV, "a.TSV.gz": FormatCSV, "a.jsonl": FormatJSON, "a.parquet": FormatParquet,
+	} {
+		if got, err := DetectFormat(path); err != nil || got != want {
+			t.Errorf("DetectFormat(%q) = %q, %v", path, got, err)
+		}
+	}
+	if _, err := DetectFormat("a.xlsx"); err == nil {
+		t.Error("expected error for unknown extension")
+	}
+}
+
+func TestInferCSV(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.csv")
+	b := filepath.Join(dir, "b.csv")
+	writeFile(t, a, "id,amount,day,note\n1,10,2024-01-01,\n2,11,2024-01-02,x\n")
+	writeFile(t, b, "id,amount,day,note\n3,1.5,2024-01-03 10:00:00,y\n")
+
+	schema, err := InferCSV([]string{a, b}, CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []metastore.Column{
+		{Name: "id", Type: TypeBigint},
+		{Name: "amount", Type: TypeDouble},
+		{Name: "day", Type: TypeTimestamp},
+		{Name: "note", Type: TypeString},
+	}
+	if !reflect.DeepEqual(schema.Columns, want) || schema.Quoted {
+		t.Fatalf("schema = %+v", schema)
+	}
+}
+
+func TestInferCSV_QuotedFieldsAreStrings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.csv")
+	writeFile(t, path, "id,name\n1,\"Smith, J\"\n")
+
+	schema, err := InferCSV([]string{path}, CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !schema.Quoted || schema.Columns[0].Type != TypeString {
+		t.Fatalf("schema = %+v", schema)
+	}
+}
+
+func TestInferCSV_NoHeaderTabAndGzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.tsv.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	gz.Write([]byte("1\ttrue\n2\tfalse\n"))
+	gz.Close()
+	f.Close()
+
+	schema, err := InferCSV([]string{path}, CSVOptions{NoHeader: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []metastore.Column{{Name: "col_1", Type: TypeBigint}, {Name: "col_2", Type: TypeBoolean}}
+	if !reflect.DeepEqual(schema.Columns, want) {
+		t.Fatalf("columns = %+v", schema.Columns)
+	}
+}
+
+func TestInferJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.jsonl")
+	writeFile(t, path, `{"id": 1, "tags": ["a"], "user": {"Name": "x", "age": 3}}
+{"id": 2.5, "extra": null, "tags": []}
+`)
+
+	schema, err := InferJSON([]string{path}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	var order []string
+	for _, c := range schema.Columns {
+		got[c.Name] = c.Type
+		order = append(order, c.Name)
+	}
+	want := map[string]string{
+		"id":    TypeDouble,
+		"tags":  "array<string>",
+		"user":  "struct<age:bigint,name:string>",
+		"extra": TypeString,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("types = %v, want %v", got, want)
+	}
+	if order[len(order)-1] != "extra" {
+		t.Fatalf("columns should keep first-appearance order, got %v", order)
+	}
+}
+
+func TestInferJSON_RejectsArrays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.json")
+	writeFile(t, path, "[{\"id\": 1}]\n")
+	if _, err := InferJSON([]string{path}, 0); err == nil {
+		t.Fatal("expected error for a JSON array file")
+	}
+}
@@ -0,0 +1,510 @@
+package ddl
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// Object kinds created by imported statements.
+const (
+	KindDatabase = "database"
+	KindTable    = "table"
+	KindView     = "view"
+)
+
+const defaultDatabase = "default"
+
+// Target describes where imported objects live on the local platform.
+type Target struct {
+	// Warehouse is the hive.metastore.warehouse.dir URI; rewritten locations
+	// are <Warehouse>/<db>.db/<table>.
+	Warehouse string
+	// DefaultFS is fs.defaultFS; hdfs:// locations on it are kept.
+	DefaultFS string
+	// Database is used for unqualified names before any USE statement.
+	Database string
+}
+
+// TargetFromConf reads the warehouse directory from hive-site.xml and
+// fs.defaultFS from core-site.xml. A warehouse path without a scheme is on
+// fs.defaultFS.
+func TargetFromConf(hiveConfDir, hadoopConfDir string) Target {
+	var t Target
+	if cfg, err := util.ParseHadoopXML(filepath.Join(hadoopConfDir, "core-site.xml")); err == nil {
+		t.DefaultFS = strings.TrimRight(strings.TrimSpace(cfg.GetProperty("fs.defaultFS")), "/")
+	}
+	if cfg, err := util.ParseHadoopXML(filepath.Join(hiveConfDir, "hive-site.xml")); err == nil {
+		t.Warehouse = strings.TrimRight(strings.TrimSpace(cfg.GetProperty("hive.metastore.warehouse.dir")), "/")
+	}
+	if strings.HasPrefix(t.Warehouse, "/") {
+		if t.DefaultFS != "" {
+			t.Warehouse = t.DefaultFS + t.Warehouse
+		} else {
+			t.Warehouse = "file:" + t.Warehouse
+		}
+	}
+	return t
+}
+
+func (t Target) databaseLocation(db string) string {
+	if db == defaultDatabase {
+		return t.Warehouse
+	}
+	return t.Warehouse + "/" + db + ".db"
+}
+
+func (t Target) tableLocation(db, table string) string {
+	return t.databaseLocation(db) + "/" + table
+}
+
+// isRemote reports whether a location is outside the local platform: an
+// object store, or an HDFS cluster other than fs.defaultFS.
+func (t Target) isRemote(location string) bool {
+	u, err := url.Parse(location)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "file":
+		return false
+	case "hdfs", "viewfs":
+		local, err := url.Parse(t.DefaultFS)
+		return err != nil || !strings.EqualFold(local.Host, u.Host) || !strings.EqualFold(local.Scheme, u.Scheme)
+	default:
+		return true
+	}
+}
+
+// Table properties dropped without a note: statistics and bookkeeping that
+// are wrong for an empty local table.
+var statsProperties = map[string]bool{
+	"transient_lastDdlTime": true,
+	"numFiles":              true,
+	"numRows":               true,
+	"rawDataSize":           true,
+	"totalSize":             true,
+	"numFilesErasureCoded":  true,
+	"numPartitions":         true,
+	"COLUMN_STATS_ACCURATE": true,
+	"last_modified_by":      true,
+	"last_modified_time":    true,
+}
+
+// Table properties of other engines and catalogs (Impala, Trino, AWS Glue)
+// and of Iceberg tables whose metadata lives in production.
+var (
+	unsupportedProperties = map[string]bool{
+		"metadata_location":                true,
+		"previous_metadata_location":       true,
+		"classification":                   true,
+		"has_encrypted_data":               true,
+		"UPDATED_BY_CRAWLER":               true,
+		"CrawlerSchemaDeserializerVersion": true,
+		"CrawlerSchemaSerializerVersion":   true,
+		"averageRecordSize":                true,
+		"objectCount":                      true,
+		"recordCount":                      true,
+		"sizeKey":                          true,
+		"compressionType":                  true,
+		"typeOfData":                       true,
+	}
+	unsupportedPropertyPrefixes = []string{"spark.sql.statistics.", "impala.", "presto_", "trino_", "aws.", "glue."}
+)
+
+// serdeReplacements maps SerDes that are not on the Hive classpath to
+// built-in equivalents.
+var serdeReplacements = map[string]string{
+	"org.openx.data.jsonserde.JsonSerDe":                      "org.apache.hadoop.hive.serde2.JsonSerDe",
+	"org.apache.hive.hcatalog.data.JsonSerDe":                 "org.apache.hadoop.hive.serde2.JsonSerDe",
+	"org.apache.hadoop.hive.contrib.serde2.MultiDelimitSerDe": "org.apache.hadoop.hive.serde2.MultiDelimitSerDe",
+}
+
+// supportedClass reports whether a SerDe or input/output format class ships
+// with Hive and Hadoop.
+func supportedClass(class string) bool {
+	for _, prefix := range []string{"org.apache.hadoop.hive.", "org.apache.hadoop.mapred.", "org.apache.hadoop.mapreduce.", "org.apache.iceberg."} {
+		if strings.HasPrefix(class, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// supportedStorageHandler reports whether a STORED BY handler is available;
+// HBase, Kafka, JDBC and Druid tables need services the platform lacks.
+func supportedStorageHandler(handler string) bool {
+	return strings.EqualFold(handler, "ICEBERG") || handler == "org.apache.iceberg.mr.hive.HiveIcebergStorageHandler"
+}
+
+// statement is a lexed statement with the positions of its significant
+// (non-space) tokens and their parenthesis depth.
+type statement struct {
+	tokens []token
+	sig    []int
+	depth  []int
+}
+
+func newStatement(sql string) *statement {
+	s := &statement{tokens: lex(sql)}
+	depth := 0
+	for i, t := range s.tokens {
+		if t.kind == tokSpace {
+			continue
+		}
+		if t.kind == tokPunct && t.text == ")" {
+			depth--
+		}
+		s.sig = append(s.sig, i)
+		s.depth = append(s.depth, depth)
+		if t.kind == tokPunct && t.text == "(" {
+			depth++
+		}
+	}
+	return s
+}
+
+// tok returns the n-th significant token, or a space token past the end.
+func (s *statement) tok(n int) token {
+	if n < 0 || n >= len(s.sig) {
+		return token{kind: tokSpace}
+	}
+	return s.tokens[s.sig[n]]
+}
+
+func (s *statement) isPunct(n int, p string) bool {
+	t := s.tok(n)
+	return t.kind == tokPunct && t.text == p
+}
+
+// name parses a possibly qualified name at significant token n and returns
+// the index after it.
+func (s *statement) name(n int) (db, name string, next int, ok bool) {
+	part := func(n int) (string, bool) {
+		t := s.tok(n)
+		return t.value(), t.kind == tokWord || t.kind == tokIdent
+	}
+	first, ok := part(n)
+	if !ok {
+		return "", "", n, false
+	}
+	if s.isPunct(n+1, ".") {
+		if second, ok := part(n + 2); ok {
+			return strings.ToLower(first), strings.ToLower(second), n + 3, true
+		}
+	}
+	// `db.table` quoted as a single identifier
+	if before, after, found := strings.Cut(first, "."); found && s.tok(n).kind == tokIdent {
+		return strings.ToLower(before), strings.ToLower(after), n + 1, true
+	}
+	return "", strings.ToLower(first), n + 1, true
+}
+
+// closing returns the significant index of the ')' matching the '(' at n.
+func (s *statement) closing(n int) int {
+	for i := n + 1; i < len(s.sig); i++ {
+		if s.depth[i] == s.depth[n] && s.isPunct(i, ")") {
+			return i
+		}
+	}
+	return len(s.sig) - 1
+}
+
+// edit replaces the tokens of significant indexes [from, to] (inclusive).
+type edit struct {
+	from, to int
+	text     string
+}
+
+// apply renders the statement with edits applied.
+func (s *statement) apply(edits []edit) string {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].from < edits[j].from })
+	var sb strings.Builder
+	pos := 0
+	for _, e := range edits {
+		start, end := s.sig[e.from], s.sig[e.to]+1
+		if start < pos {
+			continue // overlapping edit
+		}
+		sb.WriteString(render(s.tokens[pos:start]))
+		sb.WriteString(e.text)
+		pos = end
+		// drop the whitespace left behind by a removed clause
+		if e.text == "" {
+			for pos < len(s.tokens) && s.tokens[pos].kind == tokSpace && !strings.Contains(s.tokens[pos].text, "--") {
+				pos++
+			}
+		}
+	}
+	sb.WriteString(render(s.tokens[pos:]))
+	return strings.TrimSpace(sb.String())
+}
+
+// adapted is the result of adapting one statement.
+type adapted struct {
+	kind     string
+	database string
+	name     string
+	sql      string
+	notes    []string
+	skip     string // reason the statement is not imported
+	use      string // database selected by a USE statement
+	refs     []string
+}
+
+// adapt rewrites a single statement for the local platform. currentDB is the
+// database selected by earlier USE statements.
+func adapt(sql, currentDB string, target Target) adapted {
+	s := newStatement(sql)
+	first := s.tok(0)
+	switch {
+	case first.is("USE"):
+		_, db, _, _ := s.name(1)
+		return adapted{use: db}
+	case first.is("SET"):
+		return adapted{skip: "session settings are not imported"}
+	case first.is("ALTER") && s.tok(1).is("TABLE"):
+		return adapted{skip: "ALTER TABLE is not imported (partitions and statistics are not mirrored)"}
+	case !first.is("CREATE"):
+		return adapted{skip: fmt.Sprintf("%s statements are not imported", strings.ToUpper(first.text))}
+	}
+
+	n := 1
+	orReplace := false
+	if s.tok(n).is("OR") && s.tok(n+1).is("REPLACE") {
+		orReplace = true
+		n += 2
+	}
+	var modifiers []string
+	for s.tok(n).is("TEMPORARY") || s.tok(n).is("EXTERNAL") || s.tok(n).is("TRANSACTIONAL") ||
+		s.tok(n).is("MANAGED") || s.tok(n).is("MATERIALIZED") || s.tok(n).is("REMOTE") {
+		modifiers = append(modifiers, strings.ToUpper(s.tok(n).text))
+		n++
+	}
+	for _, m := range modifiers {
+		switch m {
+		case "TEMPORARY":
+			return adapted{skip: "temporary objects are not imported"}
+		case "REMOTE":
+			return adapted{skip: "remote databases are not supported"}
+		}
+	}
+
+	var res adapted
+	switch {
+	case s.tok(n).is("DATABASE") || s.tok(n).is("SCHEMA"):
+		res.kind = KindDatabase
+	case s.tok(n).is("TABLE"):
+		res.kind = KindTable
+	case s.tok(n).is("VIEW"):
+		res.kind = KindView
+	default:
+		return adapted{skip: fmt.Sprintf("CREATE %s statements are not imported", strings.ToUpper(s.tok(n).text))}
+	}
+	n++
+	keyword := n - 1
+
+	ifNotExists := s.tok(n).is("IF") && s.tok(n+1).is("NOT") && s.tok(n+2).is("EXISTS")
+	if ifNotExists {
+		n += 3
+	}
+	db, name, next, ok := s.name(n)
+	if !ok {
+		return adapted{skip: "cannot parse the object name"}
+	}
+
+	var edits []edit
+	if res.kind == KindDatabase {
+		res.database = name
+		if name == defaultDatabase {
+			return adapted{kind: KindDatabase, database: name, skip: "the default database already exists"}
+		}
+	} else {
+		if db == "" {
+			db = currentDB
+		}
+		res.database, res.name = db, name
+		edits = append(edits, edit{n, next - 1, hs2.QuoteIdent(db) + "." + hs2.QuoteIdent(name)})
+	}
+	if !ifNotExists && !orReplace {
+		edits = append(edits, edit{keyword, keyword, s.tok(keyword).text + " IF NOT EXISTS"})
+	}
+
+	more, skip := adaptClauses(s, next, &res, target)
+	if skip != "" {
+		res.skip = skip
+		return res
+	}
+	res.sql = s.apply(append(edits, more...))
+	return res
+}
+
+// adaptClauses rewrites the clauses after the object name: locations, table
+// properties, SerDes and storage formats. It also records the objects a
+// view or CREATE TABLE ... AS/LIKE depends on.
+func adaptClauses(s *statement, n int, res *adapted, target Target) ([]edit, string) {
+	var edits []edit
+	for i := n; i < len(s.sig); i++ {
+		if s.depth[i] != 0 {
+			continue
+		}
+		t := s.tok(i)
+		switch {
+		case (t.is("LOCATION") || t.is("MANAGEDLOCATION")) && s.tok(i+1).kind == tokString:
+			loc := s.tok(i + 1).value()
+			if !target.isRemote(loc) {
+				continue
+			}
+			if target.Warehouse == "" || t.is("MANAGEDLOCATION") {
+				// Hive picks the default location
+				edits = append(edits, edit{i, i + 1, ""})
+				res.notes = append(res.notes, fmt.Sprintf("removed %s %s", strings.ToLower(t.text), loc))
+				continue
+			}
+			local := target.databaseLocation(res.database)
+			if res.kind != KindDatabase {
+				local = target.tableLocation(res.database, res.name)
+			}
+			edits = append(edits, edit{i + 1, i + 1, hs2.QuoteString(local)})
+			res.notes = append(res.notes, fmt.Sprintf("location %s -> %s", loc, local))
+
+		case t.is("TBLPROPERTIES") || (t.is("DBPROPERTIES") && s.tok(i-1).is("WITH")):
+			if !s.isPunct(i+1, "(") {
+				continue
+			}
+			end := s.closing(i + 1)
+			e, note := adaptProperties(s, i, end, target)
+			if e != nil {
+				if t.is("DBPROPERTIES") && e.text == "" {
+					e.from = i - 1 // WITH DBPROPERTIES
+				}
+				edits = append(edits, *e)
+			}
+			if note != "" {
+				res.notes = append(res.notes, note)
+			}
+			i = end
+
+		case t.is("ROW") && s.tok(i+1).is("FORMAT") && s.tok(i+2).is("SERDE") && s.tok(i+3).kind == tokString:
+			serde := s.tok(i + 3).value()
+			if replacement, ok := serdeReplacements[serde]; ok {
+				edits = append(edits, edit{i + 3, i + 3, hs2.QuoteString(replacement)})
+				res.notes = append(res.notes, fmt.Sprintf("SerDe %s -> %s", serde, replacement))
+				continue
+			}
+			if supportedClass(serde) {
+				continue
+			}
+			end := i + 3
+			if s.tok(end+1).is("WITH") && s.tok(end+2).is("SERDEPROPERTIES") && s.isPunct(end+3, "(") {
+				end = s.closing(end + 3)
+			}
+			edits = append(edits, edit{i, end, ""})
+			res.notes = append(res.notes, fmt.Sprintf("removed unsupported SerDe %s", serde))
+			i = end
+
+		case t.is("STORED") && s.tok(i+1).is("BY"):
+			handler := s.tok(i + 2).value()
+			if !supportedStorageHandler(handler) {
+				return nil, fmt.Sprintf("storage handler %s is not supported", handler)
+			}
+
+		case t.is("STORED") && s.tok(i+1).is("AS") && s.tok(i+2).is("INPUTFORMAT") && s.tok(i+4).is("OUTPUTFORMAT"):
+			in, out := s.tok(i+3).value(), s.tok(i+5).value()
+			if !supportedClass(in) || !supportedClass(out) {
+				edits = append(edits, edit{i, i + 5, "STORED AS TEXTFILE"})
+				res.notes = append(res.notes, fmt.Sprintf("replaced unsupported input/output format %s with TEXTFILE", in))
+			}
+			i += 5
+
+		case t.is("LIKE") && res.kind == KindTable:
+			if db, name, _, ok := s.name(i + 1); ok {
+				res.refs = append(res.refs, qualify(db, name, res.database))
+			}
+
+		case t.is("AS") && !s.tok(i-1).is("STORED") && res.kind != KindDatabase:
+			res.refs = append(res.refs, queryRefs(s, i+1, res.database)...)
+			return edits, ""
+		}
+	}
+	return edits, ""
+}
+
+// adaptProperties filters a TBLPROPERTIES/DBPROPERTIES list whose '(' is at
+// significant index keyword+1 and ')' at end.
+func adaptProperties(s *statement, keyword, end int, target Target) (*edit, string) {
+	type property struct{ key, value string }
+	var kept []property
+	var removed []string
+	changed := false
+	for i := keyword + 2; i < end; i++ {
+		if s.tok(i).kind != tokString || !s.isPunct(i+1, "=") || s.tok(i+2).kind != tokString {
+			continue
+		}
+		key, value := s.tok(i).value(), s.tok(i+2).value()
+		i += 2
+		switch {
+		case statsProperties[key]:
+			changed = true
+		case unsupportedProperty(key) || target.isRemote(value) && strings.Contains(value, "://"):
+			changed = true
+			removed = append(removed, key)
+		default:
+			kept = append(kept, property{key, value})
+		}
+	}
+	if !changed {
+		return nil, ""
+	}
+
+	note := ""
+	if len(removed) > 0 {
+		note = "removed table properties: " + strings.Join(removed, ", ")
+	}
+	if len(kept) == 0 {
+		return &edit{keyword, end, ""}, note
+	}
+	lines := make([]string, len(kept))
+	for i, p := range kept {
+		lines[i] = "  " + hs2.QuoteString(p.key) + "=" + hs2.QuoteString(p.value)
+	}
+	return &edit{keyword, end, s.tok(keyword).text + " (\n" + strings.Join(lines, ",\n") + ")"}, note
+}
+
+func unsupportedProperty(key string) bool {
+	if unsupportedProperties[key] {
+		return true
+	}
+	for _, prefix := range unsupportedPropertyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryRefs returns the tables named after FROM and JOIN in a query.
+func queryRefs(s *statement, n int, currentDB string) []string {
+	var refs []string
+	for i := n; i < len(s.sig); i++ {
+		if !s.tok(i).is("FROM") && !s.tok(i).is("JOIN") {
+			continue
+		}
+		if db, name, _, ok := s.name(i + 1); ok && s.tok(i+1).kind != tokPunct {
+			refs = append(refs, qualify(db, name, currentDB))
+		}
+	}
+	return refs
+}
+
+func qualify(db, name, currentDB string) string {
+	if db == "" {
+		db = currentDB
+	}
+	return db + "." + name
+}
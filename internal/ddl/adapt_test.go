@@ -0,0 +1,130 @@
+package ddl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testTarget = Target{
+	Warehouse: "hdfs://localhost:8020/user/hive/warehouse",
+	DefaultFS: "hdfs://localhost:8020",
+	Database:  "default",
+}
+
+func TestLex_RoundTrip(t *testing.T) {
+	sql := "CREATE TABLE `a``b` (x STRING COMMENT 'it''s -- not a comment') -- trailing\n/* block */ LOCATION \"s3://b/k\""
+	tokens := lex(sql)
+	if got := render(tokens); got != sql {
+		t.Fatalf("render(lex(sql)) = %q", got)
+	}
+	if v := tokens[4].value(); v != "a`b" {
+		t.Fatalf("identifier value = %q", v)
+	}
+}
+
+func TestAdapt_ExternalTable(t *testing.T) {
+	sql := "CREATE EXTERNAL TABLE `orders`(\n" +
+		"  `id` bigint COMMENT 'order location',\n" +
+		"  `location` string)\n" +
+		"PARTITIONED BY (`ds` string)\n" +
+		"ROW FORMAT SERDE\n  'org.openx.data.jsonserde.JsonSerDe'\n" +
+		"STORED AS INPUTFORMAT\n  'org.apache.hadoop.mapred.TextInputFormat'\n" +
+		"OUTPUTFORMAT\n  'org.apache.hadoop.hive.ql.io.HiveIgnoreKeyTextOutputFormat'\n" +
+		"LOCATION\n  's3a://prod-lake/sales/orders'\n" +
+		"TBLPROPERTIES (\n  'classification'='json',\n  'parquet.compression'='SNAPPY',\n  'transient_lastDdlTime'='1700000000')"
+
+	a := adapt(sql, "sales", testTarget)
+	if a.skip != "" {
+		t.Fatalf("skipped: %s", a.skip)
+	}
+	if a.kind != KindTable || a.database != "sales" || a.name != "orders" {
+		t.Fatalf("object = %s %s.%s", a.kind, a.database, a.name)
+	}
+	for _, want := range []string{
+		"CREATE EXTERNAL TABLE IF NOT EXISTS `sales`.`orders`(",
+		"`location` string)",
+		"'org.apache.hadoop.hive.serde2.JsonSerDe'",
+		"LOCATION\n  'hdfs://localhost:8020/user/hive/warehouse/sales.db/orders'",
+		"TBLPROPERTIES (\n  'parquet.compression'='SNAPPY')",
+	} {
+		if !strings.Contains(a.sql, want) {
+			t.Errorf("adapted SQL missing %q:\n%s", want, a.sql)
+		}
+	}
+	if strings.Contains(a.sql, "transient_lastDdlTime") || strings.Contains(a.sql, "classification") {
+		t.Errorf("properties not removed:\n%s", a.sql)
+	}
+	want := []string{
+		"SerDe org.openx.data.jsonserde.JsonSerDe -> org.apache.hadoop.hive.serde2.JsonSerDe",
+		"location s3a://prod-lake/sales/orders -> hdfs://localhost:8020/user/hive/warehouse/sales.db/orders",
+		"removed table properties: classification",
+	}
+	if !reflect.DeepEqual(a.notes, want) {
+		t.Fatalf("notes = %q", a.notes)
+	}
+}
+
+func TestAdapt_KeepsLocalLocationsAndStripsOnlyStats(t *testing.T) {
+	sql := "CREATE TABLE `db`.`t` (id int) STORED AS ORC LOCATION 'hdfs://localhost:8020/data/t' " +
+		"TBLPROPERTIES ('numRows'='10', 'totalSize'='100')"
+	a := adapt(sql, "default", testTarget)
+	if len(a.notes) != 0 {
+		t.Fatalf("notes = %q", a.notes)
+	}
+	want := "CREATE TABLE IF NOT EXISTS `db`.`t` (id int) STORED AS ORC LOCATION 'hdfs://localhost:8020/data/t'"
+	if a.sql != want {
+		t.Fatalf("sql = %q, want %q", a.sql, want)
+	}
+}
+
+func TestAdapt_RemoteHDFSAndUnsupportedFormats(t *testing.T) {
+	sql := "CREATE TABLE logs (line string)\n" +
+		"ROW FORMAT SERDE 'com.amazon.emr.hive.serde.CloudTrailSerde' WITH SERDEPROPERTIES ('a'='b')\n" +
+		"STORED AS INPUTFORMAT 'com.amazon.emr.cloudtrail.CloudTrailInputFormat' " +
+		"OUTPUTFORMAT 'org.apache.hadoop.hive.ql.io.HiveIgnoreKeyTextOutputFormat'\n" +
+		"LOCATION 'hdfs://prod-nn:8020/logs'"
+	a := adapt(sql, "default", testTarget)
+	want := "CREATE TABLE IF NOT EXISTS `default`.`logs` (line string)\n" +
+		"STORED AS TEXTFILE\n" +
+		"LOCATION 'hdfs://localhost:8020/user/hive/warehouse/logs'"
+	if a.sql != want {
+		t.Fatalf("sql = %q\nwant  %q", a.sql, want)
+	}
+	if len(a.notes) != 3 {
+		t.Fatalf("notes = %q", a.notes)
+	}
+}
+
+func TestAdapt_Skipped(t *testing.T) {
+	tests := map[string]string{
+		"CREATE TEMPORARY TABLE t (a int)":                                                       "temporary",
+		"CREATE FUNCTION f AS 'com.example.F' USING JAR 's3://jars/f.jar'":                       "CREATE FUNCTION",
+		"ALTER TABLE t ADD PARTITION (ds='1')":                                                   "ALTER TABLE",
+		"CREATE TABLE h (k string) STORED BY 'org.apache.hadoop.hive.hbase.HBaseStorageHandler'": "storage handler",
+		"CREATE DATABASE default":                                                                "already exists",
+		"MSCK REPAIR TABLE t":                                                                    "MSCK",
+	}
+	for sql, want := range tests {
+		if a := adapt(sql, "default", testTarget); !strings.Contains(a.skip, want) {
+			t.Errorf("adapt(%q).skip = %q, want it to contain %q", sql, a.skip, want)
+		}
+	}
+}
+
+func TestAdapt_DatabaseAndView(t *testing.T) {
+	a := adapt("CREATE DATABASE `sales` LOCATION 'gs://lake/sales.db' WITH DBPROPERTIES ('glue.catalog'='x')", "default", testTarget)
+	want := "CREATE DATABASE IF NOT EXISTS `sales` LOCATION 'hdfs://localhost:8020/user/hive/warehouse/sales.db'"
+	if a.sql != want || a.kind != KindDatabase || a.database != "sales" {
+		t.Fatalf("database: %+v", a)
+	}
+
+	a = adapt("CREATE VIEW `v` AS SELECT o.id FROM `sales`.`orders` o JOIN customers c ON o.c = c.id "+
+		"WHERE o.id IN (SELECT id FROM ref.ids)", "sales", testTarget)
+	if a.kind != KindView || !strings.HasPrefix(a.sql, "CREATE VIEW IF NOT EXISTS `sales`.`v` AS") {
+		t.Fatalf("view: %+v", a)
+	}
+	if want := []string{"sales.orders", "sales.customers", "ref.ids"}; !reflect.DeepEqual(a.refs, want) {
+		t.Fatalf("refs = %v, want %v", a.refs, want)
+	}
+}
@@ -0,0 +1,115 @@
+package ddl
+
+import "strings"
+
+type tokenKind int
+
+const (
+	tokSpace  tokenKind = iota // whitespace and comments
+	tokWord                    // keywords, unquoted identifiers and numbers
+	tokString                  // '...' or "..." literal
+	tokIdent                   // `...` identifier
+	tokPunct                   // any other single character
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// is reports whether t is the keyword word (case-insensitive).
+func (t token) is(word string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, word)
+}
+
+// value returns the unquoted value of string literals and identifiers.
+func (t token) value() string {
+	switch t.kind {
+	case tokString:
+		s := t.text[1 : len(t.text)-1]
+		var sb strings.Builder
+		for i := 0; i < len(s); i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+			}
+			sb.WriteByte(s[i])
+		}
+		return sb.String()
+	case tokIdent:
+		return strings.ReplaceAll(t.text[1:len(t.text)-1], "``", "`")
+	default:
+		return t.text
+	}
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// lex splits a statement into tokens; concatenating the token texts gives
+// back the input.
+func lex(s string) []token {
+	var tokens []token
+	for i := 0; i < len(s); {
+		start := i
+		c := s[i]
+		kind := tokPunct
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			kind = tokSpace
+			for i < len(s) && strings.IndexByte(" \t\n\r", s[i]) >= 0 {
+				i++
+			}
+		case c == '-' && strings.HasPrefix(s[i:], "--"):
+			kind = tokSpace
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(s[i:], "/*"):
+			kind = tokSpace
+			if end := strings.Index(s[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				i = len(s)
+			}
+		case c == '\'' || c == '"':
+			kind = tokString
+			for i++; i < len(s) && s[i] != c; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+			i = min(i+1, len(s))
+		case c == '`':
+			kind = tokIdent
+			for i++; i < len(s); i++ {
+				if s[i] == '`' {
+					if i+1 < len(s) && s[i+1] == '`' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			i = min(i+1, len(s))
+		case isWordChar(c):
+			kind = tokWord
+			for i < len(s) && isWordChar(s[i]) {
+				i++
+			}
+		default:
+			i++
+		}
+		tokens = append(tokens, token{kind: kind, text: s[start:i]})
+	}
+	return tokens
+}
+
+// render concatenates token texts.
+func render(tokens []token) string {
+	var sb strings.Builder
+	for _, t := range tokens {
+		sb.WriteString(t.text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,322 @@
+// Package ddl imports production table definitions from SHOW CREATE TABLE
+// dumps: locations are rewritten to the local warehouse, properties and
+// SerDes the platform cannot use are removed, and objects are created in
+// dependency order over HiveServer2.
+package ddl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+	"github.com/danieljhkim/local-data-platform/internal/sqlscript"
+)
+
+// ScriptExtensions are the file extensions read from a DDL directory.
+var ScriptExtensions = []string{".sql", ".hql", ".ddl"}
+
+// Script is a DDL file.
+type Script struct {
+	Path    string
+	Content string
+}
+
+// ReadScripts reads a DDL file, or the DDL files under a directory in path
+// order.
+func ReadScripts(path string) ([]Script, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	if !info.IsDir() {
+		files = []string{path}
+	} else {
+		err = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := strings.ToLower(filepath.Ext(p))
+			for _, e := range ScriptExtensions {
+				if !d.IsDir() && ext == e {
+					files = append(files, p)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no DDL files (%s) found in %s", strings.Join(ScriptExtensions, ", "), path)
+		}
+		sort.Strings(files)
+	}
+
+	scripts := make([]Script, 0, len(files))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		scripts = append(scripts, Script{Path: f, Content: string(data)})
+	}
+	return scripts, nil
+}
+
+// Item is one imported statement.
+type Item struct {
+	File     string
+	Index    int    // statement number in File, from 1; 0 for implied databases
+	Kind     string // KindDatabase, KindTable or KindView; empty for other statements
+	Database string
+	Name     string   // table or view name
+	Original string   // statement as found in the dump
+	SQL      string   // statement to run; empty if skipped
+	Notes    []string // adaptations made
+	Skipped  string   // reason the statement is not imported
+	Err      string   // set by Apply when the statement failed
+	refs     []string
+}
+
+// Object returns the database or db.table name.
+func (it *Item) Object() string {
+	if it.Kind == KindDatabase || it.Name == "" {
+		return it.Database
+	}
+	return it.Database + "." + it.Name
+}
+
+// Adapted reports whether the statement was changed beyond name
+// qualification and IF NOT EXISTS.
+func (it *Item) Adapted() bool {
+	return it.Skipped == "" && len(it.Notes) > 0
+}
+
+// source returns file #index for messages.
+func (it *Item) source() string {
+	if it.Index == 0 {
+		return "implied"
+	}
+	return fmt.Sprintf("%s #%d", filepath.Base(it.File), it.Index)
+}
+
+// Plan adapts the statements of the scripts and orders them for creation:
+// databases first (including databases only referenced by tables), then
+// tables and views after the objects they select from. Skipped statements
+// come last.
+func Plan(scripts []Script, target Target) []*Item {
+	if target.Database == "" {
+		target.Database = defaultDatabase
+	}
+
+	var items, skipped []*Item
+	seen := map[string]*Item{}
+	for _, script := range scripts {
+		current := target.Database
+		for i, sql := range hs2.SplitStatements(script.Content) {
+			a := adapt(sql, current, target)
+			if a.use != "" {
+				current = a.use
+				continue
+			}
+			it := &Item{
+				File:     script.Path,
+				Index:    i + 1,
+				Kind:     a.kind,
+				Database: a.database,
+				Name:     a.name,
+				Original: sql,
+				SQL:      a.sql,
+				Notes:    a.notes,
+				Skipped:  a.skip,
+				refs:     a.refs,
+			}
+			if it.Skipped == "" {
+				key := it.Kind + ":" + it.Object()
+				if prev, ok := seen[key]; ok {
+					it.Skipped = "duplicate of " + prev.source()
+				} else {
+					seen[key] = it
+				}
+			}
+			if it.Skipped != "" {
+				it.SQL = ""
+				skipped = append(skipped, it)
+				continue
+			}
+			items = append(items, it)
+		}
+	}
+
+	return append(order(items, seen), skipped...)
+}
+
+// order sorts databases first, adding CREATE DATABASE for databases that are
+// used but not defined, then tables and views depth-first after the objects
+// they reference. Reference cycles keep the dump order.
+func order(items []*Item, defined map[string]*Item) []*Item {
+	var out []*Item
+	for _, it := range items {
+		if it.Kind == KindDatabase {
+			out = append(out, it)
+		}
+	}
+	for _, it := range items {
+		if it.Kind == KindDatabase || it.Database == defaultDatabase {
+			continue
+		}
+		if _, ok := defined[KindDatabase+":"+it.Database]; !ok {
+			db := &Item{
+				Kind:     KindDatabase,
+				Database: it.Database,
+				SQL:      "CREATE DATABASE IF NOT EXISTS " + hs2.QuoteIdent(it.Database),
+			}
+			defined[KindDatabase+":"+it.Database] = db
+			out = append(out, db)
+		}
+	}
+
+	objects := map[string]*Item{}
+	for _, it := range items {
+		if it.Kind != KindDatabase {
+			objects[it.Object()] = it
+		}
+	}
+	state := map[*Item]int{} // 1: visiting, 2: done
+	var visit func(it *Item)
+	visit = func(it *Item) {
+		if state[it] != 0 {
+			return
+		}
+		state[it] = 1
+		for _, ref := range it.refs {
+			if dep, ok := objects[ref]; ok {
+				visit(dep)
+			}
+		}
+		state[it] = 2
+		out = append(out, it)
+	}
+	for _, it := range items {
+		if it.Kind != KindDatabase {
+			visit(it)
+		}
+	}
+	return out
+}
+
+// Apply runs the planned statements, recording failures on the items and
+// continuing with the next statement. Table and view statements run in their
+// database so unqualified names in view queries resolve as in production.
+func Apply(ctx context.Context, client sqlscript.Querier, items []*Item) {
+	current := ""
+	for _, it := range items {
+		if it.Skipped != "" {
+			continue
+		}
+		if it.Kind != KindDatabase && it.Database != current {
+			if _, err := client.Query(ctx, "USE "+hs2.QuoteIdent(it.Database)); err != nil {
+				it.Err = err.Error()
+				continue
+			}
+			current = it.Database
+		}
+		if _, err := client.Query(ctx, it.SQL); err != nil {
+			it.Err = err.Error()
+		}
+	}
+}
+
+// Counts returns the number of created (including adapted), adapted, skipped
+// and failed items.
+func Counts(items []*Item) (created, adapted, skipped, failed int) {
+	for _, it := range items {
+		switch {
+		case it.Skipped != "":
+			skipped++
+		case it.Err != "":
+			failed++
+		default:
+			created++
+			if it.Adapted() {
+				adapted++
+			}
+		}
+	}
+	return created, adapted, skipped, failed
+}
+
+// WriteReport writes one line per statement with its adaptations, skip
+// reason or error, and a summary line.
+func WriteReport(w io.Writer, items []*Item) error {
+	var sb strings.Builder
+	for _, it := range items {
+		status, object := "created", it.Object()
+		switch {
+		case it.Skipped != "":
+			status = "skipped"
+			if object == "" {
+				object = statementSummary(it.Original)
+			}
+		case it.Err != "":
+			status = "failed"
+		case it.Adapted():
+			status = "adapted"
+		}
+		fmt.Fprintf(&sb, "  %-9s  %-8s  %-40s  %s\n", "["+status+"]", it.Kind, object, it.source())
+		for _, note := range it.Notes {
+			fmt.Fprintf(&sb, "               - %s\n", note)
+		}
+		if it.Skipped != "" {
+			fmt.Fprintf(&sb, "               - %s\n", it.Skipped)
+		}
+		if it.Err != "" {
+			fmt.Fprintf(&sb, "               - %s\n", firstLine(it.Err))
+		}
+	}
+	created, adapted, skipped, failed := Counts(items)
+	fmt.Fprintf(&sb, "%d statements: %d created (%d adapted), %d skipped, %d failed\n", len(items), created, adapted, skipped, failed)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteScript writes the planned statements as a HiveQL script, with the
+// adaptations and skipped statements as comments.
+func WriteScript(w io.Writer, items []*Item) error {
+	var sb strings.Builder
+	current := ""
+	for _, it := range items {
+		fmt.Fprintf(&sb, "-- %s\n", it.source())
+		if it.Skipped != "" {
+			fmt.Fprintf(&sb, "-- skipped: %s\n--   %s\n\n", it.Skipped, statementSummary(it.Original))
+			continue
+		}
+		for _, note := range it.Notes {
+			fmt.Fprintf(&sb, "-- %s\n", note)
+		}
+		if it.Kind != KindDatabase && it.Database != current {
+			fmt.Fprintf(&sb, "USE %s;\n", hs2.QuoteIdent(it.Database))
+			current = it.Database
+		}
+		fmt.Fprintf(&sb, "%s;\n\n", it.SQL)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// statementSummary returns the first line of a statement, shortened.
+func statementSummary(sql string) string {
+	return sqlscript.Statement{SQL: sql}.Summary()
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,172 @@
+package ddl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+)
+
+func planObjects(items []*Item) []string {
+	var out []string
+	for _, it := range items {
+		name := it.Kind + " " + it.Object()
+		if it.Skipped != "" {
+			name = "skip " + it.source()
+		}
+		out = append(out, name)
+	}
+	return out
+}
+
+func TestPlan_DependencyOrder(t *testing.T) {
+	scripts := []Script{
+		{Path: "views.sql", Content: `
+USE sales;
+CREATE VIEW daily AS SELECT ds, count(*) FROM orders_clean GROUP BY ds;
+CREATE VIEW orders_clean AS SELECT * FROM orders WHERE id IS NOT NULL;
+`},
+		{Path: "tables.sql", Content: `
+-- dumped from prod
+CREATE TABLE sales.orders (id bigint, ds string);
+CREATE TABLE ref.ids (id bigint);
+SET hive.exec.dynamic.partition=true;
+CREATE TABLE sales.orders (id bigint);
+CREATE DATABASE sales;
+`},
+	}
+
+	items := Plan(scripts, testTarget)
+	want := []string{
+		"database sales",
+		"database ref",
+		"table sales.orders",
+		"view sales.orders_clean",
+		"view sales.daily",
+		"table ref.ids",
+		"skip tables.sql #3",
+		"skip tables.sql #4",
+	}
+	if got := planObjects(items); !reflect.DeepEqual(got, want) {
+		t.Fatalf("plan = %q\nwant   %q", got, want)
+	}
+	if items[1].SQL != "CREATE DATABASE IF NOT EXISTS `ref`" || items[1].source() != "implied" {
+		t.Fatalf("implied database: %+v", items[1])
+	}
+	if !strings.HasPrefix(items[7].Skipped, "duplicate of tables.sql #1") {
+		t.Fatalf("duplicate: %q", items[7].Skipped)
+	}
+}
+
+type fakeQuerier struct {
+	stmts []string
+	fail  string
+}
+
+func (f *fakeQuerier) Query(_ context.Context, stmt string) (*hs2.Result, error) {
+	f.stmts = append(f.stmts, stmt)
+	if f.fail != "" && strings.Contains(stmt, f.fail) {
+		return nil, errors.New("query failed: boom")
+	}
+	return &hs2.Result{}, nil
+}
+
+func TestApplyAndReport(t *testing.T) {
+	scripts := []Script{{Path: "dump.sql", Content: `
+CREATE EXTERNAL TABLE a.t1 (id int) LOCATION 's3://prod/t1';
+CREATE TABLE a.t2 (id int);
+CREATE TABLE b.t3 (id int);
+CREATE INDEX i ON TABLE a.t1 (id);
+`}}
+	items := Plan(scripts, testTarget)
+	q := &fakeQuerier{fail: "`t2`"}
+	Apply(context.Background(), q, items)
+
+	wantStmts := []string{
+		"CREATE DATABASE IF NOT EXISTS `a`",
+		"CREATE DATABASE IF NOT EXISTS `b`",
+		"USE `a`",
+		"CREATE EXTERNAL TABLE IF NOT EXISTS `a`.`t1` (id int) LOCATION 'hdfs://localhost:8020/user/hive/warehouse/a.db/t1'",
+		"CREATE TABLE IF NOT EXISTS `a`.`t2` (id int)",
+		"USE `b`",
+		"CREATE TABLE IF NOT EXISTS `b`.`t3` (id int)",
+	}
+	if !reflect.DeepEqual(q.stmts, wantStmts) {
+		t.Fatalf("statements = %q", q.stmts)
+	}
+
+	created, adapted, skipped, failed := Counts(items)
+	if created != 4 || adapted != 1 || skipped != 1 || failed != 1 {
+		t.Fatalf("counts = %d %d %d %d", created, adapted, skipped, failed)
+	}
+
+	var sb strings.Builder
+	if err := WriteReport(&sb, items); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+	for _, want := range []string{
+		"[adapted]  table     a.t1",
+		"- location s3://prod/t1 -> hdfs://localhost:8020/user/hive/warehouse/a.db/t1",
+		"[failed]   table     a.t2",
+		"- query failed: boom",
+		"[skipped]",
+		"CREATE INDEX i ON TABLE a.t1 (id)",
+		"- CREATE INDEX statements are not imported",
+		"6 statements: 4 created (1 adapted), 1 skipped, 1 failed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteScript(t *testing.T) {
+	items := Plan([]Script{{Path: "dump.sql", Content: "CREATE TABLE a.t (id int) LOCATION 'abfs://c@acct.dfs.core.windows.net/t'; GRANT SELECT ON t TO ROLE r;"}}, testTarget)
+	var sb strings.Builder
+	if err := WriteScript(&sb, items); err != nil {
+		t.Fatal(err)
+	}
+	want := "-- implied\nCREATE DATABASE IF NOT EXISTS `a`;\n\n" +
+		"-- dump.sql #1\n-- location abfs://c@acct.dfs.core.windows.net/t -> hdfs://localhost:8020/user/hive/warehouse/a.db/t\n" +
+		"USE `a`;\nCREATE TABLE IF NOT EXISTS `a`.`t` (id int) LOCATION 'hdfs://localhost:8020/user/hive/warehouse/a.db/t';\n\n" +
+		"-- dump.sql #2\n-- skipped: GRANT statements are not imported\n--   GRANT SELECT ON t TO ROLE r\n\n"
+	if got := sb.String(); got != want {
+		t.Fatalf("script:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestReadScriptsAndTargetFromConf(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"b/two.hql":          "CREATE TABLE t2 (a int);",
+		"a/one.sql":          "CREATE TABLE t1 (a int);",
+		"notes.txt":          "ignored",
+		"conf/core-site.xml": `<configuration><property><name>fs.defaultFS</name><value>hdfs://localhost:8020</value></property></configuration>`,
+		"conf/hive-site.xml": `<configuration><property><name>hive.metastore.warehouse.dir</name><value>/user/hive/warehouse</value></property></configuration>`,
+	} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scripts, err := ReadScripts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scripts) != 2 || filepath.Base(scripts[0].Path) != "one.sql" {
+		t.Fatalf("scripts = %+v", scripts)
+	}
+
+	target := TargetFromConf(filepath.Join(dir, "conf"), filepath.Join(dir, "conf"))
+	if target.Warehouse != "hdfs://localhost:8020/user/hive/warehouse" || target.DefaultFS != "hdfs://localhost:8020" {
+		t.Fatalf("target = %+v", target)
+	}
+}
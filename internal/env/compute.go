@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/project"
 	"github.com/danieljhkim/local-data-platform/internal/util"
 )
 
@@ -16,6 +17,7 @@ type Environment struct {
 	BaseDir       string
 	RepoRoot      string
 	ActiveProfile string
+	ProjectFile   string // .local-data.yaml in effect, if any
 
 	HadoopHome       string
 	HadoopPrefix     string // For PATH (may differ from Home for Homebrew)
@@ -36,7 +38,13 @@ type Environment struct {
 	Path string
 
 	// Additional vars
-	HiveAuxJarsPath string
+	HiveAuxJarsPath     string // comma-separated jars for Hive
+	HadoopClasspath     string // jars prepended by the hadoop/hdfs/yarn scripts
+	HadoopOptionalTools string // HADOOP_OPTIONAL_TOOLS, e.g. hadoop-aws for S3A
+
+	// PySpark interpreters of the profile ('local-data python')
+	PySparkPython       string
+	PySparkDriverPython string
 }
 
 // Compute computes the complete environment for the active profile
@@ -54,6 +62,53 @@ func Compute(paths *config.Paths) (*Environment, error) {
 		return nil, fmt.Errorf("failed to apply profile overlay: %w", err)
 	}
 
+	return compute(paths, activeProfile, paths.CurrentConfDir())
+}
+
+// ComputeForProject computes the environment for the project file
+// (.local-data.yaml) found from the working directory, falling back to
+// Compute when there is none. The project's overlay lives in
+// $BASE_DIR/conf/projects/<id> so the shared overlay used by services is
+// left untouched.
+func ComputeForProject(paths *config.Paths) (*Environment, error) {
+	proj, err := project.Discover()
+	if err != nil {
+		return nil, err
+	}
+	if proj == nil {
+		return Compute(paths)
+	}
+
+	profile := proj.Profile
+	if profile == "" {
+		if profile, err = paths.ActiveProfile(); err != nil {
+			return nil, err
+		}
+	}
+
+	confRoot := paths.ProjectConfDir(proj.ID())
+	pm := config.NewProfileManager(paths)
+	if err := pm.ApplyProjectOverlay(profile, confRoot, proj.Overrides, proj.Jars); err != nil {
+		return nil, fmt.Errorf("failed to apply project overlay (%s): %w", proj.Path, err)
+	}
+
+	env, err := compute(paths, profile, confRoot)
+	if err != nil {
+		return nil, err
+	}
+	env.ProjectFile = proj.Path
+	if len(proj.Jars) > 0 {
+		jars := proj.Jars
+		if env.HiveAuxJarsPath != "" {
+			jars = append([]string{env.HiveAuxJarsPath}, jars...)
+		}
+		env.HiveAuxJarsPath = strings.Join(jars, ",")
+	}
+	return env, nil
+}
+
+// compute builds the environment for profile with configs under confRoot
+func compute(paths *config.Paths, activeProfile, confRoot string) (*Environment, error) {
 	// Detect environment
 	detection, err := DetectEnvironment()
 	if err != nil {
@@ -69,7 +124,7 @@ func Compute(paths *config.Paths) (*Environment, error) {
 
 	// Hadoop environment (optional - e.g., 'local' profile doesn't use it)
 	// Only set Hadoop vars if the profile includes hadoop configuration
-	hadoopConfDir := paths.CurrentHadoopConf()
+	hadoopConfDir := filepath.Join(confRoot, "hadoop")
 	if util.DirExists(hadoopConfDir) && detection.HadoopHome != "" {
 		env.HadoopHome = detection.HadoopHome
 		env.HadoopPrefix = detection.HadoopPrefix
@@ -103,12 +158,47 @@ func Compute(paths *config.Paths) (*Environment, error) {
 
 	// Hive environment (required)
 	env.HiveHome = detection.HiveHome
-	env.HiveConfDir = paths.CurrentHiveConf()
+	env.HiveConfDir = filepath.Join(confRoot, "hive")
 
 	// Spark environment (optional)
 	env.SparkHome = detection.SparkHome
 	if env.SparkHome != "" {
-		env.SparkConfDir = paths.CurrentSparkConf()
+		env.SparkConfDir = filepath.Join(confRoot, "spark")
+	}
+
+	// Jars registered in $BASE_DIR/jars ('local-data jars add')
+	repo := config.NewJarRepository(paths)
+	hiveJars, err := repo.Classpath(config.JarTargetHive)
+	if err != nil {
+		return nil, err
+	}
+	env.HiveAuxJarsPath = strings.Join(hiveJars, ",")
+	hadoopJars, err := repo.Classpath(config.JarTargetHadoop)
+	if err != nil {
+		return nil, err
+	}
+	if len(hadoopJars) > 0 {
+		if existing := os.Getenv("HADOOP_CLASSPATH"); existing != "" {
+			hadoopJars = append(hadoopJars, existing)
+		}
+		env.HadoopClasspath = strings.Join(hadoopJars, string(os.PathListSeparator))
+	}
+
+	settings, err := config.NewSettingsManager(paths).LoadOrDefault()
+	if err != nil {
+		return nil, err
+	}
+
+	// S3A lives in hadoop-aws under share/hadoop/tools/lib, which the hadoop
+	// scripts (and Hive, started through them) only load when asked to
+	if settings.UsesS3A() {
+		env.HadoopOptionalTools = withOptionalTool(os.Getenv("HADOOP_OPTIONAL_TOOLS"), "hadoop-aws")
+	}
+
+	// Python environment of the profile ('local-data python init')
+	if pyEnv, ok := settings.PythonEnvFor(activeProfile); ok {
+		env.PySparkPython = pyEnv.Python
+		env.PySparkDriverPython = pyEnv.Driver()
 	}
 
 	// Build PATH
@@ -117,6 +207,20 @@ func Compute(paths *config.Paths) (*Environment, error) {
 	return env, nil
 }
 
+// withOptionalTool adds tool to a comma-separated HADOOP_OPTIONAL_TOOLS
+// value unless it is already listed
+func withOptionalTool(tools, tool string) string {
+	if tools == "" {
+		return tool
+	}
+	for _, t := range strings.Split(tools, ",") {
+		if strings.TrimSpace(t) == tool {
+			return tools
+		}
+	}
+	return tools + "," + tool
+}
+
 // buildPath constructs the PATH environment variable
 // Mirrors the PATH deduplication logic from ld_env_print
 func buildPath(env *Environment, paths *config.Paths) string {
@@ -175,6 +279,7 @@ func (e *Environment) Export() []string {
 	add("BASE_DIR", e.BaseDir)
 	add("REPO_ROOT", e.RepoRoot)
 	add("ACTIVE_PROFILE", e.ActiveProfile)
+	add("LOCAL_DATA_PROJECT_FILE", e.ProjectFile)
 
 	// Hadoop vars (optional)
 	if e.HadoopHome != "" {
@@ -185,6 +290,8 @@ func (e *Environment) Export() []string {
 		add("HADOOP_YARN_HOME", e.HadoopYarnHome)
 		add("HADOOP_CONF_DIR", e.HadoopConfDir)
 	}
+	add("HADOOP_CLASSPATH", e.HadoopClasspath)
+	add("HADOOP_OPTIONAL_TOOLS", e.HadoopOptionalTools)
 
 	// Hive vars (required)
 	add("HIVE_HOME", e.HiveHome)
@@ -198,6 +305,8 @@ func (e *Environment) Export() []string {
 		add("SPARK_HOME", e.SparkHome)
 		add("SPARK_CONF_DIR", e.SparkConfDir)
 	}
+	add("PYSPARK_PYTHON", e.PySparkPython)
+	add("PYSPARK_DRIVER_PYTHON", e.PySparkDriverPython)
 
 	// Java
 	add("JAVA_HOME", e.JavaHome)
@@ -218,9 +327,16 @@ func (e *Environment) PrintShell() {
 		}
 	}
 
+	if e.ProjectFile != "" {
+		fmt.Printf("# local-data project: %s\n", e.ProjectFile)
+	} else {
+		fmt.Println("# local-data project: none")
+	}
+
 	emit("BASE_DIR", e.BaseDir)
 	emit("REPO_ROOT", e.RepoRoot)
 	emit("ACTIVE_PROFILE", e.ActiveProfile)
+	emit("LOCAL_DATA_PROJECT_FILE", e.ProjectFile)
 
 	// Hadoop vars (optional)
 	if e.HadoopHome != "" {
@@ -231,6 +347,8 @@ func (e *Environment) PrintShell() {
 		emit("HADOOP_YARN_HOME", e.HadoopYarnHome)
 		emit("HADOOP_CONF_DIR", e.HadoopConfDir)
 	}
+	emit("HADOOP_CLASSPATH", e.HadoopClasspath)
+	emit("HADOOP_OPTIONAL_TOOLS", e.HadoopOptionalTools)
 
 	// Hive vars (required)
 	emit("HIVE_HOME", e.HiveHome)
@@ -244,6 +362,8 @@ func (e *Environment) PrintShell() {
 		emit("SPARK_HOME", e.SparkHome)
 		emit("SPARK_CONF_DIR", e.SparkConfDir)
 	}
+	emit("PYSPARK_PYTHON", e.PySparkPython)
+	emit("PYSPARK_DRIVER_PYTHON", e.PySparkDriverPython)
 
 	// Java
 	emit("JAVA_HOME", e.JavaHome)
@@ -97,3 +97,18 @@ func TestEnvironment_Export_EmptyValues(t *testing.T) {
 		t.Errorf("BASE_DIR not exported correctly")
 	}
 }
+
+func TestWithOptionalTool(t *testing.T) {
+	tests := []struct {
+		tools, want string
+	}{
+		{"", "hadoop-aws"},
+		{"hadoop-azure", "hadoop-azure,hadoop-aws"},
+		{"hadoop-azure, hadoop-aws", "hadoop-azure, hadoop-aws"},
+	}
+	for _, tt := range tests {
+		if got := withOptionalTool(tt.tools, "hadoop-aws"); got != tt.want {
+			t.Errorf("withOptionalTool(%q) = %q, want %q", tt.tools, got, tt.want)
+		}
+	}
+}
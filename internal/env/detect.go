@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/util"
 )
 
 // HomebrewDetector handles detection of Homebrew-installed packages
@@ -19,14 +21,7 @@ func NewHomebrewDetector() *HomebrewDetector {
 // Mirrors: brew --prefix <formula>
 // Returns empty string (not an error) if formula not found
 func (h *HomebrewDetector) Prefix(formula string) string {
-	cmd := exec.Command("brew", "--prefix", formula)
-	output, err := cmd.Output()
-	if err != nil {
-		// Not found or brew not installed - not an error, just return empty
-		return ""
-	}
-
-	return strings.TrimSpace(string(output))
+	return util.BrewPrefix(formula)
 }
 
 // IsInstalled checks if brew command is available
@@ -99,57 +94,15 @@ func (t *ToolDetector) DetectAll(tools []string) map[string]bool {
 // FindSparkHome finds Spark installation home
 // For Homebrew installs, adds /libexec suffix
 func FindSparkHome() string {
-	// Check environment variable first
-	if sparkHome := os.Getenv("SPARK_HOME"); sparkHome != "" {
-		return sparkHome
-	}
-
-	// Try Homebrew
-	hb := NewHomebrewDetector()
-
-	// Try apache-spark first
-	prefix := hb.Prefix("apache-spark")
-	if prefix == "" {
-		// Fallback to spark
-		prefix = hb.Prefix("spark")
-	}
-
-	if prefix != "" {
-		// Homebrew Spark needs /libexec suffix
-		return prefix + "/libexec"
-	}
-
-	return ""
+	return config.FindSparkHome()
 }
 
 // HadoopInstall contains Hadoop installation paths
-type HadoopInstall struct {
-	Prefix string // Brew prefix (for bin/sbin in PATH)
-	Home   string // HADOOP_HOME (libexec for Homebrew)
-}
+type HadoopInstall = config.HadoopInstall
 
 // FindHadoopInstall finds Hadoop installation paths
 func FindHadoopInstall() *HadoopInstall {
-	// Check environment variable first
-	if hadoopHome := os.Getenv("HADOOP_HOME"); hadoopHome != "" {
-		return &HadoopInstall{
-			Prefix: hadoopHome,
-			Home:   hadoopHome,
-		}
-	}
-
-	// Try Homebrew
-	hb := NewHomebrewDetector()
-	prefix := hb.Prefix("hadoop")
-	if prefix != "" {
-		return &HadoopInstall{
-			Prefix: prefix,
-			// Homebrew Hadoop needs /libexec suffix for proper library resolution
-			Home: prefix + "/libexec",
-		}
-	}
-
-	return nil
+	return config.FindHadoopInstall()
 }
 
 // FindHadoopHome finds Hadoop installation home (legacy, returns Home)
@@ -13,14 +13,30 @@ type DoctorCheck struct {
 	Found    bool   // true if command is available
 }
 
+// DoctorNote is an additional finding reported by callers (e.g. metastore schema checks)
+type DoctorNote struct {
+	Message string
+	Fix     string // Suggested fix (empty if OK)
+	Ok      bool
+}
+
 // DoctorResult holds the results of all checks
 type DoctorResult struct {
 	Target      string        // Target context (e.g., "start hdfs")
 	Checks      []DoctorCheck // All checks performed
+	Notes       []DoctorNote  // Additional findings
 	JavaMajor   int           // Java major version (0 if not found)
 	HasFailures bool          // true if any required check failed
 }
 
+// AddNote records an additional finding; a failed note marks the result as failed
+func (dr *DoctorResult) AddNote(note DoctorNote) {
+	dr.Notes = append(dr.Notes, note)
+	if !note.Ok {
+		dr.HasFailures = true
+	}
+}
+
 // RunDoctor performs dependency checking based on the target context
 // Mirrors ld_doctor from doctor.sh
 func RunDoctor(target string) *DoctorResult {
@@ -132,6 +148,17 @@ func (dr *DoctorResult) Print() {
 		fmt.Printf("  %s java major version is %d (recommended: 17)\n", util.Colorf(util.Yellow, "WARN"), dr.JavaMajor)
 		fmt.Printf("       Fix: install Java 17 and set JAVA_HOME\n")
 	}
+
+	for _, note := range dr.Notes {
+		status := util.Colorf(util.Green, "OK  ")
+		if !note.Ok {
+			status = util.Colorf(util.BoldRed, "FAIL")
+		}
+		fmt.Printf("  %s %s\n", status, note.Message)
+		if note.Fix != "" {
+			fmt.Printf("       Fix: %s\n", note.Fix)
+		}
+	}
 }
 
 // ExitCode returns the appropriate exit code
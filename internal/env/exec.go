@@ -1,9 +1,12 @@
 package env
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/danieljhkim/local-data-platform/internal/config"
 )
@@ -20,12 +23,29 @@ func ExecWithEnv(paths *config.Paths, args []string, extraEnv map[string]string)
 		return fmt.Errorf("usage: local-data env exec -- <cmd...>")
 	}
 
-	// Compute environment
-	env, err := Compute(paths)
+	cmd, err := Command(paths, args, extraEnv)
 	if err != nil {
 		return err
 	}
 
+	// Connect stdio
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Run and wait
+	return cmd.Run()
+}
+
+// Command builds a command that runs with the computed environment plus
+// extra env vars. Stdio is left to the caller.
+func Command(paths *config.Paths, args []string, extraEnv map[string]string) (*exec.Cmd, error) {
+	// Compute environment
+	env, err := ComputeForProject(paths)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build command
 	cmd := exec.Command(args[0], args[1:]...)
 
@@ -38,11 +58,64 @@ func ExecWithEnv(paths *config.Paths, args []string, extraEnv map[string]string)
 	}
 	cmd.Env = cmdEnv
 
-	// Connect stdio
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	return cmd, nil
+}
 
-	// Run and wait
-	return cmd.Run()
+// sparkLogTailLines is how much of the spark-submit output errors include
+const sparkLogTailLines = 20
+
+// SparkSubmitError is returned by SparkSubmit when spark-submit exits with
+// an error. LogTail holds the last lines of its output.
+type SparkSubmitError struct {
+	Err     error
+	LogTail string
+}
+
+func (e *SparkSubmitError) Error() string {
+	return fmt.Sprintf("spark-submit failed: %v\n%s", e.Err, e.LogTail)
+}
+
+func (e *SparkSubmitError) Unwrap() error {
+	return e.Err
+}
+
+// SparkSubmit runs 'spark-submit <args...>' with the computed environment,
+// capturing its output, and kills it when ctx is done. It returns the last
+// lines of the output; a failed run returns a *SparkSubmitError.
+func SparkSubmit(ctx context.Context, paths *config.Paths, args ...string) (string, error) {
+	cmd, err := Command(paths, append([]string{"spark-submit"}, args...), nil)
+	if err != nil {
+		return "", err
+	}
+	var logs bytes.Buffer
+	cmd.Stdout = &logs
+	cmd.Stderr = &logs
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start spark-submit: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		<-done
+		return "", ctx.Err()
+	case err = <-done:
+	}
+
+	logTail := lastLines(logs.String(), sparkLogTailLines)
+	if err != nil {
+		return logTail, &SparkSubmitError{Err: err, LogTail: logTail}
+	}
+	return logTail, nil
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
 }
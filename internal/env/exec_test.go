@@ -0,0 +1,28 @@
+package env
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLastLines(t *testing.T) {
+	if got := lastLines("a\nb\nc\n", 2); got != "b\nc" {
+		t.Errorf("lastLines(3 lines, 2) = %q, want %q", got, "b\nc")
+	}
+	if got := lastLines("a\nb\n", 5); got != "a\nb" {
+		t.Errorf("lastLines(2 lines, 5) = %q, want %q", got, "a\nb")
+	}
+}
+
+func TestSparkSubmitError(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	err := error(&SparkSubmitError{Err: exitErr, LogTail: "Traceback ..."})
+
+	if !errors.Is(err, exitErr) {
+		t.Error("SparkSubmitError should unwrap to the spark-submit error")
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "spark-submit failed: exit status 1\n") || !strings.HasSuffix(msg, "Traceback ...") {
+		t.Errorf("Error() = %q", msg)
+	}
+}
@@ -0,0 +1,70 @@
+package env
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// versionJar describes where a component's version can be read from a jar name
+type versionJar struct {
+	component string
+	glob      string         // relative to the component home
+	pattern   *regexp.Regexp // first submatch is the version
+}
+
+var versionJars = []versionJar{
+	{"hadoop", "share/hadoop/common/hadoop-common-*.jar", regexp.MustCompile(`^hadoop-common-([0-9][0-9A-Za-z.\-]*)\.jar$`)},
+	{"hive", "lib/hive-exec-*.jar", regexp.MustCompile(`^hive-exec-([0-9][0-9A-Za-z.\-]*)\.jar$`)},
+	{"spark", "jars/spark-core_*.jar", regexp.MustCompile(`^spark-core_[0-9.]+-([0-9][0-9A-Za-z.\-]*)\.jar$`)},
+}
+
+// ComponentVersions returns the installed Hadoop/Hive/Spark versions
+// Versions are read from jar file names (no JVM startup); missing components are omitted
+func ComponentVersions(d *DetectionResult) map[string]string {
+	versions := make(map[string]string)
+	if d == nil {
+		return versions
+	}
+
+	homes := map[string]string{
+		"hadoop": d.HadoopHome,
+		"hive":   d.HiveHome,
+		"spark":  d.SparkHome,
+	}
+
+	for _, vj := range versionJars {
+		home := homes[vj.component]
+		if home == "" {
+			continue
+		}
+		if v := versionFromJars(filepath.Join(home, vj.glob), vj.pattern); v != "" {
+			versions[vj.component] = v
+		}
+	}
+
+	if d.JavaMajor != 0 {
+		versions["java"] = strconv.Itoa(d.JavaMajor)
+	}
+
+	return versions
+}
+
+// versionFromJars returns the version captured from the first matching jar
+// Test jars (e.g. hadoop-common-3.4.1-tests.jar) are ignored
+func versionFromJars(glob string, pattern *regexp.Regexp) string {
+	matches, _ := filepath.Glob(glob)
+	sort.Strings(matches)
+	for _, m := range matches {
+		name := filepath.Base(m)
+		if strings.HasSuffix(name, "-tests.jar") {
+			continue
+		}
+		if sub := pattern.FindStringSubmatch(name); len(sub) == 2 {
+			return sub[1]
+		}
+	}
+	return ""
+}
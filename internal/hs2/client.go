@@ -0,0 +1,294 @@
+// Package hs2 is a small HiveServer2 client on top of the HS2 Thrift protocol.
+package hs2
+
+import (
+	"context"
+	"fmt"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/beltran/gohive"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+const (
+	// DefaultHost is the HiveServer2 host used by local-data profiles.
+	DefaultHost = "localhost"
+	// DefaultPort is the HiveServer2 binary transport port used when hive-site.xml does not set one.
+	DefaultPort = 10000
+
+	defaultConnectTimeout = 5 * time.Second
+)
+
+// Options configures a HiveServer2 connection.
+type Options struct {
+	Host     string
+	Port     int
+	Username string // defaults to the current OS user
+	Password string // sent with SASL PLAIN; ignored with NOSASL
+	Database string // initial database; empty means "default"
+	// Auth is the hive.server2.authentication mode: NOSASL, or NONE, LDAP
+	// or CUSTOM, which all use SASL PLAIN over the binary transport.
+	Auth string
+	// Transport is hive.server2.transport.mode; only binary is supported.
+	Transport string
+}
+
+// Column describes a result set column.
+type Column struct {
+	Name string
+	Type string // Hive type name in lower case (e.g. "string", "bigint")
+}
+
+// Result is a fully fetched result set. Values are Go native types as returned
+// by the driver (string, int32, int64, float64, bool, ...) or nil for NULL.
+type Result struct {
+	Columns []Column
+	Rows    [][]any
+}
+
+// Client is a HiveServer2 session.
+type Client struct {
+	conn *gohive.Connection
+}
+
+// OptionsFromConf reads the HiveServer2 endpoint from hive-site.xml in hiveConfDir.
+// Missing files or properties fall back to localhost:10000 with NONE auth.
+func OptionsFromConf(hiveConfDir string) Options {
+	opts := Options{Host: DefaultHost, Port: DefaultPort, Auth: "NONE"}
+
+	cfg, err := util.ParseHadoopXML(filepath.Join(hiveConfDir, "hive-site.xml"))
+	if err != nil {
+		return opts
+	}
+	if host := strings.TrimSpace(cfg.GetProperty("hive.server2.thrift.bind.host")); host != "" {
+		opts.Host = host
+	}
+	if portStr := strings.TrimSpace(cfg.GetProperty("hive.server2.thrift.port")); portStr != "" {
+		fmt.Sscanf(portStr, "%d", &opts.Port)
+	}
+	if auth := strings.ToUpper(strings.TrimSpace(cfg.GetProperty("hive.server2.authentication"))); auth != "" {
+		opts.Auth = auth
+	}
+	opts.Transport = strings.ToLower(strings.TrimSpace(cfg.GetProperty("hive.server2.transport.mode")))
+	return opts
+}
+
+// Connect opens a HiveServer2 session.
+func Connect(ctx context.Context, opts Options) (*Client, error) {
+	if opts.Host == "" {
+		opts.Host = DefaultHost
+	}
+	if opts.Port == 0 {
+		opts.Port = DefaultPort
+	}
+
+	if opts.Transport != "" && opts.Transport != "binary" {
+		return nil, fmt.Errorf("unsupported hive.server2.transport.mode %q (supported: binary)", opts.Transport)
+	}
+
+	auth := strings.ToUpper(opts.Auth)
+	switch auth {
+	case "", "PLAIN":
+		auth = "NONE"
+	case "NONE", "NOSASL", "LDAP", "CUSTOM":
+	default:
+		return nil, fmt.Errorf("unsupported hive.server2.authentication %q (supported: NONE, NOSASL, LDAP, CUSTOM)", opts.Auth)
+	}
+
+	cfg := gohive.NewConnectConfiguration()
+	cfg.Username = opts.Username
+	if cfg.Username == "" {
+		if u, err := user.Current(); err == nil {
+			cfg.Username = u.Username
+		}
+	}
+	cfg.Password = opts.Password
+	cfg.Database = opts.Database
+	cfg.ConnectTimeout = defaultConnectTimeout
+
+	type result struct {
+		conn *gohive.Connection
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		conn, err := gohive.Connect(opts.Host, opts.Port, auth, cfg)
+		done <- result{conn, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		// Close the session if it is established after we gave up on it
+		go func() {
+			if r := <-done; r.err == nil {
+				r.conn.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	case r := <-done:
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to connect to HiveServer2 at %s:%d (is Hive running? try: local-data start hive): %w",
+				opts.Host, opts.Port, r.err)
+		}
+		return &Client{conn: r.conn}, nil
+	}
+}
+
+// Close ends the session.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Exec runs a statement that does not return rows (DDL, SET, USE, INSERT ...).
+func (c *Client) Exec(ctx context.Context, stmt string) error {
+	cursor := c.conn.Cursor()
+	defer cursor.Close()
+
+	cursor.Exec(ctx, stmt)
+	if cursor.Err != nil {
+		return fmt.Errorf("statement failed: %w", cursor.Err)
+	}
+	return nil
+}
+
+// Query runs a statement and fetches its full result set.
+func (c *Client) Query(ctx context.Context, stmt string) (*Result, error) {
+	cursor := c.conn.Cursor()
+	defer cursor.Close()
+
+	cursor.Exec(ctx, stmt)
+	if cursor.Err != nil {
+		return nil, fmt.Errorf("query failed: %w", cursor.Err)
+	}
+
+	desc := cursor.Description()
+	if cursor.Err != nil {
+		return nil, fmt.Errorf("failed to read result schema: %w", cursor.Err)
+	}
+
+	res := &Result{Columns: make([]Column, len(desc))}
+	for i, d := range desc {
+		res.Columns[i] = Column{Name: d[0], Type: TypeName(d[1])}
+	}
+	if len(desc) == 0 {
+		// DDL, SET, USE ... return no result set
+		return res, nil
+	}
+
+	for cursor.HasMore(ctx) {
+		if cursor.Err != nil {
+			return nil, fmt.Errorf("failed to fetch rows: %w", cursor.Err)
+		}
+		// Fetch by position: RowMap loses values of duplicate column names
+		dests := scanDests(desc)
+		cursor.FetchOne(ctx, dests...)
+		if cursor.Err != nil {
+			return nil, fmt.Errorf("failed to fetch rows: %w", cursor.Err)
+		}
+		res.Rows = append(res.Rows, rowValues(dests))
+	}
+	if cursor.Err != nil {
+		return nil, fmt.Errorf("failed to fetch rows: %w", cursor.Err)
+	}
+
+	return res, nil
+}
+
+// scanDests returns FetchOne destinations for the columns in desc, in
+// order. Pointers to pointers let the driver report NULL as nil; types the
+// driver sends as strings (timestamps, decimals, complex types) scan into
+// strings.
+func scanDests(desc [][]string) []any {
+	dests := make([]any, len(desc))
+	for i, d := range desc {
+		switch d[1] {
+		case "BOOLEAN_TYPE":
+			dests[i] = new(*bool)
+		case "TINYINT_TYPE":
+			dests[i] = new(*int8)
+		case "SMALLINT_TYPE":
+			dests[i] = new(*int16)
+		case "INT_TYPE":
+			dests[i] = new(*int32)
+		case "BIGINT_TYPE":
+			dests[i] = new(*int64)
+		case "FLOAT_TYPE", "DOUBLE_TYPE":
+			dests[i] = new(*float64)
+		case "BINARY_TYPE":
+			dests[i] = new([]byte)
+		default:
+			dests[i] = new(*string)
+		}
+	}
+	return dests
+}
+
+// rowValues converts filled scanDests destinations to row values, with nil
+// for NULL.
+func rowValues(dests []any) []any {
+	row := make([]any, len(dests))
+	for i, dest := range dests {
+		switch d := dest.(type) {
+		case **bool:
+			row[i] = deref(*d)
+		case **int8:
+			row[i] = deref(*d)
+		case **int16:
+			row[i] = deref(*d)
+		case **int32:
+			row[i] = deref(*d)
+		case **int64:
+			row[i] = deref(*d)
+		case **float64:
+			row[i] = deref(*d)
+		case **string:
+			row[i] = deref(*d)
+		case *[]byte:
+			if *d != nil {
+				row[i] = *d
+			}
+		}
+	}
+	return row
+}
+
+func deref[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+// ShowCreateTable returns the CREATE statement for db.table.
+func (c *Client) ShowCreateTable(ctx context.Context, db, table string) (string, error) {
+	res, err := c.Query(ctx, fmt.Sprintf("SHOW CREATE TABLE %s.%s", QuoteIdent(db), QuoteIdent(table)))
+	if err != nil {
+		return "", err
+	}
+	lines := make([]string, 0, len(res.Rows))
+	for _, row := range res.Rows {
+		if len(row) > 0 && row[0] != nil {
+			lines = append(lines, fmt.Sprint(row[0]))
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// TypeName converts a Thrift type id (e.g. "STRING_TYPE") to a Hive type name ("string").
+func TypeName(thriftType string) string {
+	return strings.ToLower(strings.TrimSuffix(thriftType, "_TYPE"))
+}
+
+// QuoteIdent quotes a Hive identifier with backticks.
+func QuoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// QuoteString quotes a Hive string literal with single quotes.
+func QuoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
@@ -0,0 +1,30 @@
+package hs2
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScanDests_RowValues(t *testing.T) {
+	// Duplicate column names (SELECT a.id, b.id) keep their own values
+	desc := [][]string{
+		{"id", "INT_TYPE"},
+		{"id", "INT_TYPE"},
+		{"name", "STRING_TYPE"},
+		{"amount", "DECIMAL_TYPE"},
+		{"raw", "BINARY_TYPE"},
+	}
+	dests := scanDests(desc)
+
+	// Fill the destinations the way gohive's FetchOne does; NULLs stay nil
+	a, b, amount := int32(1), int32(2), "9.50"
+	*dests[0].(**int32) = &a
+	*dests[1].(**int32) = &b
+	*dests[3].(**string) = &amount
+
+	got := rowValues(dests)
+	want := []any{int32(1), int32(2), nil, "9.50", nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rowValues() = %#v, want %#v", got, want)
+	}
+}
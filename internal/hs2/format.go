@@ -0,0 +1,186 @@
+package hs2
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Output formats supported by WriteResult.
+const (
+	FormatTable = "table"
+	FormatCSV   = "csv"
+	FormatTSV   = "tsv"
+	FormatJSON  = "json"
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{FormatTable, FormatCSV, FormatTSV, FormatJSON}
+
+// ValidateFormat checks that format is a supported output format.
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported output format %q (supported: %s)", format, strings.Join(Formats, ", "))
+}
+
+// WriteResult writes a result set in the given format. NULL values are
+// written as "NULL" in table output, empty fields in csv/tsv and null in json.
+func WriteResult(w io.Writer, res *Result, format string) error {
+	switch format {
+	case FormatTable:
+		return writeTable(w, res)
+	case FormatCSV:
+		return writeDelimited(w, res, ',')
+	case FormatTSV:
+		return writeDelimited(w, res, '\t')
+	case FormatJSON:
+		return writeJSON(w, res)
+	default:
+		return ValidateFormat(format)
+	}
+}
+
+// FormatValue renders a single value the way the table output does.
+func FormatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func writeTable(w io.Writer, res *Result) error {
+	widths := make([]int, len(res.Columns))
+	for i, c := range res.Columns {
+		widths[i] = utf8.RuneCountInString(c.Name)
+	}
+	cells := make([][]string, len(res.Rows))
+	for r, row := range res.Rows {
+		cells[r] = make([]string, len(res.Columns))
+		for i := range res.Columns {
+			var v any
+			if i < len(row) {
+				v = row[i]
+			}
+			cells[r][i] = strings.ReplaceAll(FormatValue(v), "\n", "\\n")
+			widths[i] = max(widths[i], utf8.RuneCountInString(cells[r][i]))
+		}
+	}
+
+	var sb strings.Builder
+	sep := func() {
+		sb.WriteString("+")
+		for _, width := range widths {
+			sb.WriteString(strings.Repeat("-", width+2) + "+")
+		}
+		sb.WriteString("\n")
+	}
+	line := func(values []string) {
+		sb.WriteString("|")
+		for i, v := range values {
+			sb.WriteString(" " + v + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(v)) + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	names := make([]string, len(res.Columns))
+	for i, c := range res.Columns {
+		names[i] = c.Name
+	}
+	sep()
+	line(names)
+	sep()
+	for _, row := range cells {
+		line(row)
+	}
+	if len(cells) > 0 {
+		sep()
+	}
+	if len(res.Rows) == 1 {
+		sb.WriteString("1 row selected\n")
+	} else {
+		fmt.Fprintf(&sb, "%d rows selected\n", len(res.Rows))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeDelimited(w io.Writer, res *Result, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	record := make([]string, len(res.Columns))
+	for i, c := range res.Columns {
+		record[i] = c.Name
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+	for _, row := range res.Rows {
+		for i := range record {
+			record[i] = ""
+			if i < len(row) && row[i] != nil {
+				record[i] = FormatValue(row[i])
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeJSON writes the rows as an array of objects keyed by column name, in
+// column order.
+func writeJSON(w io.Writer, res *Result) error {
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for r, row := range res.Rows {
+		if r > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  {")
+		for i, c := range res.Columns {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			key, _ := json.Marshal(c.Name)
+			buf.Write(key)
+			buf.WriteString(": ")
+
+			var v any
+			if i < len(row) {
+				v = row[i]
+			}
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+			}
+			value, err := json.Marshal(v)
+			if err != nil {
+				value, _ = json.Marshal(fmt.Sprint(v))
+			}
+			buf.Write(value)
+		}
+		buf.WriteString("}")
+	}
+	if len(res.Rows) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
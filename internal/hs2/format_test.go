@@ -0,0 +1,68 @@
+package hs2
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testResult() *Result {
+	return &Result{
+		Columns: []Column{{Name: "id", Type: "int"}, {Name: "name", Type: "string"}},
+		Rows: [][]any{
+			{int32(1), "alice"},
+			{int32(2), nil},
+		},
+	}
+}
+
+func TestWriteResult(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatTable, `+----+-------+
+| id | name  |
++----+-------+
+| 1  | alice |
+| 2  | NULL  |
++----+-------+
+2 rows selected
+`},
+		{FormatCSV, "id,name\n1,alice\n2,\n"},
+		{FormatTSV, "id\tname\n1\talice\n2\t\n"},
+		{FormatJSON, `[
+  {"id": 1, "name": "alice"},
+  {"id": 2, "name": null}
+]
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteResult(&buf, testResult(), tt.format); err != nil {
+				t.Fatalf("WriteResult: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Fatalf("output:\n%s\nwant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteResult_EmptyJSON(t *testing.T) {
+	var buf bytes.Buffer
+	res := &Result{Columns: []Column{{Name: "id", Type: "int"}}}
+	if err := WriteResult(&buf, res, FormatJSON); err != nil {
+		t.Fatalf("WriteResult: %v", err)
+	}
+	if buf.String() != "[]\n" {
+		t.Fatalf("output = %q", buf.String())
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	if err := ValidateFormat("xml"); err == nil || !strings.Contains(err.Error(), "supported: table, csv, tsv, json") {
+		t.Fatalf("ValidateFormat(xml) = %v", err)
+	}
+}
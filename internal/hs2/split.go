@@ -0,0 +1,103 @@
+package hs2
+
+import "strings"
+
+// Split splits a HiveQL script into statements terminated by ';'. Semicolons
+// inside quoted strings, backtick identifiers and comments do not end a
+// statement. The returned statements are trimmed and never empty; remainder
+// is the trailing text after the last terminator (trimmed), which an
+// interactive caller keeps reading into.
+func Split(script string) (statements []string, remainder string) {
+	var (
+		sb    strings.Builder
+		quote rune // current quote character, 0 outside quotes
+	)
+	flush := func() {
+		if stmt := strings.TrimSpace(sb.String()); stmt != "" && !isCommentOnly(stmt) {
+			statements = append(statements, stmt)
+		}
+		sb.Reset()
+	}
+
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case quote != 0:
+			sb.WriteRune(r)
+			if r == '\\' && quote != '`' && next != 0 {
+				sb.WriteRune(next)
+				i++
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+			sb.WriteRune(r)
+		case r == '-' && next == '-':
+			// Line comment: keep it in the statement text up to the newline.
+			for ; i < len(runes) && runes[i] != '\n'; i++ {
+				sb.WriteRune(runes[i])
+			}
+			if i < len(runes) {
+				sb.WriteRune('\n')
+			}
+		case r == '/' && next == '*':
+			end := i + 2
+			for end < len(runes) && !(runes[end] == '*' && end+1 < len(runes) && runes[end+1] == '/') {
+				end++
+			}
+			end = min(end+2, len(runes))
+			sb.WriteString(string(runes[i:end]))
+			i = end - 1
+		case r == ';':
+			flush()
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	remainder = strings.TrimSpace(sb.String())
+	if isCommentOnly(remainder) {
+		remainder = ""
+	}
+	return statements, remainder
+}
+
+// SplitStatements splits a HiveQL script into statements; a final statement
+// without a terminating ';' is included.
+func SplitStatements(script string) []string {
+	statements, remainder := Split(script)
+	if remainder != "" {
+		statements = append(statements, remainder)
+	}
+	return statements
+}
+
+// isCommentOnly reports whether s consists only of comments and whitespace.
+func isCommentOnly(s string) bool {
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		switch {
+		case strings.HasPrefix(s, "--"):
+			if i := strings.IndexByte(s, '\n'); i >= 0 {
+				s = s[i+1:]
+			} else {
+				s = ""
+			}
+		case strings.HasPrefix(s, "/*"):
+			i := strings.Index(s[2:], "*/")
+			if i < 0 {
+				return true
+			}
+			s = s[i+4:]
+		default:
+			return false
+		}
+	}
+	return true
+}
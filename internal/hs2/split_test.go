@@ -0,0 +1,61 @@
+package hs2
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name      string
+		script    string
+		want      []string
+		remainder string
+	}{
+		{
+			name:   "simple",
+			script: "SHOW DATABASES; USE sales;\nSELECT 1;",
+			want:   []string{"SHOW DATABASES", "USE sales", "SELECT 1"},
+		},
+		{
+			name:   "semicolons in quotes and identifiers",
+			script: "SELECT 'a;b', \"c;d\", `e;f` FROM t; SELECT 'it\\'s;'",
+			want:   []string{"SELECT 'a;b', \"c;d\", `e;f` FROM t"},
+
+			remainder: "SELECT 'it\\'s;'",
+		},
+		{
+			name:   "comments",
+			script: "-- header; not a statement\nSELECT 1; /* block; comment */\n-- trailing only\n",
+			want:   []string{"-- header; not a statement\nSELECT 1"},
+		},
+		{
+			name:      "incomplete statement",
+			script:    "SELECT *\nFROM t",
+			remainder: "SELECT *\nFROM t",
+		},
+		{
+			name:   "empty statements",
+			script: ";;  ;\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, remainder := Split(tt.script)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("statements = %q, want %q", got, tt.want)
+			}
+			if remainder != tt.remainder {
+				t.Fatalf("remainder = %q, want %q", remainder, tt.remainder)
+			}
+		})
+	}
+}
+
+func TestSplitStatements_IncludesUnterminated(t *testing.T) {
+	got := SplitStatements("USE sales; SELECT 1")
+	want := []string{"USE sales", "SELECT 1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("SplitStatements = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+// Package junit writes test reports in the JUnit XML format understood by CI
+// systems (Jenkins, GitHub Actions, GitLab).
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Suite is a group of test cases, e.g. one script or one test file.
+type Suite struct {
+	Name  string
+	Cases []Case
+}
+
+// Case is a single test case. A case with a non-empty Failure failed; a
+// skipped case was not run.
+type Case struct {
+	Name      string
+	Classname string
+	Time      time.Duration
+	Failure   string // failure message; empty if the case passed
+	Details   string // failure details (e.g. the statement that failed)
+	Skipped   bool
+}
+
+type xmlSuites struct {
+	XMLName  xml.Name   `xml:"testsuites"`
+	Tests    int        `xml:"tests,attr"`
+	Failures int        `xml:"failures,attr"`
+	Skipped  int        `xml:"skipped,attr"`
+	Time     string     `xml:"time,attr"`
+	Suites   []xmlSuite `xml:"testsuite"`
+}
+
+type xmlSuite struct {
+	Name     string    `xml:"name,attr"`
+	Tests    int       `xml:"tests,attr"`
+	Failures int       `xml:"failures,attr"`
+	Skipped  int       `xml:"skipped,attr"`
+	Time     string    `xml:"time,attr"`
+	Cases    []xmlCase `xml:"testcase"`
+}
+
+type xmlCase struct {
+	Name      string      `xml:"name,attr"`
+	Classname string      `xml:"classname,attr"`
+	Time      string      `xml:"time,attr"`
+	Failure   *xmlFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}   `xml:"skipped,omitempty"`
+}
+
+type xmlFailure struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// Write writes suites as a <testsuites> document.
+func Write(w io.Writer, suites []Suite) error {
+	doc := xmlSuites{}
+	var total time.Duration
+	for _, s := range suites {
+		xs := xmlSuite{Name: s.Name}
+		var suiteTime time.Duration
+		for _, c := range s.Cases {
+			xc := xmlCase{Name: c.Name, Classname: c.Classname, Time: seconds(c.Time)}
+			switch {
+			case c.Failure != "":
+				xc.Failure = &xmlFailure{Message: c.Failure, Body: c.Details}
+				xs.Failures++
+			case c.Skipped:
+				xc.Skipped = &struct{}{}
+				xs.Skipped++
+			}
+			xs.Cases = append(xs.Cases, xc)
+			xs.Tests++
+			suiteTime += c.Time
+		}
+		xs.Time = seconds(suiteTime)
+
+		doc.Suites = append(doc.Suites, xs)
+		doc.Tests += xs.Tests
+		doc.Failures += xs.Failures
+		doc.Skipped += xs.Skipped
+		total += suiteTime
+	}
+	doc.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,38 @@
+package junit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	suites := []Suite{{
+		Name: "daily.hql",
+		Cases: []Case{
+			{Name: "statement 1", Classname: "daily.hql", Time: 1500 * time.Millisecond},
+			{Name: "statement 2", Classname: "daily.hql", Time: 250 * time.Millisecond, Failure: "table not found", Details: "SELECT * FROM missing"},
+			{Name: "statement 3", Classname: "daily.hql", Skipped: true},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, suites); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<testsuites tests="3" failures="1" skipped="1" time="1.750">`,
+		`<testsuite name="daily.hql" tests="3" failures="1" skipped="1" time="1.750">`,
+		`<testcase name="statement 1" classname="daily.hql" time="1.500"></testcase>`,
+		`<failure message="table not found">SELECT * FROM missing</failure>`,
+		`<skipped></skipped>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("report missing %q:\n%s", want, out)
+		}
+	}
+}
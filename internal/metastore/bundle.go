@@ -0,0 +1,351 @@
+package metastore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+)
+
+const (
+	// BundleFormatVersion is bumped when the bundle layout changes incompatibly.
+	BundleFormatVersion = 1
+
+	bundleJSONFile = "metastore.json"
+	bundleDDLFile  = "ddl.sql"
+
+	// addPartitionsBatch limits the number of partitions per AddPartitions call.
+	addPartitionsBatch = 500
+)
+
+// Bundle is a portable dump of metastore databases, tables and partitions.
+type Bundle struct {
+	FormatVersion int         `json:"format-version"`
+	CreatedAt     time.Time   `json:"created-at"`
+	SourceDBType  string      `json:"source-db-type,omitempty"`
+	Databases     []*Database `json:"databases"`
+}
+
+// DDLSource returns CREATE statements for tables (e.g. via HS2 SHOW CREATE TABLE).
+type DDLSource interface {
+	ShowCreateTable(ctx context.Context, db, table string) (string, error)
+}
+
+// ExportOptions controls what is exported.
+type ExportOptions struct {
+	Databases    []string  // empty means all databases
+	DDL          DDLSource // optional; tables get no DDL when nil
+	SourceDBType string
+}
+
+// Export reads databases, tables and partitions from the catalog into a bundle.
+// DDL lookup failures are not fatal; they are returned as warnings.
+func Export(ctx context.Context, cat Catalog, opts ExportOptions) (*Bundle, []string, error) {
+	names := opts.Databases
+	if len(names) == 0 {
+		var err error
+		if names, err = cat.Databases(ctx); err != nil {
+			return nil, nil, fmt.Errorf("failed to list databases: %w", err)
+		}
+	}
+	sort.Strings(names)
+
+	bundle := &Bundle{
+		FormatVersion: BundleFormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		SourceDBType:  opts.SourceDBType,
+	}
+	var warnings []string
+
+	for _, name := range names {
+		db, err := cat.Database(ctx, name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tableNames, err := cat.Tables(ctx, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		sort.Strings(tableNames)
+
+		for _, tableName := range tableNames {
+			table, err := cat.Table(ctx, name, tableName)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if len(table.PartitionKeys) > 0 && !table.IsView() {
+				if table.Partitions, err = cat.Partitions(ctx, name, tableName); err != nil {
+					return nil, nil, err
+				}
+			}
+
+			if opts.DDL != nil {
+				ddl, err := opts.DDL.ShowCreateTable(ctx, name, tableName)
+				if err != nil {
+					warnings = append(warnings, fmt.Sprintf("no DDL for %s.%s: %v", name, tableName, err))
+				} else {
+					table.DDL = ddl
+				}
+			}
+
+			db.Tables = append(db.Tables, table)
+		}
+
+		bundle.Databases = append(bundle.Databases, db)
+	}
+
+	return bundle, warnings, nil
+}
+
+// Counts returns the number of databases, tables and partitions in the bundle.
+func (b *Bundle) Counts() (dbs, tables, partitions int) {
+	for _, db := range b.Databases {
+		dbs++
+		for _, t := range db.Tables {
+			tables++
+			partitions += len(t.Partitions)
+		}
+	}
+	return dbs, tables, partitions
+}
+
+// WriteBundle writes metastore.json and ddl.sql into dir.
+func WriteBundle(dir string, b *Bundle) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create bundle directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bundle: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, bundleJSONFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", bundleJSONFile, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, bundleDDLFile), []byte(RenderDDL(b)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", bundleDDLFile, err)
+	}
+	return nil
+}
+
+// ReadBundle reads a bundle from a directory (or its metastore.json file).
+func ReadBundle(path string) (*Bundle, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, bundleJSONFile)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if b.FormatVersion != BundleFormatVersion {
+		return nil, fmt.Errorf("unsupported bundle format version %d (expected %d)", b.FormatVersion, BundleFormatVersion)
+	}
+	return &b, nil
+}
+
+// RenderDDL renders the bundle as a HiveQL script. Tables without captured
+// DDL are listed as comments.
+func RenderDDL(b *Bundle) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "-- local-data metastore export (%s)\n", b.CreatedAt.Format(time.RFC3339))
+
+	for _, db := range b.Databases {
+		sb.WriteString("\n")
+		if db.Name != "default" {
+			fmt.Fprintf(&sb, "CREATE DATABASE IF NOT EXISTS %s", hs2.QuoteIdent(db.Name))
+			if db.Description != "" {
+				fmt.Fprintf(&sb, " COMMENT %s", hs2.QuoteString(db.Description))
+			}
+			if db.Location != "" {
+				fmt.Fprintf(&sb, " LOCATION %s", hs2.QuoteString(db.Location))
+			}
+			sb.WriteString(";\n")
+		}
+		fmt.Fprintf(&sb, "USE %s;\n", hs2.QuoteIdent(db.Name))
+
+		for _, t := range orderTables(db.Tables) {
+			sb.WriteString("\n")
+			if t.DDL == "" {
+				fmt.Fprintf(&sb, "-- %s.%s: DDL not captured\n", db.Name, t.Name)
+				continue
+			}
+			sb.WriteString(strings.TrimRight(t.DDL, "; \n"))
+			sb.WriteString(";\n")
+
+			for _, p := range t.Partitions {
+				fmt.Fprintf(&sb, "ALTER TABLE %s ADD IF NOT EXISTS PARTITION (%s)", hs2.QuoteIdent(t.Name), partitionSpec(t, p))
+				if p.Storage != nil && p.Storage.Location != "" {
+					fmt.Fprintf(&sb, " LOCATION %s", hs2.QuoteString(p.Storage.Location))
+				}
+				sb.WriteString(";\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// ImportStatus is the outcome of a single import action.
+type ImportStatus string
+
+const (
+	StatusCreated ImportStatus = "created"
+	StatusPlanned ImportStatus = "would create"
+	StatusExists  ImportStatus = "exists, skipped"
+	StatusFailed  ImportStatus = "failed"
+)
+
+// ImportAction records what happened (or would happen) to one object.
+type ImportAction struct {
+	Kind   string // database, table, view, partitions
+	Name   string
+	Count  int // number of partitions for Kind == "partitions"
+	Status ImportStatus
+	Err    error
+}
+
+// ImportReport lists the actions of an import run.
+type ImportReport struct {
+	DryRun  bool
+	Actions []ImportAction
+}
+
+// Failed returns the failed actions.
+func (r *ImportReport) Failed() []ImportAction {
+	var failed []ImportAction
+	for _, a := range r.Actions {
+		if a.Status == StatusFailed {
+			failed = append(failed, a)
+		}
+	}
+	return failed
+}
+
+// Import replays a bundle into the catalog. Existing databases are reused and
+// existing tables are skipped (including their partitions). Tables are created
+// before views. Individual failures are recorded and do not stop the import.
+func Import(ctx context.Context, cat Catalog, b *Bundle, dryRun bool) (*ImportReport, error) {
+	report := &ImportReport{DryRun: dryRun}
+	record := func(a ImportAction) { report.Actions = append(report.Actions, a) }
+
+	existingDBs, err := cat.Databases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list databases: %w", err)
+	}
+	dbExists := make(map[string]bool, len(existingDBs))
+	for _, name := range existingDBs {
+		dbExists[strings.ToLower(name)] = true
+	}
+
+	for _, db := range b.Databases {
+		tableExists := map[string]bool{}
+		dbCreated := true
+
+		if dbExists[strings.ToLower(db.Name)] {
+			record(ImportAction{Kind: "database", Name: db.Name, Status: StatusExists})
+			tables, err := cat.Tables(ctx, db.Name)
+			if err != nil {
+				return nil, err
+			}
+			for _, t := range tables {
+				tableExists[strings.ToLower(t)] = true
+			}
+		} else if dryRun {
+			record(ImportAction{Kind: "database", Name: db.Name, Status: StatusPlanned})
+		} else if err := cat.CreateDatabase(ctx, db); err != nil {
+			record(ImportAction{Kind: "database", Name: db.Name, Status: StatusFailed, Err: err})
+			dbCreated = false
+		} else {
+			record(ImportAction{Kind: "database", Name: db.Name, Status: StatusCreated})
+		}
+
+		for _, t := range orderTables(db.Tables) {
+			name := db.Name + "." + t.Name
+			kind := "table"
+			if t.IsView() {
+				kind = "view"
+			}
+
+			switch {
+			case !dbCreated:
+				record(ImportAction{Kind: kind, Name: name, Status: StatusFailed, Err: fmt.Errorf("database %s was not created", db.Name)})
+				continue
+			case tableExists[strings.ToLower(t.Name)]:
+				record(ImportAction{Kind: kind, Name: name, Status: StatusExists})
+				continue
+			case dryRun:
+				record(ImportAction{Kind: kind, Name: name, Status: StatusPlanned})
+				if len(t.Partitions) > 0 {
+					record(ImportAction{Kind: "partitions", Name: name, Count: len(t.Partitions), Status: StatusPlanned})
+				}
+				continue
+			}
+
+			table := *t
+			table.Database = db.Name
+			if err := cat.CreateTable(ctx, &table); err != nil {
+				record(ImportAction{Kind: kind, Name: name, Status: StatusFailed, Err: err})
+				continue
+			}
+			record(ImportAction{Kind: kind, Name: name, Status: StatusCreated})
+
+			if len(t.Partitions) == 0 {
+				continue
+			}
+			added := 0
+			for start := 0; start < len(t.Partitions); start += addPartitionsBatch {
+				end := min(start+addPartitionsBatch, len(t.Partitions))
+				if err := cat.AddPartitions(ctx, &table, t.Partitions[start:end]); err != nil {
+					record(ImportAction{Kind: "partitions", Name: name, Count: len(t.Partitions) - added, Status: StatusFailed, Err: err})
+					break
+				}
+				added = end
+			}
+			if added > 0 {
+				record(ImportAction{Kind: "partitions", Name: name, Count: added, Status: StatusCreated})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// orderTables returns tables before views so views can resolve their sources.
+func orderTables(tables []*Table) []*Table {
+	ordered := make([]*Table, 0, len(tables))
+	for _, t := range tables {
+		if !t.IsView() {
+			ordered = append(ordered, t)
+		}
+	}
+	for _, t := range tables {
+		if t.IsView() {
+			ordered = append(ordered, t)
+		}
+	}
+	return ordered
+}
+
+func partitionSpec(t *Table, p *Partition) string {
+	parts := make([]string, 0, len(p.Values))
+	for i, v := range p.Values {
+		if i >= len(t.PartitionKeys) {
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s", hs2.QuoteIdent(t.PartitionKeys[i].Name), hs2.QuoteString(v)))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,289 @@
+package metastore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeCatalog is an in-memory Catalog.
+type fakeCatalog struct {
+	dbs        map[string]*Database
+	tables     map[string]map[string]*Table
+	partitions map[string][]*Partition
+	failTable  string
+}
+
+func newFakeCatalog() *fakeCatalog {
+	return &fakeCatalog{
+		dbs:        map[string]*Database{},
+		tables:     map[string]map[string]*Table{},
+		partitions: map[string][]*Partition{},
+	}
+}
+
+func (f *fakeCatalog) Databases(context.Context) ([]string, error) {
+	var names []string
+	for name := range f.dbs {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (f *fakeCatalog) Database(_ context.Context, name string) (*Database, error) {
+	db, ok := f.dbs[name]
+	if !ok {
+		return nil, errors.New("no such database")
+	}
+	copied := *db
+	return &copied, nil
+}
+
+func (f *fakeCatalog) Tables(_ context.Context, db string) ([]string, error) {
+	var names []string
+	for name := range f.tables[db] {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (f *fakeCatalog) Table(_ context.Context, db, name string) (*Table, error) {
+	t, ok := f.tables[db][name]
+	if !ok {
+		return nil, errors.New("no such table")
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (f *fakeCatalog) Partitions(_ context.Context, db, table string) ([]*Partition, error) {
+	return f.partitions[db+"."+table], nil
+}
+
+func (f *fakeCatalog) CreateDatabase(_ context.Context, db *Database) error {
+	f.dbs[db.Name] = db
+	f.tables[db.Name] = map[string]*Table{}
+	return nil
+}
+
+func (f *fakeCatalog) CreateTable(_ context.Context, t *Table) error {
+	if t.Name == f.failTable {
+		return errors.New("boom")
+	}
+	copied := *t
+	copied.Partitions = nil
+	f.tables[t.Database][t.Name] = &copied
+	return nil
+}
+
+func (f *fakeCatalog) AddPartitions(_ context.Context, t *Table, parts []*Partition) error {
+	key := t.Database + "." + t.Name
+	f.partitions[key] = append(f.partitions[key], parts...)
+	return nil
+}
+
+func sourceCatalog() *fakeCatalog {
+	cat := newFakeCatalog()
+	cat.CreateDatabase(context.Background(), &Database{Name: "default", Location: "file:/wh"})
+	cat.CreateDatabase(context.Background(), &Database{Name: "sales", Description: "it's sales", Location: "file:/wh/sales.db"})
+
+	cat.CreateTable(context.Background(), &Table{
+		Database: "sales", Name: "orders", Type: ManagedTable,
+		Columns:       []Column{{Name: "id", Type: "bigint"}},
+		PartitionKeys: []Column{{Name: "dt", Type: "string"}},
+		Storage:       &Storage{Location: "file:/wh/sales.db/orders", SerDe: "org.apache.hadoop.hive.ql.io.parquet.serde.ParquetHiveSerDe"},
+	})
+	cat.partitions["sales.orders"] = []*Partition{
+		{Values: []string{"2026-01-01"}, Storage: &Storage{Location: "file:/wh/sales.db/orders/dt=2026-01-01"}},
+		{Values: []string{"2026-01-02"}, Storage: &Storage{Location: "file:/wh/sales.db/orders/dt=2026-01-02"}},
+	}
+	cat.CreateTable(context.Background(), &Table{
+		Database: "sales", Name: "a_view", Type: VirtualView,
+		Columns:          []Column{{Name: "id", Type: "bigint"}},
+		ViewOriginalText: "select id from orders",
+	})
+	return cat
+}
+
+type fakeDDL struct{}
+
+func (fakeDDL) ShowCreateTable(_ context.Context, db, table string) (string, error) {
+	if table == "a_view" {
+		return "", errors.New("not supported")
+	}
+	return "CREATE TABLE `" + table + "`(\n  `id` bigint)", nil
+}
+
+func TestExport_CollectsTablesPartitionsAndDDL(t *testing.T) {
+	bundle, warnings, err := Export(context.Background(), sourceCatalog(), ExportOptions{DDL: fakeDDL{}, SourceDBType: "derby"})
+	if err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	dbs, tables, partitions := bundle.Counts()
+	if dbs != 2 || tables != 2 || partitions != 2 {
+		t.Fatalf("Counts() = %d, %d, %d, want 2, 2, 2", dbs, tables, partitions)
+	}
+	if bundle.SourceDBType != "derby" || bundle.FormatVersion != BundleFormatVersion {
+		t.Errorf("unexpected bundle header: %+v", bundle)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "sales.a_view") {
+		t.Errorf("warnings = %v, want one for sales.a_view", warnings)
+	}
+
+	filtered, _, err := Export(context.Background(), sourceCatalog(), ExportOptions{Databases: []string{"default"}})
+	if err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+	if len(filtered.Databases) != 1 || filtered.Databases[0].Name != "default" {
+		t.Errorf("database filter not applied: %+v", filtered.Databases)
+	}
+}
+
+func TestWriteReadBundle_RoundTripAndDDL(t *testing.T) {
+	bundle, _, err := Export(context.Background(), sourceCatalog(), ExportOptions{DDL: fakeDDL{}})
+	if err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "bundle")
+	if err := WriteBundle(dir, bundle); err != nil {
+		t.Fatalf("WriteBundle() error: %v", err)
+	}
+
+	read, err := ReadBundle(dir)
+	if err != nil {
+		t.Fatalf("ReadBundle() error: %v", err)
+	}
+	if _, tables, partitions := read.Counts(); tables != 2 || partitions != 2 {
+		t.Fatalf("round trip lost data: %d tables, %d partitions", tables, partitions)
+	}
+
+	ddl, err := os.ReadFile(filepath.Join(dir, "ddl.sql"))
+	if err != nil {
+		t.Fatalf("read ddl.sql: %v", err)
+	}
+	for _, want := range []string{
+		"CREATE DATABASE IF NOT EXISTS `sales` COMMENT 'it\\'s sales' LOCATION 'file:/wh/sales.db';",
+		"USE `sales`;",
+		"CREATE TABLE `orders`(\n  `id` bigint);",
+		"ALTER TABLE `orders` ADD IF NOT EXISTS PARTITION (`dt`='2026-01-01') LOCATION 'file:/wh/sales.db/orders/dt=2026-01-01';",
+		"-- sales.a_view: DDL not captured",
+	} {
+		if !strings.Contains(string(ddl), want) {
+			t.Errorf("ddl.sql missing %q:\n%s", want, ddl)
+		}
+	}
+	if strings.Contains(string(ddl), "CREATE DATABASE IF NOT EXISTS `default`") {
+		t.Errorf("default database should not be created in ddl.sql")
+	}
+}
+
+func TestReadBundle_RejectsUnknownVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metastore.json")
+	if err := os.WriteFile(path, []byte(`{"format-version": 99}`), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := ReadBundle(path); err == nil {
+		t.Fatalf("ReadBundle() should reject unknown format versions")
+	}
+}
+
+func TestImport_DryRunThenApply(t *testing.T) {
+	bundle, _, err := Export(context.Background(), sourceCatalog(), ExportOptions{})
+	if err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	target := newFakeCatalog()
+	target.CreateDatabase(context.Background(), &Database{Name: "default"})
+
+	report, err := Import(context.Background(), target, bundle, true)
+	if err != nil {
+		t.Fatalf("Import(dry-run) error: %v", err)
+	}
+	if len(target.dbs) != 1 || len(target.tables["default"]) != 0 {
+		t.Fatalf("dry run must not modify the catalog")
+	}
+	want := []string{
+		"database default exists, skipped",
+		"database sales would create",
+		"table sales.orders would create",
+		"partitions sales.orders would create",
+		"view sales.a_view would create",
+	}
+	if got := describe(report); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("dry-run actions:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	report, err = Import(context.Background(), target, bundle, false)
+	if err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+	if len(report.Failed()) != 0 {
+		t.Fatalf("unexpected failures: %+v", report.Failed())
+	}
+	if _, ok := target.tables["sales"]["orders"]; !ok {
+		t.Fatalf("orders was not created")
+	}
+	if got := len(target.partitions["sales.orders"]); got != 2 {
+		t.Fatalf("partitions = %d, want 2", got)
+	}
+
+	// A second import is a no-op
+	report, err = Import(context.Background(), target, bundle, false)
+	if err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+	for _, a := range report.Actions {
+		if a.Status != StatusExists {
+			t.Errorf("re-import action %s %s = %s, want skipped", a.Kind, a.Name, a.Status)
+		}
+	}
+}
+
+func TestImport_RecordsFailuresAndContinues(t *testing.T) {
+	bundle, _, err := Export(context.Background(), sourceCatalog(), ExportOptions{})
+	if err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	target := newFakeCatalog()
+	target.failTable = "orders"
+
+	report, err := Import(context.Background(), target, bundle, false)
+	if err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+	failed := report.Failed()
+	if len(failed) != 1 || failed[0].Name != "sales.orders" {
+		t.Fatalf("Failed() = %+v, want sales.orders only", failed)
+	}
+	if _, ok := target.tables["sales"]["a_view"]; !ok {
+		t.Fatalf("view should still be created after a table failure")
+	}
+}
+
+func TestParseThriftURI(t *testing.T) {
+	host, port, err := parseThriftURI("thrift://metastore.local:9999")
+	if err != nil || host != "metastore.local" || port != 9999 {
+		t.Fatalf("parseThriftURI() = %q, %d, %v", host, port, err)
+	}
+	if _, port, _ := parseThriftURI("thrift://localhost"); port != DefaultThriftPort {
+		t.Errorf("default port = %d, want %d", port, DefaultThriftPort)
+	}
+	if _, _, err := parseThriftURI("http://localhost:9083"); err == nil {
+		t.Errorf("non-thrift URI should be rejected")
+	}
+}
+
+func describe(r *ImportReport) []string {
+	var out []string
+	for _, a := range r.Actions {
+		out = append(out, a.Kind+" "+a.Name+" "+string(a.Status))
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+package metastore
+
+import "context"
+
+// Table types as reported by the metastore.
+const (
+	ManagedTable     = "MANAGED_TABLE"
+	ExternalTable    = "EXTERNAL_TABLE"
+	VirtualView      = "VIRTUAL_VIEW"
+	MaterializedView = "MATERIALIZED_VIEW"
+)
+
+// Database is a backend-independent description of a metastore database.
+type Database struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Location    string            `json:"location,omitempty"`
+	Owner       string            `json:"owner,omitempty"`
+	Parameters  map[string]string `json:"parameters,omitempty"`
+	Tables      []*Table          `json:"tables,omitempty"`
+}
+
+// Table is a backend-independent description of a metastore table or view.
+type Table struct {
+	Database         string            `json:"database"`
+	Name             string            `json:"name"`
+	Type             string            `json:"type"`
+	Owner            string            `json:"owner,omitempty"`
+	Columns          []Column          `json:"columns"`
+	PartitionKeys    []Column          `json:"partition-keys,omitempty"`
+	Parameters       map[string]string `json:"parameters,omitempty"`
+	Storage          *Storage          `json:"storage,omitempty"`
+	ViewOriginalText string            `json:"view-original-text,omitempty"`
+	ViewExpandedText string            `json:"view-expanded-text,omitempty"`
+	DDL              string            `json:"ddl,omitempty"` // SHOW CREATE TABLE output, informational
+	Partitions       []*Partition      `json:"partitions,omitempty"`
+}
+
+// IsView reports whether the table is a (materialized) view.
+func (t *Table) IsView() bool {
+	return t.Type == VirtualView || t.Type == MaterializedView
+}
+
+// Column is a table or partition column.
+type Column struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// SortColumn is a SORTED BY column (order 1 = ASC, 0 = DESC).
+type SortColumn struct {
+	Name  string `json:"name"`
+	Order int32  `json:"order"`
+}
+
+// Storage is a storage descriptor without the column list.
+type Storage struct {
+	Location               string            `json:"location,omitempty"`
+	InputFormat            string            `json:"input-format,omitempty"`
+	OutputFormat           string            `json:"output-format,omitempty"`
+	SerDe                  string            `json:"serde,omitempty"`
+	SerDeParameters        map[string]string `json:"serde-parameters,omitempty"`
+	Compressed             bool              `json:"compressed,omitempty"`
+	NumBuckets             int32             `json:"num-buckets,omitempty"`
+	BucketColumns          []string          `json:"bucket-columns,omitempty"`
+	SortColumns            []SortColumn      `json:"sort-columns,omitempty"`
+	Parameters             map[string]string `json:"parameters,omitempty"`
+	StoredAsSubDirectories bool              `json:"stored-as-sub-directories,omitempty"`
+}
+
+// Partition is a single table partition.
+type Partition struct {
+	Values     []string          `json:"values"`
+	Storage    *Storage          `json:"storage,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// Catalog is the subset of metastore operations used by export/import and
+// catalog listing. ThriftCatalog implements it against a running metastore.
+type Catalog interface {
+	Databases(ctx context.Context) ([]string, error)
+	Database(ctx context.Context, name string) (*Database, error)
+	Tables(ctx context.Context, db string) ([]string, error)
+	Table(ctx context.Context, db, name string) (*Table, error)
+	Partitions(ctx context.Context, db, table string) ([]*Partition, error)
+	CreateDatabase(ctx context.Context, db *Database) error
+	CreateTable(ctx context.Context, table *Table) error
+	AddPartitions(ctx context.Context, table *Table, partitions []*Partition) error
+}
@@ -0,0 +1,93 @@
+package metastore
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var dbNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// ConnInfo holds connection parameters for a Postgres or MySQL metastore database.
+type ConnInfo struct {
+	DBType   DBType
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Database string
+}
+
+// ParseConnInfo parses jdbc:postgresql:// and jdbc:mysql:// URLs of the form
+// jdbc:<driver>://[user[:pass]@]host[:port]/db[?user=..&password=..].
+func ParseConnInfo(dbType DBType, dbURL string) (*ConnInfo, error) {
+	var prefix, defaultPort string
+	switch dbType {
+	case Postgres:
+		prefix, defaultPort = "jdbc:postgresql://", "5432"
+	case MySQL:
+		prefix, defaultPort = "jdbc:mysql://", "3306"
+	default:
+		return nil, fmt.Errorf("db-type %q has no network connection", dbType)
+	}
+
+	raw := strings.TrimSpace(dbURL)
+	if !strings.HasPrefix(strings.ToLower(raw), prefix) {
+		return nil, fmt.Errorf("invalid %s db-url %q", dbType, dbURL)
+	}
+	u, err := url.Parse(strings.TrimPrefix(raw, "jdbc:"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s db-url: %w", dbType, err)
+	}
+
+	dbName := strings.TrimPrefix(u.Path, "/")
+	if dbName == "" {
+		return nil, fmt.Errorf("%s db-url missing database name: %q", dbType, dbURL)
+	}
+	if !dbNamePattern.MatchString(dbName) {
+		return nil, fmt.Errorf("unsupported %s database name %q", dbType, dbName)
+	}
+
+	info := &ConnInfo{
+		DBType:   dbType,
+		Host:     u.Hostname(),
+		Port:     u.Port(),
+		Database: dbName,
+	}
+	if info.Host == "" {
+		info.Host = "localhost"
+	}
+	if info.Port == "" {
+		info.Port = defaultPort
+	}
+	if u.User != nil {
+		info.User = u.User.Username()
+		info.Password, _ = u.User.Password()
+	}
+	q := u.Query()
+	if v := q.Get("user"); v != "" && info.User == "" {
+		info.User = v
+	}
+	if v := q.Get("password"); v != "" && info.Password == "" {
+		info.Password = v
+	}
+	return info, nil
+}
+
+// WithCredentials fills user and password when the URL does not carry them.
+func (c *ConnInfo) WithCredentials(user, password string) *ConnInfo {
+	if c.User == "" {
+		c.User = user
+	}
+	if c.Password == "" {
+		c.Password = password
+	}
+	return c
+}
+
+// Address returns host:port.
+func (c *ConnInfo) Address() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
@@ -0,0 +1,85 @@
+package metastore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// ManagedSuperuser is the bootstrap superuser of a managed Postgres cluster.
+// It can only log in over the unix socket (trust auth).
+const ManagedSuperuser = "postgres"
+
+// PingManaged reports whether a managed Postgres server accepts connections
+// on its unix socket.
+func PingManaged(ctx context.Context, socketDir string, port int) error {
+	db, err := openManaged(socketDir, port)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, ProbeTimeout)
+	defer cancel()
+	return db.PingContext(ctx)
+}
+
+// ProvisionManaged ensures the metastore role and database exist on a managed
+// Postgres server. The role's password is always reset so that db-password
+// changes take effect on the next start.
+func ProvisionManaged(ctx context.Context, socketDir string, port int, role, password, database string) error {
+	if !dbNamePattern.MatchString(database) {
+		return fmt.Errorf("unsupported postgres database name %q", database)
+	}
+
+	db, err := openManaged(socketDir, port)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, ProbeTimeout)
+	defer cancel()
+
+	// Utility statements cannot take bind parameters.
+	roleIdent := pgx.Identifier{role}.Sanitize()
+	passwordLit := "'" + strings.ReplaceAll(password, "'", "''") + "'"
+
+	var n int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM pg_roles WHERE rolname = $1`, role).Scan(&n); err != nil {
+		return fmt.Errorf("failed to look up role %q: %w", role, err)
+	}
+	stmt := fmt.Sprintf("CREATE ROLE %s WITH LOGIN PASSWORD %s", roleIdent, passwordLit)
+	if n > 0 {
+		stmt = fmt.Sprintf("ALTER ROLE %s WITH LOGIN PASSWORD %s", roleIdent, passwordLit)
+	}
+	if _, err := db.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to provision role %q: %w", role, err)
+	}
+
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM pg_database WHERE datname = $1`, database).Scan(&n); err != nil {
+		return fmt.Errorf("failed to look up database %q: %w", database, err)
+	}
+	if n == 0 {
+		stmt = fmt.Sprintf(`CREATE DATABASE "%s" OWNER %s`, database, roleIdent)
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to create database %q: %w", database, err)
+		}
+	}
+	return nil
+}
+
+func openManaged(socketDir string, port int) (*sql.DB, error) {
+	quote := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	dsn := fmt.Sprintf("host='%s' port=%d user=%s dbname=postgres sslmode=disable connect_timeout=%d",
+		quote.Replace(socketDir), port, ManagedSuperuser, int(ProbeTimeout.Seconds()))
+	cfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid postgres connection settings: %w", err)
+	}
+	return stdlib.OpenDB(*cfg), nil
+}
@@ -21,6 +21,15 @@ const (
 	defaultMySQLDBURL    = "jdbc:mysql://localhost:3306/metastore"
 )
 
+// DefaultManagedPort is the default port of the managed Postgres server. It
+// differs from 5432 so it does not collide with a system-wide Postgres.
+const DefaultManagedPort = 5433
+
+// ManagedDBURL returns the metastore JDBC URL for a managed Postgres server.
+func ManagedDBURL(port int) string {
+	return fmt.Sprintf("jdbc:postgresql://localhost:%d/metastore", port)
+}
+
 // NormalizeDBType parses and validates db type values.
 func NormalizeDBType(value string) (DBType, error) {
 	switch strings.ToLower(strings.TrimSpace(value)) {
@@ -0,0 +1,246 @@
+package metastore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// Typed connectivity failures. Use errors.Is on errors returned by Check,
+// DatabaseExists and CreateDatabase.
+var (
+	ErrUnreachable     = errors.New("database host unreachable")
+	ErrAuthFailed      = errors.New("database authentication failed")
+	ErrDatabaseMissing = errors.New("metastore database does not exist")
+	ErrSchemaMissing   = errors.New("metastore schema not initialized")
+)
+
+// ProbeTimeout bounds each connection attempt.
+const ProbeTimeout = 5 * time.Second
+
+// CheckError is a classified connectivity failure.
+type CheckError struct {
+	Kind error // one of the Err* sentinels (nil if unclassified)
+	Info *ConnInfo
+	Err  error
+}
+
+func (e *CheckError) Error() string {
+	target := fmt.Sprintf("%s at %s, database %s", e.Info.DBType, e.Info.Address(), e.Info.Database)
+	if e.Kind == nil {
+		return fmt.Sprintf("%s: %v", target, e.Err)
+	}
+	return fmt.Sprintf("%v (%s): %v", e.Kind, target, e.Err)
+}
+
+func (e *CheckError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// Check verifies the metastore database is reachable, the credentials are
+// accepted, the database exists and the Hive schema has been created.
+func Check(ctx context.Context, info *ConnInfo) error {
+	exists, err := DatabaseExists(ctx, info)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &CheckError{Kind: ErrDatabaseMissing, Info: info, Err: errors.New("run: local-data init")}
+	}
+
+	db, err := open(info, info.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	query := `SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'VERSION'`
+	args := []any{}
+	if info.DBType == MySQL {
+		query += ` AND table_schema = ?`
+		args = append(args, info.Database)
+	}
+
+	var n int
+	if err := queryRow(ctx, db, query, args, &n); err != nil {
+		return classify(info, err)
+	}
+	if n == 0 {
+		return &CheckError{Kind: ErrSchemaMissing, Info: info, Err: errors.New("VERSION table not found")}
+	}
+	return nil
+}
+
+// DatabaseExists connects to the server (not the metastore database) and
+// reports whether the metastore database exists.
+func DatabaseExists(ctx context.Context, info *ConnInfo) (bool, error) {
+	db, err := openAdmin(info)
+	if err != nil {
+		return false, err
+	}
+	defer db.Close()
+
+	var query string
+	switch info.DBType {
+	case Postgres:
+		query = `SELECT COUNT(*) FROM pg_database WHERE datname = $1`
+	default:
+		query = `SELECT COUNT(*) FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME = ?`
+	}
+
+	var n int
+	if err := queryRow(ctx, db, query, []any{info.Database}, &n); err != nil {
+		return false, classify(info, err)
+	}
+	return n > 0, nil
+}
+
+// CreateDatabase creates the metastore database.
+func CreateDatabase(ctx context.Context, info *ConnInfo) error {
+	// Identifiers cannot be bound as parameters; ParseConnInfo restricts
+	// database names to [A-Za-z0-9_].
+	if !dbNamePattern.MatchString(info.Database) {
+		return fmt.Errorf("unsupported %s database name %q", info.DBType, info.Database)
+	}
+
+	db, err := openAdmin(info)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stmt := fmt.Sprintf(`CREATE DATABASE "%s"`, info.Database)
+	if info.DBType == MySQL {
+		stmt = fmt.Sprintf("CREATE DATABASE `%s`", info.Database)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ProbeTimeout)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, stmt); err != nil {
+		return classify(info, err)
+	}
+	return nil
+}
+
+// openAdmin opens a connection that does not select the metastore database.
+func openAdmin(info *ConnInfo) (*sql.DB, error) {
+	if info.DBType == Postgres {
+		return open(info, "postgres")
+	}
+	return open(info, "")
+}
+
+func open(info *ConnInfo, database string) (*sql.DB, error) {
+	switch info.DBType {
+	case Postgres:
+		u := url.URL{
+			Scheme:   "postgres",
+			Host:     info.Address(),
+			Path:     "/" + database,
+			RawQuery: fmt.Sprintf("connect_timeout=%d", int(ProbeTimeout.Seconds())),
+		}
+		if info.User != "" {
+			u.User = url.UserPassword(info.User, info.Password)
+		}
+		cfg, err := pgx.ParseConfig(u.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid postgres connection settings: %w", err)
+		}
+		return stdlib.OpenDB(*cfg), nil
+
+	case MySQL:
+		cfg := mysql.NewConfig()
+		cfg.Net = "tcp"
+		cfg.Addr = info.Address()
+		cfg.User = info.User
+		cfg.Passwd = info.Password
+		cfg.DBName = database
+		cfg.Timeout = ProbeTimeout
+		connector, err := mysql.NewConnector(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mysql connection settings: %w", err)
+		}
+		return sql.OpenDB(connector), nil
+
+	default:
+		return nil, fmt.Errorf("db-type %q has no network connection", info.DBType)
+	}
+}
+
+func queryRow(ctx context.Context, db *sql.DB, query string, args []any, dest ...any) error {
+	ctx, cancel := context.WithTimeout(ctx, ProbeTimeout)
+	defer cancel()
+	return db.QueryRowContext(ctx, query, args...).Scan(dest...)
+}
+
+// classify maps driver errors to the typed errors above.
+func classify(info *ConnInfo, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "28P01", "28000": // invalid_password, invalid_authorization_specification
+			return &CheckError{Kind: ErrAuthFailed, Info: info, Err: err}
+		case "3D000": // invalid_catalog_name
+			return &CheckError{Kind: ErrDatabaseMissing, Info: info, Err: err}
+		case "42P01": // undefined_table
+			return &CheckError{Kind: ErrSchemaMissing, Info: info, Err: err}
+		}
+		return &CheckError{Info: info, Err: err}
+	}
+
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		switch myErr.Number {
+		case 1045, 1044: // ER_ACCESS_DENIED_ERROR, ER_DBACCESS_DENIED_ERROR
+			return &CheckError{Kind: ErrAuthFailed, Info: info, Err: err}
+		case 1049: // ER_BAD_DB_ERROR
+			return &CheckError{Kind: ErrDatabaseMissing, Info: info, Err: err}
+		case 1146: // ER_NO_SUCH_TABLE
+			return &CheckError{Kind: ErrSchemaMissing, Info: info, Err: err}
+		}
+		return &CheckError{Info: info, Err: err}
+	}
+
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.EHOSTUNREACH),
+		errors.Is(err, syscall.ENETUNREACH),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &dnsErr),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return &CheckError{Kind: ErrUnreachable, Info: info, Err: err}
+	}
+
+	return &CheckError{Info: info, Err: err}
+}
+
+// FixHint returns a suggested fix for a typed connectivity error, or "".
+func FixHint(err error) string {
+	switch {
+	case errors.Is(err, ErrUnreachable):
+		return "start the database server, or point db-url at it: local-data setting set db-url <jdbc-url>"
+	case errors.Is(err, ErrAuthFailed):
+		return "check the metastore credentials: local-data setting set db-password <password>"
+	case errors.Is(err, ErrDatabaseMissing):
+		return "create the database: local-data init (answer y when asked to create it)"
+	case errors.Is(err, ErrSchemaMissing):
+		return "initialize the schema: local-data init"
+	default:
+		return ""
+	}
+}
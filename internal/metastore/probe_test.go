@@ -0,0 +1,96 @@
+package metastore
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestParseConnInfo(t *testing.T) {
+	info, err := ParseConnInfo(Postgres, "jdbc:postgresql://db.local/metastore?user=hive&password=secret")
+	if err != nil {
+		t.Fatalf("ParseConnInfo() error: %v", err)
+	}
+	want := ConnInfo{DBType: Postgres, Host: "db.local", Port: "5432", User: "hive", Password: "secret", Database: "metastore"}
+	if *info != want {
+		t.Errorf("info = %+v, want %+v", *info, want)
+	}
+
+	info, err = ParseConnInfo(MySQL, "jdbc:mysql://localhost:3307/metastore")
+	if err != nil {
+		t.Fatalf("ParseConnInfo() error: %v", err)
+	}
+	info.WithCredentials("APP", "pw")
+	if info.Address() != "localhost:3307" || info.User != "APP" || info.Password != "pw" {
+		t.Errorf("info = %+v", *info)
+	}
+
+	for _, tc := range []struct {
+		dbType DBType
+		url    string
+	}{
+		{MySQL, "jdbc:mysql://localhost/meta;drop"},
+		{Postgres, "jdbc:mysql://localhost/metastore"},
+		{Postgres, "jdbc:postgresql://localhost/"},
+		{Derby, "jdbc:derby:;databaseName=metastore_db"},
+	} {
+		if _, err := ParseConnInfo(tc.dbType, tc.url); err == nil {
+			t.Errorf("ParseConnInfo(%s, %q) should fail", tc.dbType, tc.url)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	info := &ConnInfo{DBType: Postgres, Host: "localhost", Port: "5432", Database: "metastore"}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"pg bad password", &pgconn.PgError{Code: "28P01"}, ErrAuthFailed},
+		{"pg missing db", &pgconn.PgError{Code: "3D000"}, ErrDatabaseMissing},
+		{"pg missing table", &pgconn.PgError{Code: "42P01"}, ErrSchemaMissing},
+		{"mysql access denied", &mysql.MySQLError{Number: 1045}, ErrAuthFailed},
+		{"mysql unknown db", &mysql.MySQLError{Number: 1049}, ErrDatabaseMissing},
+		{"timeout", context.DeadlineExceeded, ErrUnreachable},
+		{"dns", &net.DNSError{Err: "no such host", Name: "nope"}, ErrUnreachable},
+		{"other", errors.New("boom"), nil},
+	}
+
+	sentinels := []error{ErrUnreachable, ErrAuthFailed, ErrDatabaseMissing, ErrSchemaMissing}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classify(info, tt.err)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("classified error should wrap the driver error")
+			}
+			for _, s := range sentinels {
+				if got := errors.Is(err, s); got != (s == tt.want) {
+					t.Errorf("errors.Is(%v) = %v", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCheck_Unreachable(t *testing.T) {
+	// Reserve a port and close it so nothing is listening there.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	l.Close()
+
+	for _, dbType := range []DBType{Postgres, MySQL} {
+		info := &ConnInfo{DBType: dbType, Host: "127.0.0.1", Port: port, User: "hive", Database: "metastore"}
+		if err := Check(context.Background(), info); !errors.Is(err, ErrUnreachable) {
+			t.Errorf("%s: Check() error = %v, want ErrUnreachable", dbType, err)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package metastore
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/beltran/gohive"
+	hms "github.com/beltran/gohive/hive_metastore"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// DefaultThriftPort is the metastore port used when hive.metastore.uris is unset.
+const DefaultThriftPort = 9083
+
+// ThriftEndpoint returns the metastore host and port from hive.metastore.uris
+// in hiveConfDir/hive-site.xml (first URI wins). Falls back to localhost:9083.
+func ThriftEndpoint(hiveConfDir string) (string, int, error) {
+	cfg, err := util.ParseHadoopXML(filepath.Join(hiveConfDir, "hive-site.xml"))
+	if err != nil {
+		return "localhost", DefaultThriftPort, nil
+	}
+	if strings.EqualFold(strings.TrimSpace(cfg.GetProperty("hive.metastore.sasl.enabled")), "true") {
+		return "", 0, fmt.Errorf("hive.metastore.sasl.enabled=true is not supported")
+	}
+
+	uris := strings.TrimSpace(cfg.GetProperty("hive.metastore.uris"))
+	if uris == "" {
+		return "localhost", DefaultThriftPort, nil
+	}
+	return parseThriftURI(strings.TrimSpace(strings.Split(uris, ",")[0]))
+}
+
+func parseThriftURI(uri string) (string, int, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "thrift" || u.Hostname() == "" {
+		return "", 0, fmt.Errorf("invalid hive.metastore.uris entry %q (expected thrift://host:port)", uri)
+	}
+	port := DefaultThriftPort
+	if p := u.Port(); p != "" {
+		if port, err = strconv.Atoi(p); err != nil {
+			return "", 0, fmt.Errorf("invalid port in hive.metastore.uris entry %q", uri)
+		}
+	}
+	return u.Hostname(), port, nil
+}
+
+// ThriftCatalog is a Catalog backed by the Hive metastore Thrift API.
+type ThriftCatalog struct {
+	client *gohive.HiveMetastoreClient
+}
+
+// ConnectThrift opens a metastore Thrift connection (no SASL).
+func ConnectThrift(host string, port int) (*ThriftCatalog, error) {
+	client, err := gohive.ConnectToMetastore(host, port, "NOSASL", gohive.NewMetastoreConnectConfiguration())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to metastore at %s:%d (is Hive running? try: local-data start hive): %w",
+			host, port, err)
+	}
+	return &ThriftCatalog{client: client}, nil
+}
+
+// Close closes the connection.
+func (c *ThriftCatalog) Close() {
+	c.client.Close()
+}
+
+func (c *ThriftCatalog) Databases(ctx context.Context) ([]string, error) {
+	return c.client.Client.GetAllDatabases(ctx)
+}
+
+func (c *ThriftCatalog) Database(ctx context.Context, name string) (*Database, error) {
+	db, err := c.client.Client.GetDatabase(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database %s: %w", name, err)
+	}
+	return fromThriftDatabase(db), nil
+}
+
+func (c *ThriftCatalog) Tables(ctx context.Context, db string) ([]string, error) {
+	tables, err := c.client.Client.GetAllTables(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables in %s: %w", db, err)
+	}
+	return tables, nil
+}
+
+func (c *ThriftCatalog) Table(ctx context.Context, db, name string) (*Table, error) {
+	t, err := c.client.Client.GetTable(ctx, db, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table %s.%s: %w", db, name, err)
+	}
+	return fromThriftTable(t), nil
+}
+
+// TablesByName fetches the named tables of db in one call.
+func (c *ThriftCatalog) TablesByName(ctx context.Context, db string, names []string) ([]*Table, error) {
+	tables, err := c.client.Client.GetTableObjectsByName(ctx, db, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tables in %s: %w", db, err)
+	}
+	out := make([]*Table, 0, len(tables))
+	for _, t := range tables {
+		out = append(out, fromThriftTable(t))
+	}
+	return out, nil
+}
+
+// TableCounts returns the number of tables and views in each database in
+// one call.
+func (c *ThriftCatalog) TableCounts(ctx context.Context) (map[string]int, error) {
+	metas, err := c.client.Client.GetTableMeta(ctx, "*", "*", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	counts := map[string]int{}
+	for _, m := range metas {
+		counts[strings.ToLower(m.DbName)]++
+	}
+	return counts, nil
+}
+
+// PartitionNames returns the key=value/... names of a table's partitions,
+// without fetching their storage descriptors.
+func (c *ThriftCatalog) PartitionNames(ctx context.Context, db, table string) ([]string, error) {
+	names, err := c.client.Client.GetPartitionNames(ctx, db, table, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions of %s.%s: %w", db, table, err)
+	}
+	return names, nil
+}
+
+func (c *ThriftCatalog) Partitions(ctx context.Context, db, table string) ([]*Partition, error) {
+	parts, err := c.client.Client.GetPartitions(ctx, db, table, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions of %s.%s: %w", db, table, err)
+	}
+	out := make([]*Partition, 0, len(parts))
+	for _, p := range parts {
+		out = append(out, fromThriftPartition(p))
+	}
+	return out, nil
+}
+
+func (c *ThriftCatalog) CreateDatabase(ctx context.Context, db *Database) error {
+	return c.client.Client.CreateDatabase(ctx, toThriftDatabase(db))
+}
+
+func (c *ThriftCatalog) CreateTable(ctx context.Context, table *Table) error {
+	return c.client.Client.CreateTable(ctx, toThriftTable(table))
+}
+
+// DropTable drops a table; with deleteData the metastore also removes its files.
+func (c *ThriftCatalog) DropTable(ctx context.Context, db, name string, deleteData bool) error {
+	if err := c.client.Client.DropTable(ctx, db, name, deleteData); err != nil {
+		return fmt.Errorf("failed to drop table %s.%s: %w", db, name, err)
+	}
+	return nil
+}
+
+func (c *ThriftCatalog) AddPartitions(ctx context.Context, table *Table, partitions []*Partition) error {
+	parts := make([]*hms.Partition, 0, len(partitions))
+	for _, p := range partitions {
+		parts = append(parts, toThriftPartition(table, p))
+	}
+	_, err := c.client.Client.AddPartitions(ctx, parts)
+	return err
+}
+
+func fromThriftDatabase(db *hms.Database) *Database {
+	out := &Database{
+		Name:        db.Name,
+		Description: db.Description,
+		Location:    db.LocationUri,
+		Parameters:  db.Parameters,
+	}
+	if db.OwnerName != nil {
+		out.Owner = *db.OwnerName
+	}
+	return out
+}
+
+func toThriftDatabase(db *Database) *hms.Database {
+	out := &hms.Database{
+		Name:        db.Name,
+		Description: db.Description,
+		LocationUri: db.Location,
+		Parameters:  db.Parameters,
+	}
+	if db.Owner != "" {
+		owner, ownerType := db.Owner, hms.PrincipalType_USER
+		out.OwnerName = &owner
+		out.OwnerType = &ownerType
+	}
+	return out
+}
+
+func fromThriftTable(t *hms.Table) *Table {
+	out := &Table{
+		Database:         t.DbName,
+		Name:             t.TableName,
+		Type:             t.TableType,
+		Owner:            t.Owner,
+		PartitionKeys:    fromThriftColumns(t.PartitionKeys),
+		Parameters:       t.Parameters,
+		ViewOriginalText: t.ViewOriginalText,
+		ViewExpandedText: t.ViewExpandedText,
+	}
+	if t.Sd != nil {
+		out.Columns = fromThriftColumns(t.Sd.Cols)
+		out.Storage = fromThriftStorage(t.Sd)
+	}
+	return out
+}
+
+func toThriftTable(t *Table) *hms.Table {
+	out := hms.NewTable()
+	out.DbName = t.Database
+	out.TableName = t.Name
+	out.TableType = t.Type
+	out.Owner = t.Owner
+	out.PartitionKeys = toThriftColumns(t.PartitionKeys)
+	out.Parameters = t.Parameters
+	out.ViewOriginalText = t.ViewOriginalText
+	out.ViewExpandedText = t.ViewExpandedText
+	out.Sd = toThriftStorage(t.Storage, t.Columns)
+	return out
+}
+
+func fromThriftPartition(p *hms.Partition) *Partition {
+	return &Partition{
+		Values:     p.Values,
+		Storage:    fromThriftStorage(p.Sd),
+		Parameters: p.Parameters,
+	}
+}
+
+func toThriftPartition(t *Table, p *Partition) *hms.Partition {
+	out := hms.NewPartition()
+	out.DbName = t.Database
+	out.TableName = t.Name
+	out.Values = p.Values
+	out.Parameters = p.Parameters
+	storage := p.Storage
+	if storage == nil {
+		storage = t.Storage
+	}
+	out.Sd = toThriftStorage(storage, t.Columns)
+	return out
+}
+
+func fromThriftColumns(cols []*hms.FieldSchema) []Column {
+	out := make([]Column, 0, len(cols))
+	for _, c := range cols {
+		out = append(out, Column{Name: c.Name, Type: c.Type, Comment: c.Comment})
+	}
+	return out
+}
+
+func toThriftColumns(cols []Column) []*hms.FieldSchema {
+	out := make([]*hms.FieldSchema, 0, len(cols))
+	for _, c := range cols {
+		out = append(out, &hms.FieldSchema{Name: c.Name, Type: c.Type, Comment: c.Comment})
+	}
+	return out
+}
+
+func fromThriftStorage(sd *hms.StorageDescriptor) *Storage {
+	if sd == nil {
+		return nil
+	}
+	out := &Storage{
+		Location:      sd.Location,
+		InputFormat:   sd.InputFormat,
+		OutputFormat:  sd.OutputFormat,
+		Compressed:    sd.Compressed,
+		NumBuckets:    sd.NumBuckets,
+		BucketColumns: sd.BucketCols,
+		Parameters:    sd.Parameters,
+	}
+	if sd.SerdeInfo != nil {
+		out.SerDe = sd.SerdeInfo.SerializationLib
+		out.SerDeParameters = sd.SerdeInfo.Parameters
+	}
+	for _, o := range sd.SortCols {
+		out.SortColumns = append(out.SortColumns, SortColumn{Name: o.Col, Order: o.Order})
+	}
+	if sd.StoredAsSubDirectories != nil {
+		out.StoredAsSubDirectories = *sd.StoredAsSubDirectories
+	}
+	return out
+}
+
+func toThriftStorage(s *Storage, cols []Column) *hms.StorageDescriptor {
+	sd := &hms.StorageDescriptor{
+		Cols:      toThriftColumns(cols),
+		SerdeInfo: &hms.SerDeInfo{},
+	}
+	if s == nil {
+		return sd
+	}
+	sd.Location = s.Location
+	sd.InputFormat = s.InputFormat
+	sd.OutputFormat = s.OutputFormat
+	sd.Compressed = s.Compressed
+	sd.NumBuckets = s.NumBuckets
+	sd.BucketCols = s.BucketColumns
+	sd.Parameters = s.Parameters
+	sd.SerdeInfo.SerializationLib = s.SerDe
+	sd.SerdeInfo.Parameters = s.SerDeParameters
+	for _, c := range s.SortColumns {
+		sd.SortCols = append(sd.SortCols, &hms.Order{Col: c.Name, Order: c.Order})
+	}
+	subDirs := s.StoredAsSubDirectories
+	sd.StoredAsSubDirectories = &subDirs
+	return sd
+}
@@ -0,0 +1,732 @@
+package objectstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const xmlns = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// Handler serves the S3 REST API for a Store. Only path-style requests
+// (http://host/bucket/key) are supported and credentials are not checked.
+type Handler struct {
+	store *Store
+}
+
+// NewHandler returns a handler for store.
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+// ListenAndServe serves the store on addr until ctx is done, logging one
+// line per request with logf.
+func ListenAndServe(ctx context.Context, addr string, store *Store, logf func(format string, args ...any)) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	h := NewHandler(store)
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			h.ServeHTTP(rec, r)
+			logf("%s %s %d", r.Method, r.URL.RequestURI(), rec.status)
+		}),
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// ServeHTTP dispatches a request on its bucket, key and subresource.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("x-amz-request-id", strconv.FormatInt(time.Now().UnixNano(), 36))
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	q := r.URL.Query()
+
+	switch {
+	case bucket == "":
+		if r.Method != http.MethodGet {
+			h.methodNotAllowed(w, r)
+			return
+		}
+		h.listBuckets(w, r)
+
+	case key == "":
+		switch {
+		case r.Method == http.MethodGet && q.Has("location"):
+			h.bucketLocation(w, r, bucket)
+		case r.Method == http.MethodGet && q.Has("uploads"):
+			h.listUploads(w, r, bucket)
+		case r.Method == http.MethodGet:
+			h.listObjects(w, r, bucket)
+		case r.Method == http.MethodHead:
+			h.writeError(w, r, h.store.HasBucket(bucket))
+		case r.Method == http.MethodPut:
+			h.createBucket(w, r, bucket)
+		case r.Method == http.MethodDelete:
+			if err := h.store.DeleteBucket(bucket); err != nil {
+				h.writeError(w, r, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && q.Has("delete"):
+			h.deleteObjects(w, r, bucket)
+		default:
+			h.methodNotAllowed(w, r)
+		}
+
+	default:
+		uploadID := q.Get("uploadId")
+		switch {
+		case r.Method == http.MethodGet || r.Method == http.MethodHead:
+			h.getObject(w, r, bucket, key)
+		case r.Method == http.MethodPut && uploadID != "":
+			h.uploadPart(w, r, uploadID)
+		case r.Method == http.MethodPut && r.Header.Get("x-amz-copy-source") != "":
+			h.copyObject(w, r, bucket, key)
+		case r.Method == http.MethodPut:
+			h.putObject(w, r, bucket, key)
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			h.createUpload(w, r, bucket, key)
+		case r.Method == http.MethodPost && uploadID != "":
+			h.completeUpload(w, r, uploadID)
+		case r.Method == http.MethodDelete && uploadID != "":
+			if err := h.store.AbortUpload(uploadID); err != nil {
+				h.writeError(w, r, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodDelete:
+			if err := h.store.Delete(bucket, key); err != nil {
+				h.writeError(w, r, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			h.methodNotAllowed(w, r)
+		}
+	}
+}
+
+type listAllMyBucketsResult struct {
+	XMLName xml.Name     `xml:"ListAllMyBucketsResult"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Owner   owner        `xml:"Owner"`
+	Buckets []bucketInfo `xml:"Buckets>Bucket"`
+}
+
+type owner struct {
+	ID          string `xml:"ID"`
+	DisplayName string `xml:"DisplayName"`
+}
+
+type bucketInfo struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+func (h *Handler) listBuckets(w http.ResponseWriter, r *http.Request) {
+	buckets, err := h.store.Buckets()
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	result := listAllMyBucketsResult{Xmlns: xmlns, Owner: owner{ID: AccessKey, DisplayName: AccessKey}}
+	for _, b := range buckets {
+		result.Buckets = append(result.Buckets, bucketInfo{Name: b.Name, CreationDate: formatTime(b.Created)})
+	}
+	writeXML(w, http.StatusOK, result)
+}
+
+type locationConstraint struct {
+	XMLName xml.Name `xml:"LocationConstraint"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Region  string   `xml:",chardata"`
+}
+
+func (h *Handler) bucketLocation(w http.ResponseWriter, r *http.Request, bucket string) {
+	if err := h.store.HasBucket(bucket); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	// An empty constraint means us-east-1
+	writeXML(w, http.StatusOK, locationConstraint{Xmlns: xmlns})
+}
+
+func (h *Handler) createBucket(w http.ResponseWriter, r *http.Request, bucket string) {
+	_, _ = io.Copy(io.Discard, r.Body)
+	if err := h.store.CreateBucket(bucket); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", "/"+bucket)
+	w.WriteHeader(http.StatusOK)
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name       `xml:"ListBucketResult"`
+	Xmlns                 string         `xml:"xmlns,attr"`
+	Name                  string         `xml:"Name"`
+	Prefix                string         `xml:"Prefix"`
+	Marker                *string        `xml:"Marker,omitempty"`
+	NextMarker            string         `xml:"NextMarker,omitempty"`
+	StartAfter            string         `xml:"StartAfter,omitempty"`
+	ContinuationToken     string         `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	KeyCount              *int           `xml:"KeyCount,omitempty"`
+	MaxKeys               int            `xml:"MaxKeys"`
+	Delimiter             string         `xml:"Delimiter,omitempty"`
+	EncodingType          string         `xml:"EncodingType,omitempty"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	Contents              []objectInfo   `xml:"Contents"`
+	CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type objectInfo struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// listObjects implements ListObjects (v1) and ListObjectsV2.
+func (h *Handler) listObjects(w http.ResponseWriter, r *http.Request, bucket string) {
+	q := r.URL.Query()
+	v2 := q.Get("list-type") == "2"
+
+	opts := ListOptions{Prefix: q.Get("prefix"), Delimiter: q.Get("delimiter"), MaxKeys: 1000}
+	if s := q.Get("max-keys"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			h.writeError(w, r, &apiError{http.StatusBadRequest, "InvalidArgument", "invalid max-keys"})
+			return
+		}
+		opts.MaxKeys = n
+	}
+	token := q.Get("continuation-token")
+	if v2 {
+		opts.StartAfter = q.Get("start-after")
+		if token != "" {
+			after, err := base64.StdEncoding.DecodeString(token)
+			if err != nil {
+				h.writeError(w, r, &apiError{http.StatusBadRequest, "InvalidArgument", "invalid continuation token"})
+				return
+			}
+			opts.StartAfter = string(after)
+		}
+	} else {
+		opts.StartAfter = q.Get("marker")
+	}
+
+	result := &ListResult{}
+	if opts.MaxKeys > 0 {
+		var err error
+		if result, err = h.store.List(bucket, opts); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+	} else if err := h.store.HasBucket(bucket); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	encode := func(s string) string { return s }
+	if q.Get("encoding-type") == "url" {
+		encode = url.QueryEscape
+	}
+
+	resp := listBucketResult{
+		Xmlns:        xmlns,
+		Name:         bucket,
+		Prefix:       encode(opts.Prefix),
+		MaxKeys:      opts.MaxKeys,
+		Delimiter:    encode(opts.Delimiter),
+		EncodingType: q.Get("encoding-type"),
+		IsTruncated:  result.Truncated,
+	}
+	for _, obj := range result.Objects {
+		resp.Contents = append(resp.Contents, objectInfo{
+			Key:          encode(obj.Key),
+			LastModified: formatTime(obj.Modified),
+			ETag:         obj.ETag,
+			Size:         obj.Size,
+			StorageClass: "STANDARD",
+		})
+	}
+	for _, p := range result.CommonPrefixes {
+		resp.CommonPrefixes = append(resp.CommonPrefixes, commonPrefix{Prefix: encode(p)})
+	}
+
+	if v2 {
+		count := len(result.Objects) + len(result.CommonPrefixes)
+		resp.KeyCount = &count
+		resp.StartAfter = encode(q.Get("start-after"))
+		resp.ContinuationToken = token
+		if result.Truncated {
+			resp.NextContinuationToken = base64.StdEncoding.EncodeToString([]byte(result.Last))
+		}
+	} else {
+		marker := encode(opts.StartAfter)
+		resp.Marker = &marker
+		if result.Truncated && opts.Delimiter != "" {
+			resp.NextMarker = encode(result.Last)
+		}
+	}
+	writeXML(w, http.StatusOK, resp)
+}
+
+type deleteRequest struct {
+	Quiet   bool `xml:"Quiet"`
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+type deleteResult struct {
+	XMLName xml.Name        `xml:"DeleteResult"`
+	Xmlns   string          `xml:"xmlns,attr"`
+	Deleted []deletedObject `xml:"Deleted"`
+	Errors  []deleteError   `xml:"Error"`
+}
+
+type deletedObject struct {
+	Key string `xml:"Key"`
+}
+
+type deleteError struct {
+	Key     string `xml:"Key"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (h *Handler) deleteObjects(w http.ResponseWriter, r *http.Request, bucket string) {
+	var req deleteRequest
+	if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, &apiError{http.StatusBadRequest, "MalformedXML", err.Error()})
+		return
+	}
+	if err := h.store.HasBucket(bucket); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	result := deleteResult{Xmlns: xmlns}
+	for _, obj := range req.Objects {
+		if err := h.store.Delete(bucket, obj.Key); err != nil {
+			e := toAPIError(err)
+			result.Errors = append(result.Errors, deleteError{Key: obj.Key, Code: e.code, Message: e.message})
+			continue
+		}
+		if !req.Quiet {
+			result.Deleted = append(result.Deleted, deletedObject{Key: obj.Key})
+		}
+	}
+	writeXML(w, http.StatusOK, result)
+}
+
+func (h *Handler) getObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	f, obj, err := h.store.Open(bucket, key)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	defer f.Close()
+	w.Header().Set("ETag", obj.ETag)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	// ServeContent handles HEAD, Range and If-Match/If-None-Match
+	http.ServeContent(w, r, "", obj.Modified, f)
+}
+
+func (h *Handler) putObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	obj, err := h.store.Put(bucket, key, requestBody(r))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", obj.ETag)
+	w.WriteHeader(http.StatusOK)
+}
+
+type copyObjectResult struct {
+	XMLName      xml.Name `xml:"CopyObjectResult"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	LastModified string   `xml:"LastModified"`
+	ETag         string   `xml:"ETag"`
+}
+
+type copyPartResult struct {
+	XMLName      xml.Name `xml:"CopyPartResult"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	LastModified string   `xml:"LastModified"`
+	ETag         string   `xml:"ETag"`
+}
+
+func (h *Handler) copyObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	srcBucket, srcKey, err := copySource(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	_, _ = io.Copy(io.Discard, r.Body)
+	obj, err := h.store.Copy(srcBucket, srcKey, bucket, key)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	writeXML(w, http.StatusOK, copyObjectResult{Xmlns: xmlns, LastModified: formatTime(obj.Modified), ETag: obj.ETag})
+}
+
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+func (h *Handler) createUpload(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	id, err := h.store.CreateUpload(bucket, key)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	writeXML(w, http.StatusOK, initiateMultipartUploadResult{Xmlns: xmlns, Bucket: bucket, Key: key, UploadID: id})
+}
+
+// uploadPart implements UploadPart and UploadPartCopy.
+func (h *Handler) uploadPart(w http.ResponseWriter, r *http.Request, id string) {
+	n, err := strconv.Atoi(r.URL.Query().Get("partNumber"))
+	if err != nil {
+		h.writeError(w, r, &apiError{http.StatusBadRequest, "InvalidArgument", "invalid partNumber"})
+		return
+	}
+
+	if r.Header.Get("x-amz-copy-source") == "" {
+		etag, err := h.store.PutPart(id, n, requestBody(r))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	srcBucket, srcKey, err := copySource(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	src, obj, err := h.store.Open(srcBucket, srcKey)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	defer src.Close()
+	var body io.Reader = src
+	if rng := r.Header.Get("x-amz-copy-source-range"); rng != "" {
+		start, end, err := parseRange(rng, obj.Size)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if _, err := src.Seek(start, io.SeekStart); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		body = io.LimitReader(src, end-start+1)
+	}
+	etag, err := h.store.PutPart(id, n, body)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	writeXML(w, http.StatusOK, copyPartResult{Xmlns: xmlns, LastModified: formatTime(time.Now()), ETag: etag})
+}
+
+type completeMultipartUpload struct {
+	Parts []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+func (h *Handler) completeUpload(w http.ResponseWriter, r *http.Request, id string) {
+	var req completeMultipartUpload
+	if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, &apiError{http.StatusBadRequest, "MalformedXML", err.Error()})
+		return
+	}
+	upload, _, err := h.store.upload(id)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	parts := make([]int, len(req.Parts))
+	for i, p := range req.Parts {
+		parts[i] = p.PartNumber
+	}
+	obj, err := h.store.CompleteUpload(id, parts)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	writeXML(w, http.StatusOK, completeMultipartUploadResult{
+		Xmlns:    xmlns,
+		Location: "/" + upload.Bucket + "/" + upload.Key,
+		Bucket:   upload.Bucket,
+		Key:      upload.Key,
+		ETag:     obj.ETag,
+	})
+}
+
+type listMultipartUploadsResult struct {
+	XMLName     xml.Name     `xml:"ListMultipartUploadsResult"`
+	Xmlns       string       `xml:"xmlns,attr"`
+	Bucket      string       `xml:"Bucket"`
+	Prefix      string       `xml:"Prefix"`
+	MaxUploads  int          `xml:"MaxUploads"`
+	IsTruncated bool         `xml:"IsTruncated"`
+	Uploads     []uploadInfo `xml:"Upload"`
+}
+
+type uploadInfo struct {
+	Key          string `xml:"Key"`
+	UploadID     string `xml:"UploadId"`
+	Initiated    string `xml:"Initiated"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+func (h *Handler) listUploads(w http.ResponseWriter, r *http.Request, bucket string) {
+	prefix := r.URL.Query().Get("prefix")
+	uploads, err := h.store.Uploads(bucket, prefix)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	result := listMultipartUploadsResult{Xmlns: xmlns, Bucket: bucket, Prefix: prefix, MaxUploads: 1000}
+	for _, u := range uploads {
+		result.Uploads = append(result.Uploads, uploadInfo{
+			Key:          u.Key,
+			UploadID:     u.ID,
+			Initiated:    formatTime(u.Initiated),
+			StorageClass: "STANDARD",
+		})
+	}
+	writeXML(w, http.StatusOK, result)
+}
+
+// copySource parses x-amz-copy-source: [/]bucket/key[?versionId=...].
+func copySource(r *http.Request) (string, string, error) {
+	src, err := url.PathUnescape(r.Header.Get("x-amz-copy-source"))
+	if err != nil {
+		return "", "", &apiError{http.StatusBadRequest, "InvalidArgument", "invalid x-amz-copy-source"}
+	}
+	src, _, _ = strings.Cut(strings.TrimPrefix(src, "/"), "?versionId=")
+	bucket, key, ok := strings.Cut(src, "/")
+	if !ok || key == "" {
+		return "", "", &apiError{http.StatusBadRequest, "InvalidArgument", "invalid x-amz-copy-source"}
+	}
+	return bucket, key, nil
+}
+
+// parseRange parses "bytes=start-end" of an object of size bytes.
+func parseRange(s string, size int64) (int64, int64, error) {
+	invalid := &apiError{http.StatusRequestedRangeNotSatisfiable, "InvalidRange", "invalid range " + s}
+	first, last, ok := strings.Cut(strings.TrimPrefix(s, "bytes="), "-")
+	if !ok {
+		return 0, 0, invalid
+	}
+	start, err1 := strconv.ParseInt(first, 10, 64)
+	end, err2 := strconv.ParseInt(last, 10, 64)
+	if err1 != nil || err2 != nil || start > end || end >= size {
+		return 0, 0, invalid
+	}
+	return start, end, nil
+}
+
+// requestBody returns the payload of a request, decoding aws-chunked
+// streaming uploads.
+func requestBody(r *http.Request) io.Reader {
+	if strings.HasPrefix(r.Header.Get("x-amz-content-sha256"), "STREAMING-") ||
+		strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") {
+		return &chunkedReader{r: bufio.NewReader(r.Body)}
+	}
+	return r.Body
+}
+
+// chunkedReader decodes "<hex-size>[;chunk-signature=...]\r\n<data>\r\n"
+// chunks up to the final zero-size chunk; signatures and trailers are
+// ignored.
+type chunkedReader struct {
+	r    *bufio.Reader
+	left int64
+	done bool
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	for c.left == 0 {
+		if c.done {
+			return 0, io.EOF
+		}
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return 0, io.ErrUnexpectedEOF
+		}
+		size, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		n, err := strconv.ParseInt(size, 16, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid aws-chunked chunk size %q", size)
+		}
+		if n == 0 {
+			c.done = true
+			return 0, io.EOF
+		}
+		c.left = n
+	}
+
+	if int64(len(p)) > c.left {
+		p = p[:c.left]
+	}
+	n, err := c.r.Read(p)
+	c.left -= int64(n)
+	if c.left == 0 {
+		if _, err := c.r.ReadString('\n'); err != nil {
+			return n, io.ErrUnexpectedEOF
+		}
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.code + ": " + e.message
+}
+
+type errorResponse struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string   `xml:"Code"`
+	Message   string   `xml:"Message"`
+	Resource  string   `xml:"Resource"`
+	RequestID string   `xml:"RequestId"`
+}
+
+func toAPIError(err error) *apiError {
+	var e *apiError
+	if errors.As(err, &e) {
+		return e
+	}
+	switch {
+	case errors.Is(err, ErrNoSuchBucket):
+		return &apiError{http.StatusNotFound, "NoSuchBucket", err.Error()}
+	case errors.Is(err, ErrNoSuchKey):
+		return &apiError{http.StatusNotFound, "NoSuchKey", err.Error()}
+	case errors.Is(err, ErrNoSuchUpload):
+		return &apiError{http.StatusNotFound, "NoSuchUpload", err.Error()}
+	case errors.Is(err, ErrBucketExists):
+		return &apiError{http.StatusConflict, "BucketAlreadyOwnedByYou", err.Error()}
+	case errors.Is(err, ErrBucketNotEmpty):
+		return &apiError{http.StatusConflict, "BucketNotEmpty", err.Error()}
+	case errors.Is(err, ErrInvalidBucketName):
+		return &apiError{http.StatusBadRequest, "InvalidBucketName", err.Error()}
+	case errors.Is(err, ErrInvalidPart):
+		return &apiError{http.StatusBadRequest, "InvalidPart", err.Error()}
+	case errors.Is(err, ErrInvalidKey), errors.Is(err, ErrKeyConflict):
+		return &apiError{http.StatusBadRequest, "InvalidArgument", err.Error()}
+	default:
+		return &apiError{http.StatusInternalServerError, "InternalError", err.Error()}
+	}
+}
+
+// writeError writes an S3 error response; nil writes 200 with no body.
+// HEAD responses carry only the status.
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	e := toAPIError(err)
+	if r.Method == http.MethodHead {
+		w.WriteHeader(e.status)
+		return
+	}
+	writeXML(w, e.status, errorResponse{
+		Code:      e.code,
+		Message:   e.message,
+		Resource:  r.URL.Path,
+		RequestID: w.Header().Get("x-amz-request-id"),
+	})
+}
+
+func (h *Handler) methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	h.writeError(w, r, &apiError{http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method + " is not supported for this resource"})
+}
+
+func writeXML(w http.ResponseWriter, status int, v any) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, xml.Header)
+	_, _ = w.Write(data)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
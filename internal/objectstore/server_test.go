@@ -0,0 +1,137 @@
+package objectstore
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *Store) {
+	t.Helper()
+	store := NewStore(t.TempDir())
+	srv := httptest.NewServer(NewHandler(store))
+	t.Cleanup(srv.Close)
+	return srv, store
+}
+
+func do(t *testing.T, method, url string, body string, header map[string]string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(data)
+}
+
+func TestHandler_ObjectLifecycle(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	if resp, _ := do(t, "PUT", srv.URL+"/data", "", nil); resp.StatusCode != 200 {
+		t.Fatalf("create bucket: %d", resp.StatusCode)
+	}
+	if resp, _ := do(t, "HEAD", srv.URL+"/data", "", nil); resp.StatusCode != 200 {
+		t.Fatalf("head bucket: %d", resp.StatusCode)
+	}
+
+	resp, _ := do(t, "PUT", srv.URL+"/data/raw/a.csv", "id,name\n1,x\n", nil)
+	if resp.StatusCode != 200 || resp.Header.Get("ETag") == "" {
+		t.Fatalf("put: %d etag %q", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+
+	resp, body := do(t, "GET", srv.URL+"/data/raw/a.csv", "", map[string]string{"Range": "bytes=3-6"})
+	if resp.StatusCode != http.StatusPartialContent || body != "name" {
+		t.Errorf("ranged get = %d %q", resp.StatusCode, body)
+	}
+
+	resp, _ = do(t, "HEAD", srv.URL+"/data/raw/a.csv", "", nil)
+	if resp.StatusCode != 200 || resp.ContentLength != 12 {
+		t.Errorf("head = %d, length %d", resp.StatusCode, resp.ContentLength)
+	}
+
+	resp, body = do(t, "GET", srv.URL+"/data/raw/missing", "", nil)
+	if resp.StatusCode != 404 || !strings.Contains(body, "<Code>NoSuchKey</Code>") {
+		t.Errorf("missing key = %d %s", resp.StatusCode, body)
+	}
+
+	resp, _ = do(t, "PUT", srv.URL+"/data/copy/a.csv", "", map[string]string{"x-amz-copy-source": "/data/raw/a.csv"})
+	if resp.StatusCode != 200 {
+		t.Fatalf("copy: %d", resp.StatusCode)
+	}
+
+	resp, body = do(t, "POST", srv.URL+"/data?delete", `<Delete><Object><Key>raw/a.csv</Key></Object></Delete>`, nil)
+	if resp.StatusCode != 200 || !strings.Contains(body, "<Deleted><Key>raw/a.csv</Key></Deleted>") {
+		t.Errorf("delete objects = %d %s", resp.StatusCode, body)
+	}
+
+	_, body = do(t, "GET", srv.URL+"/data?list-type=2&delimiter=/&encoding-type=url", "", nil)
+	var list listBucketResult
+	if err := xml.Unmarshal([]byte(body), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Contents) != 0 || len(list.CommonPrefixes) != 1 || list.CommonPrefixes[0].Prefix != "copy%2F" {
+		t.Errorf("list = %s", body)
+	}
+}
+
+func TestHandler_ChunkedUpload(t *testing.T) {
+	srv, store := newTestServer(t)
+	if err := store.CreateBucket("data"); err != nil {
+		t.Fatal(err)
+	}
+
+	body := "5;chunk-signature=abc\r\nhello\r\n6;chunk-signature=def\r\n world\r\n0;chunk-signature=0\r\n\r\n"
+	resp, _ := do(t, "PUT", srv.URL+"/data/greeting", body, map[string]string{
+		"x-amz-content-sha256":         "STREAMING-AWS4-HMAC-SHA256-PAYLOAD",
+		"x-amz-decoded-content-length": "11",
+	})
+	if resp.StatusCode != 200 {
+		t.Fatalf("put: %d", resp.StatusCode)
+	}
+	if got := read(t, store, "data", "greeting"); got != "hello world" {
+		t.Errorf("content = %q", got)
+	}
+}
+
+func TestHandler_MultipartUpload(t *testing.T) {
+	srv, store := newTestServer(t)
+	if err := store.CreateBucket("data"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, body := do(t, "POST", srv.URL+"/data/big?uploads", "", nil)
+	var init initiateMultipartUploadResult
+	if err := xml.Unmarshal([]byte(body), &init); err != nil || init.UploadID == "" {
+		t.Fatalf("initiate: %s %v", body, err)
+	}
+
+	for n, part := range []string{"abc", "def"} {
+		url := srv.URL + "/data/big?partNumber=" + string(rune('1'+n)) + "&uploadId=" + init.UploadID
+		if resp, _ := do(t, "PUT", url, part, nil); resp.StatusCode != 200 {
+			t.Fatalf("part %d: %d", n+1, resp.StatusCode)
+		}
+	}
+
+	complete := `<CompleteMultipartUpload><Part><PartNumber>1</PartNumber><ETag>x</ETag></Part><Part><PartNumber>2</PartNumber><ETag>y</ETag></Part></CompleteMultipartUpload>`
+	resp, body := do(t, "POST", srv.URL+"/data/big?uploadId="+init.UploadID, complete, nil)
+	if resp.StatusCode != 200 || !strings.Contains(body, "-2&#34;</ETag>") {
+		t.Fatalf("complete: %d %s", resp.StatusCode, body)
+	}
+	if got := read(t, store, "data", "big"); got != "abcdef" {
+		t.Errorf("content = %q", got)
+	}
+}
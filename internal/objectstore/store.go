@@ -0,0 +1,654 @@
+// Package objectstore is a minimal S3-compatible object store backed by a
+// local directory. Each bucket is a directory and each object a file, so data
+// written through s3a:// paths can be inspected on disk; empty directories
+// stand for "dir/" marker objects.
+package objectstore
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPort is the port of the S3 server when s3-port is unset.
+	DefaultPort = 9878
+
+	// Region is reported for every bucket.
+	Region = "us-east-1"
+
+	// AccessKey and SecretKey are written to the generated configs. The
+	// server accepts requests with any credentials.
+	AccessKey = "local-data"
+	SecretKey = "local-data-secret"
+)
+
+var (
+	ErrNoSuchBucket      = errors.New("the specified bucket does not exist")
+	ErrNoSuchKey         = errors.New("the specified key does not exist")
+	ErrNoSuchUpload      = errors.New("the specified multipart upload does not exist")
+	ErrBucketExists      = errors.New("the bucket already exists")
+	ErrBucketNotEmpty    = errors.New("the bucket is not empty")
+	ErrInvalidBucketName = errors.New("invalid bucket name")
+	ErrInvalidKey        = errors.New("invalid object key")
+	ErrInvalidPart       = errors.New("one or more of the specified parts could not be found")
+	ErrKeyConflict       = errors.New("the key conflicts with an existing object or prefix")
+)
+
+var bucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// ValidateBucketName checks a name against the S3 bucket naming rules.
+func ValidateBucketName(name string) error {
+	if !bucketNamePattern.MatchString(name) || strings.Contains(name, "..") {
+		return fmt.Errorf("%w: %q", ErrInvalidBucketName, name)
+	}
+	return nil
+}
+
+// Bucket is a bucket in the store.
+type Bucket struct {
+	Name    string
+	Created time.Time
+}
+
+// Object describes a stored object.
+type Object struct {
+	Key      string
+	Size     int64
+	Modified time.Time
+	ETag     string // quoted
+}
+
+// Store keeps buckets under a root directory. Temporary files and multipart
+// uploads live in .tmp and .uploads, which are not valid bucket names.
+type Store struct {
+	root string
+}
+
+// NewStore returns a store rooted at dir.
+func NewStore(dir string) *Store {
+	return &Store{root: dir}
+}
+
+// Root returns the store directory.
+func (s *Store) Root() string {
+	return s.root
+}
+
+// Buckets returns the buckets in name order.
+func (s *Store) Buckets() ([]Bucket, error) {
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var buckets []Bucket
+	for _, e := range entries {
+		if !e.IsDir() || ValidateBucketName(e.Name()) != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, Bucket{Name: e.Name(), Created: info.ModTime()})
+	}
+	return buckets, nil
+}
+
+// CreateBucket creates an empty bucket.
+func (s *Store) CreateBucket(name string) error {
+	if err := ValidateBucketName(name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.root, 0755); err != nil {
+		return err
+	}
+	if err := os.Mkdir(filepath.Join(s.root, name), 0755); err != nil {
+		if os.IsExist(err) {
+			return ErrBucketExists
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteBucket removes an empty bucket.
+func (s *Store) DeleteBucket(name string) error {
+	if err := s.checkBucket(name); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(filepath.Join(s.root, name))
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return ErrBucketNotEmpty
+	}
+	return os.Remove(filepath.Join(s.root, name))
+}
+
+// HasBucket returns ErrNoSuchBucket if the bucket does not exist.
+func (s *Store) HasBucket(name string) error {
+	return s.checkBucket(name)
+}
+
+func (s *Store) checkBucket(name string) error {
+	if ValidateBucketName(name) != nil {
+		return ErrNoSuchBucket
+	}
+	info, err := os.Stat(filepath.Join(s.root, name))
+	if err != nil || !info.IsDir() {
+		return ErrNoSuchBucket
+	}
+	return nil
+}
+
+// objectPath maps a key to its file, or for "dir/" markers its directory.
+func (s *Store) objectPath(bucket, key string) (string, error) {
+	if err := s.checkBucket(bucket); err != nil {
+		return "", err
+	}
+	segments := strings.Split(strings.TrimSuffix(key, "/"), "/")
+	for _, seg := range segments {
+		if seg == "" || seg == "." || seg == ".." {
+			return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return filepath.Join(s.root, bucket, filepath.Join(segments...)), nil
+}
+
+// Put stores an object. The returned ETag is the MD5 of the content, as S3
+// clients verify it after an upload.
+func (s *Store) Put(bucket, key string, r io.Reader) (Object, error) {
+	path, err := s.objectPath(bucket, key)
+	if err != nil {
+		return Object{}, err
+	}
+
+	h := md5.New()
+	if isMarker(key) {
+		if _, err := io.Copy(h, r); err != nil {
+			return Object{}, err
+		}
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return Object{}, fmt.Errorf("%w: %s", ErrKeyConflict, key)
+		}
+		return Object{Key: key, Modified: time.Now(), ETag: quoteETag(h)}, nil
+	}
+
+	tmpDir := filepath.Join(s.root, ".tmp")
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return Object{}, err
+	}
+	tmp, err := os.CreateTemp(tmpDir, "put-*")
+	if err != nil {
+		return Object{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, io.TeeReader(r, h))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return Object{}, err
+	}
+
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return Object{}, fmt.Errorf("%w: %s", ErrKeyConflict, key)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return Object{}, fmt.Errorf("%w: %s", ErrKeyConflict, key)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return Object{}, err
+	}
+	return Object{Key: key, Size: size, Modified: time.Now(), ETag: quoteETag(h)}, nil
+}
+
+// Stat returns an object's size, modification time and ETag. ETags of
+// stored objects are derived from size and modification time rather than
+// content; they use the multipart form so clients do not compare them with
+// an MD5 of the data.
+func (s *Store) Stat(bucket, key string) (Object, error) {
+	path, err := s.objectPath(bucket, key)
+	if err != nil {
+		return Object{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Object{}, ErrNoSuchKey
+	}
+	switch {
+	case isMarker(key) && info.IsDir():
+		entries, err := os.ReadDir(path)
+		if err != nil || len(entries) > 0 {
+			return Object{}, ErrNoSuchKey
+		}
+		return newObject(key, 0, info.ModTime()), nil
+	case !isMarker(key) && info.Mode().IsRegular():
+		return newObject(key, info.Size(), info.ModTime()), nil
+	default:
+		return Object{}, ErrNoSuchKey
+	}
+}
+
+// Open returns the content of an object.
+func (s *Store) Open(bucket, key string) (io.ReadSeekCloser, Object, error) {
+	obj, err := s.Stat(bucket, key)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	if isMarker(key) {
+		return nopCloser{strings.NewReader("")}, obj, nil
+	}
+	path, _ := s.objectPath(bucket, key)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, Object{}, ErrNoSuchKey
+	}
+	return f, obj, nil
+}
+
+// Delete removes an object and any parent directories it leaves empty.
+// Deleting a missing object is not an error, as in S3.
+func (s *Store) Delete(bucket, key string) error {
+	path, err := s.objectPath(bucket, key)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if info.IsDir() != isMarker(key) {
+		return nil
+	}
+	if err := os.Remove(path); err != nil {
+		// A marker directory that still has objects under it
+		return nil
+	}
+	bucketDir := filepath.Join(s.root, bucket)
+	for dir := filepath.Dir(path); dir != bucketDir; dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// Copy copies an object, possibly to another bucket.
+func (s *Store) Copy(srcBucket, srcKey, dstBucket, dstKey string) (Object, error) {
+	r, _, err := s.Open(srcBucket, srcKey)
+	if err != nil {
+		return Object{}, err
+	}
+	defer r.Close()
+	if _, err := s.Put(dstBucket, dstKey, r); err != nil {
+		return Object{}, err
+	}
+	return s.Stat(dstBucket, dstKey)
+}
+
+// ListOptions selects the objects returned by List.
+type ListOptions struct {
+	Prefix     string
+	Delimiter  string
+	StartAfter string // key or common prefix to continue after
+	MaxKeys    int    // objects plus common prefixes; 0 means 1000
+}
+
+// ListResult is a page of objects and common prefixes in key order.
+type ListResult struct {
+	Objects        []Object
+	CommonPrefixes []string
+	Truncated      bool
+	Last           string // last key or common prefix returned
+}
+
+type listEntry struct {
+	key    string
+	prefix bool // rolled up at a "/" delimiter
+	obj    Object
+}
+
+// List returns the objects under a prefix, rolling keys up to common
+// prefixes at the delimiter.
+func (s *Store) List(bucket string, opts ListOptions) (*ListResult, error) {
+	if err := s.checkBucket(bucket); err != nil {
+		return nil, err
+	}
+	maxKeys := opts.MaxKeys
+	if maxKeys <= 0 || maxKeys > 1000 {
+		maxKeys = 1000
+	}
+
+	entries, err := s.scan(bucket, opts.Prefix, opts.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	result := &ListResult{}
+	count := 0
+	for _, e := range entries {
+		prefix := ""
+		if e.prefix {
+			prefix = e.key
+		} else if opts.Delimiter != "" {
+			rest := e.key[len(opts.Prefix):]
+			if i := strings.Index(rest, opts.Delimiter); i >= 0 {
+				prefix = opts.Prefix + rest[:i+len(opts.Delimiter)]
+			}
+		}
+
+		if prefix != "" {
+			if prefix <= opts.StartAfter || prefix == result.Last {
+				continue
+			}
+		} else if e.key <= opts.StartAfter {
+			continue
+		}
+
+		if count == maxKeys {
+			result.Truncated = true
+			break
+		}
+		count++
+		if prefix != "" {
+			result.CommonPrefixes = append(result.CommonPrefixes, prefix)
+			result.Last = prefix
+		} else {
+			result.Objects = append(result.Objects, e.obj)
+			result.Last = e.key
+		}
+	}
+	return result, nil
+}
+
+// scan walks the directory of a prefix. With a "/" delimiter, directories
+// below the prefix are returned as common prefixes without descending.
+func (s *Store) scan(bucket, prefix, delimiter string) ([]listEntry, error) {
+	bucketDir := filepath.Join(s.root, bucket)
+	root := bucketDir
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir, err := s.objectPath(bucket, prefix[:i+1])
+		if err != nil {
+			return nil, nil
+		}
+		root = dir
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return nil, nil
+	}
+
+	var entries []listEntry
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == bucketDir {
+			return nil
+		}
+		rel, err := filepath.Rel(bucketDir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+
+		if !d.IsDir() {
+			if strings.HasPrefix(key, prefix) && d.Type().IsRegular() {
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				entries = append(entries, listEntry{key: key, obj: newObject(key, info.Size(), info.ModTime())})
+			}
+			return nil
+		}
+
+		key += "/"
+		if path != root {
+			if !strings.HasPrefix(key, prefix) && !strings.HasPrefix(prefix, key) {
+				return filepath.SkipDir
+			}
+			if delimiter == "/" && strings.HasPrefix(key, prefix) {
+				if i := strings.Index(key[len(prefix):], "/"); i >= 0 {
+					entries = append(entries, listEntry{key: key[:len(prefix)+i+1], prefix: true})
+					return filepath.SkipDir
+				}
+			}
+		}
+		if strings.HasPrefix(key, prefix) {
+			children, err := os.ReadDir(path)
+			if err != nil {
+				return err
+			}
+			if len(children) == 0 {
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				entries = append(entries, listEntry{key: key, obj: newObject(key, 0, info.ModTime())})
+			}
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// Upload is an in-progress multipart upload.
+type Upload struct {
+	ID        string    `json:"id"`
+	Bucket    string    `json:"bucket"`
+	Key       string    `json:"key"`
+	Initiated time.Time `json:"initiated"`
+}
+
+func (s *Store) uploadDir(id string) (string, error) {
+	if _, err := hex.DecodeString(id); err != nil || id == "" {
+		return "", ErrNoSuchUpload
+	}
+	return filepath.Join(s.root, ".uploads", id), nil
+}
+
+// CreateUpload starts a multipart upload and returns its ID.
+func (s *Store) CreateUpload(bucket, key string) (string, error) {
+	if _, err := s.objectPath(bucket, key); err != nil {
+		return "", err
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(b)
+	dir, _ := s.uploadDir(id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(Upload{ID: id, Bucket: bucket, Key: key, Initiated: time.Now().UTC()})
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "upload.json"), data, 0644); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// upload reads the upload record of an ID.
+func (s *Store) upload(id string) (*Upload, string, error) {
+	dir, err := s.uploadDir(id)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "upload.json"))
+	if err != nil {
+		return nil, "", ErrNoSuchUpload
+	}
+	var u Upload
+	if err := json.Unmarshal(data, &u); err != nil {
+		return nil, "", err
+	}
+	return &u, dir, nil
+}
+
+// PutPart stores part n (1-10000) of an upload and returns its quoted MD5.
+func (s *Store) PutPart(id string, n int, r io.Reader) (string, error) {
+	if n < 1 || n > 10000 {
+		return "", ErrInvalidPart
+	}
+	_, dir, err := s.upload(id)
+	if err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(dir, "part-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := md5.New()
+	_, err = io.Copy(tmp, io.TeeReader(r, h))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), partPath(dir, n)); err != nil {
+		return "", err
+	}
+	return quoteETag(h), nil
+}
+
+// CompleteUpload assembles the listed parts, in ascending order, into the
+// object. The ETag is the MD5 of the part MD5s with the part count, as S3
+// computes it.
+func (s *Store) CompleteUpload(id string, parts []int) (Object, error) {
+	u, dir, err := s.upload(id)
+	if err != nil {
+		return Object{}, err
+	}
+	if len(parts) == 0 {
+		return Object{}, ErrInvalidPart
+	}
+
+	var readers []io.Reader
+	var closers []io.Closer
+	defer func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}()
+	sums := md5.New()
+	for i, n := range parts {
+		if i > 0 && n <= parts[i-1] {
+			return Object{}, ErrInvalidPart
+		}
+		f, err := os.Open(partPath(dir, n))
+		if err != nil {
+			return Object{}, ErrInvalidPart
+		}
+		closers = append(closers, f)
+		readers = append(readers, &hashingReader{r: f, h: md5.New(), sums: sums})
+	}
+
+	obj, err := s.Put(u.Bucket, u.Key, io.MultiReader(readers...))
+	if err != nil {
+		return Object{}, err
+	}
+	obj.ETag = fmt.Sprintf(`"%x-%d"`, sums.Sum(nil), len(parts))
+	return obj, os.RemoveAll(dir)
+}
+
+// AbortUpload discards an upload and its parts.
+func (s *Store) AbortUpload(id string) error {
+	_, dir, err := s.upload(id)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// Uploads returns the in-progress uploads of a bucket under a key prefix.
+func (s *Store) Uploads(bucket, prefix string) ([]Upload, error) {
+	if err := s.checkBucket(bucket); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(s.root, ".uploads"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var uploads []Upload
+	for _, e := range entries {
+		u, _, err := s.upload(e.Name())
+		if err != nil || u.Bucket != bucket || !strings.HasPrefix(u.Key, prefix) {
+			continue
+		}
+		uploads = append(uploads, *u)
+	}
+	sort.Slice(uploads, func(i, j int) bool {
+		if uploads[i].Key != uploads[j].Key {
+			return uploads[i].Key < uploads[j].Key
+		}
+		return uploads[i].Initiated.Before(uploads[j].Initiated)
+	})
+	return uploads, nil
+}
+
+func partPath(dir string, n int) string {
+	return filepath.Join(dir, fmt.Sprintf("%05d", n))
+}
+
+func isMarker(key string) bool {
+	return strings.HasSuffix(key, "/")
+}
+
+func newObject(key string, size int64, modified time.Time) Object {
+	sum := md5.Sum([]byte(fmt.Sprintf("%s:%d:%d", key, size, modified.UnixNano())))
+	return Object{Key: key, Size: size, Modified: modified, ETag: fmt.Sprintf(`"%x-1"`, sum)}
+}
+
+func quoteETag(h hash.Hash) string {
+	return `"` + hex.EncodeToString(h.Sum(nil)) + `"`
+}
+
+// hashingReader adds the MD5 of a part to sums once it is fully read.
+type hashingReader struct {
+	r    io.Reader
+	h    hash.Hash
+	sums hash.Hash
+}
+
+func (r *hashingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.h.Write(p[:n])
+	if err == io.EOF {
+		r.sums.Write(r.h.Sum(nil))
+	}
+	return n, err
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,220 @@
+package objectstore
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func newTestStore(t *testing.T, bucket string) *Store {
+	t.Helper()
+	s := NewStore(t.TempDir())
+	if err := s.CreateBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func put(t *testing.T, s *Store, bucket, key, content string) Object {
+	t.Helper()
+	obj, err := s.Put(bucket, key, strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Put(%s): %v", key, err)
+	}
+	return obj
+}
+
+func read(t *testing.T, s *Store, bucket, key string) string {
+	t.Helper()
+	r, _, err := s.Open(bucket, key)
+	if err != nil {
+		t.Fatalf("Open(%s): %v", key, err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestValidateBucketName(t *testing.T) {
+	for _, name := range []string{"data", "my-bucket.2024", "abc"} {
+		if err := ValidateBucketName(name); err != nil {
+			t.Errorf("ValidateBucketName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "ab", "Data", ".tmp", "-x-", "a..b", "a_b"} {
+		if err := ValidateBucketName(name); err == nil {
+			t.Errorf("ValidateBucketName(%q) = nil, want error", name)
+		}
+	}
+}
+
+func TestStore_PutOpenDelete(t *testing.T) {
+	s := newTestStore(t, "data")
+
+	obj := put(t, s, "data", "raw/events/part-0.json", `{"id":1}`)
+	if want := fmt.Sprintf(`"%x"`, md5.Sum([]byte(`{"id":1}`))); obj.ETag != want {
+		t.Errorf("ETag = %s, want %s", obj.ETag, want)
+	}
+	if got := read(t, s, "data", "raw/events/part-0.json"); got != `{"id":1}` {
+		t.Errorf("content = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(s.Root(), "data", "raw", "events", "part-0.json")); err != nil {
+		t.Errorf("object not stored as a file: %v", err)
+	}
+
+	if _, err := s.Stat("data", "raw/events"); !errors.Is(err, ErrNoSuchKey) {
+		t.Errorf("Stat(prefix) = %v, want ErrNoSuchKey", err)
+	}
+	if _, err := s.Stat("missing", "x"); !errors.Is(err, ErrNoSuchBucket) {
+		t.Errorf("Stat(missing bucket) = %v, want ErrNoSuchBucket", err)
+	}
+	if _, err := s.Put("data", "a/../b", strings.NewReader("")); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Put(a/../b) = %v, want ErrInvalidKey", err)
+	}
+
+	if err := s.Delete("data", "raw/events/part-0.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(s.Root(), "data", "raw")); !os.IsNotExist(err) {
+		t.Error("empty parent directories should be removed")
+	}
+	if err := s.Delete("data", "raw/events/part-0.json"); err != nil {
+		t.Errorf("deleting a missing object: %v", err)
+	}
+	if err := s.DeleteBucket("data"); err != nil {
+		t.Errorf("DeleteBucket: %v", err)
+	}
+}
+
+func TestStore_DirectoryMarkers(t *testing.T) {
+	s := newTestStore(t, "data")
+
+	put(t, s, "data", "tables/orders/", "")
+	if _, err := s.Stat("data", "tables/orders/"); err != nil {
+		t.Fatalf("Stat(marker) = %v", err)
+	}
+
+	put(t, s, "data", "tables/orders/part-0", "x")
+	if _, err := s.Stat("data", "tables/orders/"); !errors.Is(err, ErrNoSuchKey) {
+		t.Errorf("directory with objects is not a marker: %v", err)
+	}
+
+	if err := s.Delete("data", "tables/orders/part-0"); err != nil {
+		t.Fatal(err)
+	}
+	res, err := s.List("data", ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Objects) != 0 {
+		t.Errorf("objects after delete = %+v", res.Objects)
+	}
+}
+
+func TestStore_List(t *testing.T) {
+	s := newTestStore(t, "data")
+	for _, key := range []string{"a.txt", "a/b/1", "a/b/2", "a/c", "b/", "z"} {
+		put(t, s, "data", key, "x")
+	}
+
+	keys := func(objs []Object) []string {
+		var out []string
+		for _, o := range objs {
+			out = append(out, o.Key)
+		}
+		return out
+	}
+
+	res, err := s.List("data", ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a.txt", "a/b/1", "a/b/2", "a/c", "b/", "z"}; !reflect.DeepEqual(keys(res.Objects), want) {
+		t.Errorf("keys = %v, want %v", keys(res.Objects), want)
+	}
+
+	res, err = s.List("data", ListOptions{Delimiter: "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a.txt", "z"}; !reflect.DeepEqual(keys(res.Objects), want) {
+		t.Errorf("keys = %v, want %v", keys(res.Objects), want)
+	}
+	if want := []string{"a/", "b/"}; !reflect.DeepEqual(res.CommonPrefixes, want) {
+		t.Errorf("prefixes = %v, want %v", res.CommonPrefixes, want)
+	}
+
+	res, err = s.List("data", ListOptions{Prefix: "a/", Delimiter: "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys(res.Objects), []string{"a/c"}) || !reflect.DeepEqual(res.CommonPrefixes, []string{"a/b/"}) {
+		t.Errorf("list a/ = %v %v", keys(res.Objects), res.CommonPrefixes)
+	}
+
+	// Paging continues after the last key or prefix
+	var all []string
+	opts := ListOptions{Delimiter: "/", MaxKeys: 2}
+	for {
+		res, err := s.List("data", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		all = append(all, keys(res.Objects)...)
+		all = append(all, res.CommonPrefixes...)
+		if !res.Truncated {
+			break
+		}
+		opts.StartAfter = res.Last
+	}
+	sort.Strings(all)
+	if want := []string{"a.txt", "a/", "b/", "z"}; !reflect.DeepEqual(all, want) {
+		t.Errorf("paged = %v, want %v", all, want)
+	}
+}
+
+func TestStore_MultipartUpload(t *testing.T) {
+	s := newTestStore(t, "data")
+
+	id, err := s.CreateUpload("data", "big/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.PutPart(id, 2, strings.NewReader("world")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.PutPart(id, 1, strings.NewReader("hello ")); err != nil {
+		t.Fatal(err)
+	}
+	uploads, err := s.Uploads("data", "big/")
+	if err != nil || len(uploads) != 1 || uploads[0].Key != "big/file" {
+		t.Fatalf("Uploads = %+v, %v", uploads, err)
+	}
+
+	if _, err := s.CompleteUpload(id, []int{2, 1}); !errors.Is(err, ErrInvalidPart) {
+		t.Errorf("out-of-order parts: %v", err)
+	}
+	obj, err := s.CompleteUpload(id, []int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(obj.ETag, `-2"`) {
+		t.Errorf("ETag = %s, want multipart form", obj.ETag)
+	}
+	if got := read(t, s, "data", "big/file"); got != "hello world" {
+		t.Errorf("content = %q", got)
+	}
+	if err := s.AbortUpload(id); !errors.Is(err, ErrNoSuchUpload) {
+		t.Errorf("upload should be gone: %v", err)
+	}
+}
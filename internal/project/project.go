@@ -0,0 +1,136 @@
+// Package project loads project-local configuration (.local-data.yaml)
+// discovered from the working directory.
+package project
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/config/generator"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the project configuration file looked up from the working directory.
+const FileName = ".local-data.yaml"
+
+// Project is a parsed .local-data.yaml file.
+//
+//	profile: hdfs                 # profile to use instead of the active one
+//	overrides:                    # same shape as a profile in overrides.yaml
+//	  spark:
+//	    spark.sql.shuffle.partitions: 8
+//	  hadoop:
+//	    core-site:
+//	      fs.trash.interval: 0
+//	databases: [raw, staging]     # created by 'local-data project sync'
+//	jars: [lib/udfs.jar]          # relative to the project directory
+type Project struct {
+	Path string `yaml:"-"` // absolute path of the project file
+
+	Profile   string                     `yaml:"profile,omitempty"`
+	Overrides *generator.ProfileOverride `yaml:"overrides,omitempty"`
+	Databases []string                   `yaml:"databases,omitempty"`
+	Jars      []string                   `yaml:"jars,omitempty"`
+}
+
+var databaseNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// Find walks up from dir to the filesystem root and returns the first
+// project file found, or "" if there is none.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		candidate := filepath.Join(dir, FileName)
+		if util.FileExists(candidate) {
+			return candidate, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Discover loads the project file for the current working directory.
+// It returns nil without error when no project file is found.
+func Discover() (*Project, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	path, err := Find(cwd)
+	if err != nil || path == "" {
+		return nil, err
+	}
+	return Load(path)
+}
+
+// Load parses and validates a project file. Jar paths are made absolute.
+func Load(path string) (*Project, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project file: %w", err)
+	}
+
+	p := &Project{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", abs, err)
+	}
+	p.Path = abs
+	p.Profile = strings.TrimSpace(p.Profile)
+
+	for _, db := range p.Databases {
+		if !databaseNamePattern.MatchString(db) {
+			return nil, fmt.Errorf("%s: invalid database name %q", abs, db)
+		}
+	}
+
+	for i, jar := range p.Jars {
+		if strings.HasPrefix(jar, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, err
+			}
+			jar = filepath.Join(home, jar[2:])
+		} else if !filepath.IsAbs(jar) {
+			jar = filepath.Join(p.Dir(), jar)
+		}
+		if !util.FileExists(jar) {
+			return nil, fmt.Errorf("%s: jar not found: %s", abs, jar)
+		}
+		p.Jars[i] = jar
+	}
+
+	return p, nil
+}
+
+// Dir returns the directory containing the project file.
+func (p *Project) Dir() string {
+	return filepath.Dir(p.Path)
+}
+
+// ID identifies the project in $BASE_DIR/conf/projects: the directory name
+// plus a short hash of its path, so equally named checkouts do not collide.
+func (p *Project) ID() string {
+	sum := sha1.Sum([]byte(p.Dir()))
+	return filepath.Base(p.Dir()) + "-" + hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,120 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestFind_WalksUpToProjectFile(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, FileName), "profile: local\n")
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	got, err := Find(nested)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if got != filepath.Join(root, FileName) {
+		t.Fatalf("Find = %q", got)
+	}
+}
+
+func TestFind_NoProjectFile(t *testing.T) {
+	got, err := Find(t.TempDir())
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if got != "" {
+		t.Fatalf("Find = %q, want none", got)
+	}
+}
+
+func TestLoad_ParsesAndResolvesJars(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "lib", "udfs.jar"), "")
+	writeFile(t, filepath.Join(root, FileName), `profile: hdfs
+overrides:
+  spark:
+    spark.sql.shuffle.partitions: 8
+  hadoop:
+    core-site:
+      fs.trash.interval: 0
+databases: [raw, staging]
+jars: [lib/udfs.jar]
+`)
+
+	p, err := Load(filepath.Join(root, FileName))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if p.Profile != "hdfs" {
+		t.Fatalf("Profile = %q", p.Profile)
+	}
+	if got := p.Overrides.Spark["spark.sql.shuffle.partitions"]; got != 8 {
+		t.Fatalf("spark override = %v", got)
+	}
+	if got := p.Overrides.Hadoop.CoreSite["fs.trash.interval"]; got != 0 {
+		t.Fatalf("core-site override = %v", got)
+	}
+	if len(p.Databases) != 2 || p.Databases[1] != "staging" {
+		t.Fatalf("Databases = %v", p.Databases)
+	}
+	if len(p.Jars) != 1 || p.Jars[0] != filepath.Join(root, "lib", "udfs.jar") {
+		t.Fatalf("Jars = %v", p.Jars)
+	}
+	if !strings.HasPrefix(p.ID(), filepath.Base(root)+"-") {
+		t.Fatalf("ID = %q", p.ID())
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown field", "profle: hdfs\n", "profle"},
+		{"invalid database", "databases: [\"my-db\"]\n", "invalid database name"},
+		{"missing jar", "jars: [missing.jar]\n", "jar not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			path := filepath.Join(root, FileName)
+			writeFile(t, path, tt.content)
+
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Load error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_EmptyFile(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, FileName), "")
+
+	p, err := Load(filepath.Join(root, FileName))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if p.Profile != "" || p.Overrides != nil {
+		t.Fatalf("unexpected project: %+v", p)
+	}
+}
@@ -0,0 +1,65 @@
+package secret
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// ProviderPathProperty is the Hadoop property listing credential providers.
+// Hive and Spark consult it before reading cleartext passwords from config.
+const ProviderPathProperty = "hadoop.security.credential.provider.path"
+
+// ProviderURI returns the jceks:// provider URI for a local keystore file.
+func ProviderURI(path string) string {
+	return "jceks://file" + filepath.ToSlash(path)
+}
+
+// runHadoopCredential runs `hadoop credential <args>`; replaced in tests.
+var runHadoopCredential = func(args ...string) ([]byte, error) {
+	return exec.Command("hadoop", append([]string{"credential"}, args...)...).CombinedOutput()
+}
+
+// StoreCredential writes alias=value into the JCEKS keystore at path,
+// replacing an existing entry, and restricts the keystore to the owner.
+// The keystore password is Hadoop's default unless HADOOP_CREDSTORE_PASSWORD
+// is set in the environment of both this process and Hive.
+func StoreCredential(path, alias, value string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create credential store directory: %w", err)
+	}
+	provider := ProviderURI(path)
+
+	if _, err := os.Stat(path); err == nil {
+		// create refuses to overwrite an alias; delete fails harmlessly if it is missing
+		runHadoopCredential("delete", alias, "-f", "-provider", provider)
+	}
+
+	// Without -value, create prompts through System.console(), which is
+	// null unless stdin is a terminal, so the value is passed as an argument
+	out, err := runHadoopCredential("create", alias, "-value", value, "-provider", provider)
+	if err != nil {
+		return fmt.Errorf("hadoop credential create failed: %w\nOutput: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("failed to restrict credential store permissions: %w", err)
+	}
+	// Hadoop writes a .crc checksum next to the keystore
+	crc := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".crc")
+	if util.FileExists(crc) {
+		os.Chmod(crc, 0600)
+	}
+	return nil
+}
+
+// NewPassword returns a random password for services local-data provisions
+// itself, such as the managed metastore database.
+func NewPassword() string {
+	return rand.Text()
+}
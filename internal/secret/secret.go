@@ -0,0 +1,55 @@
+// Package secret resolves secret references used in settings and stores
+// secrets in Hadoop credential providers (JCEKS keystores).
+package secret
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Reference prefixes accepted by Resolve.
+const (
+	EnvPrefix  = "env:"
+	FilePrefix = "file:"
+)
+
+// IsRef reports whether value is an env: or file: reference rather than a
+// literal secret.
+func IsRef(value string) bool {
+	return strings.HasPrefix(value, EnvPrefix) || strings.HasPrefix(value, FilePrefix)
+}
+
+// Resolve returns the secret a value refers to:
+//
+//	env:NAME    value of environment variable NAME (must be set)
+//	file:/path  contents of /path without the trailing newline
+//	other       the value itself
+func Resolve(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, EnvPrefix):
+		name := strings.TrimPrefix(value, EnvPrefix)
+		if name == "" {
+			return "", fmt.Errorf("invalid secret reference %q: missing variable name", value)
+		}
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("secret reference %q: environment variable %s is not set", value, name)
+		}
+		return v, nil
+
+	case strings.HasPrefix(value, FilePrefix):
+		path := strings.TrimPrefix(value, FilePrefix)
+		if path == "" {
+			return "", fmt.Errorf("invalid secret reference %q: missing file path", value)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("secret reference %q: %w", value, err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,85 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	t.Setenv("LD_TEST_SECRET", "from-env")
+	file := filepath.Join(t.TempDir(), "pw")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"literal":            "literal",
+		"env:LD_TEST_SECRET": "from-env",
+		"file:" + file:       "from-file",
+	}
+	for value, want := range tests {
+		got, err := Resolve(value)
+		if err != nil {
+			t.Fatalf("Resolve(%q) error: %v", value, err)
+		}
+		if got != want {
+			t.Errorf("Resolve(%q) = %q, want %q", value, got, want)
+		}
+	}
+
+	for _, bad := range []string{"env:LD_TEST_SECRET_UNSET", "env:", "file:", "file:" + file + ".missing"} {
+		if _, err := Resolve(bad); err == nil {
+			t.Errorf("Resolve(%q) should fail", bad)
+		}
+	}
+}
+
+func TestStoreCredential(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secrets", "metastore.jceks")
+
+	// A fake hadoop on PATH records the argv of each command it runs
+	log := filepath.Join(dir, "hadoop.log")
+	fake := "#!/bin/sh\necho \"$*\" >> " + log + "\n: > " + path + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "hadoop"), []byte(fake), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if err := StoreCredential(path, "alias", "s3cret"); err != nil {
+		t.Fatalf("StoreCredential() error: %v", err)
+	}
+	if err := StoreCredential(path, "alias", "s3cret2"); err != nil {
+		t.Fatalf("StoreCredential() error: %v", err)
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := "jceks://file" + filepath.ToSlash(path)
+	want := []string{
+		"credential create alias -value s3cret -provider " + provider,
+		"credential delete alias -f -provider " + provider,
+		"credential create alias -value s3cret2 -provider " + provider,
+	}
+	if got := strings.TrimSpace(string(data)); got != strings.Join(want, "\n") {
+		t.Fatalf("commands =\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("keystore mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestNewPassword(t *testing.T) {
+	if a, b := NewPassword(), NewPassword(); len(a) < 16 || a == b {
+		t.Errorf("NewPassword() = %q, %q; want distinct random passwords", a, b)
+	}
+}
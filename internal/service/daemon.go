@@ -0,0 +1,132 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// Daemon runs a single background server that listens on a local TCP port,
+// such as the s3 and notebook services
+type Daemon struct {
+	Name    string          // Service name, used in messages and status
+	Process string          // PID/log file name
+	ProcMgr *ProcessManager // Owns the PID and log files
+	Timeout time.Duration   // Bounds how long Start and Stop wait
+}
+
+// LogPath returns the server log file
+func (d *Daemon) LogPath() string {
+	return filepath.Join(d.ProcMgr.LogDir, d.Process+".log")
+}
+
+// Start runs cmd in the background and waits until it listens on port.
+// The port must be free beforehand, so another server already listening
+// there is not mistaken for this one; if the server exits or does not
+// listen in time it is stopped and an error returned.
+func (d *Daemon) Start(cmd *exec.Cmd, port int) (int, error) {
+	if err := CheckPortFree(port); err != nil {
+		return 0, fmt.Errorf("cannot start %s: %w", d.Name, err)
+	}
+
+	pid, err := d.ProcMgr.Start(d.Process, cmd, d.Process+".log")
+	if err != nil {
+		return 0, fmt.Errorf("failed to start %s: %w", d.Name, err)
+	}
+	// Reap the server if it exits, so IsRunning does not see a zombie
+	go cmd.Wait()
+
+	if err := d.waitReady(port); err != nil {
+		_ = d.Stop()
+		return 0, err
+	}
+	return pid, nil
+}
+
+// Stop stops the server and waits for it to exit
+func (d *Daemon) Stop() error {
+	pid, err := d.ProcMgr.Status(d.Process)
+	if err != nil || pid == 0 {
+		return nil
+	}
+
+	if err := d.ProcMgr.Stop(d.Process); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(d.Timeout)
+	for time.Now().Before(deadline) && syscall.Kill(pid, 0) == nil {
+		time.Sleep(200 * time.Millisecond)
+	}
+	if syscall.Kill(pid, 0) == nil {
+		return fmt.Errorf("%s (pid %d) did not stop within %s", d.Name, pid, d.Timeout)
+	}
+	util.Success("Stopped %s (pid %d).", d.Name, pid)
+	return nil
+}
+
+// Status returns the status of the server
+func (d *Daemon) Status() ([]ServiceStatus, error) {
+	status := ServiceStatus{Name: d.Name}
+	pid, err := d.ProcMgr.Status(d.Process)
+	if err != nil {
+		return nil, err
+	}
+	if pid > 0 {
+		status.Running = true
+		status.PID = pid
+	}
+	return []ServiceStatus{status}, nil
+}
+
+// Logs displays the server log
+func (d *Daemon) Logs() error {
+	logFile := d.LogPath()
+	fmt.Printf("==> %s\n", logFile)
+	if _, err := os.Stat(logFile); err == nil {
+		cmd := exec.Command("tail", "-n", "120", logFile)
+		cmd.Stdout = os.Stdout
+		_ = cmd.Run()
+	} else {
+		fmt.Println("(missing)")
+	}
+	fmt.Println()
+	return nil
+}
+
+// waitReady waits for the started server to listen on port, failing as
+// soon as it exits
+func (d *Daemon) waitReady(port int) error {
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	deadline := time.Now().Add(d.Timeout)
+	for {
+		if !d.ProcMgr.IsRunning(d.Process) {
+			return fmt.Errorf("%s exited during startup (check logs: %s)", d.Name, d.LogPath())
+		}
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s did not listen on %s within %s (check logs: %s): %w", d.Name, addr, d.Timeout, d.LogPath(), err)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// CheckPortFree fails if something already listens on port
+func CheckPortFree(port int) error {
+	ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("port %d is already in use: %w", port, err)
+	}
+	return ln.Close()
+}
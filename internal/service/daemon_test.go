@@ -0,0 +1,69 @@
+package service
+
+import (
+	"net"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestDaemon(t *testing.T) *Daemon {
+	tmpDir := t.TempDir()
+	return &Daemon{
+		Name:    "test",
+		Process: "test-server",
+		ProcMgr: NewProcessManager(filepath.Join(tmpDir, "pids"), filepath.Join(tmpDir, "logs")),
+		Timeout: 5 * time.Second,
+	}
+}
+
+func TestDaemon_Start_PortInUse(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	d := newTestDaemon(t)
+	if _, err := d.Start(exec.Command("sleep", "5"), port); err == nil || !strings.Contains(err.Error(), "already in use") {
+		t.Fatalf("Start() on a port in use error = %v", err)
+	}
+	if d.ProcMgr.IsRunning(d.Process) {
+		t.Error("nothing should be started when the port is in use")
+	}
+}
+
+func TestDaemon_Start_Exits(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	// Outlives ProcessManager's startup check, then exits without listening
+	d := newTestDaemon(t)
+	if _, err := d.Start(exec.Command("sh", "-c", "sleep 1.5; exit 1"), port); err == nil || !strings.Contains(err.Error(), "exited during startup") {
+		t.Fatalf("Start() of an exiting server error = %v", err)
+	}
+	if pid, _ := d.ProcMgr.Status(d.Process); pid != 0 {
+		t.Errorf("pid file left behind for pid %d", pid)
+	}
+}
+
+func TestDaemon_Status_NotRunning(t *testing.T) {
+	d := newTestDaemon(t)
+	statuses, err := d.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 1 || statuses[0].Name != "test" || statuses[0].Running {
+		t.Errorf("Status() = %+v", statuses)
+	}
+	if err := d.Stop(); err != nil {
+		t.Errorf("Stop() with nothing running error = %v", err)
+	}
+}
@@ -212,16 +212,17 @@ func (h *HiveService) Status() ([]service.ServiceStatus, error) {
 
 // ListenerStatuses returns the listener status for Hive ports
 func (h *HiveService) ListenerStatuses() []ListenerStatus {
+	hs2Port := h.getHS2Port()
 	if _, err := exec.LookPath("lsof"); err != nil {
 		return []ListenerStatus{
 			{Label: "metastore", Port: 9083},
-			{Label: "hiveserver2", Port: 10000},
+			{Label: "hiveserver2", Port: hs2Port},
 		}
 	}
 
 	return []ListenerStatus{
 		h.checkListener(9083, "metastore"),
-		h.checkListener(10000, "hiveserver2"),
+		h.checkListener(hs2Port, "hiveserver2"),
 	}
 }
 
@@ -2,28 +2,16 @@ package hive
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
-	"net/url"
-	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
 
 	"github.com/danieljhkim/local-data-platform/internal/metastore"
 	"github.com/danieljhkim/local-data-platform/internal/util"
 )
 
-var dbIdentPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
-
-type mysqlConnInfo struct {
-	host     string
-	port     string
-	user     string
-	password string
-	dbName   string
-}
-
 // BootstrapMetastore prepares metastore dependencies and initializes schema.
 func (h *HiveService) BootstrapMetastore(in io.Reader, out, errOut io.Writer) error {
 	dbType, dbURL, err := h.detectMetastoreConfig()
@@ -75,20 +63,40 @@ func (h *HiveService) ensureJDBCDriver(dbType metastore.DBType) error {
 	switch dbType {
 	case metastore.Postgres:
 		util.Log("Postgres metastore detected, ensuring JDBC driver is available...")
-		_, err := EnsurePostgresJDBCDriver(h.env.HiveHome, h.env.SparkHome, h.paths.BaseDir)
+		jar, err := EnsurePostgresJDBCDriver(h.env.HiveHome, h.env.SparkHome, h.paths)
 		if err != nil {
 			return fmt.Errorf("failed to ensure Postgres JDBC driver: %w", err)
 		}
+		h.addAuxJar(jar)
 	case metastore.MySQL:
 		util.Log("MySQL metastore detected, ensuring JDBC driver is available...")
-		_, err := EnsureMySQLJDBCDriver(h.env.HiveHome, h.env.SparkHome, h.paths.BaseDir)
+		jar, err := EnsureMySQLJDBCDriver(h.env.HiveHome, h.env.SparkHome, h.paths)
 		if err != nil {
 			return fmt.Errorf("failed to ensure MySQL JDBC driver: %w", err)
 		}
+		h.addAuxJar(jar)
 	}
 	return nil
 }
 
+// addAuxJar adds a jar outside $HIVE_HOME/lib to HIVE_AUX_JARS_PATH of the
+// commands Hive runs next.
+func (h *HiveService) addAuxJar(jar string) {
+	if filepath.Dir(jar) == filepath.Join(h.env.HiveHome, "lib") {
+		return
+	}
+	var jars []string
+	if h.env.HiveAuxJarsPath != "" {
+		jars = strings.Split(h.env.HiveAuxJarsPath, ",")
+	}
+	for _, j := range jars {
+		if j == jar {
+			return
+		}
+	}
+	h.env.HiveAuxJarsPath = strings.Join(append(jars, jar), ",")
+}
+
 func (h *HiveService) ensureDatabaseExists(dbType metastore.DBType, dbURL string, in io.Reader, out, errOut io.Writer) error {
 	switch dbType {
 	case metastore.Derby:
@@ -131,163 +139,57 @@ func confirmYesNo(in io.Reader, out io.Writer, prompt string) (bool, error) {
 	}
 }
 
-func (h *HiveService) databaseExists(dbType metastore.DBType, dbURL string) (bool, error) {
-	switch dbType {
-	case metastore.Postgres:
-		adminURL, dbName, err := parsePostgresURL(dbURL)
-		if err != nil {
-			return false, err
-		}
-		if !dbIdentPattern.MatchString(dbName) {
-			return false, fmt.Errorf("unsupported postgres database name %q", dbName)
-		}
-		sql := fmt.Sprintf("SELECT 1 FROM pg_database WHERE datname='%s';", escapeSQLLiteral(dbName))
-		cmd := exec.Command("psql", adminURL, "-tAc", sql)
-		cmd.Env = h.env.Export()
-		out, err := cmd.CombinedOutput()
-		if err != nil {
-			return false, fmt.Errorf("postgres database existence check failed: %v\nOutput: %s", err, strings.TrimSpace(string(out)))
-		}
-		return strings.TrimSpace(string(out)) == "1", nil
-	case metastore.MySQL:
-		info, err := parseMySQLURL(dbURL)
-		if err != nil {
-			return false, err
-		}
-		if !dbIdentPattern.MatchString(info.dbName) {
-			return false, fmt.Errorf("unsupported mysql database name %q", info.dbName)
-		}
-		args := mysqlBaseArgs(info)
-		query := fmt.Sprintf("SELECT SCHEMA_NAME FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME='%s';", escapeSQLLiteral(info.dbName))
-		args = append(args, "-e", query)
-		cmd := exec.Command("mysql", args...)
-		cmd.Env = mysqlCmdEnv(h.env.Export(), info)
-		out, err := cmd.CombinedOutput()
-		if err != nil {
-			return false, fmt.Errorf("mysql database existence check failed: %v\nOutput: %s", err, strings.TrimSpace(string(out)))
-		}
-		return strings.TrimSpace(string(out)) == info.dbName, nil
-	default:
-		return true, nil
-	}
-}
-
-func (h *HiveService) createDatabase(dbType metastore.DBType, dbURL string) error {
-	switch dbType {
-	case metastore.Postgres:
-		adminURL, dbName, err := parsePostgresURL(dbURL)
-		if err != nil {
-			return err
-		}
-		if !dbIdentPattern.MatchString(dbName) {
-			return fmt.Errorf("unsupported postgres database name %q", dbName)
-		}
-		cmd := exec.Command("psql", adminURL, "-c", fmt.Sprintf("CREATE DATABASE %s;", dbName))
-		cmd.Env = h.env.Export()
-		out, err := cmd.CombinedOutput()
-		if err != nil {
-			return fmt.Errorf("failed to create postgres database %q: %v\nOutput: %s", dbName, err, strings.TrimSpace(string(out)))
-		}
-		util.Log("Created Postgres metastore database %q", dbName)
-		return nil
-	case metastore.MySQL:
-		info, err := parseMySQLURL(dbURL)
-		if err != nil {
-			return err
-		}
-		if !dbIdentPattern.MatchString(info.dbName) {
-			return fmt.Errorf("unsupported mysql database name %q", info.dbName)
-		}
-		args := mysqlBaseArgs(info)
-		args = append(args, "-e", fmt.Sprintf("CREATE DATABASE `%s`;", info.dbName))
-		cmd := exec.Command("mysql", args...)
-		cmd.Env = mysqlCmdEnv(h.env.Export(), info)
-		out, err := cmd.CombinedOutput()
-		if err != nil {
-			return fmt.Errorf("failed to create mysql database %q: %v\nOutput: %s", info.dbName, err, strings.TrimSpace(string(out)))
-		}
-		util.Log("Created MySQL metastore database %q", info.dbName)
-		return nil
-	default:
-		return nil
+// connInfo returns connection parameters for a Postgres/MySQL metastore,
+// using hive-site.xml credentials when the URL does not carry any.
+func (h *HiveService) connInfo(dbType metastore.DBType, dbURL string) (*metastore.ConnInfo, error) {
+	info, err := metastore.ParseConnInfo(dbType, dbURL)
+	if err != nil {
+		return nil, err
 	}
+	return info.WithCredentials(h.metastoreCredentials()), nil
 }
 
-func parsePostgresURL(dbURL string) (string, string, error) {
-	raw := strings.TrimSpace(dbURL)
-	if !strings.HasPrefix(strings.ToLower(raw), "jdbc:postgresql://") {
-		return "", "", fmt.Errorf("invalid postgres db-url %q", dbURL)
+func (h *HiveService) databaseExists(dbType metastore.DBType, dbURL string) (bool, error) {
+	if dbType != metastore.Postgres && dbType != metastore.MySQL {
+		return true, nil
 	}
-	u, err := url.Parse(strings.TrimPrefix(raw, "jdbc:"))
+	info, err := h.connInfo(dbType, dbURL)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to parse postgres db-url: %w", err)
-	}
-
-	dbName := strings.TrimPrefix(u.Path, "/")
-	if dbName == "" {
-		return "", "", fmt.Errorf("postgres db-url missing database name: %q", dbURL)
+		return false, err
 	}
-	admin := *u
-	admin.Path = "/postgres"
-	admin.RawQuery = ""
-	return admin.String(), dbName, nil
+	return metastore.DatabaseExists(context.Background(), info)
 }
 
-func parseMySQLURL(dbURL string) (*mysqlConnInfo, error) {
-	raw := strings.TrimSpace(dbURL)
-	if !strings.HasPrefix(strings.ToLower(raw), "jdbc:mysql://") {
-		return nil, fmt.Errorf("invalid mysql db-url %q", dbURL)
+func (h *HiveService) createDatabase(dbType metastore.DBType, dbURL string) error {
+	if dbType != metastore.Postgres && dbType != metastore.MySQL {
+		return nil
 	}
-	u, err := url.Parse(strings.TrimPrefix(raw, "jdbc:"))
+	info, err := h.connInfo(dbType, dbURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse mysql db-url: %w", err)
+		return err
 	}
-	dbName := strings.TrimPrefix(u.Path, "/")
-	if dbName == "" {
-		return nil, fmt.Errorf("mysql db-url missing database name: %q", dbURL)
+	if err := metastore.CreateDatabase(context.Background(), info); err != nil {
+		return fmt.Errorf("failed to create %s database %q: %w", dbType, info.Database, err)
 	}
-
-	password, _ := u.User.Password()
-	return &mysqlConnInfo{
-		host:     defaultString(u.Hostname(), "localhost"),
-		port:     defaultString(u.Port(), "3306"),
-		user:     u.User.Username(),
-		password: password,
-		dbName:   dbName,
-	}, nil
+	util.Log("Created %s metastore database %q", dbType, info.Database)
+	return nil
 }
 
-func mysqlBaseArgs(info *mysqlConnInfo) []string {
-	args := []string{
-		"--batch",
-		"--skip-column-names",
-		"--host", info.host,
-		"--port", info.port,
-	}
-	if info.user != "" {
-		args = append(args, "--user", info.user)
+// CheckMetastoreConnection verifies a Postgres/MySQL metastore is reachable,
+// accepts the configured credentials, exists and has the Hive schema.
+// Errors match metastore.ErrUnreachable, ErrAuthFailed, ErrDatabaseMissing
+// or ErrSchemaMissing with errors.Is. Derby metastores always return nil.
+func (h *HiveService) CheckMetastoreConnection(ctx context.Context) (metastore.DBType, error) {
+	dbType, dbURL, err := h.detectMetastoreConfig()
+	if err != nil {
+		return dbType, err
 	}
-	// Password is passed via MYSQL_PWD env var in mysqlCmdEnv() to avoid
-	// exposing it in process listings (ps aux).
-	return args
-}
-
-// mysqlCmdEnv returns environment variables for MySQL commands,
-// including MYSQL_PWD if a password is set.
-func mysqlCmdEnv(baseEnv []string, info *mysqlConnInfo) []string {
-	if info.password != "" {
-		return append(baseEnv, "MYSQL_PWD="+info.password)
+	if dbType != metastore.Postgres && dbType != metastore.MySQL {
+		return dbType, nil
 	}
-	return baseEnv
-}
-
-func escapeSQLLiteral(s string) string {
-	return strings.ReplaceAll(s, "'", "''")
-}
-
-func defaultString(value, fallback string) string {
-	if strings.TrimSpace(value) == "" {
-		return fallback
+	info, err := h.connInfo(dbType, dbURL)
+	if err != nil {
+		return dbType, err
 	}
-	return value
+	return dbType, metastore.Check(ctx, info)
 }
@@ -7,13 +7,15 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/danieljhkim/local-data-platform/internal/util"
+	"github.com/danieljhkim/local-data-platform/internal/config"
 )
 
 const DefaultMySQLJDBCVersion = "8.4.0"
 
-// EnsureMySQLJDBCDriver ensures a MySQL JDBC driver is available.
-func EnsureMySQLJDBCDriver(hiveHome, sparkHome, baseDir string) (string, error) {
+// EnsureMySQLJDBCDriver ensures a MySQL JDBC driver is available, registering
+// it in the jar repository for Hive or Spark when their install directories
+// lack one.
+func EnsureMySQLJDBCDriver(hiveHome, sparkHome string, paths *config.Paths) (string, error) {
 	version := DefaultMySQLJDBCVersion
 
 	if hiveHome == "" {
@@ -36,11 +38,10 @@ func EnsureMySQLJDBCDriver(hiveHome, sparkHome, baseDir string) (string, error)
 		}
 	}
 
-	var fallbackDir string
-	if strings.TrimSpace(baseDir) != "" {
-		fallbackDir = filepath.Join(baseDir, "lib", "jars")
+	repoDir := paths.JarsDir()
+	for _, dir := range []string{repoDir, filepath.Join(paths.BaseDir, "lib", "jars")} {
 		if foundJar == "" {
-			if jar, err := findMySQLJar(fallbackDir); err == nil {
+			if jar, err := findMySQLJar(dir); err == nil {
 				foundJar = jar
 			}
 		}
@@ -52,23 +53,16 @@ func EnsureMySQLJDBCDriver(hiveHome, sparkHome, baseDir string) (string, error)
 			version, version,
 		)
 		return "", fmt.Errorf(
-			"MySQL JDBC driver not found (expected mysql-connector-j-*.jar or mysql-connector-java-*.jar in %s%s%s). Download: %s",
+			"MySQL JDBC driver not found (expected mysql-connector-j-*.jar or mysql-connector-java-*.jar in %s%s%s). Download: %s and run: local-data jars add mysql-connector-j-%s.jar --target hive,spark",
 			primaryDir,
 			optionalDir(sparkJarsDir),
-			optionalDir(fallbackDir),
+			optionalDir(repoDir),
 			downloadURL,
+			version,
 		)
 	}
 
-	if sparkJarsDir != "" {
-		if _, err := findMySQLJar(sparkJarsDir); err != nil {
-			if copyErr := ensureJarInSparkDir(foundJar, sparkJarsDir); copyErr != nil {
-				util.Warn("Could not copy MySQL JDBC driver to %s: %v", sparkJarsDir, copyErr)
-			}
-		}
-	}
-
-	return foundJar, nil
+	return registerDriverJar(paths, foundJar, primaryDir, sparkJarsDir, findMySQLJar)
 }
 
 func findMySQLJar(dir string) (string, error) {
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/danieljhkim/local-data-platform/internal/config"
 	"github.com/danieljhkim/local-data-platform/internal/util"
 )
 
@@ -17,8 +18,10 @@ const (
 
 // EnsurePostgresJDBCDriver ensures the Postgres JDBC driver is available
 // Returns the path to the JAR file and any error
-// Also ensures the JAR is present in SPARK_HOME/jars if sparkHome is provided
-func EnsurePostgresJDBCDriver(hiveHome, sparkHome, baseDir string) (string, error) {
+// A driver missing from $HIVE_HOME/lib or $SPARK_HOME/jars is registered in
+// the jar repository ($BASE_DIR/jars) for Hive or Spark instead of being
+// copied into the install directories
+func EnsurePostgresJDBCDriver(hiveHome, sparkHome string, paths *config.Paths) (string, error) {
 	// NOTE: we intentionally do NOT enforce a specific version. Any compatible
 	// postgresql JDBC driver jar is fine.
 	version := DefaultPostgresJDBCVersion
@@ -46,18 +49,15 @@ func EnsurePostgresJDBCDriver(hiveHome, sparkHome, baseDir string) (string, erro
 		}
 	}
 
-	// Fallback location: user-controlled directory (HIVE_AUX_JARS_PATH). Only use
-	// this if the caller provided baseDir; do not guess a random path.
-	var fallbackDir, fallbackJarExample string
-	if strings.TrimSpace(baseDir) != "" {
-		fallbackDir = filepath.Join(baseDir, "lib", "jars")
+	// Then the jar repository and the legacy $BASE_DIR/lib/jars directory
+	repoDir := paths.JarsDir()
+	legacyDir := filepath.Join(paths.BaseDir, "lib", "jars")
+	for _, dir := range []string{repoDir, legacyDir} {
 		if foundJar == "" {
-			if jar, err := findPostgresJar(fallbackDir); err == nil {
-				util.Log("Using Postgres JDBC driver from HIVE_AUX_JARS_PATH: %s", jar)
+			if jar, err := findPostgresJar(dir); err == nil {
 				foundJar = jar
 			}
 		}
-		fallbackJarExample = filepath.Join(fallbackDir, "postgresql-<version>.jar")
 	}
 
 	// If we didn't find the JAR anywhere, return an error
@@ -77,58 +77,63 @@ func EnsurePostgresJDBCDriver(hiveHome, sparkHome, baseDir string) (string, erro
 		if sparkJarsDir != "" {
 			msg += "  2) " + sparkJarsDir + "\n"
 		}
-		if fallbackDir != "" {
-			msg += "  3) " + fallbackDir + "\n"
-		}
-		msg += "\n" +
+		msg += "  3) " + repoDir + "\n" +
+			"\n" +
 			"Please download the PostgreSQL JDBC driver (any recent version is fine):\n" +
 			"  " + downloadURL + "\n\n" +
-			"Then place the JAR in ONE of the locations above.\n"
-		if fallbackDir != "" {
-			msg += "\nIf using location (3), ensure HIVE_AUX_JARS_PATH includes the JAR path, e.g.:\n" +
-				"  " + fallbackJarExample + "\n"
-		} else {
-			msg += "\nTip: if you want to use a custom jars directory, pass baseDir from the CLI/config and set HIVE_AUX_JARS_PATH accordingly.\n"
-		}
+			"Then register it for Hive and Spark:\n" +
+			"  local-data jars add postgresql-" + version + ".jar --target hive,spark\n"
 
 		return "", fmt.Errorf("%s", msg)
 	}
 
-	// Ensure the JAR is also present in SPARK_HOME/jars
+	return registerDriverJar(paths, foundJar, primaryDir, sparkJarsDir, findPostgresJar)
+}
+
+// registerDriverJar registers a JDBC driver in the jar repository for the
+// tools whose install directories (hiveLibDir, sparkJarsDir) lack one, and
+// returns the path Hive should use: its own copy when hiveLibDir has one, so
+// the driver is not on its classpath twice. Failing to register only warns.
+func registerDriverJar(paths *config.Paths, jar, hiveLibDir, sparkJarsDir string, find func(string) (string, error)) (string, error) {
+	var targets []string
+	hiveJar, err := find(hiveLibDir)
+	if err != nil {
+		hiveJar = ""
+		targets = append(targets, config.JarTargetHive)
+	}
 	if sparkJarsDir != "" {
-		if _, err := findPostgresJar(sparkJarsDir); err != nil {
-			// JAR not in Spark jars dir, try to copy it
-			if err := ensureJarInSparkDir(foundJar, sparkJarsDir); err != nil {
-				util.Warn("Could not copy Postgres JDBC driver to %s: %v", sparkJarsDir, err)
-			}
+		if _, err := find(sparkJarsDir); err != nil {
+			targets = append(targets, config.JarTargetSpark)
 		}
 	}
-
-	return foundJar, nil
-}
-
-// ensureJarInSparkDir copies a JAR to SPARK_HOME/jars if possible
-func ensureJarInSparkDir(srcJar, sparkJarsDir string) error {
-	// Check if spark jars dir exists
-	if _, err := os.Stat(sparkJarsDir); os.IsNotExist(err) {
-		return fmt.Errorf("spark jars directory does not exist: %s", sparkJarsDir)
+	if len(targets) == 0 {
+		return jar, nil
 	}
 
-	jarName := filepath.Base(srcJar)
-	dstJar := filepath.Join(sparkJarsDir, jarName)
-
-	// Check if already exists
-	if _, err := os.Stat(dstJar); err == nil {
-		return nil // Already exists
+	repo := config.NewJarRepository(paths)
+	name := filepath.Base(jar)
+	hivePath := repo.Path(name)
+	if hiveJar != "" {
+		hivePath = hiveJar
 	}
-
-	// Try to copy
-	if err := util.CopyFile(srcJar, dstJar); err != nil {
-		return fmt.Errorf("failed to copy %s to %s: %w", srcJar, dstJar, err)
+	if existing, ok := repo.Find(name); ok {
+		missing := false
+		for _, t := range targets {
+			missing = missing || !existing.HasTarget(t)
+		}
+		if !missing {
+			return hivePath, nil
+		}
+		targets = append(targets, existing.Targets...)
 	}
 
-	util.Log("Copied Postgres JDBC driver to %s", dstJar)
-	return nil
+	registered, err := repo.Add(jar, targets)
+	if err != nil {
+		util.Warn("Could not register %s in %s: %v", name, paths.JarsDir(), err)
+		return jar, nil
+	}
+	util.Log("Registered %s in %s for %s", name, paths.JarsDir(), strings.Join(registered.Targets, ", "))
+	return hivePath, nil
 }
 
 // findPostgresJar returns the path to a PostgreSQL JDBC driver jar in dir.
@@ -0,0 +1,67 @@
+package hive
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+)
+
+func TestEnsurePostgresJDBCDriver_RegistersInsteadOfCopying(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := config.NewPaths(filepath.Join(tmpDir, "repo"), filepath.Join(tmpDir, "base"))
+	hiveHome := filepath.Join(tmpDir, "hive")
+	sparkHome := filepath.Join(tmpDir, "spark")
+	for _, dir := range []string{filepath.Join(hiveHome, "lib"), filepath.Join(sparkHome, "jars")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hiveJar := filepath.Join(hiveHome, "lib", "postgresql-42.7.4.jar")
+	if err := os.WriteFile(hiveJar, []byte("PK"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	jar, err := EnsurePostgresJDBCDriver(hiveHome, sparkHome, paths)
+	if err != nil {
+		t.Fatalf("EnsurePostgresJDBCDriver: %v", err)
+	}
+
+	// Hive keeps its own copy; the repository one is only for Spark
+	if jar != hiveJar {
+		t.Errorf("jar = %s, want %s", jar, hiveJar)
+	}
+	repo := config.NewJarRepository(paths)
+	if entries, _ := os.ReadDir(filepath.Join(sparkHome, "jars")); len(entries) != 0 {
+		t.Errorf("SPARK_HOME/jars was modified: %v", entries)
+	}
+	jars, err := repo.List()
+	if err != nil || len(jars) != 1 || !reflect.DeepEqual(jars[0].Targets, []string{config.JarTargetSpark}) {
+		t.Errorf("registered jars = %+v, %v", jars, err)
+	}
+
+	// Without a copy in $HIVE_HOME/lib Hive uses the repository one
+	if err := os.Remove(hiveJar); err != nil {
+		t.Fatal(err)
+	}
+	jar, err = EnsurePostgresJDBCDriver(hiveHome, sparkHome, paths)
+	if err != nil || jar != repo.Path("postgresql-42.7.4.jar") {
+		t.Errorf("EnsurePostgresJDBCDriver = %s, %v; want the repository copy", jar, err)
+	}
+	if jars, _ := repo.List(); len(jars) != 1 || !jars[0].HasTarget(config.JarTargetHive) {
+		t.Errorf("registered jars = %+v, want a hive target", jars)
+	}
+	if err := os.WriteFile(hiveJar, []byte("PK"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once Spark has its own copy nothing is registered
+	if err := os.WriteFile(filepath.Join(sparkHome, "jars", "postgresql-42.7.4.jar"), []byte("PK"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if jar, err := EnsurePostgresJDBCDriver(hiveHome, sparkHome, config.NewPaths("", filepath.Join(tmpDir, "other"))); err != nil || jar != hiveJar {
+		t.Errorf("EnsurePostgresJDBCDriver = %s, %v; want %s", jar, err, hiveJar)
+	}
+}
@@ -2,8 +2,11 @@ package hive
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
+	"regexp"
 	"strings"
 
 	"github.com/danieljhkim/local-data-platform/internal/metastore"
@@ -17,52 +20,135 @@ const (
 	SchemaUnknown SchemaStatus = iota
 	SchemaNotInitialized
 	SchemaInitialized
+	SchemaVersionMismatch
 )
 
-// checkMetastoreSchema checks if the Hive metastore schema is initialized
-// Returns SchemaInitialized if schema exists, SchemaNotInitialized if not, SchemaUnknown on error
-func (h *HiveService) checkMetastoreSchema(dbType metastore.DBType) (SchemaStatus, error) {
-	cmd := exec.Command("schematool", "-dbType", string(dbType), "-info")
-	cmd.Env = h.env.Export()
+var (
+	hiveDistVersionPattern    = regexp.MustCompile(`(?m)^\s*Hive distribution version:\s*(\S+)`)
+	schemaVersionPattern      = regexp.MustCompile(`(?m)^\s*Metastore schema version:\s*(\S+)`)
+	incompatibleSchemaPattern = regexp.MustCompile(`Hive Version:\s*([^,\s]+),\s*Database Schema Version:\s*(\S+)`)
+)
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+// SchemaInfo holds the versions reported by `schematool -info`.
+// HiveVersion is the schema version the installed Hive distribution expects.
+type SchemaInfo struct {
+	HiveVersion   string
+	SchemaVersion string
+	Incompatible  bool // schematool reported the versions as not compatible
+}
 
-	err := cmd.Run()
-	output := stdout.String() + stderr.String()
+// Mismatch reports whether the metastore schema needs an upgrade (or downgrade).
+func (s SchemaInfo) Mismatch() bool {
+	if s.Incompatible {
+		return true
+	}
+	return s.HiveVersion != "" && s.SchemaVersion != "" && s.HiveVersion != s.SchemaVersion
+}
+
+// parseSchemaInfo extracts versions from `schematool -info` output.
+func parseSchemaInfo(output string) SchemaInfo {
+	var info SchemaInfo
+	if m := hiveDistVersionPattern.FindStringSubmatch(output); m != nil {
+		info.HiveVersion = m[1]
+	}
+	if m := schemaVersionPattern.FindStringSubmatch(output); m != nil {
+		info.SchemaVersion = m[1]
+	}
+	if m := incompatibleSchemaPattern.FindStringSubmatch(output); m != nil {
+		info.Incompatible = true
+		if info.HiveVersion == "" {
+			info.HiveVersion = m[1]
+		}
+		if info.SchemaVersion == "" {
+			info.SchemaVersion = strings.TrimRight(m[2], ".")
+		}
+	}
+	if strings.Contains(output, "schema version is not compatible") {
+		info.Incompatible = true
+	}
+	return info
+}
+
+// classifySchemaInfo maps `schematool -info` output and exit error to a SchemaStatus.
+func classifySchemaInfo(output string, runErr error) (SchemaStatus, SchemaInfo, error) {
+	info := parseSchemaInfo(output)
+	if info.Mismatch() {
+		return SchemaVersionMismatch, info, nil
+	}
 
 	// schematool -info returns non-zero if schema is not initialized
-	if err != nil {
+	if runErr != nil {
 		// Check for common "schema not found" or "relation does not exist" messages
 		if strings.Contains(output, "does not exist") ||
 			strings.Contains(output, "relation") ||
 			strings.Contains(output, "Table") ||
 			strings.Contains(output, "not exist") ||
 			strings.Contains(output, "Schema initialization") {
-			return SchemaNotInitialized, nil
+			return SchemaNotInitialized, info, nil
 		}
 
 		// Connection errors or other issues
 		if strings.Contains(output, "Connection refused") ||
 			strings.Contains(output, "FATAL") ||
 			strings.Contains(output, "password authentication failed") {
-			return SchemaUnknown, fmt.Errorf("database connection error: %s", strings.TrimSpace(output))
+			return SchemaUnknown, info, fmt.Errorf("database connection error: %s", strings.TrimSpace(output))
 		}
 
 		// Other unknown error
-		return SchemaUnknown, fmt.Errorf("schematool -info failed: %v\nOutput: %s", err, strings.TrimSpace(output))
+		return SchemaUnknown, info, fmt.Errorf("schematool -info failed: %v\nOutput: %s", runErr, strings.TrimSpace(output))
+	}
+
+	// If command succeeded, the schema is initialized
+	return SchemaInitialized, info, nil
+}
+
+// checkMetastoreSchema runs `schematool -info` and classifies the result
+// Returns SchemaUnknown with an error if the state could not be determined
+func (h *HiveService) checkMetastoreSchema(dbType metastore.DBType) (SchemaStatus, SchemaInfo, error) {
+	// For network databases, check connectivity natively first so failures
+	// surface as typed errors instead of schematool output.
+	if dbType == metastore.Postgres || dbType == metastore.MySQL {
+		_, err := h.CheckMetastoreConnection(context.Background())
+		switch {
+		case errors.Is(err, metastore.ErrSchemaMissing):
+			return SchemaNotInitialized, SchemaInfo{}, nil
+		case err != nil:
+			return SchemaUnknown, SchemaInfo{}, err
+		}
+	}
+
+	cmd := exec.Command("schematool", "-dbType", string(dbType), "-info")
+	cmd.Env = h.env.Export()
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return classifySchemaInfo(stdout.String()+stderr.String(), err)
+}
+
+// ErrDerbyInUse is returned when the embedded Derby metastore is locked by a running Hive.
+var ErrDerbyInUse = errors.New("embedded Derby is in use by Hive")
+
+// MetastoreSchema reports the metastore schema status and versions for the
+// metastore configured in the active overlay.
+func (h *HiveService) MetastoreSchema() (SchemaStatus, SchemaInfo, error) {
+	dbType, _, err := h.detectMetastoreConfig()
+	if err != nil {
+		return SchemaUnknown, SchemaInfo{}, err
 	}
 
-	// Success - schema is initialized
-	// Look for "Hive distribution version" or similar success indicators
-	if strings.Contains(output, "Hive distribution version") ||
-		strings.Contains(output, "Metastore schema version") {
-		return SchemaInitialized, nil
+	// Embedded Derby only allows one JVM; schematool cannot open it while Hive runs.
+	if dbType == metastore.Derby {
+		for _, svc := range []string{"metastore", "hiveserver2"} {
+			if pid, _ := h.procMgr.Status(svc); pid > 0 {
+				return SchemaUnknown, SchemaInfo{}, ErrDerbyInUse
+			}
+		}
 	}
 
-	// If command succeeded but output is unexpected, assume initialized
-	return SchemaInitialized, nil
+	return h.checkMetastoreSchema(dbType)
 }
 
 // initMetastoreSchema initializes the Hive metastore schema
@@ -106,7 +192,7 @@ func (h *HiveService) ensureMetastoreSchema() error {
 func (h *HiveService) ensureMetastoreSchemaForType(dbType metastore.DBType, strict bool) error {
 	util.Log("Checking Hive metastore schema...")
 
-	status, err := h.checkMetastoreSchema(dbType)
+	status, info, err := h.checkMetastoreSchema(dbType)
 	if err != nil {
 		if strict {
 			return err
@@ -121,6 +207,11 @@ func (h *HiveService) ensureMetastoreSchemaForType(dbType metastore.DBType, stri
 		util.Log("Metastore schema is initialized")
 		return nil
 
+	case SchemaVersionMismatch:
+		// Starting the metastore would fail with an obscure error; fail early instead.
+		return fmt.Errorf("metastore schema version %s does not match Hive %s (run: local-data metastore upgrade)",
+			orUnknown(info.SchemaVersion), orUnknown(info.HiveVersion))
+
 	case SchemaNotInitialized:
 		util.Log("Metastore schema not found, initializing...")
 		if err := h.initMetastoreSchema(dbType); err != nil {
@@ -149,3 +240,10 @@ func (h *HiveService) ensureMetastoreSchemaStrict(dbType metastore.DBType) error
 func (h *HiveService) isPostgresMetastore() bool {
 	return h.usesPostgresMetastore
 }
+
+func orUnknown(v string) string {
+	if v == "" {
+		return "unknown"
+	}
+	return v
+}
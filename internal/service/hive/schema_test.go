@@ -1,6 +1,7 @@
 package hive
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -188,3 +189,77 @@ func TestHiveService_EnsureMetastoreSchema_PostgresNoSchematool(t *testing.T) {
 // - schematool in PATH
 // - Hive installed
 // These should be done in integration tests.
+
+func TestClassifySchemaInfo(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		runErr     error
+		wantStatus SchemaStatus
+		wantInfo   SchemaInfo
+		wantErr    bool
+	}{
+		{
+			name: "initialized and matching",
+			output: "Metastore connection URL:\t jdbc:derby:;databaseName=/tmp/metastore_db;create=true\n" +
+				"Hive distribution version:\t 4.0.1\n" +
+				"Metastore schema version:\t 4.0.1\n" +
+				"schemaTool completed\n",
+			wantStatus: SchemaInitialized,
+			wantInfo:   SchemaInfo{HiveVersion: "4.0.1", SchemaVersion: "4.0.1"},
+		},
+		{
+			name: "mismatch reported by schematool",
+			output: "Hive distribution version:\t 4.0.1\n" +
+				"Metastore schema version:\t 3.1.0\n" +
+				"org.apache.hadoop.hive.metastore.HiveMetaException: Metastore schema version is not compatible. " +
+				"Hive Version: 4.0.1, Database Schema Version: 3.1.0\n" +
+				"*** schemaTool failed ***\n",
+			runErr:     errors.New("exit status 1"),
+			wantStatus: SchemaVersionMismatch,
+			wantInfo:   SchemaInfo{HiveVersion: "4.0.1", SchemaVersion: "3.1.0", Incompatible: true},
+		},
+		{
+			name:       "mismatch from exception only",
+			output:     "HiveMetaException: Metastore schema version is not compatible. Hive Version: 4.0.1, Database Schema Version: 3.1.0\n",
+			runErr:     errors.New("exit status 1"),
+			wantStatus: SchemaVersionMismatch,
+			wantInfo:   SchemaInfo{HiveVersion: "4.0.1", SchemaVersion: "3.1.0", Incompatible: true},
+		},
+		{
+			name:       "versions differ without error",
+			output:     "Hive distribution version:\t 4.0.1\nMetastore schema version:\t 4.0.0\n",
+			wantStatus: SchemaVersionMismatch,
+			wantInfo:   SchemaInfo{HiveVersion: "4.0.1", SchemaVersion: "4.0.0"},
+		},
+		{
+			name:       "not initialized",
+			output:     "Hive distribution version:\t 4.0.1\nERROR: relation \"VERSION\" does not exist\n",
+			runErr:     errors.New("exit status 1"),
+			wantStatus: SchemaNotInitialized,
+			wantInfo:   SchemaInfo{HiveVersion: "4.0.1"},
+		},
+		{
+			name:       "connection refused",
+			output:     "org.postgresql.util.PSQLException: Connection refused\n",
+			runErr:     errors.New("exit status 1"),
+			wantStatus: SchemaUnknown,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, info, err := classifySchemaInfo(tt.output, tt.runErr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("status = %v, want %v", status, tt.wantStatus)
+			}
+			if info != tt.wantInfo {
+				t.Errorf("info = %+v, want %+v", info, tt.wantInfo)
+			}
+		})
+	}
+}
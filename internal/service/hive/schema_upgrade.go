@@ -0,0 +1,185 @@
+package hive
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/metastore"
+	"github.com/danieljhkim/local-data-platform/internal/secret"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// UpgradeResult describes a completed metastore schema upgrade.
+type UpgradeResult struct {
+	DBType     metastore.DBType
+	From       string
+	To         string
+	BackupPath string
+}
+
+// UpgradeMetastoreSchema backs up the metastore database into backupDir and
+// runs `schematool -upgradeSchema`. Hive must be stopped. Returns a nil result
+// if the schema is already up to date.
+func (h *HiveService) UpgradeMetastoreSchema(backupDir string) (*UpgradeResult, error) {
+	for _, svc := range []string{"metastore", "hiveserver2"} {
+		if pid, _ := h.procMgr.Status(svc); pid > 0 {
+			return nil, fmt.Errorf("hive %s is running (pid %d); stop it first: local-data stop hive", svc, pid)
+		}
+	}
+
+	dbType, dbURL, err := h.detectMetastoreConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := h.ensureJDBCDriver(dbType); err != nil {
+		return nil, err
+	}
+
+	util.Log("Checking Hive metastore schema...")
+	status, info, err := h.checkMetastoreSchema(dbType)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case SchemaNotInitialized:
+		return nil, fmt.Errorf("metastore schema is not initialized (run: local-data init)")
+	case SchemaInitialized:
+		util.Log("Metastore schema version %s matches Hive %s; nothing to upgrade",
+			orUnknown(info.SchemaVersion), orUnknown(info.HiveVersion))
+		return nil, nil
+	}
+
+	result := &UpgradeResult{DBType: dbType, From: info.SchemaVersion, To: info.HiveVersion}
+
+	util.Log("Backing up %s metastore...", dbType)
+	result.BackupPath, err = h.backupMetastore(dbType, dbURL, backupDir)
+	if err != nil {
+		return nil, fmt.Errorf("metastore backup failed, schema not upgraded: %w", err)
+	}
+	util.Success("Metastore backed up to %s", result.BackupPath)
+
+	util.Log("Upgrading metastore schema %s -> %s...", orUnknown(info.SchemaVersion), orUnknown(info.HiveVersion))
+	cmd := exec.Command("schematool", "-dbType", string(dbType), "-upgradeSchema")
+	cmd.Env = h.env.Export()
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return result, fmt.Errorf("schematool -upgradeSchema failed: %v\nOutput: %s\nBackup: %s",
+			err, strings.TrimSpace(output.String()), result.BackupPath)
+	}
+
+	status, info, err = h.checkMetastoreSchema(dbType)
+	if err != nil {
+		return result, fmt.Errorf("upgrade finished but schema check failed: %w", err)
+	}
+	if status == SchemaVersionMismatch {
+		return result, fmt.Errorf("metastore schema is still at %s after upgrade (Hive expects %s); backup: %s",
+			orUnknown(info.SchemaVersion), orUnknown(info.HiveVersion), result.BackupPath)
+	}
+	result.To = info.SchemaVersion
+	return result, nil
+}
+
+// backupMetastore copies the Derby database directory or dumps Postgres/MySQL
+// with pg_dump/mysqldump. Returns the backup path.
+func (h *HiveService) backupMetastore(dbType metastore.DBType, dbURL, backupDir string) (string, error) {
+	if err := util.MkdirAll(backupDir); err != nil {
+		return "", err
+	}
+	stamp := time.Now().Format("20060102-150405")
+
+	switch dbType {
+	case metastore.Derby:
+		dbPath := extractDerbyDBPath(dbURL)
+		if dbPath == "" || !filepath.IsAbs(dbPath) {
+			return "", fmt.Errorf("cannot locate Derby database from URL %q", dbURL)
+		}
+		if !util.DirExists(dbPath) {
+			return "", fmt.Errorf("derby database not found at %s", dbPath)
+		}
+		dst := filepath.Join(backupDir, "metastore_db-"+stamp)
+		if err := util.CopyDir(dbPath, dst); err != nil {
+			return "", err
+		}
+		return dst, nil
+
+	case metastore.Postgres:
+		conn, err := h.connInfo(dbType, dbURL)
+		if err != nil {
+			return "", err
+		}
+		dst := filepath.Join(backupDir, fmt.Sprintf("metastore-%s-%s.dump", conn.Database, stamp))
+		args := []string{"--format=custom", "--file", dst, "--host", conn.Host, "--port", conn.Port}
+		if conn.User != "" {
+			args = append(args, "--username", conn.User)
+		}
+		args = append(args, conn.Database)
+		env := h.env.Export()
+		if conn.Password != "" {
+			env = append(env, "PGPASSWORD="+conn.Password)
+		}
+		return dst, runDump("pg_dump", args, env, dst)
+
+	case metastore.MySQL:
+		conn, err := h.connInfo(dbType, dbURL)
+		if err != nil {
+			return "", err
+		}
+		dst := filepath.Join(backupDir, fmt.Sprintf("metastore-%s-%s.sql", conn.Database, stamp))
+		args := []string{"--single-transaction", "--routines", "--result-file", dst, "--host", conn.Host, "--port", conn.Port}
+		if conn.User != "" {
+			args = append(args, "--user", conn.User)
+		}
+		args = append(args, conn.Database)
+		// Password is passed via MYSQL_PWD to keep it out of process listings.
+		env := h.env.Export()
+		if conn.Password != "" {
+			env = append(env, "MYSQL_PWD="+conn.Password)
+		}
+		return dst, runDump("mysqldump", args, env, dst)
+
+	default:
+		return "", fmt.Errorf("unsupported metastore db-type %q", dbType)
+	}
+}
+
+func runDump(tool string, args, env []string, dst string) error {
+	if _, err := exec.LookPath(tool); err != nil {
+		return fmt.Errorf("%s not found in PATH (needed to back up the metastore)", tool)
+	}
+	cmd := exec.Command(tool, args...)
+	cmd.Env = env
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		os.Remove(dst)
+		return fmt.Errorf("%s failed: %v\nOutput: %s", tool, err, strings.TrimSpace(string(out)))
+	}
+	// Dumps contain credentials-adjacent metadata; keep them private.
+	return os.Chmod(dst, 0600)
+}
+
+// metastoreCredentials returns the JDO connection user and password from
+// hive-site.xml. When the password lives in a credential store instead, it is
+// resolved from settings.
+func (h *HiveService) metastoreCredentials() (string, string) {
+	cfg, err := util.ParseHadoopXML(filepath.Join(h.env.HiveConfDir, "hive-site.xml"))
+	if err != nil {
+		return "", ""
+	}
+	user := strings.TrimSpace(cfg.GetProperty("javax.jdo.option.ConnectionUserName"))
+	password := cfg.GetProperty("javax.jdo.option.ConnectionPassword")
+
+	if password == "" && cfg.GetProperty(secret.ProviderPathProperty) != "" {
+		if settings, err := config.NewSettingsManager(h.paths).LoadOrDefault(); err == nil {
+			password, _ = settings.ResolveDBPassword()
+		}
+	}
+	return user, password
+}
@@ -0,0 +1,284 @@
+package metastoredb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/metastore"
+	"github.com/danieljhkim/local-data-platform/internal/service"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// processName is the PID/log file name of the managed server
+const processName = "postgres"
+
+// startTimeout bounds how long Start waits for the server to accept connections
+const startTimeout = 30 * time.Second
+
+// ErrNotManaged is returned by Start when the metastore DB is not managed by local-data
+var ErrNotManaged = errors.New("metastore-db is not managed by local-data (enable with: local-data setting set db-type postgres --managed)")
+
+// MetastoreDBService manages a local Postgres server backing the Hive metastore
+// State lives under $BASE_DIR/state/postgres: data/ (cluster), tmp/ (unix socket),
+// pids/ and logs/
+type MetastoreDBService struct {
+	paths     *config.Paths
+	settings  *config.Settings
+	procMgr   *service.ProcessManager
+	dataDir   string
+	socketDir string
+}
+
+// NewMetastoreDBService creates a new managed metastore DB service
+func NewMetastoreDBService(paths *config.Paths) (*MetastoreDBService, error) {
+	settings, err := config.NewSettingsManager(paths).LoadOrDefault()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	stateDir := filepath.Join(paths.StateDir(), "postgres")
+	pidDir := filepath.Join(stateDir, "pids")
+	logDir := filepath.Join(stateDir, "logs")
+
+	return &MetastoreDBService{
+		paths:     paths,
+		settings:  settings,
+		procMgr:   service.NewProcessManager(pidDir, logDir),
+		dataDir:   filepath.Join(stateDir, "data"),
+		socketDir: filepath.Join(stateDir, "tmp"),
+	}, nil
+}
+
+// Enabled reports whether settings ask for a managed metastore DB
+func (m *MetastoreDBService) Enabled() bool {
+	return m.settings.DBManaged
+}
+
+// IsRunning reports whether the managed server is running
+func (m *MetastoreDBService) IsRunning() bool {
+	return m.procMgr.IsRunning(processName)
+}
+
+// Port returns the port the managed server listens on
+func (m *MetastoreDBService) Port() int {
+	if m.settings.DBManagedPort > 0 {
+		return m.settings.DBManagedPort
+	}
+	return metastore.DefaultManagedPort
+}
+
+// Start initializes the data directory if needed, starts Postgres and ensures
+// the metastore role and database exist
+func (m *MetastoreDBService) Start() error {
+	if !m.Enabled() {
+		return ErrNotManaged
+	}
+
+	if pid, err := m.procMgr.Status(processName); err == nil && pid > 0 {
+		util.Log("metastore-db already running (pid %d).", pid)
+		return m.provision()
+	}
+
+	if err := util.MkdirAll(m.socketDir); err != nil {
+		return fmt.Errorf("failed to create metastore-db directories: %w", err)
+	}
+	if err := m.initDataDir(); err != nil {
+		return err
+	}
+
+	postgres, err := findBinary("postgres")
+	if err != nil {
+		return err
+	}
+
+	port := m.Port()
+	util.Log("Starting metastore-db (postgres on port %d)...", port)
+
+	// -h localhost: TCP for Hive (password auth); -k: unix socket for provisioning (trust auth)
+	cmd := exec.Command(postgres,
+		"-D", m.dataDir,
+		"-p", strconv.Itoa(port),
+		"-h", "localhost",
+		"-k", m.socketDir,
+	)
+	startedPid, err := m.procMgr.Start(processName, cmd, processName+".log")
+	if err != nil {
+		return fmt.Errorf("failed to start metastore-db: %w", err)
+	}
+
+	if err := m.waitReady(); err != nil {
+		return err
+	}
+	util.Success("metastore-db started (pid %d, port %d).", startedPid, port)
+
+	return m.provision()
+}
+
+// Stop performs a fast shutdown of the managed server
+func (m *MetastoreDBService) Stop() error {
+	pid, err := m.procMgr.Status(processName)
+	if err != nil || pid == 0 {
+		return nil
+	}
+
+	// SIGTERM waits for clients (e.g. the Hive metastore) to disconnect;
+	// SIGINT rolls back open transactions and shuts down immediately.
+	if err := syscall.Kill(pid, syscall.SIGINT); err != nil && err != syscall.ESRCH {
+		return fmt.Errorf("failed to stop metastore-db: %w", err)
+	}
+
+	deadline := time.Now().Add(startTimeout)
+	for time.Now().Before(deadline) && syscall.Kill(pid, 0) == nil {
+		time.Sleep(200 * time.Millisecond)
+	}
+	if syscall.Kill(pid, 0) == nil {
+		return fmt.Errorf("metastore-db (pid %d) did not stop within %s", pid, startTimeout)
+	}
+
+	if err := m.procMgr.Stop(processName); err != nil {
+		return err
+	}
+	util.Success("Stopped metastore-db (pid %d).", pid)
+	return nil
+}
+
+// Status returns the status of the managed server
+func (m *MetastoreDBService) Status() ([]service.ServiceStatus, error) {
+	status := service.ServiceStatus{Name: "metastore-db"}
+	pid, err := m.procMgr.Status(processName)
+	if err != nil {
+		return nil, err
+	}
+	if pid > 0 {
+		status.Running = true
+		status.PID = pid
+	}
+	return []service.ServiceStatus{status}, nil
+}
+
+// Logs displays the managed server log
+func (m *MetastoreDBService) Logs() error {
+	logFile := filepath.Join(m.procMgr.LogDir, processName+".log")
+	fmt.Printf("==> %s\n", logFile)
+	if _, err := os.Stat(logFile); err == nil {
+		cmd := exec.Command("tail", "-n", "120", logFile)
+		cmd.Stdout = os.Stdout
+		_ = cmd.Run()
+	} else {
+		fmt.Println("(missing)")
+	}
+	fmt.Println()
+	return nil
+}
+
+// initDataDir runs initdb once. Only the bootstrap superuser exists afterwards;
+// it can log in over the unix socket only.
+func (m *MetastoreDBService) initDataDir() error {
+	if util.FileExists(filepath.Join(m.dataDir, "PG_VERSION")) {
+		return nil
+	}
+
+	initdb, err := findBinary("initdb")
+	if err != nil {
+		return err
+	}
+
+	util.Log("Initializing metastore-db data directory: %s", m.dataDir)
+	if err := os.MkdirAll(m.dataDir, 0700); err != nil {
+		return fmt.Errorf("failed to create metastore-db data directory: %w", err)
+	}
+
+	cmd := exec.Command(initdb,
+		"-D", m.dataDir,
+		"-U", metastore.ManagedSuperuser,
+		"-E", "UTF8",
+		"--no-locale",
+		"--auth-local=trust",
+		"--auth-host=scram-sha-256",
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("initdb failed: %w\nOutput: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (m *MetastoreDBService) waitReady() error {
+	ctx := context.Background()
+	deadline := time.Now().Add(startTimeout)
+	for {
+		err := metastore.PingManaged(ctx, m.socketDir, m.Port())
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			logPath := filepath.Join(m.procMgr.LogDir, processName+".log")
+			return fmt.Errorf("metastore-db did not accept connections within %s (check logs: %s): %w", startTimeout, logPath, err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// provision creates the metastore role and database named in settings
+func (m *MetastoreDBService) provision() error {
+	info, err := metastore.ParseConnInfo(metastore.Postgres, m.settings.DBURL)
+	if err != nil {
+		return err
+	}
+	role := metastore.ConnectionUser(metastore.Postgres, m.settings.User)
+	password, err := m.settings.ResolveDBPassword()
+	if err != nil {
+		return err
+	}
+
+	if err := metastore.ProvisionManaged(context.Background(), m.socketDir, m.Port(), role, password, info.Database); err != nil {
+		return fmt.Errorf("failed to provision metastore-db: %w", err)
+	}
+	return nil
+}
+
+// findBinary looks up a Postgres server binary on PATH, then in common
+// keg-only/versioned install locations (Homebrew postgresql@N, Debian)
+func findBinary(name string) (string, error) {
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+
+	var candidates []string
+	for _, pattern := range []string{
+		"/opt/homebrew/opt/postgresql@*/bin/" + name,
+		"/usr/local/opt/postgresql@*/bin/" + name,
+		"/usr/lib/postgresql/*/bin/" + name,
+	} {
+		matches, _ := filepath.Glob(pattern)
+		candidates = append(candidates, matches...)
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%s not found (install Postgres, e.g.: brew install postgresql@16)", name)
+	}
+
+	// Prefer the newest major version
+	sort.Slice(candidates, func(i, j int) bool {
+		return pgMajor(candidates[i]) > pgMajor(candidates[j])
+	})
+	return candidates[0], nil
+}
+
+// pgMajor extracts N from .../postgresql@N/bin/x or .../postgresql/N/bin/x
+func pgMajor(path string) int {
+	dir := filepath.Base(filepath.Dir(filepath.Dir(path)))
+	if i := strings.LastIndexByte(dir, '@'); i >= 0 {
+		dir = dir[i+1:]
+	}
+	n, _ := strconv.Atoi(dir)
+	return n
+}
@@ -0,0 +1,40 @@
+package metastoredb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+)
+
+func TestStart_NotManaged(t *testing.T) {
+	paths := config.NewPaths("", t.TempDir())
+	svc, err := NewMetastoreDBService(paths)
+	if err != nil {
+		t.Fatalf("NewMetastoreDBService() error = %v", err)
+	}
+
+	if svc.Enabled() {
+		t.Fatalf("default settings should not enable metastore-db")
+	}
+	if err := svc.Start(); !errors.Is(err, ErrNotManaged) {
+		t.Fatalf("Start() error = %v, want ErrNotManaged", err)
+	}
+	if err := svc.Stop(); err != nil {
+		t.Fatalf("Stop() with nothing running error = %v", err)
+	}
+}
+
+func TestPgMajor(t *testing.T) {
+	tests := map[string]int{
+		"/opt/homebrew/opt/postgresql@16/bin/initdb": 16,
+		"/usr/local/opt/postgresql@9/bin/postgres":   9,
+		"/usr/lib/postgresql/15/bin/initdb":          15,
+		"/usr/bin/initdb":                            0,
+	}
+	for path, want := range tests {
+		if got := pgMajor(path); got != want {
+			t.Errorf("pgMajor(%q) = %d, want %d", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package notebook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/env"
+	"github.com/danieljhkim/local-data-platform/internal/service"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// processName is the PID/log file name of the server
+const processName = "jupyter"
+
+// DefaultPort is the port Jupyter listens on unless Port is set
+const DefaultPort = 8888
+
+// KernelName is the name of the generated PySpark kernel spec
+const KernelName = "local-data-pyspark"
+
+// startTimeout bounds how long Start waits for Jupyter to listen
+const startTimeout = 60 * time.Second
+
+// serverState records how the running server was started, for status
+type serverState struct {
+	Port        int    `json:"port"`
+	Token       string `json:"token"`
+	NotebookDir string `json:"notebook-dir"`
+}
+
+// NotebookService runs JupyterLab in the background with the computed
+// environment and a PySpark kernel spec. State lives under
+// $BASE_DIR/state/notebook: data/ (the kernel spec under jupyter/kernels
+// and server.json), pids/ and logs/
+type NotebookService struct {
+	paths   *config.Paths
+	daemon  *service.Daemon
+	dataDir string
+
+	// Port and NotebookDir are used by Start
+	Port        int
+	NotebookDir string
+}
+
+// NewNotebookService creates a new notebook service
+func NewNotebookService(paths *config.Paths) (*NotebookService, error) {
+	sp := paths.NotebookPaths()
+	return &NotebookService{
+		paths: paths,
+		daemon: &service.Daemon{
+			Name:    "notebook",
+			Process: processName,
+			ProcMgr: service.NewProcessManager(sp.PidsDir, sp.LogsDir),
+			Timeout: startTimeout,
+		},
+		dataDir:     sp.DataDir,
+		Port:        DefaultPort,
+		NotebookDir: paths.NotebooksDir(),
+	}, nil
+}
+
+// IsRunning reports whether Jupyter is running
+func (n *NotebookService) IsRunning() bool {
+	return n.daemon.ProcMgr.IsRunning(processName)
+}
+
+// JupyterPath returns the directory added to JUPYTER_PATH, which holds the
+// generated kernel spec in kernels/<KernelName>
+func (n *NotebookService) JupyterPath() string {
+	return filepath.Join(n.dataDir, "jupyter")
+}
+
+// URL returns the URL of the running server, including its token
+func (n *NotebookService) URL() (string, error) {
+	state, err := n.readState()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("http://127.0.0.1:%d/lab?token=%s", state.Port, state.Token), nil
+}
+
+// Start computes the environment, writes the kernel spec and runs
+// 'jupyter lab' from the active profile's virtualenv or PATH
+func (n *NotebookService) Start() error {
+	if pid, err := n.daemon.ProcMgr.Status(processName); err == nil && pid > 0 {
+		util.Log("notebook already running (pid %d).", pid)
+		return nil
+	}
+
+	environment, err := env.Compute(n.paths)
+	if err != nil {
+		return fmt.Errorf("failed to compute environment: %w", err)
+	}
+	if environment.SparkHome == "" {
+		return fmt.Errorf("SPARK_HOME not found; the PySpark kernel needs Spark (see: local-data env doctor)")
+	}
+
+	jupyter, err := n.findJupyter(environment.ActiveProfile)
+	if err != nil {
+		return err
+	}
+	if err := n.writeKernelSpec(environment); err != nil {
+		return err
+	}
+	if err := util.MkdirAll(n.NotebookDir); err != nil {
+		return fmt.Errorf("failed to create notebook directory: %w", err)
+	}
+
+	state := serverState{Port: n.Port, NotebookDir: n.NotebookDir}
+	if state.Token, err = newToken(); err != nil {
+		return err
+	}
+
+	util.Log("Starting notebook (port %d, notebooks %s)...", state.Port, state.NotebookDir)
+
+	cmd := exec.Command(jupyter, "lab",
+		"--no-browser",
+		"--ip=127.0.0.1",
+		"--port="+strconv.Itoa(state.Port),
+		"--port-retries=0",
+		"--notebook-dir="+state.NotebookDir,
+	)
+	cmd.Dir = state.NotebookDir
+	cmd.Env = append(environment.MergeWithCurrent(),
+		"JUPYTER_PATH="+joinPathList(n.JupyterPath(), os.Getenv("JUPYTER_PATH")),
+		"JUPYTER_TOKEN="+state.Token,
+	)
+	// server.json is written first so the URL is known once it listens
+	if err := n.writeState(state); err != nil {
+		return err
+	}
+	// With --port-retries=0 Jupyter exits rather than picking another port
+	// when the port is taken, which the daemon checks beforehand
+	startedPid, err := n.daemon.Start(cmd, state.Port)
+	if err != nil {
+		_ = os.Remove(n.statePath())
+		return err
+	}
+	url, err := n.URL()
+	if err != nil {
+		return err
+	}
+	util.Success("notebook started (pid %d): %s", startedPid, url)
+	return nil
+}
+
+// Stop stops Jupyter, which shuts down its kernels, and waits for it to exit
+func (n *NotebookService) Stop() error {
+	return n.daemon.Stop()
+}
+
+// Status returns the status of the server
+func (n *NotebookService) Status() ([]service.ServiceStatus, error) {
+	return n.daemon.Status()
+}
+
+// Logs displays the server log
+func (n *NotebookService) Logs() error {
+	return n.daemon.Logs()
+}
+
+// findJupyter prefers jupyter from the profile's virtualenv
+// ('local-data python init') over the one on PATH
+func (n *NotebookService) findJupyter(profile string) (string, error) {
+	venvJupyter := filepath.Join(n.paths.PythonEnvDir(profile), "bin", "jupyter")
+	if util.FileExists(venvJupyter) {
+		return venvJupyter, nil
+	}
+	if path, err := exec.LookPath("jupyter"); err == nil {
+		return path, nil
+	}
+	return "", fmt.Errorf("jupyter not found in %s or PATH (install jupyterlab and ipykernel, e.g. with: local-data python init -r requirements.txt)",
+		n.paths.PythonEnvDir(profile))
+}
+
+// kernelSpec is a Jupyter kernel.json
+type kernelSpec struct {
+	Argv        []string          `json:"argv"`
+	DisplayName string            `json:"display_name"`
+	Language    string            `json:"language"`
+	Env         map[string]string `json:"env"`
+}
+
+// newKernelSpec returns an IPython kernel that runs PySpark's shell.py at
+// startup, so notebooks get 'spark' and 'sc' configured from SPARK_CONF_DIR
+// (spark-defaults.conf and hive-site.xml, i.e. the Hive metastore).
+func newKernelSpec(e *env.Environment, python string) *kernelSpec {
+	sparkPython := filepath.Join(e.SparkHome, "python")
+	pythonPath := []string{sparkPython}
+	if py4j, _ := filepath.Glob(filepath.Join(sparkPython, "lib", "py4j-*-src.zip")); len(py4j) > 0 {
+		pythonPath = append(pythonPath, py4j[len(py4j)-1])
+	}
+
+	vars := map[string]string{
+		"SPARK_HOME":          e.SparkHome,
+		"SPARK_CONF_DIR":      e.SparkConfDir,
+		"HIVE_CONF_DIR":       e.HiveConfDir,
+		"HADOOP_CONF_DIR":     e.HadoopConfDir,
+		"JAVA_HOME":           e.JavaHome,
+		"PYSPARK_PYTHON":      e.PySparkPython,
+		"PYTHONPATH":          strings.Join(pythonPath, string(os.PathListSeparator)),
+		"PYTHONSTARTUP":       filepath.Join(sparkPython, "pyspark", "shell.py"),
+		"PYSPARK_SUBMIT_ARGS": "--name local-data-notebook pyspark-shell",
+	}
+	for name, value := range vars {
+		if value == "" {
+			delete(vars, name)
+		}
+	}
+
+	return &kernelSpec{
+		Argv:        []string{python, "-m", "ipykernel_launcher", "-f", "{connection_file}"},
+		DisplayName: fmt.Sprintf("PySpark (local-data, %s)", e.ActiveProfile),
+		Language:    "python",
+		Env:         vars,
+	}
+}
+
+// writeKernelSpec writes the PySpark kernel spec for the environment. The
+// kernel runs the profile's PYSPARK_DRIVER_PYTHON, or python3 from PATH.
+func (n *NotebookService) writeKernelSpec(e *env.Environment) error {
+	python := e.PySparkDriverPython
+	if python == "" {
+		python = "python3"
+	}
+	if path, err := exec.LookPath(python); err == nil {
+		python = path
+	}
+
+	dir := filepath.Join(n.JupyterPath(), "kernels", KernelName)
+	if err := util.MkdirAll(dir); err != nil {
+		return fmt.Errorf("failed to create kernel spec directory: %w", err)
+	}
+	data, err := json.MarshalIndent(newKernelSpec(e, python), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "kernel.json"), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write kernel spec: %w", err)
+	}
+	return nil
+}
+
+func (n *NotebookService) statePath() string {
+	return filepath.Join(n.dataDir, "server.json")
+}
+
+func (n *NotebookService) readState() (*serverState, error) {
+	data, err := os.ReadFile(n.statePath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notebook state: %w", err)
+	}
+	var state serverState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid notebook state %s: %w", n.statePath(), err)
+	}
+	return &state, nil
+}
+
+// writeState saves the server state; it holds the token, so mode 0600
+func (n *NotebookService) writeState(state serverState) error {
+	if err := util.MkdirAll(n.dataDir); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(n.statePath(), data, 0600); err != nil {
+		return fmt.Errorf("failed to write notebook state: %w", err)
+	}
+	return nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate notebook token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func joinPathList(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, string(os.PathListSeparator))
+}
@@ -0,0 +1,91 @@
+package notebook
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/env"
+)
+
+func TestWriteKernelSpec(t *testing.T) {
+	tmpDir := t.TempDir()
+	sparkHome := filepath.Join(tmpDir, "spark")
+	py4j := filepath.Join(sparkHome, "python", "lib", "py4j-0.10.9.7-src.zip")
+	if err := os.MkdirAll(filepath.Dir(py4j), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(py4j, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	paths := config.NewPaths("", filepath.Join(tmpDir, "base"))
+	svc, err := NewNotebookService(paths)
+	if err != nil {
+		t.Fatalf("NewNotebookService() error = %v", err)
+	}
+	e := &env.Environment{
+		ActiveProfile:       "local",
+		SparkHome:           sparkHome,
+		SparkConfDir:        "/base/conf/current/spark",
+		HiveConfDir:         "/base/conf/current/hive",
+		PySparkPython:       "/base/python/local/bin/python",
+		PySparkDriverPython: "/base/python/local/bin/python",
+	}
+	if err := svc.writeKernelSpec(e); err != nil {
+		t.Fatalf("writeKernelSpec() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(svc.JupyterPath(), "kernels", KernelName, "kernel.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec kernelSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("kernel.json: %v", err)
+	}
+
+	if spec.Argv[0] != e.PySparkDriverPython || spec.Language != "python" {
+		t.Errorf("argv = %v, language = %q", spec.Argv, spec.Language)
+	}
+	want := map[string]string{
+		"SPARK_HOME":     sparkHome,
+		"SPARK_CONF_DIR": e.SparkConfDir,
+		"HIVE_CONF_DIR":  e.HiveConfDir,
+		"PYSPARK_PYTHON": e.PySparkPython,
+		"PYTHONPATH":     filepath.Join(sparkHome, "python") + string(os.PathListSeparator) + py4j,
+		"PYTHONSTARTUP":  filepath.Join(sparkHome, "python", "pyspark", "shell.py"),
+	}
+	for name, value := range want {
+		if spec.Env[name] != value {
+			t.Errorf("env %s = %q, want %q", name, spec.Env[name], value)
+		}
+	}
+	// Unset variables are left out rather than cleared
+	if _, ok := spec.Env["HADOOP_CONF_DIR"]; ok {
+		t.Errorf("env HADOOP_CONF_DIR should be omitted, got %q", spec.Env["HADOOP_CONF_DIR"])
+	}
+}
+
+func TestStop_NotRunning(t *testing.T) {
+	svc, err := NewNotebookService(config.NewPaths("", t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewNotebookService() error = %v", err)
+	}
+
+	if svc.IsRunning() {
+		t.Fatal("IsRunning() = true with no server started")
+	}
+	statuses, err := svc.Status()
+	if err != nil || len(statuses) != 1 || statuses[0].Name != "notebook" || statuses[0].Running {
+		t.Fatalf("Status() = %+v, %v", statuses, err)
+	}
+	if err := svc.Stop(); err != nil {
+		t.Fatalf("Stop() with nothing running error = %v", err)
+	}
+	if _, err := svc.URL(); err == nil {
+		t.Fatal("URL() without a started server: expected error")
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -160,8 +161,8 @@ func isProcessRunning(pid int) bool {
 		return true
 	}
 
-	// ESRCH means process doesn't exist
-	if err == syscall.ESRCH {
+	// ESRCH (or ErrProcessDone from pidfd-based lookups) means process doesn't exist
+	if err == syscall.ESRCH || errors.Is(err, os.ErrProcessDone) {
 		return false
 	}
 
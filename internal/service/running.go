@@ -0,0 +1,50 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RunningDaemons scans $BASE_DIR/state/<service>/pids for live PID files
+// Returns one ServiceStatus per running daemon, named "<service>/<daemon>"
+func RunningDaemons(stateDir string) ([]ServiceStatus, error) {
+	entries, err := os.ReadDir(stateDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var running []ServiceStatus
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		pidDir := filepath.Join(stateDir, entry.Name(), "pids")
+		pidFiles, err := filepath.Glob(filepath.Join(pidDir, "*.pid"))
+		if err != nil || len(pidFiles) == 0 {
+			continue
+		}
+
+		pm := NewProcessManager(pidDir, filepath.Join(stateDir, entry.Name(), "logs"))
+		for _, pidFile := range pidFiles {
+			name := strings.TrimSuffix(filepath.Base(pidFile), ".pid")
+			pid, err := pm.Status(name)
+			if err != nil || pid == 0 {
+				continue
+			}
+			running = append(running, ServiceStatus{
+				Name:    entry.Name() + "/" + name,
+				Running: true,
+				PID:     pid,
+			})
+		}
+	}
+
+	sort.Slice(running, func(i, j int) bool { return running[i].Name < running[j].Name })
+	return running, nil
+}
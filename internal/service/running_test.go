@@ -0,0 +1,46 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestRunningDaemons(t *testing.T) {
+	stateDir := t.TempDir()
+
+	hivePids := filepath.Join(stateDir, "hive", "pids")
+	if err := os.MkdirAll(hivePids, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	// Current test process is guaranteed to be running
+	if err := os.WriteFile(filepath.Join(hivePids, "metastore.pid"), []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		t.Fatalf("write pid: %v", err)
+	}
+	// Stale PID file should be ignored
+	if err := os.WriteFile(filepath.Join(hivePids, "hiveserver2.pid"), []byte("999999"), 0644); err != nil {
+		t.Fatalf("write pid: %v", err)
+	}
+
+	running, err := RunningDaemons(stateDir)
+	if err != nil {
+		t.Fatalf("RunningDaemons() error = %v", err)
+	}
+	if len(running) != 1 {
+		t.Fatalf("RunningDaemons() = %+v, want 1 entry", running)
+	}
+	if running[0].Name != "hive/metastore" || running[0].PID != os.Getpid() {
+		t.Errorf("RunningDaemons()[0] = %+v", running[0])
+	}
+}
+
+func TestRunningDaemons_MissingStateDir(t *testing.T) {
+	running, err := RunningDaemons(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatalf("RunningDaemons() error = %v", err)
+	}
+	if len(running) != 0 {
+		t.Errorf("RunningDaemons() = %+v, want none", running)
+	}
+}
@@ -0,0 +1,116 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/service"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// processName is the PID/log file name of the server
+const processName = "s3"
+
+// startTimeout bounds how long Start waits for the server to listen
+const startTimeout = 10 * time.Second
+
+// ErrNotEnabled is returned by Start when the s3 setting is off
+var ErrNotEnabled = errors.New("s3 is not enabled (enable with: local-data setting set s3 on)")
+
+// S3Service runs the embedded S3-compatible server ('local-data s3 serve')
+// in the background. State lives under $BASE_DIR/state/s3: data/ (one
+// directory per bucket), pids/ and logs/
+type S3Service struct {
+	paths    *config.Paths
+	settings *config.Settings
+	daemon   *service.Daemon
+	dataDir  string
+}
+
+// NewS3Service creates a new S3 service
+func NewS3Service(paths *config.Paths) (*S3Service, error) {
+	settings, err := config.NewSettingsManager(paths).LoadOrDefault()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	sp := paths.S3Paths()
+	return &S3Service{
+		paths:    paths,
+		settings: settings,
+		daemon: &service.Daemon{
+			Name:    "s3",
+			Process: processName,
+			ProcMgr: service.NewProcessManager(sp.PidsDir, sp.LogsDir),
+			Timeout: startTimeout,
+		},
+		dataDir: sp.DataDir,
+	}, nil
+}
+
+// Enabled reports whether settings turn the S3 server on
+func (s *S3Service) Enabled() bool {
+	return s.settings.S3Enabled
+}
+
+// IsRunning reports whether the server is running
+func (s *S3Service) IsRunning() bool {
+	return s.daemon.ProcMgr.IsRunning(processName)
+}
+
+// Port returns the port the server listens on
+func (s *S3Service) Port() int {
+	return s.settings.S3ServerPort()
+}
+
+// Start runs the server from the local-data binary itself
+func (s *S3Service) Start() error {
+	if !s.Enabled() {
+		return ErrNotEnabled
+	}
+
+	if pid, err := s.daemon.ProcMgr.Status(processName); err == nil && pid > 0 {
+		util.Log("s3 already running (pid %d).", pid)
+		return nil
+	}
+
+	if err := util.MkdirAll(s.dataDir); err != nil {
+		return fmt.Errorf("failed to create s3 data directory: %w", err)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate local-data binary: %w", err)
+	}
+
+	port := s.Port()
+	util.Log("Starting s3 (port %d, data %s)...", port, s.dataDir)
+
+	cmd := exec.Command(exe, "--base-dir", s.paths.BaseDir, "s3", "serve", "--port", strconv.Itoa(port))
+	startedPid, err := s.daemon.Start(cmd, port)
+	if err != nil {
+		return err
+	}
+	util.Success("s3 started (pid %d, endpoint %s).", startedPid, s.settings.S3Endpoint())
+	return nil
+}
+
+// Stop stops the server and waits for it to exit
+func (s *S3Service) Stop() error {
+	return s.daemon.Stop()
+}
+
+// Status returns the status of the server
+func (s *S3Service) Status() ([]service.ServiceStatus, error) {
+	return s.daemon.Status()
+}
+
+// Logs displays the server log
+func (s *S3Service) Logs() error {
+	return s.daemon.Logs()
+}
@@ -0,0 +1,30 @@
+package s3
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/objectstore"
+)
+
+func TestStart_NotEnabled(t *testing.T) {
+	paths := config.NewPaths("", t.TempDir())
+	svc, err := NewS3Service(paths)
+	if err != nil {
+		t.Fatalf("NewS3Service() error = %v", err)
+	}
+
+	if svc.Enabled() {
+		t.Fatalf("default settings should not enable s3")
+	}
+	if svc.Port() != objectstore.DefaultPort {
+		t.Fatalf("Port() = %d, want %d", svc.Port(), objectstore.DefaultPort)
+	}
+	if err := svc.Start(); !errors.Is(err, ErrNotEnabled) {
+		t.Fatalf("Start() error = %v, want ErrNotEnabled", err)
+	}
+	if err := svc.Stop(); err != nil {
+		t.Fatalf("Stop() with nothing running error = %v", err)
+	}
+}
@@ -0,0 +1,425 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+// Format identifies how snapshot contents are stored on disk.
+type Format string
+
+const (
+	// FormatArchive stores captured state as a single tar+zstd archive.
+	FormatArchive Format = "tar.zst"
+	// FormatClone stores captured state as copy-on-write (reflink) copies.
+	// Hardlinks are intentionally not used: Derby and HDFS modify files in place,
+	// which would silently change the snapshot as well.
+	FormatClone Format = "clone"
+)
+
+const (
+	metadataFile = "metadata.json"
+	archiveFile  = "state.tar.zst"
+	cloneDir     = "files"
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// skippedStateDirs are per-service directories that are never captured
+// (runtime-only data that must not be rolled back).
+var skippedStateDirs = map[string]bool{
+	"logs": true,
+	"pids": true,
+	"tmp":  true,
+}
+
+// Metadata describes a snapshot. It is stored as metadata.json next to the data.
+type Metadata struct {
+	Name      string            `json:"name"`
+	CreatedAt time.Time         `json:"created-at"`
+	Profile   string            `json:"profile"`
+	DBType    string            `json:"db-type"`
+	Format    Format            `json:"format"`
+	Roots     []string          `json:"roots"` // base-dir relative paths captured
+	Versions  map[string]string `json:"versions,omitempty"`
+	SizeBytes int64             `json:"size-bytes"`
+}
+
+// Manager creates, lists, restores and deletes snapshots under $BASE_DIR/snapshots.
+// Callers are responsible for stopping services before Create and Restore.
+type Manager struct {
+	paths *config.Paths
+}
+
+// NewManager creates a snapshot manager.
+func NewManager(paths *config.Paths) *Manager {
+	return &Manager{paths: paths}
+}
+
+// DefaultName returns a timestamp-based snapshot name.
+func DefaultName(now time.Time) string {
+	return now.Format("20060102-150405")
+}
+
+// ParseFormat validates a snapshot format value.
+func ParseFormat(value string) (Format, error) {
+	switch Format(value) {
+	case "", FormatArchive:
+		return FormatArchive, nil
+	case FormatClone:
+		return FormatClone, nil
+	default:
+		return "", fmt.Errorf("unknown snapshot format %q (supported: %s, %s)", value, FormatArchive, FormatClone)
+	}
+}
+
+func (m *Manager) dir(name string) string {
+	return filepath.Join(m.paths.SnapshotsDir(), name)
+}
+
+// Create captures the current state into a new snapshot.
+func (m *Manager) Create(name string, format Format, versions map[string]string) (*Metadata, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid snapshot name %q (allowed: letters, digits, '.', '_', '-')", name)
+	}
+	dst := m.dir(name)
+	if util.FileExists(dst) {
+		return nil, fmt.Errorf("snapshot %q already exists", name)
+	}
+
+	roots, err := captureRoots(m.paths.BaseDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("nothing to snapshot under %s (run: local-data init)", m.paths.BaseDir)
+	}
+
+	profile, _ := m.paths.ActiveProfile()
+	settings, err := config.NewSettingsManager(m.paths).LoadOrDefault()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	meta := &Metadata{
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+		Profile:   profile,
+		DBType:    settings.DBType,
+		Format:    format,
+		Roots:     roots,
+		Versions:  versions,
+	}
+
+	// Write into a temporary directory first so a failed create leaves nothing behind.
+	tmp := dst + ".partial"
+	if err := os.RemoveAll(tmp); err != nil {
+		return nil, err
+	}
+	if err := util.MkdirAll(tmp); err != nil {
+		return nil, err
+	}
+
+	if err := m.capture(meta, tmp); err != nil {
+		os.RemoveAll(tmp)
+		return nil, err
+	}
+
+	size, err := dirSize(tmp)
+	if err != nil {
+		os.RemoveAll(tmp)
+		return nil, err
+	}
+	meta.SizeBytes = size
+
+	if err := writeMetadata(filepath.Join(tmp, metadataFile), meta); err != nil {
+		os.RemoveAll(tmp)
+		return nil, err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.RemoveAll(tmp)
+		return nil, fmt.Errorf("failed to finalize snapshot: %w", err)
+	}
+
+	return meta, nil
+}
+
+func (m *Manager) capture(meta *Metadata, dst string) error {
+	switch meta.Format {
+	case FormatArchive:
+		return writeArchive(m.paths.BaseDir, meta.Roots, filepath.Join(dst, archiveFile))
+	case FormatClone:
+		for _, root := range meta.Roots {
+			if err := cloneTree(filepath.Join(m.paths.BaseDir, root), filepath.Join(dst, cloneDir, root)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported snapshot format %q", meta.Format)
+	}
+}
+
+// List returns all snapshots sorted by creation time (oldest first).
+func (m *Manager) List() ([]*Metadata, error) {
+	entries, err := os.ReadDir(m.paths.SnapshotsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var snapshots []*Metadata
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		meta, err := readMetadata(filepath.Join(m.paths.SnapshotsDir(), entry.Name(), metadataFile))
+		if err != nil {
+			// Skip incomplete snapshots (e.g. *.partial)
+			continue
+		}
+		snapshots = append(snapshots, meta)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
+
+// Get returns the metadata of a snapshot.
+func (m *Manager) Get(name string) (*Metadata, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid snapshot name %q", name)
+	}
+	meta, err := readMetadata(filepath.Join(m.dir(name), metadataFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("snapshot %q not found (run: local-data snapshot list)", name)
+		}
+		return nil, err
+	}
+	return meta, nil
+}
+
+// Delete removes a snapshot.
+func (m *Manager) Delete(name string) error {
+	if _, err := m.Get(name); err != nil {
+		return err
+	}
+	return os.RemoveAll(m.dir(name))
+}
+
+// Restore replaces the captured state with the contents of a snapshot.
+// State is staged next to the live data and swapped in with renames; if any
+// swap fails, all previously swapped paths are rolled back.
+func (m *Manager) Restore(name string) (*Metadata, error) {
+	meta, err := m.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	baseDir := m.paths.BaseDir
+	staging := filepath.Join(baseDir, ".snapshot-restore")
+	if err := os.RemoveAll(staging); err != nil {
+		return nil, err
+	}
+	if err := util.MkdirAll(staging); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	if err := m.stage(meta, staging); err != nil {
+		return nil, err
+	}
+
+	// Paths created after the snapshot was taken are removed as well, so the
+	// restored state matches the snapshot exactly.
+	current, err := captureRoots(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	targets := unionSorted(meta.Roots, current)
+
+	backup := filepath.Join(baseDir, ".snapshot-backup")
+	if err := os.RemoveAll(backup); err != nil {
+		return nil, err
+	}
+	if err := swapRoots(baseDir, staging, backup, targets); err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(backup); err != nil {
+		util.Warn("Failed to remove restore backup %s: %v", backup, err)
+	}
+
+	// Rebuild the runtime overlay for the restored active profile.
+	pm := config.NewProfileManager(m.paths)
+	if pm.IsInitialized() {
+		if err := pm.Apply(""); err != nil {
+			return meta, fmt.Errorf("state restored but failed to apply profile overlay: %w", err)
+		}
+	}
+
+	return meta, nil
+}
+
+func (m *Manager) stage(meta *Metadata, staging string) error {
+	switch meta.Format {
+	case FormatArchive:
+		return extractArchive(filepath.Join(m.dir(meta.Name), archiveFile), staging)
+	case FormatClone:
+		for _, root := range meta.Roots {
+			src := filepath.Join(m.dir(meta.Name), cloneDir, root)
+			if err := cloneTree(src, filepath.Join(staging, root)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported snapshot format %q", meta.Format)
+	}
+}
+
+// swapRoots moves each live target into backup and the staged copy into place.
+func swapRoots(baseDir, staging, backup string, targets []string) error {
+	// Each completed rename is recorded so it can be undone in reverse order.
+	type move struct{ cur, orig string }
+	var done []move
+
+	rollback := func() {
+		for i := len(done) - 1; i >= 0; i-- {
+			if err := os.Rename(done[i].cur, done[i].orig); err != nil {
+				util.Warn("Rollback failed for %s: %v", done[i].orig, err)
+			}
+		}
+	}
+	rename := func(from, to string) error {
+		if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+			return err
+		}
+		if err := os.Rename(from, to); err != nil {
+			return err
+		}
+		done = append(done, move{cur: to, orig: from})
+		return nil
+	}
+
+	for _, target := range targets {
+		live := filepath.Join(baseDir, target)
+		if util.FileExists(live) {
+			if err := rename(live, filepath.Join(backup, target)); err != nil {
+				rollback()
+				return fmt.Errorf("failed to move aside %s: %w", live, err)
+			}
+		}
+
+		staged := filepath.Join(staging, target)
+		if util.FileExists(staged) {
+			if err := rename(staged, live); err != nil {
+				rollback()
+				return fmt.Errorf("failed to restore %s: %w", live, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// captureRoots returns the base-dir relative paths that make up local state:
+// state/<service>/<dir> (except logs/pids/tmp), settings, profiles, overrides
+// and the active profile marker.
+func captureRoots(baseDir string) ([]string, error) {
+	var roots []string
+
+	stateDir := filepath.Join(baseDir, "state")
+	services, err := os.ReadDir(stateDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, svc := range services {
+		if !svc.IsDir() {
+			continue
+		}
+		children, err := os.ReadDir(filepath.Join(stateDir, svc.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if skippedStateDirs[child.Name()] {
+				continue
+			}
+			roots = append(roots, filepath.ToSlash(filepath.Join("state", svc.Name(), child.Name())))
+		}
+	}
+
+	for _, rel := range []string{
+		"settings",
+		"conf/profiles",
+		"conf/overrides.yaml",
+		"conf/active_profile",
+	} {
+		if util.FileExists(filepath.Join(baseDir, rel)) {
+			roots = append(roots, rel)
+		}
+	}
+
+	sort.Strings(roots)
+	return roots, nil
+}
+
+func unionSorted(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var out []string
+	for _, s := range append(append([]string{}, a...), b...) {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func writeMetadata(path string, meta *Metadata) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot metadata: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func readMetadata(path string) (*Metadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var meta Metadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &meta, nil
+}
+
+func dirSize(root string) (int64, error) {
+	var size int64
+	err := filepath.Walk(root, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,175 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/util"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func setupState(t *testing.T) *config.Paths {
+	t.Helper()
+	paths := config.NewPaths("", t.TempDir())
+
+	pm := config.NewProfileManager(paths)
+	if err := pm.Init(false, nil); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := pm.Set("local"); err != nil {
+		t.Fatalf("set local: %v", err)
+	}
+
+	writeFile(t, filepath.Join(paths.StateDir(), "hive", "metastore_db", "seg0", "c1.dat"), "v1")
+	writeFile(t, filepath.Join(paths.StateDir(), "hive", "warehouse", "t1", "part-0"), "row1")
+	writeFile(t, filepath.Join(paths.StateDir(), "hive", "logs", "metastore.log"), "log-v1")
+	return paths
+}
+
+func TestCreateRestore_RoundTrip(t *testing.T) {
+	paths := setupState(t)
+	m := NewManager(paths)
+
+	meta, err := m.Create("before", FormatArchive, map[string]string{"hive": "4.0.1"})
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if meta.Profile != "local" {
+		t.Errorf("Profile = %q, want local", meta.Profile)
+	}
+	if meta.DBType != "derby" {
+		t.Errorf("DBType = %q, want derby", meta.DBType)
+	}
+	for _, root := range meta.Roots {
+		if root == "state/hive/logs" {
+			t.Fatalf("logs must not be captured: %v", meta.Roots)
+		}
+	}
+
+	// Mutate state after the snapshot
+	metastoreFile := filepath.Join(paths.StateDir(), "hive", "metastore_db", "seg0", "c1.dat")
+	writeFile(t, metastoreFile, "v2")
+	newTable := filepath.Join(paths.StateDir(), "hive", "warehouse", "t2", "part-0")
+	writeFile(t, newTable, "row2")
+	newState := filepath.Join(paths.StateDir(), "spark", "events", "app-1")
+	writeFile(t, newState, "event")
+	logFile := filepath.Join(paths.StateDir(), "hive", "logs", "metastore.log")
+	writeFile(t, logFile, "log-v2")
+
+	if _, err := m.Restore("before"); err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+
+	if got := readFile(t, metastoreFile); got != "v1" {
+		t.Errorf("metastore content = %q, want v1", got)
+	}
+	if util.FileExists(newTable) {
+		t.Errorf("table created after snapshot should be removed")
+	}
+	if util.FileExists(newState) {
+		t.Errorf("state created after snapshot should be removed")
+	}
+	if got := readFile(t, logFile); got != "log-v2" {
+		t.Errorf("logs should be left untouched, got %q", got)
+	}
+	if active, _ := paths.ActiveProfile(); active != "local" {
+		t.Errorf("active profile = %q, want local", active)
+	}
+	if !util.FileExists(filepath.Join(paths.CurrentHiveConf(), "hive-site.xml")) {
+		t.Errorf("runtime overlay should be re-applied after restore")
+	}
+	for _, leftover := range []string{".snapshot-restore", ".snapshot-backup"} {
+		if util.FileExists(filepath.Join(paths.BaseDir, leftover)) {
+			t.Errorf("%s should be cleaned up", leftover)
+		}
+	}
+}
+
+func TestCreate_RejectsInvalidOrDuplicateName(t *testing.T) {
+	paths := setupState(t)
+	m := NewManager(paths)
+
+	for _, name := range []string{"", "../escape", "a/b", ".hidden"} {
+		if _, err := m.Create(name, FormatArchive, nil); err == nil {
+			t.Errorf("Create(%q) should fail", name)
+		}
+	}
+
+	if _, err := m.Create("dup", FormatArchive, nil); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if _, err := m.Create("dup", FormatArchive, nil); err == nil {
+		t.Fatalf("Create() with existing name should fail")
+	}
+}
+
+func TestListAndDelete(t *testing.T) {
+	paths := setupState(t)
+	m := NewManager(paths)
+
+	for _, name := range []string{"first", "second"} {
+		if _, err := m.Create(name, FormatArchive, nil); err != nil {
+			t.Fatalf("Create(%s) error: %v", name, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Leftovers from an interrupted create are not listed
+	if err := os.MkdirAll(filepath.Join(paths.SnapshotsDir(), "third.partial"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	snapshots, err := m.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Name != "first" || snapshots[1].Name != "second" {
+		t.Fatalf("List() = %v, want [first second]", snapshots)
+	}
+	if snapshots[0].SizeBytes == 0 {
+		t.Errorf("SizeBytes should be recorded")
+	}
+
+	if err := m.Delete("first"); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if err := m.Delete("first"); err == nil {
+		t.Fatalf("Delete() of missing snapshot should fail")
+	}
+	snapshots, _ = m.List()
+	if len(snapshots) != 1 || snapshots[0].Name != "second" {
+		t.Fatalf("List() after delete = %v, want [second]", snapshots)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat(""); err != nil || f != FormatArchive {
+		t.Errorf("ParseFormat(\"\") = %q, %v", f, err)
+	}
+	if f, err := ParseFormat("clone"); err != nil || f != FormatClone {
+		t.Errorf("ParseFormat(clone) = %q, %v", f, err)
+	}
+	if _, err := ParseFormat("zip"); err == nil {
+		t.Errorf("ParseFormat(zip) should fail")
+	}
+}
@@ -0,0 +1,73 @@
+package sqlscript
+
+import (
+	"context"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+)
+
+// Engine names accepted by 'local-data sql run --engine'.
+const (
+	EngineHive  = "hive"
+	EngineSpark = "spark"
+)
+
+// Result is the outcome of one statement.
+type Result struct {
+	Statement Statement
+	Duration  time.Duration
+	Rows      int    // rows returned; 0 for statements without a result set
+	Err       string // empty if the statement succeeded
+	Skipped   bool   // not run because an earlier statement failed
+}
+
+// Failed reports whether the statement ran and failed.
+func (r Result) Failed() bool {
+	return r.Err != ""
+}
+
+// Engine executes statements in order. Unless continueOnError is set, the
+// statements after the first failure are reported as skipped. The returned
+// error is for engine failures, not statement failures.
+type Engine interface {
+	Run(ctx context.Context, statements []Statement, continueOnError bool) ([]Result, error)
+}
+
+// Querier runs a statement against HiveServer2; *hs2.Client implements it.
+type Querier interface {
+	Query(ctx context.Context, stmt string) (*hs2.Result, error)
+}
+
+// HS2Engine runs statements over a HiveServer2 session.
+type HS2Engine struct {
+	Client Querier
+}
+
+// Run implements Engine.
+func (e *HS2Engine) Run(ctx context.Context, statements []Statement, continueOnError bool) ([]Result, error) {
+	results := make([]Result, 0, len(statements))
+	failed := false
+	for _, stmt := range statements {
+		if failed && !continueOnError {
+			results = append(results, Result{Statement: stmt, Skipped: true})
+			continue
+		}
+
+		start := time.Now()
+		res, err := e.Client.Query(ctx, stmt.SQL)
+		r := Result{Statement: stmt, Duration: time.Since(start)}
+		if err != nil {
+			r.Err = err.Error()
+			failed = true
+		} else {
+			r.Rows = len(res.Rows)
+		}
+		results = append(results, r)
+
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,63 @@
+package sqlscript
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+)
+
+type fakeQuerier struct {
+	fail map[string]bool
+}
+
+func (f *fakeQuerier) Query(_ context.Context, stmt string) (*hs2.Result, error) {
+	if f.fail[stmt] {
+		return nil, fmt.Errorf("query failed: %s", stmt)
+	}
+	return &hs2.Result{
+		Columns: []hs2.Column{{Name: "c", Type: "int"}},
+		Rows:    [][]any{{int32(1)}, {int32(2)}},
+	}, nil
+}
+
+func statements(sqls ...string) []Statement {
+	out := make([]Statement, len(sqls))
+	for i, sql := range sqls {
+		out[i] = Statement{File: "test.sql", Index: i + 1, SQL: sql}
+	}
+	return out
+}
+
+func TestHS2Engine_StopsOnError(t *testing.T) {
+	eng := &HS2Engine{Client: &fakeQuerier{fail: map[string]bool{"bad": true}}}
+	results, err := eng.Run(context.Background(), statements("good", "bad", "good"), false)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if results[0].Rows != 2 || results[0].Failed() {
+		t.Fatalf("result 1 = %+v", results[0])
+	}
+	if !results[1].Failed() {
+		t.Fatalf("result 2 should fail: %+v", results[1])
+	}
+	if !results[2].Skipped {
+		t.Fatalf("result 3 should be skipped: %+v", results[2])
+	}
+	if passed, failed, skipped := Counts(results); passed != 1 || failed != 1 || skipped != 1 {
+		t.Fatalf("Counts = %d/%d/%d", passed, failed, skipped)
+	}
+}
+
+func TestHS2Engine_ContinueOnError(t *testing.T) {
+	eng := &HS2Engine{Client: &fakeQuerier{fail: map[string]bool{"bad": true}}}
+	results, err := eng.Run(context.Background(), statements("bad", "good"), true)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !results[0].Failed() || results[1].Skipped || results[1].Failed() {
+		t.Fatalf("results = %+v", results)
+	}
+}
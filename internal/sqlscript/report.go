@@ -0,0 +1,108 @@
+package sqlscript
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/junit"
+)
+
+// Report formats accepted by 'local-data sql run --report'.
+const (
+	ReportText  = "text"
+	ReportJUnit = "junit"
+)
+
+// Counts returns the number of passed, failed and skipped statements.
+func Counts(results []Result) (passed, failed, skipped int) {
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			skipped++
+		case r.Failed():
+			failed++
+		default:
+			passed++
+		}
+	}
+	return passed, failed, skipped
+}
+
+// WriteReport writes results in the given format.
+func WriteReport(w io.Writer, results []Result, format string) error {
+	switch format {
+	case ReportText:
+		return writeText(w, results)
+	case ReportJUnit:
+		return junit.Write(w, junitSuites(results))
+	default:
+		return fmt.Errorf("unsupported report format %q (supported: %s, %s)", format, ReportText, ReportJUnit)
+	}
+}
+
+func writeText(w io.Writer, results []Result) error {
+	var sb strings.Builder
+	var total time.Duration
+	file := ""
+	for i, r := range results {
+		if i == 0 || r.Statement.File != file {
+			file = r.Statement.File
+			fmt.Fprintf(&sb, "%s\n", file)
+		}
+		total += r.Duration
+
+		switch {
+		case r.Skipped:
+			fmt.Fprintf(&sb, "  [skip] #%-3d %8s  %-10s %s\n", r.Statement.Index, "", "", r.Statement.Summary())
+		case r.Failed():
+			fmt.Fprintf(&sb, "  [FAIL] #%-3d %8s  %-10s %s\n", r.Statement.Index, formatSeconds(r.Duration), "", r.Statement.Summary())
+			fmt.Fprintf(&sb, "         %s\n", r.Err)
+		default:
+			fmt.Fprintf(&sb, "  [ok]   #%-3d %8s  %-10s %s\n", r.Statement.Index, formatSeconds(r.Duration), formatRows(r.Rows), r.Statement.Summary())
+		}
+	}
+
+	passed, failed, skipped := Counts(results)
+	fmt.Fprintf(&sb, "\n%d statements: %d passed, %d failed, %d skipped (%s)\n",
+		len(results), passed, failed, skipped, formatSeconds(total))
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// junitSuites maps each script to a test suite and each statement to a case.
+func junitSuites(results []Result) []junit.Suite {
+	var suites []junit.Suite
+	for _, r := range results {
+		if len(suites) == 0 || suites[len(suites)-1].Name != r.Statement.File {
+			suites = append(suites, junit.Suite{Name: r.Statement.File})
+		}
+		s := &suites[len(suites)-1]
+
+		c := junit.Case{
+			Name:      fmt.Sprintf("statement %d: %s", r.Statement.Index, r.Statement.Summary()),
+			Classname: r.Statement.File,
+			Time:      r.Duration,
+			Skipped:   r.Skipped,
+		}
+		if r.Failed() {
+			c.Failure = r.Err
+			c.Details = r.Statement.SQL
+		}
+		s.Cases = append(s.Cases, c)
+	}
+	return suites
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
+
+func formatRows(n int) string {
+	if n == 1 {
+		return "1 row"
+	}
+	return fmt.Sprintf("%d rows", n)
+}
@@ -0,0 +1,57 @@
+package sqlscript
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteReport_Text(t *testing.T) {
+	results := []Result{
+		{Statement: Statement{File: "a.sql", Index: 1, SQL: "SELECT 1"}, Duration: 120 * time.Millisecond, Rows: 1},
+		{Statement: Statement{File: "a.sql", Index: 2, SQL: "SELECT * FROM missing"}, Duration: 50 * time.Millisecond, Err: "Table not found: missing"},
+		{Statement: Statement{File: "b.sql", Index: 1, SQL: "DROP TABLE t"}, Skipped: true},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, results, ReportText); err != nil {
+		t.Fatalf("WriteReport: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"a.sql\n",
+		"[ok]   #1      0.12s  1 row      SELECT 1",
+		"[FAIL] #2      0.05s",
+		"Table not found: missing",
+		"b.sql\n",
+		"[skip] #1",
+		"3 statements: 1 passed, 1 failed, 1 skipped (0.17s)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("report missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteReport_JUnit(t *testing.T) {
+	results := []Result{
+		{Statement: Statement{File: "a.sql", Index: 1, SQL: "SELECT 1"}, Duration: time.Second, Rows: 1},
+		{Statement: Statement{File: "b.sql", Index: 1, SQL: "SELECT x"}, Err: "bad column"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, results, ReportJUnit); err != nil {
+		t.Fatalf("WriteReport: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<testsuite name="a.sql" tests="1" failures="0" skipped="0" time="1.000">`,
+		`<testcase name="statement 1: SELECT 1" classname="a.sql" time="1.000">`,
+		`<failure message="bad column">SELECT x</failure>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("report missing %q:\n%s", want, out)
+		}
+	}
+}
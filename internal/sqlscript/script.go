@@ -0,0 +1,126 @@
+// Package sqlscript runs HiveQL scripts statement by statement against
+// HiveServer2 or Spark SQL and reports per-statement results.
+package sqlscript
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+)
+
+// Statement is one statement of a script, with variables substituted.
+type Statement struct {
+	File  string // script path, or "-e" for inline statements
+	Index int    // 1-based position in the script
+	SQL   string
+}
+
+// Summary returns the first line of the statement, shortened for reports.
+func (s Statement) Summary() string {
+	line, _, _ := strings.Cut(strings.TrimSpace(stripLeadingComments(s.SQL)), "\n")
+	line = strings.TrimSpace(line)
+	if len(line) > 60 {
+		line = line[:57] + "..."
+	}
+	return line
+}
+
+// Script is a parsed script file.
+type Script struct {
+	Path       string
+	Statements []Statement
+}
+
+// setVarPattern matches 'SET hivevar:name=value' and 'SET define:name=value'.
+var setVarPattern = regexp.MustCompile(`(?is)^set\s+(?:hivevar|define):([A-Za-z0-9_.]+)\s*=\s*(.*)$`)
+
+// varPattern matches ${name} and ${namespace:name}.
+var varPattern = regexp.MustCompile(`\$\{(?:(hivevar|define|env|hiveconf|system):)?([A-Za-z0-9_.]+)\}`)
+
+// Parse splits a script into statements and substitutes variables. Variables
+// set in the script with 'SET hivevar:name=value' apply to the statements
+// after it; vars itself is not modified.
+func Parse(path, content string, vars Variables) *Script {
+	scope := vars.clone()
+	script := &Script{Path: path}
+	for i, sql := range hs2.SplitStatements(content) {
+		sql = scope.Substitute(sql)
+		if m := setVarPattern.FindStringSubmatch(strings.TrimSpace(stripLeadingComments(sql))); m != nil {
+			scope[m[1]] = strings.TrimSpace(m[2])
+		}
+		script.Statements = append(script.Statements, Statement{File: path, Index: i + 1, SQL: sql})
+	}
+	return script
+}
+
+// Variables are --hivevar/--define values substituted into statements.
+type Variables map[string]string
+
+// ParseVariables parses name=value pairs.
+func ParseVariables(pairs []string) (Variables, error) {
+	vars := Variables{}
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid variable %q (expected name=value)", pair)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// Substitute replaces ${name}, ${hivevar:name}, ${define:name} and
+// ${env:NAME} references. Unknown references and ${hiveconf:...} or
+// ${system:...} are left for the server, as Hive does.
+func (v Variables) Substitute(sql string) string {
+	return varPattern.ReplaceAllStringFunc(sql, func(ref string) string {
+		m := varPattern.FindStringSubmatch(ref)
+		namespace, name := m[1], m[2]
+		switch namespace {
+		case "", "hivevar", "define":
+			if value, ok := v[name]; ok {
+				return value
+			}
+		case "env":
+			if value, ok := os.LookupEnv(name); ok {
+				return value
+			}
+		}
+		return ref
+	})
+}
+
+func (v Variables) clone() Variables {
+	c := make(Variables, len(v))
+	for name, value := range v {
+		c[name] = value
+	}
+	return c
+}
+
+// stripLeadingComments removes comment lines and blocks before the statement text.
+func stripLeadingComments(sql string) string {
+	for {
+		sql = strings.TrimSpace(sql)
+		switch {
+		case strings.HasPrefix(sql, "--"):
+			_, rest, ok := strings.Cut(sql, "\n")
+			if !ok {
+				return ""
+			}
+			sql = rest
+		case strings.HasPrefix(sql, "/*"):
+			_, rest, ok := strings.Cut(sql[2:], "*/")
+			if !ok {
+				return ""
+			}
+			sql = rest
+		default:
+			return sql
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package sqlscript
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse_SubstitutesVariables(t *testing.T) {
+	t.Setenv("LDP_TEST_OWNER", "etl")
+	vars := Variables{"ds": "2024-01-01", "db": "sales"}
+
+	script := Parse("daily.hql", `
+-- load one day
+USE ${db};
+SET hivevar:tbl=orders;
+SELECT * FROM ${hivevar:tbl} WHERE ds = '${ds}' AND owner = '${env:LDP_TEST_OWNER}';
+SELECT '${hiveconf:mapreduce.job.queuename}', '${undefined}';
+`, vars)
+
+	want := []string{
+		"-- load one day\nUSE sales",
+		"SET hivevar:tbl=orders",
+		"SELECT * FROM orders WHERE ds = '2024-01-01' AND owner = 'etl'",
+		"SELECT '${hiveconf:mapreduce.job.queuename}', '${undefined}'",
+	}
+	if len(script.Statements) != len(want) {
+		t.Fatalf("got %d statements: %+v", len(script.Statements), script.Statements)
+	}
+	for i, s := range script.Statements {
+		if s.SQL != want[i] {
+			t.Fatalf("statement %d = %q, want %q", i+1, s.SQL, want[i])
+		}
+		if s.Index != i+1 || s.File != "daily.hql" {
+			t.Fatalf("statement %d: index %d file %q", i+1, s.Index, s.File)
+		}
+	}
+	if _, ok := vars["tbl"]; ok {
+		t.Fatal("SET hivevar in a script leaked into the caller's variables")
+	}
+}
+
+func TestParseVariables(t *testing.T) {
+	vars, err := ParseVariables([]string{"ds=2024-01-01", "filter=a=b", "empty="})
+	if err != nil {
+		t.Fatalf("ParseVariables: %v", err)
+	}
+	if vars["ds"] != "2024-01-01" || vars["filter"] != "a=b" || vars["empty"] != "" {
+		t.Fatalf("vars = %v", vars)
+	}
+
+	if _, err := ParseVariables([]string{"novalue"}); err == nil || !strings.Contains(err.Error(), "name=value") {
+		t.Fatalf("expected name=value error, got %v", err)
+	}
+}
+
+func TestStatementSummary(t *testing.T) {
+	s := Statement{SQL: "-- comment\n/* block */\nSELECT a,\n  b FROM t"}
+	if got := s.Summary(); got != "SELECT a," {
+		t.Fatalf("Summary = %q", got)
+	}
+
+	long := Statement{SQL: "SELECT " + strings.Repeat("x", 100)}
+	if got := long.Summary(); len(got) != 60 || !strings.HasSuffix(got, "...") {
+		t.Fatalf("Summary = %q", got)
+	}
+}
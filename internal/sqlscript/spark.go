@@ -0,0 +1,172 @@
+package sqlscript
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/config"
+	"github.com/danieljhkim/local-data-platform/internal/env"
+)
+
+// sparkRunner is the PySpark job run by SparkEngine. It reads statements from
+// argv[1] and writes one JSON result per line to argv[2], so results survive
+// Spark's own logging on stdout/stderr.
+const sparkRunner = `import json
+import sys
+import time
+
+from pyspark.sql import SparkSession
+
+statements = json.load(open(sys.argv[1]))
+continue_on_error = sys.argv[3] == "true"
+spark = SparkSession.builder.appName("local-data-sql-run").enableHiveSupport().getOrCreate()
+
+failed = False
+with open(sys.argv[2], "w") as out:
+    for sql in statements:
+        if failed and not continue_on_error:
+            out.write(json.dumps({"skipped": True}) + "\n")
+            continue
+        start = time.time()
+        result = {"rows": 0}
+        try:
+            df = spark.sql(sql)
+            if df.columns:
+                result["rows"] = df.count()
+        except Exception as e:
+            result["error"] = str(e).strip().splitlines()[0] if str(e).strip() else type(e).__name__
+            failed = True
+        result["seconds"] = time.time() - start
+        out.write(json.dumps(result) + "\n")
+        out.flush()
+
+spark.stop()
+`
+
+// SparkEngine runs statements with spark.sql() in a single spark-submit job
+// using the local-data environment.
+type SparkEngine struct {
+	Paths *config.Paths
+}
+
+type sparkResult struct {
+	Rows    int     `json:"rows"`
+	Seconds float64 `json:"seconds"`
+	Error   string  `json:"error"`
+	Skipped bool    `json:"skipped"`
+}
+
+// Run implements Engine.
+func (e *SparkEngine) Run(ctx context.Context, statements []Statement, continueOnError bool) ([]Result, error) {
+	if len(statements) == 0 {
+		return nil, nil
+	}
+
+	dir, err := os.MkdirTemp("", "local-data-sql-run-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	job := filepath.Join(dir, "sql_run.py")
+	input := filepath.Join(dir, "statements.json")
+	output := filepath.Join(dir, "results.jsonl")
+
+	sqls := make([]string, len(statements))
+	for i, s := range statements {
+		sqls[i] = s.SQL
+	}
+	data, err := json.Marshal(sqls)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(input, data, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(job, []byte(sparkRunner), 0644); err != nil {
+		return nil, err
+	}
+
+	cmd, err := env.Command(e.Paths, []string{"spark-submit", job, input, output, fmt.Sprint(continueOnError)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	var logs bytes.Buffer
+	cmd.Stdout = &logs
+	cmd.Stderr = &logs
+
+	done := make(chan error, 1)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start spark-submit: %w", err)
+	}
+	go func() { done <- cmd.Wait() }()
+	select {
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		<-done
+		return nil, ctx.Err()
+	case err = <-done:
+	}
+
+	parsed, readErr := readSparkResults(output)
+	if err != nil && len(parsed) < len(statements) {
+		return nil, fmt.Errorf("spark-submit failed: %w\n%s", err, tail(logs.String(), 20))
+	}
+	if readErr != nil {
+		return nil, readErr
+	}
+	if len(parsed) != len(statements) {
+		return nil, fmt.Errorf("spark job reported %d results for %d statements\n%s", len(parsed), len(statements), tail(logs.String(), 20))
+	}
+
+	results := make([]Result, len(statements))
+	for i, p := range parsed {
+		results[i] = Result{
+			Statement: statements[i],
+			Duration:  time.Duration(p.Seconds * float64(time.Second)),
+			Rows:      p.Rows,
+			Err:       p.Error,
+			Skipped:   p.Skipped,
+		}
+	}
+	return results, nil
+}
+
+func readSparkResults(path string) ([]sparkResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var results []sparkResult
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r sparkResult
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("failed to parse spark job results: %w", err)
+		}
+		results = append(results, r)
+	}
+	return results, scanner.Err()
+}
+
+// tail returns the last n lines of s.
+func tail(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
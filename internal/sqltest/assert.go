@@ -0,0 +1,116 @@
+package sqltest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+)
+
+// Check evaluates the expectations against a query result and returns one
+// message per failed assertion. Values are compared by their text form, so
+// 1, "1" and int64(1) are equal and null matches NULL.
+func (e Expect) Check(res *hs2.Result) []string {
+	var failures []string
+
+	if e.Empty || e.isZero() {
+		if len(res.Rows) > 0 {
+			failures = append(failures, fmt.Sprintf("expected no rows, got %d; first: %s", len(res.Rows), formatRow(res.Rows[0])))
+		}
+		return failures
+	}
+
+	if e.RowCount != nil && len(res.Rows) != *e.RowCount {
+		failures = append(failures, fmt.Sprintf("row_count: expected %d, got %d", *e.RowCount, len(res.Rows)))
+	}
+
+	for _, col := range e.NotNull {
+		i, err := columnIndex(res, col)
+		if err != nil {
+			failures = append(failures, "not_null: "+err.Error())
+			continue
+		}
+		nulls := 0
+		for _, row := range res.Rows {
+			if row[i] == nil {
+				nulls++
+			}
+		}
+		if nulls > 0 {
+			failures = append(failures, fmt.Sprintf("not_null: %s has %d NULL value(s)", col, nulls))
+		}
+	}
+
+	for _, col := range e.Unique {
+		i, err := columnIndex(res, col)
+		if err != nil {
+			failures = append(failures, "unique: "+err.Error())
+			continue
+		}
+		seen := map[string]bool{}
+		var dups []string
+		for _, row := range res.Rows {
+			v := hs2.FormatValue(row[i])
+			if seen[v] {
+				dups = append(dups, v)
+			}
+			seen[v] = true
+		}
+		if len(dups) > 0 {
+			failures = append(failures, fmt.Sprintf("unique: %s has duplicate value(s): %s", col, strings.Join(dups, ", ")))
+		}
+	}
+
+	if e.Rows != nil {
+		if msg := compareRows(e.Rows, res.Rows, e.Ordered == nil || *e.Ordered); msg != "" {
+			failures = append(failures, "rows: "+msg)
+		}
+	}
+
+	return failures
+}
+
+func columnIndex(res *hs2.Result, name string) (int, error) {
+	for i, c := range res.Columns {
+		// HS2 may qualify result columns with the table alias (t.id)
+		if strings.EqualFold(c.Name, name) || strings.HasSuffix(strings.ToLower(c.Name), "."+strings.ToLower(name)) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("column %s not in result", name)
+}
+
+func compareRows(expected, actual [][]any, ordered bool) string {
+	want := make([]string, len(expected))
+	for i, row := range expected {
+		want[i] = formatRow(row)
+	}
+	got := make([]string, len(actual))
+	for i, row := range actual {
+		got[i] = formatRow(row)
+	}
+	if !ordered {
+		sort.Strings(want)
+		sort.Strings(got)
+	}
+
+	if len(want) != len(got) {
+		return fmt.Sprintf("expected %d row(s), got %d\n  expected: %s\n  actual:   %s",
+			len(want), len(got), strings.Join(want, " "), strings.Join(got, " "))
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			return fmt.Sprintf("row %d differs\n  expected: %s\n  actual:   %s", i+1, want[i], got[i])
+		}
+	}
+	return ""
+}
+
+func formatRow(row []any) string {
+	values := make([]string, len(row))
+	for i, v := range row {
+		values[i] = hs2.FormatValue(v)
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
@@ -0,0 +1,67 @@
+package sqltest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+)
+
+func result(rows ...[]any) *hs2.Result {
+	return &hs2.Result{
+		Columns: []hs2.Column{{Name: "t.id", Type: "int"}, {Name: "t.amount", Type: "double"}},
+		Rows:    rows,
+	}
+}
+
+func intPtr(n int) *int { return &n }
+
+func TestExpectCheck_Passes(t *testing.T) {
+	unordered := false
+	e := Expect{
+		Rows:     [][]any{{2, nil}, {1, 10.5}},
+		Ordered:  &unordered,
+		RowCount: intPtr(2),
+		NotNull:  []string{"id"},
+		Unique:   []string{"id"},
+	}
+	if failures := e.Check(result([]any{int32(1), 10.5}, []any{int32(2), nil})); len(failures) != 0 {
+		t.Fatalf("unexpected failures: %v", failures)
+	}
+}
+
+func TestExpectCheck_Failures(t *testing.T) {
+	e := Expect{
+		Rows:     [][]any{{1, 10.5}, {2, 3.0}},
+		RowCount: intPtr(3),
+		NotNull:  []string{"amount"},
+		Unique:   []string{"id", "missing"},
+	}
+	failures := e.Check(result([]any{int32(1), 10.5}, []any{int32(1), nil}))
+
+	for _, want := range []string{
+		"row_count: expected 3, got 2",
+		"not_null: amount has 1 NULL value(s)",
+		"unique: id has duplicate value(s): 1",
+		"unique: column missing not in result",
+		"rows: row 2 differs",
+	} {
+		found := false
+		for _, f := range failures {
+			found = found || strings.HasPrefix(f, want)
+		}
+		if !found {
+			t.Fatalf("missing failure %q in %v", want, failures)
+		}
+	}
+}
+
+func TestExpectCheck_DefaultsToEmpty(t *testing.T) {
+	if failures := (Expect{}).Check(result()); len(failures) != 0 {
+		t.Fatalf("unexpected failures: %v", failures)
+	}
+	failures := (Expect{}).Check(result([]any{int32(7), nil}))
+	if len(failures) != 1 || !strings.Contains(failures[0], "expected no rows, got 1; first: [7, NULL]") {
+		t.Fatalf("failures = %v", failures)
+	}
+}
@@ -0,0 +1,201 @@
+// Package sqltest runs data-driven SQL assertion tests: setup DDL, fixture
+// rows and a query whose result is checked against expectations, each in its
+// own throwaway database.
+package sqltest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+	"gopkg.in/yaml.v3"
+)
+
+// Test file suffixes discovered by Discover.
+const (
+	YAMLSuffix = ".test.yaml"
+	YMLSuffix  = ".test.yml"
+	SQLSuffix  = ".test.sql"
+)
+
+// Case is one test. In YAML files (several cases may be separated by '---'):
+//
+//	name: orders are deduplicated
+//	vars: {ds: 2024-01-01}
+//	setup: |                        # or setup_file: ../ddl/orders.sql
+//	  CREATE TABLE orders (id INT, amount DOUBLE);
+//	fixtures:
+//	  orders:
+//	    - {id: 1, amount: 10.5}
+//	    - {id: 1, amount: 10.5}
+//	query: SELECT DISTINCT id, amount FROM orders   # or query_file
+//	expect:
+//	  rows: [[1, 10.5]]
+//	  row_count: 1
+//	  not_null: [id]
+//	  unique: [id]
+//
+// A .test.sql file is a case whose last statement is the query and whose
+// other statements are the setup; it passes when the query returns no rows.
+//
+// Statements run in an isolated database, available as ${test_db}.
+type Case struct {
+	Name      string            `yaml:"name"`
+	Vars      map[string]string `yaml:"vars,omitempty"`
+	Setup     string            `yaml:"setup,omitempty"`
+	SetupFile string            `yaml:"setup_file,omitempty"`
+	Fixtures  map[string][]any  `yaml:"fixtures,omitempty"`
+	Query     string            `yaml:"query,omitempty"`
+	QueryFile string            `yaml:"query_file,omitempty"`
+	Expect    Expect            `yaml:"expect"`
+
+	File string `yaml:"-"` // test file the case was loaded from
+}
+
+// Expect lists the assertions checked against the query result. An empty
+// Expect asserts that the query returns no rows.
+type Expect struct {
+	Rows     [][]any  `yaml:"rows,omitempty"`
+	Ordered  *bool    `yaml:"ordered,omitempty"` // compare rows in order (default true)
+	RowCount *int     `yaml:"row_count,omitempty"`
+	NotNull  []string `yaml:"not_null,omitempty"`
+	Unique   []string `yaml:"unique,omitempty"`
+	Empty    bool     `yaml:"empty,omitempty"`
+}
+
+func (e Expect) isZero() bool {
+	return e.Rows == nil && e.Ordered == nil && e.RowCount == nil && len(e.NotNull) == 0 && len(e.Unique) == 0
+}
+
+// Discover returns the test files under each path, in lexical order. Paths
+// that are files are returned as is.
+func Discover(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		err = filepath.WalkDir(p, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && IsTestFile(path) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// IsTestFile reports whether path has a test file suffix.
+func IsTestFile(path string) bool {
+	return strings.HasSuffix(path, YAMLSuffix) || strings.HasSuffix(path, YMLSuffix) || strings.HasSuffix(path, SQLSuffix)
+}
+
+// Load reads the cases of a test file and resolves setup_file/query_file
+// relative to it.
+func Load(path string) ([]*Case, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test file: %w", err)
+	}
+
+	if strings.HasSuffix(path, SQLSuffix) {
+		c := sqlCase(path, string(data))
+		if c.Query == "" {
+			return nil, fmt.Errorf("%s: no statements", path)
+		}
+		return []*Case{c}, nil
+	}
+
+	var cases []*Case
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	for i := 1; ; i++ {
+		c := &Case{}
+		if err := dec.Decode(c); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		c.File = path
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("%s #%d", strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), i)
+		}
+		if err := c.resolveFiles(); err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(c.Query) == "" {
+			return nil, fmt.Errorf("%s: test %q has no query", path, c.Name)
+		}
+		if c.Expect.Ordered != nil && c.Expect.Rows == nil {
+			return nil, fmt.Errorf("%s: test %q sets ordered without rows", path, c.Name)
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+// sqlCase builds a case from a .test.sql file.
+func sqlCase(path, content string) *Case {
+	c := &Case{
+		Name:   strings.TrimSuffix(filepath.Base(path), SQLSuffix),
+		File:   path,
+		Expect: Expect{Empty: true},
+	}
+	statements := hs2.SplitStatements(content)
+	if len(statements) > 0 {
+		c.Query = statements[len(statements)-1]
+		c.Setup = strings.Join(statements[:len(statements)-1], ";\n")
+	}
+	return c
+}
+
+func (c *Case) resolveFiles() error {
+	read := func(name string) (string, error) {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(filepath.Dir(c.File), name)
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return "", fmt.Errorf("%s: test %q: %w", c.File, c.Name, err)
+		}
+		return string(data), nil
+	}
+
+	if c.SetupFile != "" {
+		setup, err := read(c.SetupFile)
+		if err != nil {
+			return err
+		}
+		c.Setup = setup + ";\n" + c.Setup
+	}
+	if c.QueryFile != "" {
+		if c.Query != "" {
+			return fmt.Errorf("%s: test %q sets both query and query_file", c.File, c.Name)
+		}
+		query, err := read(c.QueryFile)
+		if err != nil {
+			return err
+		}
+		c.Query = query
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package sqltest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "b.test.sql"), "SELECT 1")
+	writeFile(t, filepath.Join(dir, "nested", "a.test.yaml"), "query: SELECT 1")
+	writeFile(t, filepath.Join(dir, "ddl.sql"), "CREATE TABLE t (id INT)")
+
+	files, err := Discover([]string{dir})
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	want := []string{filepath.Join(dir, "b.test.sql"), filepath.Join(dir, "nested", "a.test.yaml")}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("files = %v, want %v", files, want)
+	}
+}
+
+func TestLoad_YAMLMultipleCasesAndFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "ddl", "orders.sql"), "CREATE TABLE orders (id INT)")
+	path := filepath.Join(dir, "orders.test.yaml")
+	writeFile(t, path, `name: first
+setup_file: ddl/orders.sql
+fixtures:
+  orders:
+    - {id: 1}
+query: SELECT id FROM orders
+expect:
+  rows: [[1]]
+---
+query: SELECT 1
+`)
+
+	cases, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cases) != 2 {
+		t.Fatalf("got %d cases", len(cases))
+	}
+	if !strings.HasPrefix(cases[0].Setup, "CREATE TABLE orders") {
+		t.Fatalf("setup_file not loaded: %q", cases[0].Setup)
+	}
+	if cases[1].Name != "orders.test #2" {
+		t.Fatalf("default name = %q", cases[1].Name)
+	}
+}
+
+func TestLoad_SQLCase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "no_orphans.test.sql")
+	writeFile(t, path, "CREATE TABLE a (id INT);\nSELECT * FROM a WHERE id IS NULL;\n")
+
+	cases, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	c := cases[0]
+	if c.Name != "no_orphans" || c.Setup != "CREATE TABLE a (id INT)" || c.Query != "SELECT * FROM a WHERE id IS NULL" || !c.Expect.Empty {
+		t.Fatalf("case = %+v", c)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"unknown field", "query: SELECT 1\nexpected: {}\n", "expected"},
+		{"no query", "name: empty\n", "has no query"},
+		{"missing query_file", "query_file: missing.sql\n", "missing.sql"},
+		{"ordered without rows", "query: SELECT 1\nexpect: {ordered: false}\n", "ordered without rows"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "x.test.yaml")
+			writeFile(t, path, tt.content)
+			if _, err := Load(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Load error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
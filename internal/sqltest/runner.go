@@ -0,0 +1,256 @@
+package sqltest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+	"github.com/danieljhkim/local-data-platform/internal/junit"
+	"github.com/danieljhkim/local-data-platform/internal/sqlscript"
+)
+
+// DatabasePrefix starts the name of every isolated test database.
+const DatabasePrefix = "ldt_"
+
+// Result is the outcome of one test case.
+type Result struct {
+	Case     *Case
+	Database string
+	Duration time.Duration
+	Failures []string // failed assertions, or the error that stopped the test
+}
+
+// Passed reports whether every assertion held.
+func (r Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// Runner runs test cases over a HiveServer2 session.
+type Runner struct {
+	Client sqlscript.Querier
+	Vars   sqlscript.Variables // applied before each case's own vars
+	// KeepDatabases leaves the isolated databases in place for debugging.
+	KeepDatabases bool
+}
+
+// Run runs a case in a fresh database and drops the database afterwards.
+func (r *Runner) Run(ctx context.Context, c *Case) Result {
+	start := time.Now()
+	res := Result{Case: c, Database: databaseName(c.Name)}
+	res.Failures = r.run(ctx, c, res.Database)
+	res.Duration = time.Since(start)
+	return res
+}
+
+func (r *Runner) run(ctx context.Context, c *Case, db string) []string {
+	if _, err := r.Client.Query(ctx, "CREATE DATABASE "+hs2.QuoteIdent(db)); err != nil {
+		return []string{fmt.Sprintf("failed to create test database: %v", err)}
+	}
+	if !r.KeepDatabases {
+		defer r.Client.Query(context.WithoutCancel(ctx), "DROP DATABASE IF EXISTS "+hs2.QuoteIdent(db)+" CASCADE")
+	}
+	defer r.Client.Query(context.WithoutCancel(ctx), "USE default")
+
+	vars := sqlscript.Variables{}
+	for name, value := range r.Vars {
+		vars[name] = value
+	}
+	for name, value := range c.Vars {
+		vars[name] = value
+	}
+	vars["test_db"] = db
+
+	statements := []string{"USE " + hs2.QuoteIdent(db)}
+	statements = append(statements, hs2.SplitStatements(c.Setup)...)
+	fixtures, err := fixtureStatements(c.Fixtures)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	statements = append(statements, fixtures...)
+	for _, stmt := range statements {
+		if _, err := r.Client.Query(ctx, vars.Substitute(stmt)); err != nil {
+			return []string{fmt.Sprintf("setup failed: %v\n  statement: %s", err, firstLine(stmt))}
+		}
+	}
+
+	queries := hs2.SplitStatements(c.Query)
+	if len(queries) != 1 {
+		return []string{fmt.Sprintf("query must be a single statement, got %d", len(queries))}
+	}
+	result, err := r.Client.Query(ctx, vars.Substitute(queries[0]))
+	if err != nil {
+		return []string{fmt.Sprintf("query failed: %v", err)}
+	}
+	return c.Expect.Check(result)
+}
+
+// fixtureStatements renders fixture rows as one multi-row INSERT per table,
+// in table name order. A table's rows are either all maps (column: value;
+// columns missing from a row are NULL) or all lists of values in table
+// column order.
+func fixtureStatements(fixtures map[string][]any) ([]string, error) {
+	tables := make([]string, 0, len(fixtures))
+	for table := range fixtures {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	var statements []string
+	for _, table := range tables {
+		if len(fixtures[table]) == 0 {
+			continue
+		}
+		stmt, err := insertStatement(table, fixtures[table])
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", table, err)
+		}
+		statements = append(statements, stmt)
+	}
+	return statements, nil
+}
+
+func insertStatement(table string, rows []any) (string, error) {
+	target := quoteTable(table)
+	switch rows[0].(type) {
+	case map[string]any:
+		seen := map[string]bool{}
+		var columns []string
+		for _, row := range rows {
+			m, ok := row.(map[string]any)
+			if !ok {
+				return "", fmt.Errorf("rows must all be maps or all be lists, got %T", row)
+			}
+			for col := range m {
+				if !seen[col] {
+					seen[col] = true
+					columns = append(columns, col)
+				}
+			}
+		}
+		sort.Strings(columns)
+		quoted := make([]string, len(columns))
+		for i, col := range columns {
+			quoted[i] = hs2.QuoteIdent(col)
+		}
+		tuples := make([]string, len(rows))
+		for i, row := range rows {
+			m := row.(map[string]any)
+			values := make([]any, len(columns))
+			for j, col := range columns {
+				values[j] = m[col]
+			}
+			tuple, err := tupleLiteral(values)
+			if err != nil {
+				return "", err
+			}
+			tuples[i] = tuple
+		}
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", target, strings.Join(quoted, ", "), strings.Join(tuples, ", ")), nil
+	case []any:
+		tuples := make([]string, len(rows))
+		for i, row := range rows {
+			values, ok := row.([]any)
+			if !ok {
+				return "", fmt.Errorf("rows must all be maps or all be lists, got %T", row)
+			}
+			tuple, err := tupleLiteral(values)
+			if err != nil {
+				return "", err
+			}
+			tuples[i] = tuple
+		}
+		return fmt.Sprintf("INSERT INTO %s VALUES %s", target, strings.Join(tuples, ", ")), nil
+	default:
+		return "", fmt.Errorf("row must be a map or a list, got %T", rows[0])
+	}
+}
+
+// tupleLiteral renders values as a parenthesized VALUES row.
+func tupleLiteral(values []any) (string, error) {
+	rendered := make([]string, len(values))
+	for i, v := range values {
+		lit, err := literal(v)
+		if err != nil {
+			return "", err
+		}
+		rendered[i] = lit
+	}
+	return "(" + strings.Join(rendered, ", ") + ")", nil
+}
+
+// literal renders a YAML scalar as a HiveQL literal. Nested lists and maps
+// are rejected: they would need array(), map() or named_struct() matching
+// the column type, which a fixture row does not carry.
+func literal(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return hs2.QuoteString(v), nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case time.Time:
+		return hs2.QuoteString(v.Format("2006-01-02 15:04:05")), nil
+	case int, int64, uint64, float64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v (%T): fixture values must be scalars; insert complex types from setup", v, v)
+	}
+}
+
+func quoteTable(table string) string {
+	parts := strings.Split(table, ".")
+	for i, p := range parts {
+		parts[i] = hs2.QuoteIdent(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+var nonIdentChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// databaseName returns a unique database name for a test: ldt_<name>_<random>.
+func databaseName(testName string) string {
+	name := strings.Trim(nonIdentChars.ReplaceAllString(strings.ToLower(testName), "_"), "_")
+	if len(name) > 40 {
+		name = name[:40]
+	}
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	if name == "" {
+		return DatabasePrefix + hex.EncodeToString(suffix)
+	}
+	return DatabasePrefix + name + "_" + hex.EncodeToString(suffix)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+// JUnitSuites maps each test file to a suite and each case to a test case.
+func JUnitSuites(results []Result) []junit.Suite {
+	var suites []junit.Suite
+	for _, r := range results {
+		if len(suites) == 0 || suites[len(suites)-1].Name != r.Case.File {
+			suites = append(suites, junit.Suite{Name: r.Case.File})
+		}
+		s := &suites[len(suites)-1]
+
+		c := junit.Case{Name: r.Case.Name, Classname: r.Case.File, Time: r.Duration}
+		if !r.Passed() {
+			c.Failure = firstLine(r.Failures[0])
+			c.Details = strings.Join(r.Failures, "\n")
+		}
+		s.Cases = append(s.Cases, c)
+	}
+	return suites
+}
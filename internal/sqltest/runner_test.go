@@ -0,0 +1,121 @@
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/danieljhkim/local-data-platform/internal/hs2"
+)
+
+type fakeClient struct {
+	statements []string
+	result     *hs2.Result
+	fail       string // statement prefix that fails
+}
+
+func (f *fakeClient) Query(_ context.Context, stmt string) (*hs2.Result, error) {
+	f.statements = append(f.statements, stmt)
+	if f.fail != "" && strings.HasPrefix(stmt, f.fail) {
+		return nil, fmt.Errorf("query failed: boom")
+	}
+	if strings.HasPrefix(stmt, "SELECT") {
+		return f.result, nil
+	}
+	return &hs2.Result{}, nil
+}
+
+func TestRunner_IsolatedDatabaseAndFixtures(t *testing.T) {
+	client := &fakeClient{result: &hs2.Result{
+		Columns: []hs2.Column{{Name: "id", Type: "int"}},
+		Rows:    [][]any{{int32(1)}},
+	}}
+	c := &Case{
+		Name:  "Orders: dedup",
+		Setup: "CREATE TABLE orders (id INT, note STRING, ds STRING); CREATE TABLE users (id INT, name STRING);",
+		Fixtures: map[string][]any{
+			"orders": {
+				map[string]any{"id": 1, "note": "it's", "ds": nil},
+				map[string]any{"id": 2, "ds": "${ds}"},
+			},
+			"users": {
+				[]any{1, "ann"},
+				[]any{2, "bo"},
+			},
+		},
+		Vars:   map[string]string{"ds": "2024-01-01"},
+		Query:  "SELECT id FROM ${test_db}.orders",
+		Expect: Expect{Rows: [][]any{{1}}},
+	}
+
+	r := (&Runner{Client: client}).Run(context.Background(), c)
+	if !r.Passed() {
+		t.Fatalf("failures: %v", r.Failures)
+	}
+	if !strings.HasPrefix(r.Database, "ldt_orders_dedup_") {
+		t.Fatalf("database = %q", r.Database)
+	}
+
+	db := "`" + r.Database + "`"
+	want := []string{
+		"CREATE DATABASE " + db,
+		"USE " + db,
+		"CREATE TABLE orders (id INT, note STRING, ds STRING)",
+		"CREATE TABLE users (id INT, name STRING)",
+		"INSERT INTO `orders` (`ds`, `id`, `note`) VALUES (NULL, 1, 'it\\'s'), ('2024-01-01', 2, NULL)",
+		"INSERT INTO `users` VALUES (1, 'ann'), (2, 'bo')",
+		"SELECT id FROM " + r.Database + ".orders",
+		"USE default",
+		"DROP DATABASE IF EXISTS " + db + " CASCADE",
+	}
+	if strings.Join(client.statements, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("statements:\n%s\nwant:\n%s", strings.Join(client.statements, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRunner_SetupFailureStillDropsDatabase(t *testing.T) {
+	client := &fakeClient{fail: "CREATE TABLE"}
+	c := &Case{Name: "broken", Setup: "CREATE TABLE t (id INT)", Query: "SELECT 1"}
+
+	r := (&Runner{Client: client}).Run(context.Background(), c)
+	if r.Passed() || !strings.HasPrefix(r.Failures[0], "setup failed") {
+		t.Fatalf("failures = %v", r.Failures)
+	}
+	if last := client.statements[len(client.statements)-1]; !strings.HasPrefix(last, "DROP DATABASE") {
+		t.Fatalf("database not dropped; last statement %q", last)
+	}
+}
+
+func TestFixtureStatements_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		rows []any
+		want string
+	}{
+		{"mixed rows", []any{map[string]any{"id": 1}, []any{2}}, "all be maps or all be lists"},
+		{"nested list", []any{[]any{1, []any{"a", "b"}}}, "must be scalars"},
+		{"nested map", []any{map[string]any{"attrs": map[string]any{"k": "v"}}}, "must be scalars"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := fixtureStatements(map[string][]any{"t": tt.rows}); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("fixtureStatements error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJUnitSuites(t *testing.T) {
+	results := []Result{
+		{Case: &Case{Name: "a", File: "x.test.yaml"}},
+		{Case: &Case{Name: "b", File: "x.test.yaml"}, Failures: []string{"rows: row 1 differs\n  expected: [1]", "row_count: expected 1, got 2"}},
+	}
+	suites := JUnitSuites(results)
+	if len(suites) != 1 || len(suites[0].Cases) != 2 {
+		t.Fatalf("suites = %+v", suites)
+	}
+	if got := suites[0].Cases[1].Failure; got != "rows: row 1 differs" {
+		t.Fatalf("failure message = %q", got)
+	}
+}
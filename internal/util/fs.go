@@ -34,12 +34,20 @@ func CopyFile(src, dst string) error {
 	}
 	defer srcFile.Close()
 
-	// Create destination file
-	dstFile, err := os.Create(dst)
+	srcInfo, err := srcFile.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat source file: %w", err)
+	}
+
+	// Create destination file with the source permissions (e.g. 0600 for files with secrets)
+	dstFile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, srcInfo.Mode().Perm())
 	if err != nil {
 		return fmt.Errorf("failed to create destination file: %w", err)
 	}
 	defer dstFile.Close()
+	if err := dstFile.Chmod(srcInfo.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set destination permissions: %w", err)
+	}
 
 	// Copy contents
 	if _, err := io.Copy(dstFile, srcFile); err != nil {
@@ -107,3 +115,38 @@ func CopyDir(src, dst string) error {
 		return CopyFile(path, dstPath)
 	})
 }
+
+// MoveDir moves a directory from src to dst
+// Uses rename when possible and falls back to copy + remove across filesystems
+func MoveDir(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create destination parent: %w", err)
+	}
+
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	// Rename fails across devices (EXDEV); copy then remove the source
+	if err := CopyDir(src, dst); err != nil {
+		return fmt.Errorf("failed to copy %s to %s: %w", src, dst, err)
+	}
+	if err := os.RemoveAll(src); err != nil {
+		return fmt.Errorf("copied to %s but failed to remove %s: %w", dst, src, err)
+	}
+	return nil
+}
+
+// FormatSize renders a byte count for humans (e.g. 12.3 MiB).
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"os/exec"
 	"strings"
 )
 
@@ -67,3 +68,13 @@ func needsQuoting(s string) bool {
 	}
 	return false
 }
+
+// BrewPrefix returns the installation prefix of a Homebrew formula
+// (brew --prefix <formula>), or "" when brew or the formula is missing
+func BrewPrefix(formula string) string {
+	output, err := exec.Command("brew", "--prefix", formula).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -61,6 +61,17 @@ func (c *HadoopConfiguration) SetProperty(name, value string) {
 	})
 }
 
+// RemoveProperty removes a property if present
+func (c *HadoopConfiguration) RemoveProperty(name string) {
+	kept := c.Properties[:0]
+	for _, prop := range c.Properties {
+		if prop.Name != name {
+			kept = append(kept, prop)
+		}
+	}
+	c.Properties = kept
+}
+
 // WriteXML writes the configuration back to a file
 func (c *HadoopConfiguration) WriteXML(path string) error {
 	data, err := xml.MarshalIndent(c, "", "  ")